@@ -90,6 +90,16 @@ type BitbucketConfig struct {
 	DeployKey    string
 }
 
+// GiteaConfig is the configuration for self-hosted Gitea and Forgejo instances.
+// Both providers expose the same OAuth2 and REST API.
+type GiteaConfig struct {
+	BaseURL       string // e.g. https://git.example.org
+	ClientID      string
+	ClientSecret  string
+	RedirectURL   string
+	WebhookSecret string // Used to verify the signature of inbound webhooks
+}
+
 type UserManagement struct {
 	Whitelist  []string `json:"whitelist"`  // Whitelist of pre-approved email domains (e.g. "example.com")
 	SignUpMode string   `json:"signUpMode"` // "on" | "off" | "waitlist" (default: on)
@@ -99,6 +109,7 @@ type AuthConfig struct {
 	Github         GithubConfig    `json:"github,omitempty"`
 	Gitlab         GitlabConfig    `json:"gitlab,omitempty"`
 	Bitbucket      BitbucketConfig `json:"bitbucket,omitempty"`
+	Gitea          GiteaConfig     `json:"gitea,omitempty"`
 	UserManagement UserManagement  `json:"userManagement,omitempty"`
 }
 
@@ -110,6 +121,16 @@ type DomainConfig struct {
 	Webhooks string `json:"webhooks,omitempty"` // e.g. webhooks.stormkit.io
 }
 
+// BuildQueueConfig limits the number of deployments that are built at the same time.
+// A limit of zero means that there is no limit for the given scope.
+type BuildQueueConfig struct {
+	MaxConcurrentBuilds        int            `json:"maxConcurrentBuilds"`        // Limit for the whole instance. Defaults to the runner concurrency.
+	MaxConcurrentBuildsPerTeam int            `json:"maxConcurrentBuildsPerTeam"` // Default limit for every team
+	MaxConcurrentBuildsPerApp  int            `json:"maxConcurrentBuildsPerApp"`  // Default limit for every app
+	Teams                      map[string]int `json:"teams,omitempty"`            // Team specific limits, keyed by the team id
+	Apps                       map[string]int `json:"apps,omitempty"`             // App specific limits, keyed by the app id
+}
+
 type InstanceConfig struct {
 	AdminUserConfig    *AdminUserConfig    `json:"adminUser"`
 	VolumesConfig      *VolumesConfig      `json:"volumes"`
@@ -119,6 +140,7 @@ type InstanceConfig struct {
 	LicenseConfig      *LicenseConfig      `json:"license,omitempty"`
 	AuthConfig         *AuthConfig         `json:"auth,omitempty"`
 	DomainConfig       *DomainConfig       `json:"domains,omitempty"`
+	BuildQueueConfig   *BuildQueueConfig   `json:"buildQueue,omitempty"`
 }
 
 // Scan implements the sql.Scanner interface
@@ -161,6 +183,8 @@ func (c *InstanceConfig) Scan(val any) error {
 		c.AuthConfig.Gitlab.ClientSecret = utils.DecryptToString(c.AuthConfig.Gitlab.ClientSecret)
 		c.AuthConfig.Bitbucket.ClientSecret = utils.DecryptToString(c.AuthConfig.Bitbucket.ClientSecret)
 		c.AuthConfig.Bitbucket.DeployKey = utils.DecryptToString(c.AuthConfig.Bitbucket.DeployKey)
+		c.AuthConfig.Gitea.ClientSecret = utils.DecryptToString(c.AuthConfig.Gitea.ClientSecret)
+		c.AuthConfig.Gitea.WebhookSecret = utils.DecryptToString(c.AuthConfig.Gitea.WebhookSecret)
 	}
 
 	return nil
@@ -204,6 +228,14 @@ func (c InstanceConfig) Value() (driver.Value, error) {
 		if c.AuthConfig.Bitbucket.DeployKey != "" {
 			c.AuthConfig.Bitbucket.DeployKey = utils.EncryptToString(c.AuthConfig.Bitbucket.DeployKey)
 		}
+
+		if c.AuthConfig.Gitea.ClientSecret != "" {
+			c.AuthConfig.Gitea.ClientSecret = utils.EncryptToString(c.AuthConfig.Gitea.ClientSecret)
+		}
+
+		if c.AuthConfig.Gitea.WebhookSecret != "" {
+			c.AuthConfig.Gitea.WebhookSecret = utils.EncryptToString(c.AuthConfig.Gitea.WebhookSecret)
+		}
 	}
 
 	return json.Marshal(c)
@@ -215,7 +247,27 @@ func (vc InstanceConfig) IsEmpty() bool {
 
 // IsAuthEnabled returns whether the auth config is enabled or not.
 func (vc InstanceConfig) IsAuthEnabled() bool {
-	return vc.IsGithubEnabled() || vc.IsGitlabEnabled() || vc.IsBitbucketEnabled()
+	return vc.IsGithubEnabled() || vc.IsGitlabEnabled() || vc.IsBitbucketEnabled() || vc.IsGiteaEnabled()
+}
+
+// IsGiteaEnabled returns whether the Gitea (or Forgejo) auth config is enabled or not.
+func (vc InstanceConfig) IsGiteaEnabled() bool {
+	if vc.AuthConfig == nil {
+		return false
+	}
+
+	return vc.AuthConfig.Gitea.BaseURL != "" &&
+		vc.AuthConfig.Gitea.ClientID != "" &&
+		vc.AuthConfig.Gitea.ClientSecret != ""
+}
+
+// GiteaURL returns the url of the given path on the Gitea instance.
+func (vc InstanceConfig) GiteaURL(path string) string {
+	if vc.AuthConfig == nil {
+		return ""
+	}
+
+	return strings.TrimSuffix(vc.AuthConfig.Gitea.BaseURL, "/") + path
 }
 
 // IsBitbucketEnabled returns whether the Bitbucket auth config is enabled or not.
@@ -251,6 +303,42 @@ func (vc InstanceConfig) IsGithubEnabled() bool {
 		vc.AuthConfig.Github.AppID > 0
 }
 
+// BuildQueue returns the build queue limits. When the limits are not
+// configured, only the instance limit is applied using the runner concurrency.
+func (vc InstanceConfig) BuildQueue() BuildQueueConfig {
+	bq := BuildQueueConfig{}
+
+	if vc.BuildQueueConfig != nil {
+		bq = *vc.BuildQueueConfig
+	}
+
+	if bq.MaxConcurrentBuilds == 0 {
+		if runner := config.Get().Runner; runner != nil {
+			bq.MaxConcurrentBuilds = runner.Concurrency
+		}
+	}
+
+	return bq
+}
+
+// TeamLimit returns the maximum number of concurrent builds for the given team.
+func (bq BuildQueueConfig) TeamLimit(teamID types.ID) int {
+	if limit, ok := bq.Teams[teamID.String()]; ok {
+		return limit
+	}
+
+	return bq.MaxConcurrentBuildsPerTeam
+}
+
+// AppLimit returns the maximum number of concurrent builds for the given app.
+func (bq BuildQueueConfig) AppLimit(appID types.ID) int {
+	if limit, ok := bq.Apps[appID.String()]; ok {
+		return limit
+	}
+
+	return bq.MaxConcurrentBuildsPerApp
+}
+
 // SignUpMode returns the configured sign up mode.
 // If the AuthConfig or UserManagement configurations are not defined,
 // the default is `on`
@@ -328,7 +416,7 @@ func (vc InstanceConfig) ApiURL(path string) string {
 	return vc.DomainConfig.API + "/" + strings.TrimLeft(path, "/")
 }
 
-// WebhooksURL returns the URL for webhooks from GitLab, GitHub and Bitbucket.
+// WebhooksURL returns the URL for webhooks from GitLab, GitHub, Bitbucket and Gitea.
 func (vc InstanceConfig) WebhooksURL(path string) string {
 	if vc.DomainConfig == nil {
 		return ""
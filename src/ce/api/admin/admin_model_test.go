@@ -9,7 +9,9 @@ import (
 	"testing"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/lib/config"
 	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/mise"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/sys"
@@ -196,6 +198,26 @@ func (s *AdminModelSuite) Test_SignUpMode() {
 	s.Equal(admin.SIGNUP_MODE_WAITLIST, vc.SignUpMode())
 }
 
+func (s *AdminModelSuite) Test_BuildQueue() {
+	vc := admin.InstanceConfig{}
+	s.Equal(config.Get().Runner.Concurrency, vc.BuildQueue().MaxConcurrentBuilds)
+
+	vc.BuildQueueConfig = &admin.BuildQueueConfig{
+		MaxConcurrentBuilds:        20,
+		MaxConcurrentBuildsPerTeam: 5,
+		MaxConcurrentBuildsPerApp:  2,
+		Teams:                      map[string]int{"1": 10},
+		Apps:                       map[string]int{"7": 0},
+	}
+
+	bq := vc.BuildQueue()
+	s.Equal(20, bq.MaxConcurrentBuilds)
+	s.Equal(10, bq.TeamLimit(types.ID(1)))
+	s.Equal(5, bq.TeamLimit(types.ID(2)))
+	s.Equal(0, bq.AppLimit(types.ID(7)))
+	s.Equal(2, bq.AppLimit(types.ID(8)))
+}
+
 func TestAdminModel(t *testing.T) {
 	suite.Run(t, &AdminModelSuite{})
 }
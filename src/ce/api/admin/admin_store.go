@@ -155,6 +155,7 @@ func (s *store) Config(ctx context.Context) (InstanceConfig, error) {
 		ghAppName := utils.GetString(os.Getenv("GITHUB_APP_NAME"), os.Getenv("GITHUB_ACCOUNT"))
 		glClientID := os.Getenv("GITLAB_CLIENT_ID")
 		bitbucketID := os.Getenv("BITBUCKET_CLIENT_ID")
+		giteaURL := os.Getenv("GITEA_URL")
 
 		if decoded, err := utils.DecodeString(secrets["GITHUB_PRIV_KEY"]); err == nil && decoded != nil {
 			ghPrivKey = string(decoded)
@@ -193,6 +194,19 @@ func (s *store) Config(ctx context.Context) (InstanceConfig, error) {
 				DeployKey:    os.Getenv("BITBUCKET_DEPLOY_KEY"),
 			}
 		}
+
+		if giteaURL != "" {
+			cnf.AuthConfig.Gitea = GiteaConfig{
+				BaseURL:       giteaURL,
+				ClientID:      os.Getenv("GITEA_CLIENT_ID"),
+				ClientSecret:  secrets["GITEA_SECRET"],
+				WebhookSecret: secrets["GITEA_WEBHOOK_SECRET"],
+				RedirectURL: utils.GetString(
+					os.Getenv("GITEA_REDIRECT_URL"),
+					cnf.ApiURL("/auth/gitea/callback"),
+				),
+			}
+		}
 	}
 
 	cachedConfigMux.Lock()
@@ -0,0 +1,63 @@
+package adminhandlers
+
+import (
+	"net/http"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+)
+
+type BuildQueueUpdateRequest struct {
+	admin.BuildQueueConfig
+}
+
+// handlerBuildQueueUpdate replaces the concurrency limits of the build queue.
+func handlerBuildQueueUpdate(req *user.RequestContext) *shttp.Response {
+	data := BuildQueueUpdateRequest{}
+
+	if err := req.Post(&data); err != nil {
+		return shttp.Error(err)
+	}
+
+	limits := []int{
+		data.MaxConcurrentBuilds,
+		data.MaxConcurrentBuildsPerTeam,
+		data.MaxConcurrentBuildsPerApp,
+	}
+
+	for _, limit := range data.Teams {
+		limits = append(limits, limit)
+	}
+
+	for _, limit := range data.Apps {
+		limits = append(limits, limit)
+	}
+
+	for _, limit := range limits {
+		if limit < 0 {
+			return shttp.BadRequest(map[string]any{
+				"error": "Concurrency limits cannot be negative. Use 0 to remove the limit.",
+			})
+		}
+	}
+
+	vc, err := admin.Store().Config(req.Context())
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	vc.BuildQueueConfig = &data.BuildQueueConfig
+
+	if err := admin.Store().UpsertConfig(req.Context(), vc); err != nil {
+		return shttp.Error(err)
+	}
+
+	return &shttp.Response{
+		Status: http.StatusOK,
+		Data: map[string]any{
+			"buildQueue": vc.BuildQueue(),
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package adminhandlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin/adminhandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stretchr/testify/suite"
+)
+
+type HandlerBuildQueueUpdateSuite struct {
+	suite.Suite
+	*factory.Factory
+	conn databasetest.TestDB
+}
+
+func (s *HandlerBuildQueueUpdateSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+}
+
+func (s *HandlerBuildQueueUpdateSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+	admin.ResetCache(context.Background())
+}
+
+func (s *HandlerBuildQueueUpdateSuite) update(usr *factory.MockUser, payload map[string]any) shttptest.Response {
+	return shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(adminhandlers.Services).Router().Handler(),
+		shttp.MethodPut,
+		"/admin/system/build-queue",
+		payload,
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+}
+
+func (s *HandlerBuildQueueUpdateSuite) Test_Success() {
+	usr := s.MockUser(map[string]any{"IsAdmin": true})
+
+	response := s.update(usr, map[string]any{
+		"maxConcurrentBuilds":        5,
+		"maxConcurrentBuildsPerTeam": 2,
+		"maxConcurrentBuildsPerApp":  1,
+		"teams":                      map[string]int{"15": 3},
+	})
+
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{
+		"buildQueue": {
+			"maxConcurrentBuilds": 5,
+			"maxConcurrentBuildsPerTeam": 2,
+			"maxConcurrentBuildsPerApp": 1,
+			"teams": { "15": 3 }
+		}
+	}`, response.String())
+
+	cnf := admin.MustConfig().BuildQueue()
+	s.Equal(5, cnf.MaxConcurrentBuilds)
+	s.Equal(3, cnf.TeamLimit(15))
+	s.Equal(1, cnf.AppLimit(1))
+}
+
+func (s *HandlerBuildQueueUpdateSuite) Test_NegativeLimit() {
+	usr := s.MockUser(map[string]any{"IsAdmin": true})
+
+	response := s.update(usr, map[string]any{
+		"maxConcurrentBuilds": 5,
+		"apps":                map[string]int{"1": -1},
+	})
+
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.JSONEq(`{"error":"Concurrency limits cannot be negative. Use 0 to remove the limit."}`, response.String())
+}
+
+func (s *HandlerBuildQueueUpdateSuite) Test_NonAdmin() {
+	usr := s.MockUser(map[string]any{"IsAdmin": false})
+	response := s.update(usr, map[string]any{"maxConcurrentBuilds": 5})
+	s.Equal(http.StatusUnauthorized, response.Code)
+}
+
+func TestHandlerBuildQueueUpdate(t *testing.T) {
+	suite.Run(t, &HandlerBuildQueueUpdateSuite{})
+}
@@ -2,6 +2,7 @@ package adminhandlers
 
 import (
 	"context"
+	"strings"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/user"
@@ -10,17 +11,19 @@ import (
 )
 
 type GitConfigureRequest struct {
-	Provider     string `json:"provider"`     // github, gitlab, or bitbucket
-	AppID        string `json:"appId"`        // Github application id
-	Account      string `json:"account"`      // GitHub account name
-	Organization string `json:"organization"` // GitHub organization (optional)
-	PrivateKey   string `json:"privateKey"`   // GitHub App Private Key
-	ClientID     string `json:"clientId"`     // OAuth Client ID
-	ClientSecret string `json:"clientSecret"` // OAuth Client Secret
-	RedirectURL  string `json:"redirectUrl"`  // GitLab/Bitbucket Redirect URL
-	DeployKey    string `json:"deployKey"`    // Bitbucket Deploy Key
-	RunnerRepo   string `json:"runnerRepo"`   // GitHub Runner Repository
-	RunnerToken  string `json:"runnerToken"`  // GitHub Runner Token
+	Provider      string `json:"provider"`      // github, gitlab, bitbucket or gitea
+	AppID         string `json:"appId"`         // Github application id
+	Account       string `json:"account"`       // GitHub account name
+	Organization  string `json:"organization"`  // GitHub organization (optional)
+	PrivateKey    string `json:"privateKey"`    // GitHub App Private Key
+	ClientID      string `json:"clientId"`      // OAuth Client ID
+	ClientSecret  string `json:"clientSecret"`  // OAuth Client Secret
+	RedirectURL   string `json:"redirectUrl"`   // GitLab/Bitbucket/Gitea Redirect URL
+	DeployKey     string `json:"deployKey"`     // Bitbucket Deploy Key
+	RunnerRepo    string `json:"runnerRepo"`    // GitHub Runner Repository
+	RunnerToken   string `json:"runnerToken"`   // GitHub Runner Token
+	BaseURL       string `json:"baseUrl"`       // Gitea/Forgejo instance URL
+	WebhookSecret string `json:"webhookSecret"` // Gitea/Forgejo webhook secret (optional)
 }
 
 func handlerGitConfigure(req *user.RequestContext) *shttp.Response {
@@ -86,9 +89,31 @@ func handlerGitConfigure(req *user.RequestContext) *shttp.Response {
 				"error": "Bitbucket is not properly configured. Please provide all required fields.",
 			})
 		}
+	case "gitea":
+		cnf.AuthConfig.Gitea = admin.GiteaConfig{
+			BaseURL:      strings.TrimSuffix(data.BaseURL, "/"),
+			ClientID:     data.ClientID,
+			ClientSecret: data.ClientSecret,
+			RedirectURL: utils.GetString(
+				data.RedirectURL,
+				cnf.AuthConfig.Gitea.RedirectURL,
+				admin.MustConfig().ApiURL("/auth/gitea/callback"),
+			),
+			WebhookSecret: utils.GetString(
+				data.WebhookSecret,
+				cnf.AuthConfig.Gitea.WebhookSecret,
+				utils.RandomToken(32),
+			),
+		}
+
+		if !cnf.IsGiteaEnabled() {
+			return shttp.BadRequest(map[string]any{
+				"error": "Gitea is not properly configured. Please provide all required fields.",
+			})
+		}
 	default:
 		return shttp.BadRequest(map[string]any{
-			"error": "Invalid provider. Must be one of: github, gitlab, bitbucket",
+			"error": "Invalid provider. Must be one of: github, gitlab, bitbucket, gitea",
 		})
 	}
 
@@ -25,6 +25,7 @@ func handlerGitDetails(req *user.RequestContext) *shttp.Response {
 				"github":    nil,
 				"gitlab":    nil,
 				"bitbucket": nil,
+				"gitea":     nil,
 			},
 		}
 	}
@@ -59,6 +60,16 @@ func handlerGitDetails(req *user.RequestContext) *shttp.Response {
 		}
 	}
 
+	if cnf.IsGiteaEnabled() {
+		data["gitea"] = map[string]any{
+			"baseUrl":          auth.Gitea.BaseURL,
+			"clientId":         auth.Gitea.ClientID,
+			"redirectUrl":      auth.Gitea.RedirectURL,
+			"hasClientSecret":  true,
+			"hasWebhookSecret": auth.Gitea.WebhookSecret != "",
+		}
+	}
+
 	return &shttp.Response{
 		Status: http.StatusOK,
 		Data:   data,
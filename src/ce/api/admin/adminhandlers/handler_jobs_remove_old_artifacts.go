@@ -11,7 +11,7 @@ import (
 
 func handlerJobsRemoveOldArtifacts(req *user.RequestContext) *shttp.Response {
 	ctx := context.WithValue(req.Context(), jobs.KeyContextNumberOfDeploymentsToDelete{}, 50)
-	ids, err := jobs.RemoveDeploymentArtifactsManually(ctx, 30)
+	ids, err := jobs.RemoveDeploymentArtifactsManually(ctx, 0)
 
 	if err != nil {
 		return &shttp.Response{
@@ -0,0 +1,39 @@
+package adminhandlers
+
+import (
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
+)
+
+// handlerOSV returns the status of the advisory database that is
+// used to scan the dependencies for vulnerabilities.
+func handlerOSV(req *user.RequestContext) *shttp.Response {
+	services := []string{
+		rediscache.ServiceWorkerserver,
+	}
+
+	status, err := rediscache.Status(req.Context(), "osv_update", services)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	updatedAt, err := rediscache.Client().Get(req.Context(), osv.KeyUpdatedAt).Int64()
+
+	if err != nil && err != redis.Nil {
+		return shttp.Error(err)
+	}
+
+	return &shttp.Response{
+		Status: http.StatusOK,
+		Data: map[string]any{
+			"status":    status,
+			"updatedAt": updatedAt,
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package adminhandlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin/adminhandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
+	"github.com/stretchr/testify/suite"
+)
+
+type HandlerOSVSuite struct {
+	suite.Suite
+	*factory.Factory
+
+	conn databasetest.TestDB
+}
+
+func (s *HandlerOSVSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+}
+
+func (s *HandlerOSVSuite) AfterTest(suiteName, _ string) {
+	s.conn.CloseTx()
+	rediscache.Client().Del(context.Background(), osv.KeyUpdatedAt)
+}
+
+func (s *HandlerOSVSuite) Test_Success() {
+	usr := s.MockUser(map[string]any{"IsAdmin": true})
+
+	s.NoError(rediscache.Client().Set(context.Background(), osv.KeyUpdatedAt, 1760000000, 0).Err())
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(adminhandlers.Services).Router().Handler(),
+		shttp.MethodGet,
+		"/admin/system/osv",
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+
+	var data map[string]any
+	s.NoError(json.Unmarshal(response.Body.Bytes(), &data))
+	s.Equal(float64(1760000000), data["updatedAt"])
+	s.Equal(rediscache.StatusOK, data["status"])
+}
+
+func TestHandlerOSVSuite(t *testing.T) {
+	suite.Run(t, &HandlerOSVSuite{})
+}
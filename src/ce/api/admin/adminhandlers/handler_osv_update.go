@@ -0,0 +1,33 @@
+package adminhandlers
+
+import (
+	"net/http"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+)
+
+// handlerOSVUpdate triggers the refresh of the advisory database.
+// The database is downloaded by the workerservers that run the builds.
+func handlerOSVUpdate(req *user.RequestContext) *shttp.Response {
+	services := []string{
+		rediscache.ServiceWorkerserver,
+	}
+
+	if err := rediscache.SetAll("osv_update", rediscache.StatusSent, services); err != nil {
+		return shttp.Error(err)
+	}
+
+	if err := rediscache.Broadcast(rediscache.EventOSVUpdate); err != nil {
+		if err := rediscache.SetAll("osv_update", rediscache.StatusErr, services); err != nil {
+			return shttp.Error(err)
+		}
+
+		return shttp.Error(err)
+	}
+
+	return &shttp.Response{
+		Status: http.StatusOK,
+	}
+}
@@ -0,0 +1,100 @@
+package adminhandlers_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin/adminhandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stormkit-io/stormkit-io/src/mocks"
+	"github.com/stretchr/testify/suite"
+)
+
+type HandlerOSVUpdateSuite struct {
+	suite.Suite
+	*factory.Factory
+
+	conn    databasetest.TestDB
+	service *mocks.MicroServiceInterface
+}
+
+func (s *HandlerOSVUpdateSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+	s.service = &mocks.MicroServiceInterface{}
+	rediscache.DefaultService = s.service
+}
+
+func (s *HandlerOSVUpdateSuite) AfterTest(suiteName, _ string) {
+	s.conn.CloseTx()
+	rediscache.DefaultService = nil
+}
+
+func (s *HandlerOSVUpdateSuite) Test_Success() {
+	usr := s.MockUser(map[string]any{"IsAdmin": true})
+	services := []string{
+		rediscache.ServiceWorkerserver,
+	}
+
+	s.service.On("SetAll", "osv_update", rediscache.StatusSent, services).Return(nil).Once()
+	s.service.On("Broadcast", rediscache.EventOSVUpdate).Return(nil).Once()
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(adminhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/admin/system/osv",
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+}
+
+func (s *HandlerOSVUpdateSuite) Test_BroadcastError() {
+	usr := s.MockUser(map[string]any{"IsAdmin": true})
+	expectedError := errors.New("broadcast failed")
+
+	s.service.On("SetAll", "osv_update", rediscache.StatusSent, []string{"workerserver"}).Return(nil).Once()
+	s.service.On("Broadcast", rediscache.EventOSVUpdate).Return(expectedError).Once()
+	s.service.On("SetAll", "osv_update", rediscache.StatusErr, []string{"workerserver"}).Return(nil).Once()
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(adminhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/admin/system/osv",
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusInternalServerError, response.Code)
+}
+
+func (s *HandlerOSVUpdateSuite) Test_NonAdmin() {
+	usr := s.MockUser(map[string]any{"IsAdmin": false})
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(adminhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/admin/system/osv",
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusUnauthorized, response.Code)
+}
+
+func TestHandlerOSVUpdateSuite(t *testing.T) {
+	suite.Run(t, &HandlerOSVUpdateSuite{})
+}
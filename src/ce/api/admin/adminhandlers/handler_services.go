@@ -0,0 +1,26 @@
+package adminhandlers
+
+import (
+	"net/http"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+)
+
+// handlerServices returns the services that are running on each hosting instance
+// together with their resource usage.
+func handlerServices(req *user.RequestContext) *shttp.Response {
+	instances, err := integrations.ServiceUsage(req.Context())
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	return &shttp.Response{
+		Status: http.StatusOK,
+		Data: map[string]any{
+			"instances": instances,
+		},
+	}
+}
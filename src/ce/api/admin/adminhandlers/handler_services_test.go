@@ -0,0 +1,90 @@
+package adminhandlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin/adminhandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+)
+
+type HandlerServicesSuite struct {
+	suite.Suite
+	*factory.Factory
+	conn databasetest.TestDB
+}
+
+func (s *HandlerServicesSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+}
+
+func (s *HandlerServicesSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+	rediscache.Client().Del(context.Background(), "service_usage:hosting-1")
+}
+
+func (s *HandlerServicesSuite) Test_Get_Success() {
+	usr := s.MockUser(map[string]any{"IsAdmin": true})
+
+	s.NoError(integrations.SaveServiceUsage(context.Background(), "hosting-1", []integrations.ServiceStatus{
+		{
+			ARN:   "local:1:1",
+			AppID: 1,
+			EnvID: 1,
+			State: integrations.ServiceStateRunning,
+			Usage: &integrations.ResourceUsage{MemoryBytes: 52428800, CPUSeconds: 1.5, MemoryLimit: 134217728},
+		},
+	}))
+
+	resp := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(adminhandlers.Services).Router().Handler(),
+		shttp.MethodGet,
+		"/admin/system/services",
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusOK, resp.Code)
+
+	var payload struct {
+		Instances map[string][]integrations.ServiceStatus `json:"instances"`
+	}
+
+	s.NoError(json.Unmarshal([]byte(resp.String()), &payload))
+	s.Len(payload.Instances["hosting-1"], 1)
+	s.Equal(int64(52428800), payload.Instances["hosting-1"][0].Usage.MemoryBytes)
+	s.Equal(int64(134217728), payload.Instances["hosting-1"][0].Usage.MemoryLimit)
+}
+
+func (s *HandlerServicesSuite) Test_Get_Unauthorized_NonAdmin() {
+	usr := s.MockUser(map[string]any{"IsAdmin": false})
+
+	resp := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(adminhandlers.Services).Router().Handler(),
+		shttp.MethodGet,
+		"/admin/system/services",
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusUnauthorized, resp.Code)
+}
+
+func TestHandlerServicesSuite(t *testing.T) {
+	suite.Run(t, &HandlerServicesSuite{})
+}
@@ -19,8 +19,13 @@ func Services(r *shttp.Router) *shttp.Service {
 		Handler(shttp.MethodPost, "/runtimes", user.WithAdmin(handlerRuntimesInstall)).
 		Handler(shttp.MethodGet, "/mise", user.WithAdmin(handlerMise)).
 		Handler(shttp.MethodPost, "/mise", user.WithAdmin(handlerMiseUpdate)).
+		Handler(shttp.MethodGet, "/osv", user.WithAdmin(handlerOSV)).
+		Handler(shttp.MethodPost, "/osv", user.WithAdmin(handlerOSVUpdate)).
 		Handler(shttp.MethodGet, "/proxies", user.WithAdmin(handlerProxies)).
-		Handler(shttp.MethodPut, "/proxies", user.WithAdmin(handlerProxiesUpdate))
+		Handler(shttp.MethodPut, "/proxies", user.WithAdmin(handlerProxiesUpdate)).
+		Handler(shttp.MethodGet, "/services", user.WithAdmin(handlerServices)).
+		Handler(shttp.MethodGet, "/build-queue", user.WithAdmin(handlerBuildQueue)).
+		Handler(shttp.MethodPut, "/build-queue", user.WithAdmin(handlerBuildQueueUpdate))
 
 	s.NewEndpoint("/admin/license").
 		Handler(shttp.MethodPost, "", user.WithAdmin(handlerLicenseSet))
@@ -23,9 +23,12 @@ func (s *ServicesSuite) Test_Services_SelfHosted() {
 		"GET:/admin/domains",
 		"GET:/admin/git/details",
 		"GET:/admin/git/github/callback",
+		"GET:/admin/system/build-queue",
 		"GET:/admin/system/mise",
+		"GET:/admin/system/osv",
 		"GET:/admin/system/proxies",
 		"GET:/admin/system/runtimes",
+		"GET:/admin/system/services",
 		"GET:/admin/users/sign-up-mode",
 		"POST:/admin/domains",
 		"POST:/admin/git/configure",
@@ -34,8 +37,10 @@ func (s *ServicesSuite) Test_Services_SelfHosted() {
 		"POST:/admin/jobs/sync-analytics",
 		"POST:/admin/license",
 		"POST:/admin/system/mise",
+		"POST:/admin/system/osv",
 		"POST:/admin/system/runtimes",
 		"POST:/admin/users/sign-up-mode",
+		"PUT:/admin/system/build-queue",
 		"PUT:/admin/system/proxies",
 	}
 
@@ -54,9 +59,12 @@ func (s *ServicesSuite) Test_Services_Cloud() {
 		"GET:/admin/domains",
 		"GET:/admin/git/details",
 		"GET:/admin/git/github/callback",
+		"GET:/admin/system/build-queue",
 		"GET:/admin/system/mise",
+		"GET:/admin/system/osv",
 		"GET:/admin/system/proxies",
 		"GET:/admin/system/runtimes",
+		"GET:/admin/system/services",
 		"GET:/admin/users/sign-up-mode",
 		"POST:/admin/cloud/impersonate",
 		"POST:/admin/cloud/license",
@@ -67,8 +75,10 @@ func (s *ServicesSuite) Test_Services_Cloud() {
 		"POST:/admin/jobs/sync-analytics",
 		"POST:/admin/license",
 		"POST:/admin/system/mise",
+		"POST:/admin/system/osv",
 		"POST:/admin/system/runtimes",
 		"POST:/admin/users/sign-up-mode",
+		"PUT:/admin/system/build-queue",
 		"PUT:/admin/system/proxies",
 	}
 
@@ -0,0 +1,84 @@
+package app
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/bitbucket"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitea"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/github"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitlab"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/framework"
+)
+
+// DetectFramework inspects the root level of the repository on the given branch
+// and returns the proposed build settings. It returns nil when the repository
+// is not accessible or no framework is detected.
+func (a *App) DetectFramework(ctx context.Context, branch string) (*framework.Preset, error) {
+	var files []string
+	var readFile func(string) ([]byte, error)
+	var err error
+
+	if branch == "" {
+		branch = a.DefaultBranch()
+	}
+
+	switch {
+	case strings.HasPrefix(a.Repo, "github/"):
+		files, err = github.ListFiles(ctx, a.Repo, branch)
+		readFile = func(name string) ([]byte, error) {
+			return github.ReadFile(ctx, a.Repo, branch, name)
+		}
+	case strings.HasPrefix(a.Repo, "gitlab/"):
+		client, _ := gitlab.NewClient(a.UserID)
+
+		if client == nil {
+			return nil, nil
+		}
+
+		files, err = client.ListFiles(ctx, a.Repo, branch)
+		readFile = func(name string) ([]byte, error) {
+			return client.ReadFile(ctx, a.Repo, branch, name)
+		}
+	case strings.HasPrefix(a.Repo, "bitbucket/"):
+		client, _ := bitbucket.NewClient(a.UserID)
+
+		if client == nil {
+			return nil, nil
+		}
+
+		bapp := &bitbucket.App{ID: a.ID, Repo: a.Repo}
+		files, err = client.ListFiles(ctx, bapp, branch)
+		readFile = func(name string) ([]byte, error) {
+			return client.ReadFile(ctx, bapp, branch, name)
+		}
+	case a.IsGitea():
+		client, _ := gitea.NewClient(a.UserID)
+
+		if client == nil {
+			return nil, nil
+		}
+
+		files, err = client.ListFiles(ctx, a.Repo, branch)
+		readFile = func(name string) ([]byte, error) {
+			return client.ReadFile(ctx, a.Repo, branch, name)
+		}
+	default:
+		return nil, nil
+	}
+
+	if err != nil || len(files) == 0 {
+		return nil, err
+	}
+
+	args := framework.DetectArgs{Files: files}
+
+	if slices.Contains(files, "package.json") {
+		if args.PackageJson, err = readFile("package.json"); err != nil {
+			return nil, err
+		}
+	}
+
+	return framework.Detect(args), nil
+}
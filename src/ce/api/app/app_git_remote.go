@@ -0,0 +1,323 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/sys"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// GitRemoteProviderName is the provider of the applications that are
+// deployed from a generic git remote. The repository is stored as git/<url>.
+const GitRemoteProviderName = "git"
+
+const (
+	GitRemoteAuthSSH   = "ssh"   // Uses the deploy key of the application
+	GitRemoteAuthHTTPS = "https" // Uses the stored username and password
+	GitRemoteAuthNone  = "none"  // Public repositories
+)
+
+// scpLikeURL matches the scp-like syntax of ssh urls, e.g. git@example.org:org/repo.git
+var scpLikeURL = regexp.MustCompile(`^[\w.-]+@[\w.-]+:[\w./~-]+$`)
+
+// GitRemote represents the credentials and the change detection
+// settings of an application that is deployed from a generic git remote.
+type GitRemote struct {
+	AppID         types.ID
+	AuthType      string
+	Username      string
+	Password      string            // Password is the https password or token, stored encrypted
+	KnownHosts    string            // KnownHosts are the pinned host keys of the ssh server
+	WebhookSecret string            // WebhookSecret is used to sign the inbound webhooks, stored encrypted
+	PollInterval  int               // PollInterval is the number of minutes between polls, 0 disables polling
+	LastRefs      map[string]string // LastRefs maps the refs of the remote to their commit sha
+	LastPolledAt  utils.Unix
+}
+
+// IsGitRemote returns true if the application is deployed from a generic git remote.
+func (a *App) IsGitRemote() bool {
+	return strings.HasPrefix(a.Repo, GitRemoteProviderName+"/")
+}
+
+// GitRemoteURL returns the clone url of a generic git remote.
+func (a *App) GitRemoteURL() string {
+	return strings.TrimPrefix(a.Repo, GitRemoteProviderName+"/")
+}
+
+// IsValidGitRemoteURL checks whether the given address is an ssh or https git url.
+// The url is passed to git as it is, therefore whitespaces and quotes are not allowed.
+func IsValidGitRemoteURL(address string) bool {
+	if scpLikeURL.MatchString(address) {
+		return true
+	}
+
+	if strings.ContainsAny(address, " \t\n'\"`$;") {
+		return false
+	}
+
+	u, err := url.Parse(address)
+
+	if err != nil || u.Host == "" || strings.Trim(u.Path, "/") == "" {
+		return false
+	}
+
+	return u.Scheme == "https" || u.Scheme == "ssh"
+}
+
+// IsSSHGitRemoteURL returns true when the given address is cloned over ssh.
+func IsSSHGitRemoteURL(address string) bool {
+	return strings.HasPrefix(address, "ssh://") || scpLikeURL.MatchString(address)
+}
+
+// GitRemoteHost returns the host and port of the ssh server for the given address.
+func GitRemoteHost(address string) string {
+	if scpLikeURL.MatchString(address) {
+		host := strings.SplitN(strings.SplitN(address, "@", 2)[1], ":", 2)[0]
+		return net.JoinHostPort(host, "22")
+	}
+
+	u, err := url.Parse(address)
+
+	if err != nil {
+		return ""
+	}
+
+	if u.Port() == "" {
+		return net.JoinHostPort(u.Hostname(), "22")
+	}
+
+	return u.Host
+}
+
+// Validate validates the git remote settings.
+func (r *GitRemote) Validate() error {
+	switch r.AuthType {
+	case GitRemoteAuthSSH, GitRemoteAuthNone:
+	case GitRemoteAuthHTTPS:
+		if r.Username == "" || r.Password == "" {
+			return ErrInvalidGitRemoteCreds
+		}
+	default:
+		return ErrInvalidGitRemoteAuth
+	}
+
+	if r.PollInterval < 0 {
+		return ErrInvalidGitRemotePoll
+	}
+
+	if r.KnownHosts != "" && !IsValidKnownHosts(r.KnownHosts) {
+		return ErrInvalidKnownHosts
+	}
+
+	return nil
+}
+
+// IsValidKnownHosts checks whether the given content contains at least
+// one entry and that all the entries are in known_hosts format.
+func IsValidKnownHosts(content string) bool {
+	rest := []byte(content)
+	found := false
+
+	for {
+		_, _, _, _, next, err := ssh.ParseKnownHosts(rest)
+
+		if err == io.EOF {
+			return found
+		}
+
+		if err != nil {
+			return false
+		}
+
+		found = true
+		rest = next
+	}
+}
+
+// VerifySignature verifies the signature of an inbound webhook. The signature is the
+// hex encoded HMAC-SHA256 digest of the payload, optionally prefixed with sha256=.
+func (r *GitRemote) VerifySignature(payload []byte, signature string) bool {
+	if r.WebhookSecret == "" || signature == "" {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(r.WebhookSecret))
+	mac.Write(payload)
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	return hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(signature, "sha256=")))
+}
+
+// Credentials returns the credentials that are passed to the runner. The format is shared with
+// the Bitbucket deploy keys: <type>|<public key or username>|<private key or password>|<known hosts>
+// The username and the password are query escaped as they may contain the separator.
+func (r *GitRemote) Credentials(pkey *utils.PrivateKey) string {
+	var creds string
+
+	switch r.AuthType {
+	case GitRemoteAuthSSH:
+		creds = fmt.Sprintf("%s|%s|%s|%s", "git", pkey.SSHPubKey(), pkey.SSHPrivKey(), r.KnownHosts)
+	case GitRemoteAuthHTTPS:
+		creds = fmt.Sprintf("%s|%s|%s|", "https", url.QueryEscape(r.Username), url.QueryEscape(r.Password))
+	default:
+		return ""
+	}
+
+	return utils.EncodeToString([]byte(creds))
+}
+
+// ScanHostKeys connects to the ssh server of the given address and returns its host
+// keys in known_hosts format. It is used to pin the host keys when the remote is set up.
+var ScanHostKeys = func(address string) (string, error) {
+	host := GitRemoteHost(address)
+	lines := []string{}
+	errCaptured := errors.New("host key captured")
+
+	algorithms := []string{
+		ssh.KeyAlgoED25519,
+		ssh.KeyAlgoECDSA256,
+		ssh.KeyAlgoRSASHA512,
+	}
+
+	var lastErr error
+
+	for _, algo := range algorithms {
+		_, err := ssh.Dial("tcp", host, &ssh.ClientConfig{
+			User:              "git",
+			HostKeyAlgorithms: []string{algo},
+			Timeout:           10 * time.Second,
+			HostKeyCallback: func(_ string, _ net.Addr, key ssh.PublicKey) error {
+				lines = append(lines, knownhosts.Line([]string{knownhosts.Normalize(host)}, key))
+				return errCaptured
+			},
+		})
+
+		if err != nil && !errors.Is(err, errCaptured) {
+			lastErr = err
+		}
+	}
+
+	if len(lines) == 0 {
+		return "", fmt.Errorf("cannot retrieve the host keys of %s: %w", host, lastErr)
+	}
+
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// RemoteRefs lists the branches and the tags of the remote repository
+// together with the commit sha they point to.
+func (r *GitRemote) RemoteRefs(ctx context.Context, address string, pkey *utils.PrivateKey) (map[string]string, error) {
+	out, err := r.lsRemote(ctx, address, pkey, "--heads", "--tags")
+
+	if err != nil {
+		return nil, err
+	}
+
+	refs := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(out))
+
+	for scanner.Scan() {
+		pieces := strings.Fields(scanner.Text())
+
+		if len(pieces) != 2 {
+			continue
+		}
+
+		sha, ref := pieces[0], pieces[1]
+
+		// Annotated tags are listed twice, the peeled entry points to the commit.
+		if peeled := strings.TrimSuffix(ref, "^{}"); peeled != ref {
+			refs[peeled] = sha
+		} else if _, ok := refs[ref]; !ok {
+			refs[ref] = sha
+		}
+	}
+
+	return refs, nil
+}
+
+// DefaultBranch returns the branch that the HEAD of the remote repository points to.
+func (r *GitRemote) DefaultBranch(ctx context.Context, address string, pkey *utils.PrivateKey) (string, error) {
+	out, err := r.lsRemote(ctx, address, pkey, "--symref")
+
+	if err != nil {
+		return "", err
+	}
+
+	// The first line is in the following format: ref: refs/heads/main	HEAD
+	for _, line := range strings.Split(out, "\n") {
+		pieces := strings.Fields(line)
+
+		if len(pieces) == 3 && pieces[0] == "ref:" && pieces[2] == "HEAD" {
+			return strings.TrimPrefix(pieces[1], "refs/heads/"), nil
+		}
+	}
+
+	return "", nil
+}
+
+// lsRemote runs git ls-remote against the remote repository with the stored credentials.
+// Host keys are always verified against the pinned known_hosts entries.
+func (r *GitRemote) lsRemote(ctx context.Context, address string, pkey *utils.PrivateKey, args ...string) (string, error) {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	switch r.AuthType {
+	case GitRemoteAuthSSH:
+		dir, err := os.MkdirTemp("", "git-remote-")
+
+		if err != nil {
+			return "", err
+		}
+
+		defer os.RemoveAll(dir)
+
+		keyFile := path.Join(dir, "id_rsa")
+		knownHostsFile := path.Join(dir, "known_hosts")
+
+		if err := os.WriteFile(keyFile, []byte(pkey.SSHPrivKey()), 0600); err != nil {
+			return "", err
+		}
+
+		if err := os.WriteFile(knownHostsFile, []byte(r.KnownHosts), 0600); err != nil {
+			return "", err
+		}
+
+		env = append(env, fmt.Sprintf("GIT_SSH_COMMAND=ssh -o IdentitiesOnly=yes -o StrictHostKeyChecking=yes -o UserKnownHostsFile=%s -i %s", knownHostsFile, keyFile))
+
+	case GitRemoteAuthHTTPS:
+		u, err := url.Parse(address)
+
+		if err != nil {
+			return "", err
+		}
+
+		u.User = url.UserPassword(r.Username, r.Password)
+		address = u.String()
+	}
+
+	cmd := sys.Command(ctx, sys.CommandOpts{
+		Name: "git",
+		Args: append(append([]string{"ls-remote"}, args...), address),
+		Env:  env,
+	})
+
+	out, err := cmd.Output()
+	return string(out), err
+}
@@ -0,0 +1,113 @@
+package app_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/sys"
+	"github.com/stormkit-io/stormkit-io/src/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const sampleKnownHosts = "git.example.org ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl\n"
+
+type GitRemoteSuite struct {
+	suite.Suite
+}
+
+func (s *GitRemoteSuite) AfterTest(_, _ string) {
+	sys.DefaultCommand = nil
+}
+
+func (s *GitRemoteSuite) Test_IsValidGitRemoteURL() {
+	s.True(app.IsValidGitRemoteURL("git@git.example.org:org/repo.git"))
+	s.True(app.IsValidGitRemoteURL("ssh://git@git.example.org:2222/org/repo.git"))
+	s.True(app.IsValidGitRemoteURL("https://git.example.org/org/repo.git"))
+	s.False(app.IsValidGitRemoteURL("http://git.example.org/org/repo.git"))
+	s.False(app.IsValidGitRemoteURL("https://git.example.org"))
+	s.False(app.IsValidGitRemoteURL("https://git.example.org/org/repo.git; rm -rf /"))
+	s.False(app.IsValidGitRemoteURL("file:///tmp/repo"))
+}
+
+func (s *GitRemoteSuite) Test_GitRemoteHost() {
+	s.Equal("git.example.org:22", app.GitRemoteHost("git@git.example.org:org/repo.git"))
+	s.Equal("git.example.org:2222", app.GitRemoteHost("ssh://git@git.example.org:2222/org/repo.git"))
+	s.Equal("git.example.org:22", app.GitRemoteHost("ssh://git@git.example.org/org/repo.git"))
+}
+
+func (s *GitRemoteSuite) Test_Validate() {
+	s.NoError((&app.GitRemote{AuthType: app.GitRemoteAuthSSH, KnownHosts: sampleKnownHosts}).Validate())
+	s.NoError((&app.GitRemote{AuthType: app.GitRemoteAuthNone}).Validate())
+	s.Equal(app.ErrInvalidGitRemoteAuth, (&app.GitRemote{AuthType: "token"}).Validate())
+	s.Equal(app.ErrInvalidGitRemoteCreds, (&app.GitRemote{AuthType: app.GitRemoteAuthHTTPS, Username: "john"}).Validate())
+	s.Equal(app.ErrInvalidGitRemotePoll, (&app.GitRemote{AuthType: app.GitRemoteAuthNone, PollInterval: -1}).Validate())
+	s.Equal(app.ErrInvalidKnownHosts, (&app.GitRemote{AuthType: app.GitRemoteAuthSSH, KnownHosts: "not a host key"}).Validate())
+}
+
+func (s *GitRemoteSuite) Test_VerifySignature() {
+	remote := &app.GitRemote{WebhookSecret: "my-secret"}
+	payload := []byte(`{"ref":"refs/heads/main"}`)
+
+	mac := hmac.New(sha256.New, []byte("my-secret"))
+	mac.Write(payload)
+	signature := hex.EncodeToString(mac.Sum(nil))
+
+	s.True(remote.VerifySignature(payload, signature))
+	s.True(remote.VerifySignature(payload, "sha256="+signature))
+	s.False(remote.VerifySignature(payload, "invalid"))
+	s.False(remote.VerifySignature([]byte(`{"ref":"refs/heads/dev"}`), signature))
+	s.False((&app.GitRemote{}).VerifySignature(payload, signature))
+}
+
+func (s *GitRemoteSuite) Test_Credentials() {
+	remote := &app.GitRemote{AuthType: app.GitRemoteAuthHTTPS, Username: "john", Password: "p|ss"}
+	decoded, err := utils.DecodeString(remote.Credentials(nil))
+	s.NoError(err)
+	s.Equal("https|john|p%7Css|", string(decoded))
+
+	utils.SetKeySize(2056)
+	pkey := utils.NewPrivateKey()
+	remote = &app.GitRemote{AuthType: app.GitRemoteAuthSSH, KnownHosts: sampleKnownHosts}
+	decoded, err = utils.DecodeString(remote.Credentials(pkey))
+	s.NoError(err)
+	s.Equal("git|"+pkey.SSHPubKey()+"|"+pkey.SSHPrivKey()+"|"+sampleKnownHosts, string(decoded))
+
+	s.Equal("", (&app.GitRemote{AuthType: app.GitRemoteAuthNone}).Credentials(nil))
+}
+
+func (s *GitRemoteSuite) Test_RemoteRefs() {
+	mockCmd := &mocks.CommandInterface{}
+	sys.DefaultCommand = mockCmd
+
+	output := strings.Join([]string{
+		"1111111111111111111111111111111111111111\trefs/heads/main",
+		"2222222222222222222222222222222222222222\trefs/tags/v1.0.0",
+		"3333333333333333333333333333333333333333\trefs/tags/v1.0.0^{}",
+	}, "\n")
+
+	mockCmd.On("SetOpts", mock.MatchedBy(func(opts sys.CommandOpts) bool {
+		return opts.Name == "git" && strings.Join(opts.Args, " ") == "ls-remote --heads --tags https://git.example.org/org/repo.git"
+	})).Return(mockCmd)
+
+	mockCmd.On("Output").Return([]byte(output), nil)
+
+	remote := &app.GitRemote{AuthType: app.GitRemoteAuthNone}
+	refs, err := remote.RemoteRefs(context.Background(), "https://git.example.org/org/repo.git", nil)
+
+	s.NoError(err)
+	s.Equal(map[string]string{
+		"refs/heads/main":  "1111111111111111111111111111111111111111",
+		"refs/tags/v1.0.0": "3333333333333333333333333333333333333333",
+	}, refs)
+}
+
+func TestGitRemoteSuite(t *testing.T) {
+	suite.Run(t, &GitRemoteSuite{})
+}
@@ -10,6 +10,7 @@ import (
 	randomdata "github.com/Pallinder/go-randomdata"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/bitbucket"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitea"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/github"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitlab"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/user"
@@ -40,7 +41,7 @@ type App struct {
 	TeamID types.ID `json:"teamId,omitempty,string"`
 
 	// Repo represents the application repository. It is in the following format:
-	// :provider/:owner/:slug where provider can be either github, bitbucket or gitlab.
+	// :provider/:owner/:slug where provider can be either github, bitbucket, gitlab or gitea.
 	Repo string `json:"repo"`
 
 	// DisplayName is the app's nickname. It will be used as a subdomain stormkit.dev
@@ -177,6 +178,11 @@ func (a *App) IsGithub() bool {
 	return strings.HasPrefix(a.Repo, "github")
 }
 
+// IsGitea returns true if the application repository is hosted on Gitea or Forgejo.
+func (a *App) IsGitea() bool {
+	return strings.HasPrefix(a.Repo, "gitea/")
+}
+
 // DefaultBranch returns the default branch of the repository.
 func (a *App) DefaultBranch() (db string) {
 	if a.defaultBranch != "" {
@@ -201,6 +207,21 @@ func (a *App) DefaultBranch() (db string) {
 		}
 	}
 
+	if a.IsGitea() {
+		if client, _ := gitea.NewClient(a.UserID); client != nil {
+			db, err = client.DefaultBranch(a.Repo)
+		}
+	}
+
+	if a.IsGitRemote() {
+		ctx := context.Background()
+		remote, _ := NewStore().GitRemote(ctx, a.ID)
+
+		if remote != nil {
+			db, err = remote.DefaultBranch(ctx, a.GitRemoteURL(), a.PrivateKey(ctx))
+		}
+	}
+
 	if err != nil {
 		if !strings.Contains(err.Error(), "Repository is not accessible.") {
 			slog.Errorf("default-branch: %v", err)
@@ -282,6 +303,33 @@ func (a *App) GitCreds(ctx context.Context) (string, error) {
 		return client.Token(), nil
 	}
 
+	// Gitea and Forgejo accept the oauth2 token for cloning over https.
+	if a.IsGitea() {
+		client, err := gitea.NewClient(a.UserID)
+
+		if err != nil || client == nil {
+			return "", err
+		}
+
+		// Ensure that webhooks are installed
+		if _, err := client.InstallWebhooks(a.Repo); err != nil {
+			return "", err
+		}
+
+		return client.Token(), nil
+	}
+
+	// Generic git remotes use the deploy key of the application or the stored https credentials.
+	if a.IsGitRemote() {
+		remote, err := NewStore().GitRemote(ctx, a.ID)
+
+		if err != nil || remote == nil {
+			return "", err
+		}
+
+		return remote.Credentials(a.PrivateKey(ctx)), nil
+	}
+
 	return "", ErrRepoInvalidProvider
 }
 
@@ -295,8 +343,10 @@ func (a *App) Validate() *shttperr.ValidationError {
 		isGithub := strings.HasPrefix(a.Repo, "github/")
 		isGitlab := strings.HasPrefix(a.Repo, "gitlab/")
 
-		if !isBitbucket && !isGithub && !isGitlab {
+		if !isBitbucket && !isGithub && !isGitlab && !a.IsGitea() && !a.IsGitRemote() {
 			err.SetError("repo", ErrRepoInvalidProvider.Error())
+		} else if a.IsGitRemote() && !IsValidGitRemoteURL(a.GitRemoteURL()) {
+			err.SetError("repo", ErrInvalidGitRemoteURL.Error())
 		}
 	}
 
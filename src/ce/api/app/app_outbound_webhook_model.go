@@ -14,6 +14,9 @@ const TriggerOnPublish = "on_publish"
 const TriggerOnDeploySuccess = "on_deploy_success"
 const TriggerOnDeployFailed = "on_deploy_failed"
 const TriggerOnCachePurge = "on_cache_purge"
+const TriggerOnApprovalRequested = "on_approval_requested"
+const TriggerOnRollback = "on_rollback"
+const TriggerOnServiceCrashLoop = "on_service_crash_loop"
 
 type OutboundWebhook struct {
 	WebhookID      types.ID          `json:"id,string"`
@@ -58,6 +61,18 @@ func (wh OutboundWebhook) TriggerOnCachePurge() bool {
 	return wh.TriggerWhen == TriggerOnCachePurge
 }
 
+func (wh OutboundWebhook) TriggerOnApprovalRequested() bool {
+	return wh.TriggerWhen == TriggerOnApprovalRequested
+}
+
+func (wh OutboundWebhook) TriggerOnRollback() bool {
+	return wh.TriggerWhen == TriggerOnRollback
+}
+
+func (wh OutboundWebhook) TriggerOnServiceCrashLoop() bool {
+	return wh.TriggerWhen == TriggerOnServiceCrashLoop
+}
+
 // Dispatch an outbound webhook
 func (wh OutboundWebhook) Dispatch(settings OutboundWebhookSettings) DispatchOutput {
 	req := shttp.NewRequestV2(wh.RequestMethod, wh.RequestURL)
@@ -12,6 +12,7 @@ var (
 	tableEnvs             = "apps_build_conf"
 	tableDomains          = "domains"
 	tableOutboundWebhooks = "app_outbound_webhooks"
+	tableGitRemotes       = "app_git_remotes"
 )
 
 type statement struct {
@@ -35,6 +36,10 @@ type statement struct {
 	insertOutboundWebhook  string
 	updateOutboundWebhook  string
 	deleteOutboundWebhook  string
+	selectGitRemote        string
+	upsertGitRemote        string
+	claimDueGitRemotes     string
+	updateGitRemoteRefs    string
 }
 
 var stmt = &statement{
@@ -229,4 +234,49 @@ var stmt = &statement{
 	deleteOutboundWebhook: fmt.Sprintf(`
 		DELETE FROM %s WHERE app_id = $1 AND wh_id = $2;
 	`, tableOutboundWebhooks),
+
+	selectGitRemote: fmt.Sprintf(`
+		SELECT
+			r.app_id, r.auth_type, COALESCE(r.https_username, ''),
+			COALESCE(r.https_password, ''), COALESCE(r.known_hosts, ''),
+			r.webhook_secret, r.poll_interval, r.last_refs, r.last_polled_at
+		FROM %s r
+		WHERE r.app_id = $1;
+	`, tableGitRemotes),
+
+	upsertGitRemote: fmt.Sprintf(`
+		INSERT INTO %s (
+			app_id, auth_type, https_username, https_password,
+			known_hosts, webhook_secret, poll_interval
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (app_id) DO UPDATE SET
+			auth_type = EXCLUDED.auth_type,
+			https_username = EXCLUDED.https_username,
+			https_password = EXCLUDED.https_password,
+			known_hosts = EXCLUDED.known_hosts,
+			webhook_secret = EXCLUDED.webhook_secret,
+			poll_interval = EXCLUDED.poll_interval,
+			updated_at = NOW() AT TIME ZONE 'UTC';
+	`, tableGitRemotes),
+
+	claimDueGitRemotes: fmt.Sprintf(`
+		UPDATE %s r SET
+			last_polled_at = NOW() AT TIME ZONE 'UTC'
+		FROM %s a
+		WHERE
+			a.app_id = r.app_id AND
+			a.deleted_at IS NULL AND
+			r.poll_interval > 0 AND (
+				r.last_polled_at IS NULL OR
+				r.last_polled_at <= NOW() AT TIME ZONE 'UTC' - make_interval(mins => r.poll_interval)
+			)
+		RETURNING
+			r.app_id, r.auth_type, COALESCE(r.https_username, ''),
+			COALESCE(r.https_password, ''), COALESCE(r.known_hosts, ''),
+			r.webhook_secret, r.poll_interval, r.last_refs, r.last_polled_at;
+	`, tableGitRemotes, tableApps),
+
+	updateGitRemoteRefs: fmt.Sprintf(`
+		UPDATE %s SET last_refs = $1 WHERE app_id = $2;
+	`, tableGitRemotes),
 }
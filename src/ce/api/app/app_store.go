@@ -17,6 +17,7 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/lib/database"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
 	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 )
 
 // Store is the store to handle app logic
@@ -607,3 +608,101 @@ func (s *Store) DeleteOutboundWebhook(ctx context.Context, appID, whID types.ID)
 	_, err := s.Exec(ctx, stmt.deleteOutboundWebhook, appID, whID)
 	return err
 }
+
+// GitRemote returns the git remote settings of the given application.
+// It returns nil when the application has no settings.
+func (s *Store) GitRemote(ctx context.Context, appID types.ID) (*GitRemote, error) {
+	row, err := s.QueryRow(ctx, stmt.selectGitRemote, appID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	remote, err := scanGitRemote(row)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return remote, err
+}
+
+// UpsertGitRemote inserts or updates the git remote settings of the application.
+func (s *Store) UpsertGitRemote(ctx context.Context, r *GitRemote) error {
+	_, err := s.Exec(
+		ctx,
+		stmt.upsertGitRemote,
+		r.AppID,
+		r.AuthType,
+		null.NewString(r.Username, r.Username != ""),
+		null.NewString(utils.EncryptToString(r.Password), r.Password != ""),
+		null.NewString(r.KnownHosts, r.KnownHosts != ""),
+		utils.EncryptToString(r.WebhookSecret),
+		r.PollInterval,
+	)
+
+	return err
+}
+
+// ClaimDueGitRemotes marks the git remotes that are due for polling as polled and returns them.
+func (s *Store) ClaimDueGitRemotes(ctx context.Context) ([]*GitRemote, error) {
+	rows, err := s.Query(ctx, stmt.claimDueGitRemotes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	remotes := []*GitRemote{}
+
+	for rows.Next() {
+		remote, err := scanGitRemote(rows)
+
+		if err != nil {
+			return nil, err
+		}
+
+		remotes = append(remotes, remote)
+	}
+
+	return remotes, nil
+}
+
+// UpdateGitRemoteRefs stores the refs that were seen during the last poll.
+func (s *Store) UpdateGitRemoteRefs(ctx context.Context, appID types.ID, refs map[string]string) error {
+	data, err := json.Marshal(refs)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = s.Exec(ctx, stmt.updateGitRemoteRefs, data, appID)
+	return err
+}
+
+func scanGitRemote(row interface{ Scan(...any) error }) (*GitRemote, error) {
+	var refs []byte
+
+	r := &GitRemote{}
+
+	err := row.Scan(
+		&r.AppID, &r.AuthType, &r.Username, &r.Password, &r.KnownHosts,
+		&r.WebhookSecret, &r.PollInterval, &refs, &r.LastPolledAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.Password = utils.DecryptToString(r.Password)
+	r.WebhookSecret = utils.DecryptToString(r.WebhookSecret)
+
+	if refs != nil {
+		if err := json.Unmarshal(refs, &r.LastRefs); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
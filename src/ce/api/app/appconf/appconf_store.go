@@ -27,6 +27,7 @@ import (
 type statement struct {
 	selectResetCacheArgs string
 	selectConfigs        string
+	selectWorkerEnvs     string
 }
 
 var stmt = &statement{
@@ -113,6 +114,22 @@ var stmt = &statement{
 			d.display_name, d.env_name, d.subscription_tier, d.billing_user_id
 		FROM deployment d
 	`,
+
+	selectWorkerEnvs: `
+		SELECT
+			e.env_id, a.display_name, e.env_name
+		FROM apps_build_conf e
+			INNER JOIN apps a ON a.app_id = e.app_id
+		WHERE
+			e.deleted_at IS NULL AND
+			a.deleted_at IS NULL AND
+			e.build_conf->>'workers' IS NOT NULL AND
+			EXISTS (
+				SELECT 1 FROM deployments_published dp
+				WHERE dp.env_id = e.env_id AND dp.percentage_released = 100
+			)
+		ORDER BY e.env_id;
+	`,
 }
 
 // Store is the store to handle appconf logic
@@ -203,6 +220,38 @@ func (s *Store) Configs(ctx context.Context, filters ConfigFilters) ([]*Config,
 	return rowsToConfigs(s.Query(ctx, query, params...))
 }
 
+// WorkerEnv identifies an environment that runs background workers.
+type WorkerEnv struct {
+	EnvID       types.ID
+	DisplayName string
+	EnvName     string
+}
+
+// WorkerEnvs returns the environments that have workers and a published deployment.
+func (s *Store) WorkerEnvs(ctx context.Context) ([]WorkerEnv, error) {
+	rows, err := s.Query(ctx, stmt.selectWorkerEnvs)
+
+	if rows == nil || err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	envs := []WorkerEnv{}
+
+	for rows.Next() {
+		env := WorkerEnv{}
+
+		if err := rows.Scan(&env.EnvID, &env.DisplayName, &env.EnvName); err != nil {
+			return nil, err
+		}
+
+		envs = append(envs, env)
+	}
+
+	return envs, rows.Err()
+}
+
 type Snippets []struct {
 	Content  string                 `json:"content"`
 	Location string                 `json:"location"`
@@ -278,6 +327,11 @@ func rowsToConfigs(rows *sql.Rows, err error) ([]*Config, error) {
 
 			cnf.Redirects = data.Redirects
 			cnf.ServerCmd = data.ServerCmd
+			cnf.HealthCheck = data.HealthCheck.Options()
+			cnf.RestartPolicy = data.RestartPolicy.Options()
+			cnf.Replicas = data.Replicas.Options()
+			cnf.Resources = data.Resources.Options(cnf.AppID)
+			cnf.Workers = data.Workers
 			cnf.ErrorFile = data.ErrorFile
 			cnf.EnvVariables = data.InterpolatedVars(
 				buildconf.InterpolatedVarsOpts{
@@ -0,0 +1,176 @@
+package apphandlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+)
+
+// GitRemoteRequest represents the settings of a generic git remote.
+type GitRemoteRequest struct {
+	// AuthType is one of ssh, https or none.
+	AuthType string `json:"authType"`
+
+	// Username and Password are used for https authentication. When they
+	// are omitted, the previously stored credentials are kept.
+	Username string `json:"username"`
+	Password string `json:"password"`
+
+	// KnownHosts are the pinned host keys of the ssh server. When omitted,
+	// the previously pinned keys are kept or scanned for the first time.
+	KnownHosts string `json:"knownHosts"`
+
+	// RefreshHostKeys scans the host keys of the ssh server again.
+	RefreshHostKeys bool `json:"refreshHostKeys"`
+
+	// PollInterval is the number of minutes between polls. 0 disables polling.
+	PollInterval int `json:"pollInterval"`
+
+	// RotateWebhookSecret generates a new secret for the inbound webhook.
+	RotateWebhookSecret bool `json:"rotateWebhookSecret"`
+}
+
+// handlerAppGitRemoteGet returns the settings of the generic git remote.
+func handlerAppGitRemoteGet(req *app.RequestContext) *shttp.Response {
+	if !req.App.IsGitRemote() {
+		return shttp.BadRequest(map[string]any{
+			"error": "The application is not deployed from a generic git remote.",
+		})
+	}
+
+	remote, err := app.NewStore().GitRemote(req.Context(), req.App.ID)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	if remote == nil {
+		remote = &app.GitRemote{AppID: req.App.ID, AuthType: defaultGitRemoteAuthType(req.App)}
+	}
+
+	return &shttp.Response{
+		Data: gitRemoteResponse(req.Context(), req.App, remote),
+	}
+}
+
+// handlerAppGitRemoteSet updates the settings of the generic git remote.
+func handlerAppGitRemoteSet(req *app.RequestContext) *shttp.Response {
+	if !req.App.IsGitRemote() {
+		return shttp.BadRequest(map[string]any{
+			"error": "The application is not deployed from a generic git remote.",
+		})
+	}
+
+	data := &GitRemoteRequest{}
+
+	if err := req.Post(data); err != nil {
+		return shttp.BadRequest().SetError(err)
+	}
+
+	store := app.NewStore()
+	remote, err := store.GitRemote(req.Context(), req.App.ID)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	if remote == nil {
+		remote = &app.GitRemote{AppID: req.App.ID}
+	}
+
+	remote.AuthType = utils.GetString(data.AuthType, remote.AuthType, defaultGitRemoteAuthType(req.App))
+	remote.PollInterval = data.PollInterval
+
+	if username := strings.TrimSpace(data.Username); username != "" || remote.AuthType != app.GitRemoteAuthHTTPS {
+		remote.Username = username
+	}
+
+	if data.Password != "" || remote.AuthType != app.GitRemoteAuthHTTPS {
+		remote.Password = data.Password
+	}
+
+	if data.KnownHosts != "" {
+		remote.KnownHosts = data.KnownHosts
+	} else if data.RefreshHostKeys {
+		remote.KnownHosts = ""
+	}
+
+	if data.RotateWebhookSecret {
+		remote.WebhookSecret = ""
+	}
+
+	if err := setupGitRemote(req.Context(), req.App, remote); err != nil {
+		return shttp.Error(err)
+	}
+
+	return &shttp.Response{
+		Data: gitRemoteResponse(req.Context(), req.App, remote),
+	}
+}
+
+// setupGitRemote validates and stores the given remote. The host keys of ssh remotes
+// are pinned the first time the remote is set up, and a webhook secret is generated
+// when there is none.
+func setupGitRemote(ctx context.Context, a *app.App, remote *app.GitRemote) error {
+	if err := remote.Validate(); err != nil {
+		return err
+	}
+
+	if err := pinGitRemoteHostKeys(a, remote); err != nil {
+		return err
+	}
+
+	if remote.WebhookSecret == "" {
+		remote.WebhookSecret = utils.RandomToken(32)
+	}
+
+	return app.NewStore().UpsertGitRemote(ctx, remote)
+}
+
+// pinGitRemoteHostKeys captures the host keys of the ssh server unless they are already pinned.
+func pinGitRemoteHostKeys(a *app.App, remote *app.GitRemote) error {
+	address := a.GitRemoteURL()
+
+	if remote.AuthType != app.GitRemoteAuthSSH || remote.KnownHosts != "" || !app.IsSSHGitRemoteURL(address) {
+		return nil
+	}
+
+	knownHosts, err := app.ScanHostKeys(address)
+
+	if err != nil {
+		slog.Errorf("cannot scan host keys of %s: %v", address, err)
+		return app.ErrGitRemoteHostKeys
+	}
+
+	remote.KnownHosts = knownHosts
+	return nil
+}
+
+// defaultGitRemoteAuthType returns ssh for ssh urls and none for https urls.
+func defaultGitRemoteAuthType(a *app.App) string {
+	if app.IsSSHGitRemoteURL(a.GitRemoteURL()) {
+		return app.GitRemoteAuthSSH
+	}
+
+	return app.GitRemoteAuthNone
+}
+
+// gitRemoteResponse returns the settings of the remote. Secrets are not returned
+// except the webhook secret, which is needed to configure the git server.
+func gitRemoteResponse(ctx context.Context, a *app.App, remote *app.GitRemote) map[string]any {
+	return map[string]any{
+		"authType":      remote.AuthType,
+		"username":      remote.Username,
+		"hasPassword":   remote.Password != "",
+		"knownHosts":    remote.KnownHosts,
+		"publicKey":     a.PrivateKey(ctx).SSHPubKey(),
+		"webhookUrl":    admin.MustConfig().WebhooksURL("/app/webhooks/git/" + a.Secret()),
+		"webhookSecret": remote.WebhookSecret,
+		"pollInterval":  remote.PollInterval,
+	}
+}
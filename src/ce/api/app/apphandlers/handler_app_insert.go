@@ -7,6 +7,7 @@ import (
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/bitbucket"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitea"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/github"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitlab"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/user"
@@ -23,7 +24,7 @@ type appInsertPost struct {
 	model.Model
 
 	Repo     string   `json:"repo"`     // :owner/:slug
-	Provider string   `json:"provider"` // github|gitlab|bitbucket
+	Provider string   `json:"provider"` // github|gitlab|bitbucket|gitea|git
 	TeamID   types.ID `json:"teamId,string"`
 }
 
@@ -42,10 +43,17 @@ func (a *appInsertPost) Validate() *shttperr.ValidationError {
 			github.ProviderName,
 			gitlab.ProviderName,
 			bitbucket.ProviderName,
+			gitea.ProviderName,
+			app.GitRemoteProviderName,
 		}
 
 		if !utils.InSliceString(providers, a.Provider) {
-			err.SetError("provider", "The provider can only be github, gitlab or bitbucket.")
+			err.SetError("provider", "The provider can only be github, gitlab, bitbucket, gitea or git.")
+		}
+
+		// Generic git remotes are stored with their clone url.
+		if a.Provider == app.GitRemoteProviderName && !app.IsValidGitRemoteURL(a.Repo) {
+			err.SetError("repo", app.ErrInvalidGitRemoteURL.Error())
 		}
 	}
 
@@ -66,6 +74,18 @@ func handlerAppInsert(req *user.RequestContext) *shttp.Response {
 		myApp.Repo = fmt.Sprintf("%s/%s", data.Provider, data.Repo)
 	}
 
+	var remote *app.GitRemote
+
+	// Pin the host keys before creating the app, so that a remote that
+	// cannot be verified does not leave an app behind.
+	if myApp.IsGitRemote() {
+		remote = &app.GitRemote{AuthType: defaultGitRemoteAuthType(myApp)}
+
+		if err := pinGitRemoteHostKeys(myApp, remote); err != nil {
+			return shttp.Error(err)
+		}
+	}
+
 	myApp.TeamID = data.TeamID
 
 	// Non-enterprise users cannot set TeamID
@@ -97,6 +117,14 @@ func handlerAppInsert(req *user.RequestContext) *shttp.Response {
 		return shttp.Error(err)
 	}
 
+	if remote != nil {
+		remote.AppID = myApp.ID
+
+		if err := setupGitRemote(req.Context(), myApp, remote); err != nil {
+			return shttp.Error(err)
+		}
+	}
+
 	if req.License().Enterprise {
 		diff := &audit.Diff{
 			New: audit.DiffFields{
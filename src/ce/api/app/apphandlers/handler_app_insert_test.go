@@ -171,7 +171,7 @@ func (s *HandleAppInsertSuite) Test_InvalidRepoProvider() {
 		},
 	)
 
-	expected := `{"errors":{"provider":"The provider can only be github, gitlab or bitbucket."}}`
+	expected := `{"errors":{"provider":"The provider can only be github, gitlab, bitbucket, gitea or git."}}`
 	s.Equal(http.StatusBadRequest, response.Code)
 	s.Equal(expected, response.String())
 
@@ -2,6 +2,7 @@ package apphandlers
 
 import (
 	"strconv"
+	"time"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
@@ -18,6 +19,11 @@ type hookParams struct {
 }
 
 // handlerAppHooksDeploy deploys an application.
+//
+// Deploy hooks are authenticated by the trigger hash rather than a user, therefore
+// freeze windows cannot be overridden through them: publishing during a freeze
+// window is rejected with 423. Hooks can still deploy with publish=false, and team
+// owners can publish the deployment by overriding the freeze from the application.
 func handlerAppHooksDeploy(req *app.RequestContext) *shttp.Response {
 	var err error
 	var settings *app.Settings
@@ -85,6 +91,18 @@ func handlerAppHooksDeploy(req *app.RequestContext) *shttp.Response {
 		depl.ShouldPublish = env.AutoPublish
 	}
 
+	if depl.ShouldPublish {
+		if window := env.Data.ActiveFreezeWindow(time.Now()); window != nil {
+			return &shttp.Response{
+				Status: deploy.ErrFreezeWindowActive.Status(),
+				Data: map[string]any{
+					"error":        deploy.ErrFreezeWindowActive.Error(),
+					"freezeWindow": window,
+				},
+			}
+		}
+	}
+
 	if params.Branch.Valid {
 		depl.Branch = params.Branch.ValueOrZero()
 	} else {
@@ -4,19 +4,21 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/autodeploy"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
 	"gopkg.in/go-playground/webhooks.v5/bitbucket"
 )
 
 // processBitbucketPayload processes a bitbucket payload and starts a new deployment.
-func processBitbucketPayload(req *shttp.RequestContext) (*TriggerDeployInput, error) {
+func processBitbucketPayload(req *shttp.RequestContext) (*autodeploy.TriggerDeployInput, error) {
 	hook, _ := bitbucket.New()
 	payload, err := hook.Parse(
 		req.Request,
 		bitbucket.RepoPushEvent,
 		bitbucket.PullRequestCreatedEvent,
 		bitbucket.PullRequestMergedEvent,
+		bitbucket.PullRequestDeclinedEvent,
 	)
 
 	if err != nil {
@@ -26,8 +28,8 @@ func processBitbucketPayload(req *shttp.RequestContext) (*TriggerDeployInput, er
 		return nil, err
 	}
 
-	input := TriggerDeployInput{
-		payload: payload,
+	input := autodeploy.TriggerDeployInput{
+		Payload: payload,
 	}
 
 	switch event := payload.(type) {
@@ -39,8 +41,13 @@ func processBitbucketPayload(req *shttp.RequestContext) (*TriggerDeployInput, er
 		input.CheckoutRepo = fmt.Sprintf("bitbucket/%s", event.Repository.FullName)
 		input.Message = event.Push.Changes[0].New.Target.Message
 		input.EventType = event.Push.Changes[0].New.Target.Type // This value is either commit or something else. We don't care about the 'something else' case.
+		input.CommitSha = event.Push.Changes[0].New.Target.Hash
 		input.IsFork = false
 
+		if event.Push.Changes[0].New.Type == "tag" {
+			input.Tag = input.Branch
+		}
+
 	// Pull request create event
 	// Build the source branch in this case.
 	case bitbucket.PullRequestCreatedPayload:
@@ -48,7 +55,7 @@ func processBitbucketPayload(req *shttp.RequestContext) (*TriggerDeployInput, er
 		input.Repo = fmt.Sprintf("bitbucket/%s", event.Repository.FullName)
 		input.CheckoutRepo = fmt.Sprintf("bitbucket/%s", event.PullRequest.Source.Repository.FullName)
 		input.Message = event.PullRequest.Title
-		input.EventType = typePullRequest
+		input.EventType = autodeploy.TypePullRequest
 		input.PullRequestNumber = event.PullRequest.ID
 		input.IsFork = !strings.EqualFold(input.CheckoutRepo, input.Repo)
 
@@ -58,9 +65,16 @@ func processBitbucketPayload(req *shttp.RequestContext) (*TriggerDeployInput, er
 		input.Repo = fmt.Sprintf("bitbucket/%s", event.Repository.FullName)
 		input.CheckoutRepo = input.Repo
 		input.Message = event.PullRequest.Title
-		input.EventType = typePullRequest
+		input.EventType = autodeploy.TypePullRequest
+		input.ClosedPullRequestNumber = event.PullRequest.ID
 		input.IsFork = false
 
+	// Pull request declined event
+	case bitbucket.PullRequestDeclinedPayload:
+		input.Repo = fmt.Sprintf("bitbucket/%s", event.Repository.FullName)
+		input.EventType = autodeploy.TypePullRequestClosed
+		input.ClosedPullRequestNumber = event.PullRequest.ID
+
 	default:
 		return nil, nil
 	}
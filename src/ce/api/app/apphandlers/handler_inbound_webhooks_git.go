@@ -0,0 +1,81 @@
+package apphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/autodeploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+)
+
+var errGitRemoteInvalidSignature = errors.New("invalid git webhook signature")
+
+// GitRemotePushPayload is the payload of the generic inbound webhook. It is signed
+// with the webhook secret of the app and sent in the X-Stormkit-Signature header.
+type GitRemotePushPayload struct {
+	Ref     string `json:"ref"`     // refs/heads/<branch> or refs/tags/<tag>
+	After   string `json:"after"`   // The commit sha that the ref points to
+	Message string `json:"message"` // The commit message, optional
+}
+
+// handlerInboundWebhooksGit handles the push events of generic git remotes.
+// Unlike the other providers, the webhook url is unique per application.
+func handlerInboundWebhooksGit(req *shttp.RequestContext) *shttp.Response {
+	appID, err := utils.DecryptID(req.Vars()["secret-id"])
+
+	if err != nil || appID == 0 {
+		return shttp.NotFound()
+	}
+
+	store := app.NewStore()
+	a, err := store.AppByID(req.Context(), appID)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	if a == nil || !a.IsGitRemote() {
+		return shttp.NotFound()
+	}
+
+	remote, err := store.GitRemote(req.Context(), appID)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	body, err := io.ReadAll(req.Body)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	if remote == nil || !remote.VerifySignature(body, req.Header.Get("X-Stormkit-Signature")) {
+		slog.Infof("git webhook failed for app id=%d: invalid signature", appID)
+		return shttp.Forbidden().SetError(errGitRemoteInvalidSignature)
+	}
+
+	payload := GitRemotePushPayload{}
+
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return shttp.BadRequest().SetError(err)
+	}
+
+	input := autodeploy.NewGitRemoteTriggerInput(a, payload.Ref, payload.After, payload.Message)
+
+	if input == nil {
+		return shttp.NoContent()
+	}
+
+	input.Payload = payload
+
+	if response := autodeploy.TriggerDeploy(req.Context(), *input); response != nil {
+		return response
+	}
+
+	return shttp.NoContent()
+}
@@ -0,0 +1,137 @@
+package apphandlers_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/apphandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stormkit-io/stormkit-io/src/mocks"
+)
+
+const gitRemotePushExample = `{
+	"ref": "refs/heads/main",
+	"after": "7d1ab1c5c6e0b4e1f2a3d4c5b6a7980112233445",
+	"message": "Update readme\n\nMore details"
+}`
+
+type InboundGitRemoteSuite struct {
+	suite.Suite
+	*factory.Factory
+
+	conn         databasetest.TestDB
+	mockDeployer *mocks.Deployer
+}
+
+func (s *InboundGitRemoteSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+	s.mockDeployer = &mocks.Deployer{}
+	s.mockDeployer.On("Deploy", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	deployservice.MockDeployer = s.mockDeployer
+}
+
+func (s *InboundGitRemoteSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+	deployservice.MockDeployer = nil
+}
+
+func (s *InboundGitRemoteSuite) request(appl *factory.MockApp, body, secret string) shttptest.Response {
+	payload := map[string]any{}
+	s.NoError(json.Unmarshal([]byte(body), &payload))
+
+	// The request body is the marshaled payload, so sign the same bytes.
+	data, err := json.Marshal(payload)
+	s.NoError(err)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+
+	return shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(apphandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/webhooks/git/"+appl.Secret(),
+		payload,
+		map[string]string{
+			"X-Stormkit-Signature": "sha256=" + hex.EncodeToString(mac.Sum(nil)),
+		},
+	)
+}
+
+func (s *InboundGitRemoteSuite) app() *factory.MockApp {
+	appl := s.MockApp(nil, map[string]any{
+		"Repo": "git/git@git.example.org:stormkit-test-acc/test-repo.git",
+	})
+
+	s.MockEnv(appl, map[string]any{
+		"AutoDeploy": true,
+	})
+
+	s.NoError(app.NewStore().UpsertGitRemote(context.Background(), &app.GitRemote{
+		AppID:         appl.ID,
+		AuthType:      app.GitRemoteAuthSSH,
+		KnownHosts:    "git.example.org ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIOMqqnkVzrm0SdG6UOoqKLsabgH5C9okWi0dh2l9GKJl\n",
+		WebhookSecret: "webhook-secret",
+	}))
+
+	return appl
+}
+
+func (s *InboundGitRemoteSuite) Test_InvalidSignature() {
+	appl := s.app()
+
+	response := s.request(appl, gitRemotePushExample, "wrong-secret")
+	s.Equal(http.StatusForbidden, response.Code)
+	s.mockDeployer.AssertNotCalled(s.T(), "Deploy")
+}
+
+func (s *InboundGitRemoteSuite) Test_PushEvent() {
+	appl := s.app()
+
+	// Another app that is deployed from the same remote should not be deployed.
+	other := s.MockApp(nil, map[string]any{"Repo": appl.Repo})
+	s.MockEnv(other, map[string]any{"AutoDeploy": true})
+
+	response := s.request(appl, gitRemotePushExample, "webhook-secret")
+	s.Equal(http.StatusOK, response.Code)
+
+	s.mockDeployer.AssertNumberOfCalls(s.T(), "Deploy", 1)
+	s.mockDeployer.AssertCalled(s.T(), "Deploy",
+		mock.Anything, mock.MatchedBy(func(_appl *app.App) bool {
+			return s.Equal(appl.ID, _appl.ID)
+		}),
+		mock.MatchedBy(func(_depl *deploy.Deployment) bool {
+			return s.Equal(appl.Repo, _depl.CheckoutRepo) &&
+				s.Equal("main", _depl.Branch) &&
+				s.Equal("7d1ab1c5c6e0b4e1f2a3d4c5b6a7980112233445", _depl.Commit.ID.ValueOrZero()) &&
+				s.True(_depl.IsAutoDeploy)
+		}),
+	)
+}
+
+func (s *InboundGitRemoteSuite) Test_DeletedRef() {
+	appl := s.app()
+
+	body := `{ "ref": "refs/heads/main", "after": "0000000000000000000000000000000000000000" }`
+	response := s.request(appl, body, "webhook-secret")
+	s.Equal(http.StatusNoContent, response.Code)
+	s.mockDeployer.AssertNotCalled(s.T(), "Deploy")
+}
+
+func TestInboundGitRemote(t *testing.T) {
+	suite.Run(t, &InboundGitRemoteSuite{})
+}
@@ -0,0 +1,146 @@
+package apphandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/autodeploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitea"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+)
+
+var errGiteaInvalidSignature = errors.New("invalid gitea webhook signature")
+
+// GiteaRepository represents the repository object in gitea webhook payloads.
+type GiteaRepository struct {
+	FullName      string `json:"full_name"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// GiteaCommit represents a commit in gitea push payloads.
+type GiteaCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message"`
+}
+
+// GiteaPushPayload is the payload sent by Gitea and Forgejo on push events.
+type GiteaPushPayload struct {
+	Ref        string          `json:"ref"`
+	After      string          `json:"after"`
+	HeadCommit *GiteaCommit    `json:"head_commit"`
+	Repository GiteaRepository `json:"repository"`
+}
+
+// GiteaPullRequestPayload is the payload sent by Gitea and Forgejo on pull request events.
+type GiteaPullRequestPayload struct {
+	Action      string `json:"action"`
+	Number      int64  `json:"number"`
+	PullRequest struct {
+		Title string `json:"title"`
+		Head  struct {
+			Ref  string          `json:"ref"`
+			Sha  string          `json:"sha"`
+			Repo GiteaRepository `json:"repo"`
+		} `json:"head"`
+	} `json:"pull_request"`
+	Repository GiteaRepository `json:"repository"`
+}
+
+// processGiteaPayload processes a gitea (or forgejo) payload and starts a new deployment.
+func processGiteaPayload(req *shttp.RequestContext) (*autodeploy.TriggerDeployInput, error) {
+	body, err := io.ReadAll(req.Body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Forgejo sends both the X-Forgejo-* and the X-Gitea-* headers.
+	eventType := utils.GetString(req.Header.Get("X-Gitea-Event"), req.Header.Get("X-Forgejo-Event"))
+	signature := utils.GetString(req.Header.Get("X-Gitea-Signature"), req.Header.Get("X-Forgejo-Signature"))
+
+	if !gitea.VerifySignature(body, signature) {
+		slog.Infof("Gitea request failed for event type %s: invalid signature", eventType)
+		return nil, errGiteaInvalidSignature
+	}
+
+	switch eventType {
+
+	// Commit event
+	case "push":
+		event := GiteaPushPayload{}
+
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, err
+		}
+
+		// Deleted the branch or the tag.
+		if event.HeadCommit == nil {
+			return nil, nil
+		}
+
+		repo := fmt.Sprintf("gitea/%s", event.Repository.FullName)
+		input := &autodeploy.TriggerDeployInput{
+			Payload:      event,
+			Repo:         repo,
+			CheckoutRepo: repo,
+			Message:      strings.Split(event.HeadCommit.Message, "\n")[0],
+			CommitSha:    event.HeadCommit.ID,
+			EventType:    autodeploy.TypeCommit,
+		}
+
+		switch {
+		case strings.HasPrefix(event.Ref, "refs/heads/"):
+			input.Branch = strings.TrimPrefix(event.Ref, "refs/heads/")
+		case strings.HasPrefix(event.Ref, "refs/tags/"):
+			input.Tag = strings.TrimPrefix(event.Ref, "refs/tags/")
+			input.Branch = input.Tag
+		default:
+			return nil, nil
+		}
+
+		return input, nil
+
+	// Pull request event
+	// Build the source branch in this case.
+	case "pull_request":
+		event := GiteaPullRequestPayload{}
+
+		if err := json.Unmarshal(body, &event); err != nil {
+			return nil, err
+		}
+
+		if event.Action == "closed" {
+			return &autodeploy.TriggerDeployInput{
+				Payload:                 event,
+				Repo:                    fmt.Sprintf("gitea/%s", event.Repository.FullName),
+				EventType:               autodeploy.TypePullRequestClosed,
+				ClosedPullRequestNumber: event.Number,
+			}, nil
+		}
+
+		if event.Action != "opened" && event.Action != "reopened" && event.Action != "synchronized" {
+			return nil, nil
+		}
+
+		input := &autodeploy.TriggerDeployInput{
+			Payload:           event,
+			Repo:              fmt.Sprintf("gitea/%s", event.Repository.FullName),
+			CheckoutRepo:      fmt.Sprintf("gitea/%s", event.PullRequest.Head.Repo.FullName),
+			Branch:            event.PullRequest.Head.Ref,
+			Message:           event.PullRequest.Title,
+			CommitSha:         event.PullRequest.Head.Sha,
+			PullRequestNumber: event.Number,
+			EventType:         autodeploy.TypePullRequest,
+		}
+
+		input.IsFork = !strings.EqualFold(input.CheckoutRepo, input.Repo)
+		return input, nil
+	}
+
+	return nil, nil
+}
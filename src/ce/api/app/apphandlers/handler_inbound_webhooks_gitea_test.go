@@ -0,0 +1,171 @@
+package apphandlers_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/apphandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stormkit-io/stormkit-io/src/mocks"
+)
+
+const giteaPushExample = `{
+	"ref": "refs/heads/main",
+	"after": "7d1ab1c5c6e0b4e1f2a3d4c5b6a7980112233445",
+	"head_commit": {
+		"id": "7d1ab1c5c6e0b4e1f2a3d4c5b6a7980112233445",
+		"message": "Update readme\n\nMore details"
+	},
+	"repository": {
+		"full_name": "stormkit-test-acc/test-repo",
+		"default_branch": "main"
+	}
+}`
+
+const giteaPullRequestExample = `{
+	"action": "opened",
+	"number": 12,
+	"pull_request": {
+		"title": "Add landing page",
+		"head": {
+			"ref": "feature/landing",
+			"sha": "ab12cd34",
+			"repo": { "full_name": "stormkit-test-acc/test-repo" }
+		}
+	},
+	"repository": {
+		"full_name": "stormkit-test-acc/test-repo",
+		"default_branch": "main"
+	}
+}`
+
+type InboundGiteaSuite struct {
+	suite.Suite
+	*factory.Factory
+
+	conn         databasetest.TestDB
+	mockDeployer *mocks.Deployer
+}
+
+func (s *InboundGiteaSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+	s.mockDeployer = &mocks.Deployer{}
+	s.mockDeployer.On("Deploy", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	deployservice.MockDeployer = s.mockDeployer
+
+	cnf := admin.MustConfig()
+	cnf.AuthConfig = &admin.AuthConfig{
+		Gitea: admin.GiteaConfig{
+			BaseURL:       "https://git.example.org",
+			ClientID:      "client-id",
+			ClientSecret:  "client-secret",
+			WebhookSecret: "webhook-secret",
+		},
+	}
+
+	s.NoError(admin.Store().UpsertConfig(context.Background(), cnf))
+}
+
+func (s *InboundGiteaSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+	deployservice.MockDeployer = nil
+	admin.ResetCache(context.Background())
+}
+
+func (s *InboundGiteaSuite) request(event, body, secret string) shttptest.Response {
+	payload := map[string]any{}
+	s.NoError(json.Unmarshal([]byte(body), &payload))
+
+	// The request body is the marshaled payload, so sign the same bytes.
+	data, err := json.Marshal(payload)
+	s.NoError(err)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(data)
+
+	return shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(apphandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/webhooks/gitea",
+		payload,
+		map[string]string{
+			"X-Gitea-Event":     event,
+			"X-Gitea-Signature": hex.EncodeToString(mac.Sum(nil)),
+		},
+	)
+}
+
+func (s *InboundGiteaSuite) app() *factory.MockApp {
+	appl := s.MockApp(nil, map[string]any{
+		"Repo": "gitea/stormkit-test-acc/test-repo",
+	})
+
+	s.MockEnv(appl, map[string]any{
+		"AutoDeploy": true,
+	})
+
+	return appl
+}
+
+func (s *InboundGiteaSuite) Test_InvalidSignature() {
+	s.app()
+
+	response := s.request("push", giteaPushExample, "wrong-secret")
+	s.Equal(http.StatusForbidden, response.Code)
+	s.mockDeployer.AssertNotCalled(s.T(), "Deploy")
+}
+
+func (s *InboundGiteaSuite) Test_PushEvent() {
+	appl := s.app()
+
+	response := s.request("push", giteaPushExample, "webhook-secret")
+	s.Equal(http.StatusOK, response.Code)
+
+	s.mockDeployer.AssertCalled(s.T(), "Deploy",
+		mock.Anything, mock.MatchedBy(func(_appl *app.App) bool {
+			return s.Equal(appl.ID, _appl.ID)
+		}),
+		mock.MatchedBy(func(_depl *deploy.Deployment) bool {
+			return s.Equal("gitea/stormkit-test-acc/test-repo", _depl.CheckoutRepo) &&
+				s.Equal("main", _depl.Branch) &&
+				s.Equal("7d1ab1c5c6e0b4e1f2a3d4c5b6a7980112233445", _depl.Commit.ID.ValueOrZero()) &&
+				s.True(_depl.IsAutoDeploy)
+		}),
+	)
+}
+
+func (s *InboundGiteaSuite) Test_PullRequestOpened() {
+	s.app()
+
+	response := s.request("pull_request", giteaPullRequestExample, "webhook-secret")
+	s.Equal(http.StatusOK, response.Code)
+
+	s.mockDeployer.AssertCalled(s.T(), "Deploy",
+		mock.Anything, mock.Anything,
+		mock.MatchedBy(func(_depl *deploy.Deployment) bool {
+			return s.Equal("feature/landing", _depl.Branch) &&
+				s.Equal(int64(12), _depl.PullRequestNumber.ValueOrZero()) &&
+				s.False(_depl.IsFork)
+		}),
+	)
+}
+
+func TestInboundGitea(t *testing.T) {
+	suite.Run(t, &InboundGiteaSuite{})
+}
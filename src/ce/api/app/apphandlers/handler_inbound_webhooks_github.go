@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/autodeploy"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
@@ -18,7 +19,7 @@ var whiteList = []string{
 }
 
 // processGithubPayload processes a github payload and starts a new deployment.
-func processGithubPayload(req *shttp.RequestContext) (*TriggerDeployInput, error) {
+func processGithubPayload(req *shttp.RequestContext) (*autodeploy.TriggerDeployInput, error) {
 	hook, _ := github.New()
 	eventType := req.Header.Get("X-GitHub-Event")
 
@@ -42,22 +43,33 @@ func processGithubPayload(req *shttp.RequestContext) (*TriggerDeployInput, error
 		return nil, nil
 	}
 
-	input := TriggerDeployInput{
-		payload: payload,
+	input := autodeploy.TriggerDeployInput{
+		Payload: payload,
 	}
 
 	switch event := payload.(type) {
 
 	// Commit event
 	case github.PushPayload:
+		// Deleted a branch or a tag.
+		if event.Deleted {
+			return nil, nil
+		}
+
 		input.Branch = strings.Replace(event.Ref, "refs/heads/", "", 1)
 		input.Message = event.HeadCommit.Message
+		input.CommitSha = event.HeadCommit.ID
 		input.Repo = fmt.Sprintf("github/%s", event.Repository.FullName)
 		input.CheckoutRepo = fmt.Sprintf("github/%s", event.Repository.FullName)
-		input.EventType = typeCommit
+		input.EventType = autodeploy.TypeCommit
 		input.IsFork = false
 
-		// Pushed something else: for instance a tag.
+		if strings.HasPrefix(event.Ref, "refs/tags/") {
+			input.Tag = strings.TrimPrefix(event.Ref, "refs/tags/")
+			input.Branch = input.Tag
+		}
+
+		// Pushed something else without a head commit.
 		if input.Message == "" {
 			return nil, nil
 		}
@@ -65,13 +77,19 @@ func processGithubPayload(req *shttp.RequestContext) (*TriggerDeployInput, error
 	// Pull request event
 	case github.PullRequestPayload:
 		input.Message = event.PullRequest.Title
-		input.EventType = typePullRequest
+		input.EventType = autodeploy.TypePullRequest
 		input.Repo = fmt.Sprintf("github/%s", event.Repository.FullName)
 		input.CheckoutRepo = fmt.Sprintf("github/%s", event.PullRequest.Head.Repo.FullName)
 		input.IsFork = event.PullRequest.Head.Repo.FullName != event.PullRequest.Base.Repo.FullName
 		input.PullRequestNumber = event.PullRequest.Number
 		input.Branch = event.PullRequest.Head.Ref
 
+		if event.Action == "closed" {
+			input.EventType = autodeploy.TypePullRequestClosed
+			input.ClosedPullRequestNumber = event.PullRequest.Number
+			return &input, nil
+		}
+
 		if event.Action != "opened" && event.Action != "synchronize" {
 			return nil, nil
 		}
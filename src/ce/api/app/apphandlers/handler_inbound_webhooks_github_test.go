@@ -1,6 +1,7 @@
 package apphandlers_test
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha1"
 	"encoding/hex"
@@ -10,15 +11,18 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/apphandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
 	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
 	"github.com/stormkit-io/stormkit-io/src/lib/factory"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
 	"github.com/stormkit-io/stormkit-io/src/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -169,6 +173,44 @@ func (s *InboundGithubSuite) Test_ShouldNotDeployTags() {
 	s.mockDeployer.AssertNotCalled(s.T(), "Deploy")
 }
 
+func (s *InboundGithubSuite) Test_PushEvent_TagMatchesPattern() {
+	appl := s.app(map[string]any{
+		"AutoPublish": true,
+		"Data": &buildconf.BuildConf{
+			AutoDeployTags: `^v\d+`,
+		},
+	})
+
+	payload := map[string]any{}
+	s.NoError(json.Unmarshal([]byte(strings.Replace(githubPushExample, "refs/heads/main", "refs/tags/v1.2.0", 1)), &payload))
+	payload["head_commit"].(map[string]any)["id"] = "790dcef2a8c61ff6011a4b595cdcb2f0de6c4e2b"
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(apphandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/webhooks/github",
+		payload,
+		map[string]string{
+			"X-Github-Event":  "push",
+			"X-Hub-Signature": fmt.Sprintf("sha1=%s", hex.EncodeToString(githubMac(payload).Sum(nil))),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+
+	s.mockDeployer.AssertCalled(s.T(), "Deploy",
+		mock.Anything, mock.MatchedBy(func(_appl *app.App) bool {
+			return s.Equal(appl.ID, _appl.ID)
+		}),
+		mock.MatchedBy(func(_depl *deploy.Deployment) bool {
+			return s.Equal("v1.2.0", _depl.Branch) &&
+				s.Equal("v1.2.0", _depl.Tag.ValueOrZero()) &&
+				s.Equal("790dcef2a8c61ff6011a4b595cdcb2f0de6c4e2b", _depl.Commit.ID.ValueOrZero()) &&
+				s.True(_depl.ShouldPublish)
+		}),
+	)
+}
+
 func (s *InboundGithubSuite) Test_PushEventSuccess_BranchNameMatches() {
 	a := assert.New(s.T())
 	appl := s.app(nil)
@@ -202,6 +244,42 @@ func (s *InboundGithubSuite) Test_PushEventSuccess_BranchNameMatches() {
 	)
 }
 
+func (s *InboundGithubSuite) Test_PushEvent_FreezeWindow() {
+	now := time.Now().Unix()
+	appl := s.app(map[string]any{
+		"AutoPublish": true,
+		"Data": &buildconf.BuildConf{
+			FreezeWindows: []buildconf.FreezeWindow{{From: now - 3600, To: now + 3600}},
+		},
+	})
+
+	payload := map[string]any{}
+	s.NoError(json.Unmarshal([]byte(githubPushExample), &payload))
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(apphandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		fmt.Sprintf("/app/webhooks/github/%s", appl.Secret()),
+		payload,
+		map[string]string{
+			"X-Github-Event":  "push",
+			"X-Hub-Signature": fmt.Sprintf("sha1=%s", hex.EncodeToString(githubMac(payload).Sum(nil))),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+
+	// The deployment is built, but it is not published during the freeze
+	s.mockDeployer.AssertCalled(s.T(), "Deploy",
+		mock.Anything, mock.MatchedBy(func(_appl *app.App) bool {
+			return s.Equal(appl.ID, _appl.ID)
+		}),
+		mock.MatchedBy(func(_depl *deploy.Deployment) bool {
+			return s.Equal("main", _depl.Branch) && s.False(_depl.ShouldPublish)
+		}),
+	)
+}
+
 func (s *InboundGithubSuite) Test_PushEvent_BranchNameDoesNotMatch() {
 	appl := s.app(map[string]any{
 		"AutoDeployBranches": null.StringFrom("should-not-exist"),
@@ -319,6 +397,112 @@ func (s *InboundGithubSuite) Test_PullRequestMerged() {
 	s.mockDeployer.AssertNotCalled(s.T(), "Deploy")
 }
 
+func (s *InboundGithubSuite) pullRequestTemplate() *factory.MockApp {
+	return s.app(map[string]any{
+		"Data": &buildconf.BuildConf{
+			BuildCmd: "npm run build",
+			Vars: map[string]string{
+				"DATABASE_URL": "postgres://production",
+			},
+			PullRequestEnvs: &buildconf.PullRequestEnvs{
+				Enabled: true,
+				Vars:    map[string]string{"DATABASE_URL": "postgres://preview"},
+			},
+		},
+	})
+}
+
+func (s *InboundGithubSuite) Test_PullRequestOpened_EphemeralEnv() {
+	appl := s.pullRequestTemplate()
+
+	repo := strings.Replace(appl.Repo, "github/", "", 1)
+	payload := map[string]any{}
+	params := githubMergeParams{status: "opened", merged: false, baseRepo: repo, headRepo: repo}
+	s.NoError(json.Unmarshal([]byte(githubMergeExample(params)), &payload))
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(apphandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		fmt.Sprintf("/app/webhooks/github/%s", appl.Secret()),
+		payload,
+		map[string]string{
+			"X-Github-Event":  "pull_request",
+			"X-Hub-Signature": fmt.Sprintf("sha1=%s", hex.EncodeToString(githubMac(payload).Sum(nil))),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+
+	env, err := buildconf.NewStore().Environment(context.Background(), appl.ID, "pr-53")
+	s.NoError(err)
+	s.NotNil(env)
+	s.True(env.IsPullRequestEnv(53))
+	s.Equal("my-pr-branch", env.Branch)
+	s.Equal("postgres://preview", env.Data.Vars["DATABASE_URL"])
+
+	s.mockDeployer.AssertNumberOfCalls(s.T(), "Deploy", 1)
+	s.mockDeployer.AssertCalled(s.T(), "Deploy",
+		mock.Anything, mock.Anything,
+		mock.MatchedBy(func(_depl *deploy.Deployment) bool {
+			return s.Equal(env.ID, _depl.EnvID) &&
+				s.Equal("pr-53", _depl.Env) &&
+				s.True(_depl.ShouldPublish) &&
+				s.Equal("postgres://preview", _depl.BuildConfig.Vars["DATABASE_URL"])
+		}),
+	)
+}
+
+func (s *InboundGithubSuite) Test_PullRequestClosed_TeardownEnv() {
+	appl := s.pullRequestTemplate()
+	template := s.GetEnv()
+
+	env, err := buildconf.NewPullRequestEnv(template.Env, 53, "my-pr-branch")
+	s.NoError(err)
+	s.NoError(buildconf.NewStore().Insert(context.Background(), env))
+
+	depl := s.MockDeployment(&factory.MockEnv{Env: env}, map[string]any{
+		"PublishedV2": deploy.PublishedInfoV2{
+			{EnvID: env.ID, Percentage: 100},
+		},
+	})
+
+	repo := strings.Replace(appl.Repo, "github/", "", 1)
+	payload := map[string]any{}
+	params := githubMergeParams{status: "closed", merged: true, baseRepo: repo, headRepo: repo}
+	s.NoError(json.Unmarshal([]byte(githubMergeExample(params)), &payload))
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(apphandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		fmt.Sprintf("/app/webhooks/github/%s", appl.Secret()),
+		payload,
+		map[string]string{
+			"X-Github-Event":  "pull_request",
+			"X-Hub-Signature": fmt.Sprintf("sha1=%s", hex.EncodeToString(githubMac(payload).Sum(nil))),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+	s.mockDeployer.AssertNotCalled(s.T(), "Deploy")
+
+	removed, err := buildconf.NewStore().Environment(context.Background(), appl.ID, "pr-53")
+	s.NoError(err)
+	s.Nil(removed)
+
+	// The deployments are unpublished and deleted, so that their artifacts are cleaned up.
+	published, err := deploy.NewStore().PublishedDeployments(context.Background(), []types.ID{env.ID})
+	s.NoError(err)
+	s.Empty(published)
+
+	deleted, err := deploy.NewStore().DeploymentByID(context.Background(), depl.ID)
+	s.NoError(err)
+	s.Nil(deleted)
+
+	template2, err := buildconf.NewStore().EnvironmentByID(context.Background(), template.ID)
+	s.NoError(err)
+	s.NotNil(template2)
+}
+
 func TestInboundGithub(t *testing.T) {
 	suite.Run(t, &InboundGithubSuite{})
 }
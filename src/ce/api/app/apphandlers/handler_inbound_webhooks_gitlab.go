@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/autodeploy"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
 	"gopkg.in/go-playground/webhooks.v5/gitlab"
@@ -11,11 +12,12 @@ import (
 
 const VisibilityLevelPublic = 20
 
-func processGitlabPayload(req *shttp.RequestContext) (*TriggerDeployInput, error) {
+func processGitlabPayload(req *shttp.RequestContext) (*autodeploy.TriggerDeployInput, error) {
 	hook, _ := gitlab.New()
 	payload, err := hook.Parse(
 		req.Request,
 		gitlab.PushEvents,
+		gitlab.TagEvents,
 		gitlab.MergeRequestEvents,
 		gitlab.CommentEvents,
 	)
@@ -26,8 +28,8 @@ func processGitlabPayload(req *shttp.RequestContext) (*TriggerDeployInput, error
 		return nil, err
 	}
 
-	input := TriggerDeployInput{
-		payload: payload,
+	input := autodeploy.TriggerDeployInput{
+		Payload: payload,
 	}
 
 	switch event := payload.(type) {
@@ -37,8 +39,9 @@ func processGitlabPayload(req *shttp.RequestContext) (*TriggerDeployInput, error
 		input.Branch = strings.Replace(event.Ref, "refs/heads/", "", 1)
 		input.Repo = fmt.Sprintf("gitlab/%s", event.Project.PathWithNamespace)
 		input.CheckoutRepo = input.Repo
-		input.EventType = typeCommit
+		input.EventType = autodeploy.TypeCommit
 		input.Message = strings.Split(event.Commits[0].Message, "\n")[0]
+		input.CommitSha = event.CheckoutSHA
 		input.IsFork = false
 
 		// Do not build commits that were not in default branch because:
@@ -48,6 +51,25 @@ func processGitlabPayload(req *shttp.RequestContext) (*TriggerDeployInput, error
 			return nil, nil
 		}
 
+	// Tag event
+	case gitlab.TagEventPayload:
+		// The tag was deleted.
+		if event.CheckoutSHA == "" {
+			return nil, nil
+		}
+
+		input.Tag = strings.TrimPrefix(event.Ref, "refs/tags/")
+		input.Branch = input.Tag
+		input.Repo = fmt.Sprintf("gitlab/%s", event.Project.PathWithNamespace)
+		input.CheckoutRepo = input.Repo
+		input.EventType = autodeploy.TypeCommit
+		input.CommitSha = event.CheckoutSHA
+		input.IsFork = false
+
+		if len(event.Commits) > 0 {
+			input.Message = strings.Split(event.Commits[0].Message, "\n")[0]
+		}
+
 	// Pull request event
 	// Build the source branch in this case.
 	//
@@ -57,6 +79,13 @@ func processGitlabPayload(req *shttp.RequestContext) (*TriggerDeployInput, error
 	case gitlab.MergeRequestEventPayload:
 		// This is a no-op, we only want to build when the pull request is opened.
 		// When there is a new commit on the PR, we still receive `opened` state anyways.
+		if event.ObjectAttributes.State == "closed" || event.ObjectAttributes.State == "merged" {
+			input.Repo = fmt.Sprintf("gitlab/%s", event.Project.PathWithNamespace)
+			input.EventType = autodeploy.TypePullRequestClosed
+			input.ClosedPullRequestNumber = event.ObjectAttributes.IID
+			return &input, nil
+		}
+
 		if event.ObjectAttributes.State != "opened" {
 			return nil, nil
 		}
@@ -68,7 +97,7 @@ func processGitlabPayload(req *shttp.RequestContext) (*TriggerDeployInput, error
 		input.PullRequestNumber = event.ObjectAttributes.IID
 		input.Branch = event.ObjectAttributes.SourceBranch
 		input.CommitSha = event.ObjectAttributes.LastCommit.ID
-		input.EventType = typePullRequest
+		input.EventType = autodeploy.TypePullRequest
 
 	default:
 		return nil, nil
@@ -235,6 +235,33 @@ func (s *InboundGitlabSuite) Test_DoNotRebuildSameCommits() {
 	s.Equal(http.StatusAlreadyReported, response.Code)
 }
 
+func (s *InboundGitlabSuite) Test_DoNotRebuildSameCommits_Push() {
+	appl := s.app(true)
+
+	s.MockDeployment(s.GetEnv(), map[string]any{
+		"Commit": deploy.CommitInfo{
+			ID: null.NewString("da1560886d4f094c3e6c9ef40349f7d38b5d27d7", true),
+		},
+	})
+
+	payload := map[string]any{}
+	s.NoError(json.Unmarshal([]byte(gitlabPushExample), &payload))
+	payload["checkout_sha"] = "da1560886d4f094c3e6c9ef40349f7d38b5d27d7"
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(apphandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		fmt.Sprintf("/app/webhooks/gitlab/%s", appl.Secret()),
+		payload,
+		map[string]string{
+			"X-Gitlab-Event": "Push Hook",
+		},
+	)
+
+	s.mockDeployer.AssertNotCalled(s.T(), "Deploy")
+	s.Equal(http.StatusAlreadyReported, response.Code)
+}
+
 func TestInboundGitlab(t *testing.T) {
 	suite.Run(t, &InboundGitlabSuite{})
 }
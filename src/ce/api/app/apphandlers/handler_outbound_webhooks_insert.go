@@ -32,6 +32,9 @@ func (wh *outboundWebhookRequestData) Validate() *shttperr.ValidationError {
 		app.TriggerOnDeployFailed,
 		app.TriggerOnPublish,
 		app.TriggerOnCachePurge,
+		app.TriggerOnApprovalRequested,
+		app.TriggerOnRollback,
+		app.TriggerOnServiceCrashLoop,
 	}
 
 	// Backwards compatibility
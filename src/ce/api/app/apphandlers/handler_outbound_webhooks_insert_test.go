@@ -36,11 +36,14 @@ func (s *OutboundWebhooksSuite) AfterTest(suiteName, _ string) {
 // Payload not nil, headers nil
 func (s *OutboundWebhooksSuite) Test_Success() {
 	triggerWhen := map[string]string{
-		app.TriggerOnCachePurge:    "on_cache_purge",
-		app.TriggerOnPublish:       "on_publish",
-		app.TriggerOnDeployFailed:  "on_deploy_failed",
-		app.TriggerOnDeploySuccess: "on_deploy_success",
-		"on_deploy":                "on_deploy_success", // Backwards compatibility
+		app.TriggerOnCachePurge:        "on_cache_purge",
+		app.TriggerOnPublish:           "on_publish",
+		app.TriggerOnDeployFailed:      "on_deploy_failed",
+		app.TriggerOnDeploySuccess:     "on_deploy_success",
+		app.TriggerOnApprovalRequested: "on_approval_requested",
+		app.TriggerOnRollback:          "on_rollback",
+		app.TriggerOnServiceCrashLoop:  "on_service_crash_loop",
+		"on_deploy":                    "on_deploy_success", // Backwards compatibility
 	}
 
 	for tw, expected := range triggerWhen {
@@ -97,7 +100,7 @@ func (s *OutboundWebhooksSuite) Test_BadRequest() {
 		"errors": {
 			"requesUrl": "parse \"invalid_url\": invalid URI for request",
 			"requestMethod":"Invalid requestMethod value. Accepted values are: POST | GET | HEAD",
-			"triggerWhen":"Invalid triggerWhen value. Accepted values are: on_deploy_success | on_deploy_failed | on_publish | on_cache_purge"
+			"triggerWhen":"Invalid triggerWhen value. Accepted values are: on_deploy_success | on_deploy_failed | on_publish | on_cache_purge | on_approval_requested | on_rollback | on_service_crash_loop"
 		}
 	}`
 
@@ -19,13 +19,16 @@ func Services(r *shttp.Router) *shttp.Service {
 		Handler(shttp.MethodGet, "/{did:[0-9]+}", app.WithApp(handlerAppGet)).
 		Handler(shttp.MethodPut, "", app.WithApp(handlerAppUpdate)).
 		Handler(shttp.MethodPost, "", user.WithAuth(handlerAppInsert)).
-		Handler(shttp.MethodPost, "/webhooks/{provider:github|gitlab|bitbucket}", handlerInboundWebhooks).
+		Handler(shttp.MethodPost, "/webhooks/{provider:github|gitlab|bitbucket|gitea}", handlerInboundWebhooks).
 		Handler(shttp.MethodPost, "/webhooks/{provider:github|gitlab|bitbucket}/{secret-id}", handlerInboundWebhooks). // Backwards compatibility
+		Handler(shttp.MethodPost, "/webhooks/git/{secret-id}", handlerInboundWebhooksGit).
 		Handler(shttp.MethodPost, "/proxy", app.WithApp(handlerAppProxy)).
 		Handler(shttp.MethodDelete, "", app.WithApp(handlerAppDelete)).
 		Handler(shttp.MethodGet, "/{did:[0-9]+}/settings", app.WithApp(handlerAppSettings)).
 		Handler(shttp.MethodPut, "/deploy-trigger", app.WithApp(handlerAppDeployTriggerSet)).
 		Handler(shttp.MethodDelete, "/{did:[0-9]+}/deploy-trigger", app.WithApp(handlerAppDeployTriggerDelete)).
+		Handler(shttp.MethodGet, "/{did:[0-9]+}/git-remote", app.WithApp(handlerAppGitRemoteGet)).
+		Handler(shttp.MethodPut, "/git-remote", app.WithApp(handlerAppGitRemoteSet)).
 		Handler(shttp.MethodGet, "/{did:[0-9]+}/outbound-webhooks", app.WithApp(handlerOutboundWebhookList)).
 		Handler(shttp.MethodGet, "/{did:[0-9]+}/outbound-webhooks/{wid:[0-9]+}/trigger", app.WithApp(handlerOutboundWebhookSample)).
 		Handler(shttp.MethodPost, "/outbound-webhooks", app.WithApp(handlerOutboundWebhookInsert)).
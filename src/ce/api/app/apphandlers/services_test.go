@@ -21,6 +21,7 @@ func (s *ServicesSuite) Test_Services() {
 		"DELETE:/app/outbound-webhooks",
 		"DELETE:/app/{did:[0-9]+}/deploy-trigger",
 		"GET:/app/{did:[0-9]+}",
+		"GET:/app/{did:[0-9]+}/git-remote",
 		"GET:/app/{did:[0-9]+}/outbound-webhooks",
 		"GET:/app/{did:[0-9]+}/outbound-webhooks/{wid:[0-9]+}/trigger",
 		"GET:/app/{did:[0-9]+}/settings",
@@ -30,11 +31,13 @@ func (s *ServicesSuite) Test_Services() {
 		"POST:/app",
 		"POST:/app/outbound-webhooks",
 		"POST:/app/proxy",
-		"POST:/app/webhooks/{provider:github|gitlab|bitbucket}",
+		"POST:/app/webhooks/git/{secret-id}",
+		"POST:/app/webhooks/{provider:github|gitlab|bitbucket|gitea}",
 		"POST:/app/webhooks/{provider:github|gitlab|bitbucket}/{secret-id}",
 		"POST:/hooks/app/{did:[0-9]+}/deploy/{hash}/{env}",
 		"PUT:/app",
 		"PUT:/app/deploy-trigger",
+		"PUT:/app/git-remote",
 		"PUT:/app/outbound-webhooks",
 	}
 
@@ -0,0 +1,244 @@
+// Package autodeploy triggers the deployments of the environments that are
+// configured to deploy automatically when a repository receives new commits.
+package autodeploy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dlclark/regexp2"
+	"gopkg.in/guregu/null.v3"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhooks"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+)
+
+// The event types of the inputs.
+const (
+	TypeCommit            = "commit"
+	TypePullRequest       = "pull_request"
+	TypePullRequestClosed = "pull_request_closed"
+)
+
+// TriggerDeployInput represents the input for the TriggerDeploy function.
+type TriggerDeployInput struct {
+	Fail                    bool   // used to debug leaving failure pr comments
+	Repo                    string // represents the base repository that the app is created
+	CheckoutRepo            string // represents the repository that will be checked out
+	IsFork                  bool   // whether or not this deployment is a fork
+	Branch                  string
+	Message                 string
+	EventType               string
+	CommitSha               string
+	Tag                     string   // set when a tag is pushed, in which case Branch is the tag name
+	AppID                   types.ID // restricts the deployment to the given app, used by generic git remotes
+	PullRequestNumber       int64
+	ClosedPullRequestNumber int64 // set when a pull request is closed or merged, to tear down its environments
+
+	Payload any // The payload that is sent by the provider - we store this in the database.
+}
+
+// NewTriggerDeployInput is a helper function to
+// initiate a new TriggerDeployInput instance.
+func NewTriggerDeployInput(repo, branch string) TriggerDeployInput {
+	return TriggerDeployInput{
+		Repo:   repo,
+		Branch: branch,
+	}
+}
+
+// TriggerDeploy triggers a new deploy given the repository, and the branch name.
+// See tests for an example input event.
+func TriggerDeploy(ctx context.Context, input TriggerDeployInput) *shttp.Response {
+	// Do not deploy automatically sample projects
+	if input.Repo == app.SampleProjectRepo {
+		return nil
+	}
+
+	// This is mostly for GitLab as we may end up deploying the same commit again and
+	// again because GitLab sends the same payload.
+	if alreadyBuilt, err := commitHasBeenBuilt(ctx, input); err != nil || alreadyBuilt {
+		if err != nil {
+			return shttp.Error(err)
+		}
+
+		return &shttp.Response{
+			Status: http.StatusAlreadyReported,
+		}
+	}
+
+	apps, err := app.NewStore().DeployCandidates(ctx, input.Repo)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	candidates := FilterDeployCandidates(input, apps)
+
+	// Pull requests of apps with a pull request template are deployed to ephemeral environments.
+	if input.EventType == TypePullRequest && input.PullRequestNumber != 0 {
+		if candidates, err = pullRequestCandidates(ctx, input, apps, candidates); err != nil {
+			return shttp.Error(err)
+		}
+	}
+
+	numberOfBuilds := 0
+
+	for _, a := range candidates {
+		// Generic git remotes are not unique per provider account, therefore
+		// only the app that received the event is deployed.
+		if input.AppID != 0 && a.ID != input.AppID {
+			continue
+		}
+
+		if input.IsFork {
+			a.ShouldPublish = false
+		}
+
+		// Tags that match the environment pattern are treated as releases.
+		if a.EnvDefaultBranch != input.Branch && input.Tag == "" {
+			a.ShouldPublish = false
+		}
+
+		// Deployments are built but not published during freeze windows.
+		if a.ShouldPublish && a.BuildConfig.ActiveFreezeWindow(time.Now()) != nil {
+			slog.Infof("skipping auto publish for app id=%d, env=%s: environment is in a freeze window", a.ID, a.EnvName)
+			a.ShouldPublish = false
+		}
+
+		depl := deploy.New(a.ID)
+		depl.WebhookEvent = input.Payload
+		depl.Branch = input.Branch
+		depl.Env = a.EnvName
+		depl.EnvID = a.EnvID
+		depl.IsAutoDeploy = true
+		depl.Commit.ID = null.NewString(input.CommitSha, input.CommitSha != "")
+		depl.Tag = null.NewString(input.Tag, input.Tag != "")
+		depl.CheckoutRepo = input.CheckoutRepo
+		depl.IsFork = input.IsFork
+		depl.BuildConfig = a.BuildConfig
+		depl.ShouldPublish = a.ShouldPublish
+
+		if input.PullRequestNumber != 0 {
+			depl.PullRequestNumber = null.NewInt(input.PullRequestNumber, true)
+		}
+
+		if err := deployservice.New().Deploy(ctx, a.App, depl); err != nil {
+			isContextCanceled := errors.Is(err, context.Canceled)
+
+			if !isContextCanceled {
+				slog.Errorf("auto deployment failed for app id=%d, clone url:%s, err=%v", a.ID, depl.CheckoutRepo, err)
+			}
+
+			return shttp.Error(err)
+		}
+
+		// Post the pending status to the git provider.
+		status := deployhooks.NewDeploymentStatus(a.Repo, a.UserID, depl, deployhooks.StatusPending)
+
+		if err := deployhooks.ReportStatus(status); err != nil {
+			slog.Errorf("error while creating pending commit status: %s", err.Error())
+		}
+
+		numberOfBuilds = numberOfBuilds + 1
+	}
+
+	if numberOfBuilds > 0 {
+		return shttp.OK()
+	}
+
+	return shttp.NoContent()
+}
+
+// FilterDeployCandidates checks the following conditions and determines
+// whether a deploy candidate should be deployed or not.
+//
+//  1. If the branch name matches the branch of an environment, return
+//     that environment
+//  2. If we still have nothing, check the Auto Deploy Branch config. Return
+//     all matches. If nothing is found, return empty.
+//
+// Tags are only deployed to environments whose Auto Deploy Tags config matches.
+func FilterDeployCandidates(input TriggerDeployInput, dcs []*app.DeployCandidate) []*app.DeployCandidate {
+	filtered := []*app.DeployCandidate{}
+
+	// All candidates have auto_deploy turned on
+	for _, dc := range dcs {
+		if input.Tag != "" {
+			if dc.BuildConfig != nil && dc.BuildConfig.AutoDeployTags != "" && MatchPattern(dc.BuildConfig.AutoDeployTags, input.Tag) {
+				filtered = append(filtered, dc)
+			}
+
+			continue
+		}
+
+		patternBranches := dc.AutoDeployBranches.ValueOrZero()
+		patternCommits := dc.AutoDeployCommits.ValueOrZero()
+
+		// If the pattern is empty, it means we want to deploy all branches/commits
+		if patternBranches == "" && patternCommits == "" {
+			filtered = append(filtered, dc)
+			continue
+		}
+
+		if patternBranches != "" {
+			// When the default branch is the same with the current branch include the dc
+			// This feature is only available when deploy branches is specified
+			if strings.EqualFold(dc.EnvDefaultBranch, input.Branch) {
+				filtered = append(filtered, dc)
+			} else if MatchPattern(patternBranches, input.Branch) {
+				filtered = append(filtered, dc)
+			}
+
+			continue
+		}
+
+		// Make sure to build commits on the release branch only.
+		if input.Branch != dc.EnvDefaultBranch {
+			continue
+		}
+
+		if patternCommits != "" && input.Message != "" && MatchPattern(patternCommits, input.Message) {
+			filtered = append(filtered, dc)
+		}
+	}
+
+	return filtered
+}
+
+// commitHasBeenBuilt checks whether there is already a build for the commit or not.
+// Tags are not checked, as they usually point to a commit that has already been built
+// on a branch, and still need a deployment.
+func commitHasBeenBuilt(ctx context.Context, input TriggerDeployInput) (bool, error) {
+	if input.Tag != "" {
+		return false, nil
+	}
+
+	return deploy.NewStore().IsDeploymentAlreadyBuilt(ctx, input.CommitSha)
+}
+
+// MatchPattern matches the given branch name against the given glob pattern.
+func MatchPattern(pattern, branch string) bool {
+	r, err := regexp2.Compile(pattern, regexp2.IgnoreCase)
+
+	if err != nil {
+		return false
+	}
+
+	matched, err := r.MatchString(branch)
+
+	if err != nil {
+		slog.Errorf("error while matching string: %s", err.Error())
+		return false
+	}
+
+	return matched
+}
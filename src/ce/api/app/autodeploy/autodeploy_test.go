@@ -1,4 +1,4 @@
-package apphandlers_test
+package autodeploy_test
 
 import (
 	"testing"
@@ -8,11 +8,12 @@ import (
 	"gopkg.in/guregu/null.v3"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
-	"github.com/stormkit-io/stormkit-io/src/ce/api/app/apphandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/autodeploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/lib/factory"
 )
 
-type InboundWebhooksSuite struct {
+type AutoDeploySuite struct {
 	suite.Suite
 	*factory.Factory
 
@@ -20,7 +21,7 @@ type InboundWebhooksSuite struct {
 	list []*app.DeployCandidate
 }
 
-func (s *InboundWebhooksSuite) BeforeTest(suiteName, _ string) {
+func (s *AutoDeploySuite) BeforeTest(suiteName, _ string) {
 	myApp := &app.MyApp{
 		App: &app.App{
 			DefaultEnv: "development",
@@ -52,8 +53,8 @@ func (s *InboundWebhooksSuite) BeforeTest(suiteName, _ string) {
 	}
 }
 
-func (s *InboundWebhooksSuite) Test_TriggerDeploy() {
-	commitInput := apphandlers.TriggerDeployInput{
+func (s *AutoDeploySuite) Test_TriggerDeploy() {
+	commitInput := autodeploy.TriggerDeployInput{
 		Repo:         "github/stormkit-io/sample-project",
 		CheckoutRepo: "github/stormkit-io/sample-project",
 		Branch:       "main",
@@ -61,7 +62,7 @@ func (s *InboundWebhooksSuite) Test_TriggerDeploy() {
 		EventType:    "commit",
 	}
 
-	pullRequestInput := apphandlers.TriggerDeployInput{
+	pullRequestInput := autodeploy.TriggerDeployInput{
 		Repo:              "github/stormkit-io/sample-project",
 		CheckoutRepo:      "github/stormkit-io/sample-project",
 		IsFork:            false,
@@ -76,33 +77,33 @@ func (s *InboundWebhooksSuite) Test_TriggerDeploy() {
 	s.NotEmpty(commitInput)
 }
 
-func (s *InboundWebhooksSuite) Test_MatchPattern() {
+func (s *AutoDeploySuite) Test_MatchPattern() {
 	a := assert.New(s.T())
-	a.True(apphandlers.MatchPattern("^dependabot/.*", "dependabot/bump-node-forge-1.4.5"))
-	a.True(apphandlers.MatchPattern("^(dependabot|renovate)/.*", "dependabot/bump-node-forge-1.4.5"))
-	a.True(apphandlers.MatchPattern("^(dependabot|renovate)/.*", "renovate/bump-node-forge-1.4.5"))
-	a.False(apphandlers.MatchPattern("^(?!dependabot|renovate).*/.*", "renovate/bump-node-forge-1.4.5"))
-	a.False(apphandlers.MatchPattern("^(?!dependabot|renovate).*/.*", "dependabot/bump-node-forge-1.4.5"))
-	a.False(apphandlers.MatchPattern("^(?!dependabot|renovate).*/.*", "release"))
-	a.True(apphandlers.MatchPattern("^(?!dependabot|renovate).*/.*", "release/staging"))
-	a.True(apphandlers.MatchPattern("^(?!dependabot).+", "auto-deploy-branches"))
-	a.True(apphandlers.MatchPattern("my-branch", "hello-my-branch"))
-	a.False(apphandlers.MatchPattern("^my-branch", "hello-my-branch"))
-	a.False(apphandlers.MatchPattern("my-branch", "my-b"))
-	a.True(apphandlers.MatchPattern("release-*", "release-staging"))
-	a.True(apphandlers.MatchPattern(`^chore\(release\):.+`, "chore(release): version 10.504.21"))
-	a.False(apphandlers.MatchPattern(`^chore(release):.+`, "chore(release): version 10.504.21"))
-	a.False(apphandlers.MatchPattern(`\[deploy\]`, "chore: remove env variable"))
-	a.True(apphandlers.MatchPattern(`\[deploy\]`, "chore: remove env variable [deploy]"))
+	a.True(autodeploy.MatchPattern("^dependabot/.*", "dependabot/bump-node-forge-1.4.5"))
+	a.True(autodeploy.MatchPattern("^(dependabot|renovate)/.*", "dependabot/bump-node-forge-1.4.5"))
+	a.True(autodeploy.MatchPattern("^(dependabot|renovate)/.*", "renovate/bump-node-forge-1.4.5"))
+	a.False(autodeploy.MatchPattern("^(?!dependabot|renovate).*/.*", "renovate/bump-node-forge-1.4.5"))
+	a.False(autodeploy.MatchPattern("^(?!dependabot|renovate).*/.*", "dependabot/bump-node-forge-1.4.5"))
+	a.False(autodeploy.MatchPattern("^(?!dependabot|renovate).*/.*", "release"))
+	a.True(autodeploy.MatchPattern("^(?!dependabot|renovate).*/.*", "release/staging"))
+	a.True(autodeploy.MatchPattern("^(?!dependabot).+", "auto-deploy-branches"))
+	a.True(autodeploy.MatchPattern("my-branch", "hello-my-branch"))
+	a.False(autodeploy.MatchPattern("^my-branch", "hello-my-branch"))
+	a.False(autodeploy.MatchPattern("my-branch", "my-b"))
+	a.True(autodeploy.MatchPattern("release-*", "release-staging"))
+	a.True(autodeploy.MatchPattern(`^chore\(release\):.+`, "chore(release): version 10.504.21"))
+	a.False(autodeploy.MatchPattern(`^chore(release):.+`, "chore(release): version 10.504.21"))
+	a.False(autodeploy.MatchPattern(`\[deploy\]`, "chore: remove env variable"))
+	a.True(autodeploy.MatchPattern(`\[deploy\]`, "chore: remove env variable [deploy]"))
 }
 
-func (s *InboundWebhooksSuite) Test_FilterDeployCandidates_AutoDeploy() {
+func (s *AutoDeploySuite) Test_FilterDeployCandidates_AutoDeploy() {
 	s.list[0].AutoDeployBranches = null.NewString("some-regex", true)
 	s.list[1].AutoDeployBranches = null.NewString("some-other-regex", true)
 	s.list[2].AutoDeployBranches = null.NewString("another-regex", true)
 	s.list[3].AutoDeployBranches = null.NewString("", false)
 
-	c := apphandlers.FilterDeployCandidates(apphandlers.TriggerDeployInput{
+	c := autodeploy.FilterDeployCandidates(autodeploy.TriggerDeployInput{
 		Branch: "staging",
 	}, s.list)
 
@@ -110,7 +111,7 @@ func (s *InboundWebhooksSuite) Test_FilterDeployCandidates_AutoDeploy() {
 	s.Equal("development", c[0].EnvName)
 	s.Equal("auto-deploy-branch", c[1].EnvName)
 
-	c = apphandlers.FilterDeployCandidates(apphandlers.TriggerDeployInput{
+	c = autodeploy.FilterDeployCandidates(autodeploy.TriggerDeployInput{
 		Branch: "master",
 	}, s.list)
 
@@ -120,7 +121,7 @@ func (s *InboundWebhooksSuite) Test_FilterDeployCandidates_AutoDeploy() {
 	s.Equal("auto-deploy-branch", c[1].EnvName)
 	s.Equal("", c[1].AutoDeployBranches.ValueOrZero())
 
-	c = apphandlers.FilterDeployCandidates(apphandlers.TriggerDeployInput{
+	c = autodeploy.FilterDeployCandidates(autodeploy.TriggerDeployInput{
 		Branch:            "feature-branch",
 		PullRequestNumber: 201,
 	}, s.list)
@@ -129,7 +130,28 @@ func (s *InboundWebhooksSuite) Test_FilterDeployCandidates_AutoDeploy() {
 	s.Len(c, 1)
 }
 
-func (s *InboundWebhooksSuite) Test_FilterDeployCandidates_AutoDeployBranchesConfig() {
+func (s *AutoDeploySuite) Test_FilterDeployCandidates_Tags() {
+	s.list[0].BuildConfig = &buildconf.BuildConf{AutoDeployTags: `^v\d+\.\d+\.\d+$`}
+	s.list[1].BuildConfig = &buildconf.BuildConf{AutoDeployTags: `^release-`}
+
+	c := autodeploy.FilterDeployCandidates(autodeploy.TriggerDeployInput{
+		Branch: "v1.2.0",
+		Tag:    "v1.2.0",
+	}, s.list)
+
+	s.Len(c, 1)
+	s.Equal("development", c[0].EnvName)
+
+	// Environments without a tag pattern ignore tags, even when they deploy all branches
+	c = autodeploy.FilterDeployCandidates(autodeploy.TriggerDeployInput{
+		Branch: "nightly",
+		Tag:    "nightly",
+	}, s.list)
+
+	s.Len(c, 0)
+}
+
+func (s *AutoDeploySuite) Test_FilterDeployCandidates_AutoDeployBranchesConfig() {
 	myApp := &app.MyApp{
 		App: &app.App{
 			DefaultEnv: "development",
@@ -157,14 +179,14 @@ func (s *InboundWebhooksSuite) Test_FilterDeployCandidates_AutoDeployBranchesCon
 		},
 	}
 
-	c := apphandlers.FilterDeployCandidates(apphandlers.TriggerDeployInput{
+	c := autodeploy.FilterDeployCandidates(autodeploy.TriggerDeployInput{
 		Branch: "staging",
 	}, list)
 
 	s.Equal("development", c[0].EnvName)
 	s.Len(c, 1)
 
-	c = apphandlers.FilterDeployCandidates(apphandlers.TriggerDeployInput{
+	c = autodeploy.FilterDeployCandidates(autodeploy.TriggerDeployInput{
 		Branch:            "branch-1-a",
 		PullRequestNumber: 201,
 	}, list)
@@ -173,7 +195,7 @@ func (s *InboundWebhooksSuite) Test_FilterDeployCandidates_AutoDeployBranchesCon
 	s.Equal("development", c[0].EnvName)
 	s.Equal("testing", c[1].EnvName)
 
-	c = apphandlers.FilterDeployCandidates(apphandlers.TriggerDeployInput{
+	c = autodeploy.FilterDeployCandidates(autodeploy.TriggerDeployInput{
 		Branch:            "branch-3",
 		PullRequestNumber: 201,
 	}, list)
@@ -182,7 +204,7 @@ func (s *InboundWebhooksSuite) Test_FilterDeployCandidates_AutoDeployBranchesCon
 	s.Len(c, 1)
 }
 
-func (s *InboundWebhooksSuite) Test_FilterDeployCandidates_AutoDeployCommitsConfig() {
+func (s *AutoDeploySuite) Test_FilterDeployCandidates_AutoDeployCommitsConfig() {
 	myApp := &app.MyApp{
 		App: &app.App{
 			DefaultEnv: "development",
@@ -205,7 +227,7 @@ func (s *InboundWebhooksSuite) Test_FilterDeployCandidates_AutoDeployCommitsConf
 	}
 
 	// The commit should only be checked if the branches match
-	c := apphandlers.FilterDeployCandidates(apphandlers.TriggerDeployInput{
+	c := autodeploy.FilterDeployCandidates(autodeploy.TriggerDeployInput{
 		Message: "release: hello world",
 		Branch:  "staging",
 	}, list)
@@ -214,7 +236,7 @@ func (s *InboundWebhooksSuite) Test_FilterDeployCandidates_AutoDeployCommitsConf
 	s.Len(c, 1)
 
 	// The commit should not be checked if branches do not match
-	c = apphandlers.FilterDeployCandidates(apphandlers.TriggerDeployInput{
+	c = autodeploy.FilterDeployCandidates(autodeploy.TriggerDeployInput{
 		Message: "release: hello world",
 		Branch:  "some-random-branch",
 	}, list)
@@ -222,6 +244,18 @@ func (s *InboundWebhooksSuite) Test_FilterDeployCandidates_AutoDeployCommitsConf
 	s.Len(c, 0)
 }
 
-func TestIncomingWebhooks(t *testing.T) {
-	suite.Run(t, &InboundWebhooksSuite{})
+func (s *AutoDeploySuite) Test_NewGitRemoteTriggerInput() {
+	a := &app.App{ID: 5, Repo: "git/https://git.example.org/org/repo.git"}
+
+	input := autodeploy.NewGitRemoteTriggerInput(a, "refs/tags/v1.0.0", "abc", "Release\n\nNotes")
+	s.Equal("v1.0.0", input.Tag)
+	s.Equal("v1.0.0", input.Branch)
+	s.Equal("Release", input.Message)
+	s.Equal(a.ID, input.AppID)
+
+	s.Nil(autodeploy.NewGitRemoteTriggerInput(a, "refs/notes/commits", "abc", ""))
+}
+
+func TestAutoDeploy(t *testing.T) {
+	suite.Run(t, &AutoDeploySuite{})
 }
@@ -0,0 +1,37 @@
+package autodeploy
+
+import (
+	"strings"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+)
+
+// NewGitRemoteTriggerInput returns the input to deploy the given ref of a generic git remote.
+// It is shared by the inbound webhook and the poller. Returns nil when the ref is neither a
+// branch nor a tag, or when the ref has been deleted.
+func NewGitRemoteTriggerInput(a *app.App, ref, sha, message string) *TriggerDeployInput {
+	if sha != "" && strings.Trim(sha, "0") == "" {
+		return nil
+	}
+
+	input := &TriggerDeployInput{
+		AppID:        a.ID,
+		Repo:         a.Repo,
+		CheckoutRepo: a.Repo,
+		Message:      strings.Split(message, "\n")[0],
+		CommitSha:    sha,
+		EventType:    TypeCommit,
+	}
+
+	switch {
+	case strings.HasPrefix(ref, "refs/heads/"):
+		input.Branch = strings.TrimPrefix(ref, "refs/heads/")
+	case strings.HasPrefix(ref, "refs/tags/"):
+		input.Tag = strings.TrimPrefix(ref, "refs/tags/")
+		input.Branch = input.Tag
+	default:
+		return nil
+	}
+
+	return input
+}
@@ -0,0 +1,172 @@
+package autodeploy
+
+import (
+	"context"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/appcache"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/database"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+)
+
+// pullRequestTemplates returns the environments that are configured as pull request
+// templates, keyed by the app id. Only the first template of each app is used.
+func pullRequestTemplates(dcs []*app.DeployCandidate) map[types.ID]*app.DeployCandidate {
+	templates := map[types.ID]*app.DeployCandidate{}
+
+	for _, dc := range dcs {
+		if dc.BuildConfig == nil || !dc.BuildConfig.PullRequestEnvs.IsEnabled() {
+			continue
+		}
+
+		if templates[dc.ID] == nil {
+			templates[dc.ID] = dc
+		}
+	}
+
+	return templates
+}
+
+// pullRequestCandidates replaces the deploy candidates of the apps that have a pull request
+// template with a single candidate that points to the ephemeral environment of the pull request.
+// The environment is created from the template when it does not exist yet.
+func pullRequestCandidates(ctx context.Context, input TriggerDeployInput, all, filtered []*app.DeployCandidate) ([]*app.DeployCandidate, error) {
+	templates := pullRequestTemplates(all)
+
+	if len(templates) == 0 {
+		return filtered, nil
+	}
+
+	candidates := []*app.DeployCandidate{}
+
+	for _, dc := range filtered {
+		if templates[dc.ID] == nil {
+			candidates = append(candidates, dc)
+		}
+	}
+
+	store := buildconf.NewStore()
+	name := buildconf.PullRequestEnvName(input.PullRequestNumber)
+
+	for appID, template := range templates {
+		if input.AppID != 0 && appID != input.AppID {
+			continue
+		}
+
+		env, err := store.Environment(ctx, appID, name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// An environment with the same name was created manually, leave it untouched.
+		if env != nil && !env.IsPullRequestEnv(input.PullRequestNumber) {
+			slog.Infof("skipping pull request environment for app id=%d: environment %s already exists", appID, name)
+			continue
+		}
+
+		if env == nil {
+			env, err = buildconf.NewPullRequestEnv(&buildconf.Env{
+				AppID: appID,
+				Name:  template.EnvName,
+				Data:  template.BuildConfig,
+			}, input.PullRequestNumber, input.Branch)
+
+			if err != nil {
+				return nil, err
+			}
+
+			if err := store.Insert(ctx, env); err != nil {
+				if !database.IsDuplicate(err) {
+					return nil, err
+				}
+
+				// A concurrent event of the same pull request created the environment in the meantime.
+				if env, err = store.Environment(ctx, appID, name); err != nil {
+					return nil, err
+				}
+
+				if env == nil || !env.IsPullRequestEnv(input.PullRequestNumber) {
+					continue
+				}
+			}
+		}
+
+		dc := *template
+		dc.EnvID = env.ID
+		dc.EnvName = env.Name
+		dc.EnvDefaultBranch = input.Branch
+		dc.BuildConfig = env.Data
+		dc.ShouldPublish = true
+
+		candidates = append(candidates, &dc)
+	}
+
+	return candidates, nil
+}
+
+// TeardownPullRequestEnvs removes the ephemeral environments of the given pull request.
+// The deployments of the environments are unpublished and marked as deleted, so that
+// their artifacts are removed by the artifact cleanup, and the running services are
+// stopped through a broadcast to the hosting instances. It returns the number of
+// removed environments.
+func TeardownPullRequestEnvs(ctx context.Context, repo string, number int64) (int, error) {
+	dcs, err := app.NewStore().DeployCandidates(ctx, repo)
+
+	if err != nil {
+		return 0, err
+	}
+
+	store := buildconf.NewStore()
+	name := buildconf.PullRequestEnvName(number)
+	visited := map[types.ID]bool{}
+	removed := 0
+
+	for _, dc := range dcs {
+		if visited[dc.ID] {
+			continue
+		}
+
+		visited[dc.ID] = true
+
+		env, err := store.Environment(ctx, dc.ID, name)
+
+		if err != nil {
+			return removed, err
+		}
+
+		if !env.IsPullRequestEnv(number) {
+			continue
+		}
+
+		deleted, err := store.MarkAsDeleted(ctx, env.ID)
+
+		if err != nil {
+			return removed, err
+		}
+
+		if !deleted {
+			continue
+		}
+
+		if err := deploy.NewStore().MarkEnvDeploymentsAsDeleted(ctx, env.ID); err != nil {
+			return removed, err
+		}
+
+		if err := appcache.Service().Reset(env.ID); err != nil {
+			slog.Errorf("error while resetting cache for env id=%d: %v", env.ID, err)
+		}
+
+		if err := rediscache.Service().Broadcast(rediscache.EventStopEnvServices, env.ID.String()); err != nil {
+			slog.Errorf("error while stopping services for env id=%d: %v", env.ID, err)
+		}
+
+		removed = removed + 1
+	}
+
+	return removed, nil
+}
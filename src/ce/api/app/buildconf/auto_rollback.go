@@ -0,0 +1,53 @@
+package buildconf
+
+import "time"
+
+// AutoRollback configures the health monitoring that takes place after a publish.
+// When the thresholds are exceeded during the watch window, the previously
+// published deployment is published again.
+type AutoRollback struct {
+	WatchWindow     int     `json:"watchWindow,omitempty"`     // WatchWindow is the number of minutes to monitor the deployment after publish, defaults to 15
+	ErrorRate       float64 `json:"errorRate,omitempty"`       // ErrorRate is the percentage of 5xx responses that triggers a rollback, defaults to 10
+	MinRequests     int     `json:"minRequests,omitempty"`     // MinRequests is the number of requests required before evaluating the error rate, defaults to 20
+	MaxPingFailures int     `json:"maxPingFailures,omitempty"` // MaxPingFailures is the number of failing domain pings that is tolerated, defaults to 0
+}
+
+// Validate validates the auto rollback policy.
+func (ar *AutoRollback) Validate() error {
+	if ar.WatchWindow < 0 || ar.MinRequests < 0 || ar.MaxPingFailures < 0 {
+		return ErrInvalidAutoRollback
+	}
+
+	if ar.ErrorRate < 0 || ar.ErrorRate > 100 {
+		return ErrInvalidAutoRollback
+	}
+
+	return nil
+}
+
+// Window returns the duration of the watch window.
+func (ar *AutoRollback) Window() time.Duration {
+	if ar.WatchWindow == 0 {
+		return 15 * time.Minute
+	}
+
+	return time.Duration(ar.WatchWindow) * time.Minute
+}
+
+// ErrorRateThreshold returns the percentage of 5xx responses that triggers a rollback.
+func (ar *AutoRollback) ErrorRateThreshold() float64 {
+	if ar.ErrorRate == 0 {
+		return 10
+	}
+
+	return ar.ErrorRate
+}
+
+// RequestsThreshold returns the number of requests required before evaluating the error rate.
+func (ar *AutoRollback) RequestsThreshold() int {
+	if ar.MinRequests == 0 {
+		return 20
+	}
+
+	return ar.MinRequests
+}
@@ -0,0 +1,36 @@
+package buildconf_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stretchr/testify/suite"
+)
+
+type AutoRollbackSuite struct {
+	suite.Suite
+}
+
+func (s *AutoRollbackSuite) Test_Validate() {
+	s.NoError((&buildconf.AutoRollback{}).Validate())
+	s.NoError((&buildconf.AutoRollback{WatchWindow: 30, ErrorRate: 5, MinRequests: 100}).Validate())
+	s.ErrorIs((&buildconf.AutoRollback{ErrorRate: 120}).Validate(), buildconf.ErrInvalidAutoRollback)
+	s.ErrorIs((&buildconf.AutoRollback{WatchWindow: -1}).Validate(), buildconf.ErrInvalidAutoRollback)
+}
+
+func (s *AutoRollbackSuite) Test_Defaults() {
+	ar := &buildconf.AutoRollback{}
+	s.Equal(15*time.Minute, ar.Window())
+	s.Equal(10.0, ar.ErrorRateThreshold())
+	s.Equal(20, ar.RequestsThreshold())
+
+	ar = &buildconf.AutoRollback{WatchWindow: 5, ErrorRate: 2.5, MinRequests: 50}
+	s.Equal(5*time.Minute, ar.Window())
+	s.Equal(2.5, ar.ErrorRateThreshold())
+	s.Equal(50, ar.RequestsThreshold())
+}
+
+func TestAutoRollback(t *testing.T) {
+	suite.Run(t, &AutoRollbackSuite{})
+}
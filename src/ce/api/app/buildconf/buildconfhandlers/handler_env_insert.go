@@ -1,14 +1,19 @@
 package buildconfhandlers
 
 import (
+	"context"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/ee/api/audit"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttperr"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/framework"
 )
 
 // handlerEnvInsert inserts a build configuration for the given application.
@@ -51,6 +56,11 @@ func handlerEnvInsert(req *app.RequestContext) *shttp.Response {
 		}
 	}
 
+	// Propose build settings when none of them is provided
+	if !hasBuildSettings(cnf.Data) {
+		go proposeBuildSettings(DetectFramework, req.App, cnf.ID, cnf.Branch)
+	}
+
 	return &shttp.Response{
 		Status: http.StatusCreated,
 		Data: map[string]any{
@@ -58,3 +68,38 @@ func handlerEnvInsert(req *app.RequestContext) *shttp.Response {
 		},
 	}
 }
+
+// hasBuildSettings returns true when any of the settings that are proposed is set.
+func hasBuildSettings(data *buildconf.BuildConf) bool {
+	return data.BuildCmd != "" || data.DistFolder != "" || data.ServerCmd != ""
+}
+
+// proposeTimeout is the duration the git provider has to return the files of the repository.
+var proposeTimeout = time.Minute
+
+// proposeBuildSettings detects the framework of the repository and fills in the build settings
+// of the environment. It runs in the background as it calls the git provider, and leaves the
+// settings untouched when they were changed in the meantime.
+func proposeBuildSettings(detect func(*app.App, context.Context, string) (*framework.Preset, error), a *app.App, envID types.ID, branch string) {
+	ctx, cancel := context.WithTimeout(context.Background(), proposeTimeout)
+	defer cancel()
+
+	preset, err := detect(a, ctx, branch)
+
+	if err != nil {
+		slog.Errorf("error while detecting framework: %v", err)
+		return
+	}
+
+	if preset == nil {
+		return
+	}
+
+	// The settings are updated only when they are still empty, so that the changes
+	// that were made while the framework was being detected are not overwritten.
+	_, err = buildconf.NewStore().ProposeBuildSettings(ctx, envID, preset.BuildCmd, preset.DistFolder, preset.ServerCmd, preset.APIFolder)
+
+	if err != nil {
+		slog.Errorf("error while proposing build settings: %v", err)
+	}
+}
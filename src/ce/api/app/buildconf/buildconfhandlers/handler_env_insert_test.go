@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
 	"github.com/stormkit-io/stormkit-io/src/ee/api/audit"
 	"github.com/stretchr/testify/suite"
@@ -18,6 +19,7 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/lib/factory"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/framework"
 )
 
 type HandlerEnvInsertSuite struct {
@@ -30,10 +32,15 @@ type HandlerEnvInsertSuite struct {
 func (s *HandlerEnvInsertSuite) BeforeTest(suiteName, _ string) {
 	s.conn = databasetest.InitTx(suiteName)
 	s.Factory = factory.New(s.conn)
+
+	buildconfhandlers.DetectFramework = func(*app.App, context.Context, string) (*framework.Preset, error) {
+		return nil, nil
+	}
 }
 
 func (s *HandlerEnvInsertSuite) AfterTest(_, _ string) {
 	s.conn.CloseTx()
+	buildconfhandlers.DetectFramework = (*app.App).DetectFramework
 }
 
 func (s *HandlerEnvInsertSuite) Test_BadRequestEnvMissing() {
@@ -184,6 +191,99 @@ func (s *HandlerEnvInsertSuite) Test_SuccessWithSameBranch() {
 	s.Equal(http.StatusCreated, response.Code)
 }
 
+func (s *HandlerEnvInsertSuite) Test_SuccessWithFrameworkPreset() {
+	myApp := s.MockApp(nil)
+	branches := make(chan string, 1)
+
+	buildconfhandlers.DetectFramework = func(_ *app.App, _ context.Context, b string) (*framework.Preset, error) {
+		branches <- b
+
+		return &framework.Preset{
+			ID:         "nuxt",
+			Name:       "Nuxt",
+			BuildCmd:   "npm run build",
+			DistFolder: ".output",
+			ServerCmd:  "node .output/server/index.mjs",
+		}, nil
+	}
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(buildconfhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/env",
+		map[string]any{
+			"env":    "dev",
+			"branch": "dev-branch",
+			"appId":  myApp.ID.String(),
+			"build":  map[string]any{},
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(myApp.UserID),
+		},
+	)
+
+	s.Equal(http.StatusCreated, response.Code)
+	s.Equal("dev-branch", <-branches)
+
+	// The settings are proposed in the background.
+	s.Eventually(func() bool {
+		conf, err := buildconf.NewStore().Environment(context.Background(), myApp.ID, "dev")
+		return err == nil && conf.Data.BuildCmd == "npm run build"
+	}, 5*time.Second, 50*time.Millisecond)
+
+	conf, err := buildconf.NewStore().Environment(context.Background(), myApp.ID, "dev")
+	s.NoError(err)
+	s.Equal(".output", conf.Data.DistFolder)
+	s.Equal("node .output/server/index.mjs", conf.Data.ServerCmd)
+}
+
+func (s *HandlerEnvInsertSuite) Test_SuccessWithFrameworkPreset_ChangedInTheMeantime() {
+	myApp := s.MockApp(nil)
+	release := make(chan bool)
+
+	buildconfhandlers.DetectFramework = func(*app.App, context.Context, string) (*framework.Preset, error) {
+		<-release
+
+		return &framework.Preset{
+			ID:         "nuxt",
+			Name:       "Nuxt",
+			BuildCmd:   "npm run build",
+			DistFolder: ".output",
+		}, nil
+	}
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(buildconfhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/env",
+		map[string]any{
+			"env":    "dev",
+			"branch": "dev-branch",
+			"appId":  myApp.ID.String(),
+			"build":  map[string]any{},
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(myApp.UserID),
+		},
+	)
+
+	s.Equal(http.StatusCreated, response.Code)
+
+	// The user updates the build settings while the framework is being detected.
+	store := buildconf.NewStore()
+	conf, err := store.Environment(context.Background(), myApp.ID, "dev")
+	s.NoError(err)
+	conf.Data.BuildCmd = "pnpm build"
+	s.NoError(store.Update(context.Background(), conf))
+
+	close(release)
+
+	s.Never(func() bool {
+		conf, err := store.Environment(context.Background(), myApp.ID, "dev")
+		return err != nil || conf.Data.BuildCmd != "pnpm build" || conf.Data.DistFolder != ""
+	}, 500*time.Millisecond, 50*time.Millisecond)
+}
+
 func TestHandlerEnvInsert(t *testing.T) {
 	suite.Run(t, &HandlerEnvInsertSuite{})
 }
@@ -0,0 +1,26 @@
+package buildconfhandlers
+
+import (
+	"net/http"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+)
+
+// handlerEnvServices returns the status of the services that are started with the
+// server command of the environment, including their restart counts and exit codes.
+func handlerEnvServices(req *app.RequestContext) *shttp.Response {
+	statuses, err := integrations.ServiceStatuses(req.Context(), req.EnvID)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	return &shttp.Response{
+		Status: http.StatusOK,
+		Data: map[string]any{
+			"services": statuses,
+		},
+	}
+}
@@ -0,0 +1,59 @@
+package buildconfhandlers
+
+import (
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+)
+
+type envWorkersRequest struct {
+	// Name is the name of the worker. When empty, all workers of the environment are affected.
+	Name string `json:"name"`
+}
+
+// handlerEnvWorkers returns a handler that starts, stops or restarts the workers of the
+// environment. Stopped workers are not started again until they are started manually
+// or a new deployment is published.
+func handlerEnvWorkers(action string) func(*app.RequestContext) *shttp.Response {
+	return func(req *app.RequestContext) *shttp.Response {
+		data := envWorkersRequest{}
+
+		if err := req.Post(&data); err != nil {
+			return shttp.Error(err)
+		}
+
+		env, err := buildconf.NewStore().EnvironmentByID(req.Context(), req.EnvID)
+
+		if err != nil {
+			return shttp.Error(err)
+		}
+
+		if env == nil || env.Data == nil || len(env.Data.Workers) == 0 {
+			return shttp.NotFound()
+		}
+
+		names := env.Data.Workers.Names()
+
+		if data.Name != "" {
+			if env.Data.Workers[data.Name] == "" {
+				return shttp.NotFound()
+			}
+
+			names = []string{data.Name}
+		}
+
+		for _, name := range names {
+			if err := integrations.SetWorkerStopped(req.Context(), env.ID, name, action == integrations.WorkerActionStop); err != nil {
+				return shttp.Error(err)
+			}
+		}
+
+		if err := rediscache.Broadcast(rediscache.EventWorkers, rediscache.EncodePayload(action, env.ID.String(), req.App.DisplayName, env.Name, data.Name)); err != nil {
+			return shttp.Error(err)
+		}
+
+		return shttp.OK()
+	}
+}
@@ -0,0 +1,123 @@
+package buildconfhandlers_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf/buildconfhandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stormkit-io/stormkit-io/src/mocks"
+)
+
+type HandlerEnvWorkersSuite struct {
+	suite.Suite
+	*factory.Factory
+
+	conn databasetest.TestDB
+	usr  *factory.MockUser
+	app  *factory.MockApp
+	env  *factory.MockEnv
+}
+
+func (s *HandlerEnvWorkersSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+	s.usr = s.MockUser()
+	s.app = s.MockApp(s.usr)
+	s.env = s.MockEnv(s.app, map[string]any{
+		"Data": &buildconf.BuildConf{
+			ServerCmd: "node server.js",
+			Workers: buildconf.Workers{
+				"consumer": "node consumer.js",
+				"mailer":   "node mailer.js",
+			},
+		},
+	})
+}
+
+func (s *HandlerEnvWorkersSuite) AfterTest(_, _ string) {
+	s.NoError(integrations.ClearStoppedWorkers(context.Background(), s.env.ID))
+	s.conn.CloseTx()
+}
+
+func (s *HandlerEnvWorkersSuite) request(action, name string) shttptest.Response {
+	return shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(buildconfhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/env/workers/"+action,
+		map[string]any{
+			"envId": s.env.ID.String(),
+			"name":  name,
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(s.usr.ID),
+		},
+	)
+}
+
+func (s *HandlerEnvWorkersSuite) Test_Stop_And_Start() {
+	ctx := context.Background()
+
+	response := s.request(integrations.WorkerActionStop, "consumer")
+	s.Equal(http.StatusOK, response.Code)
+
+	stopped, err := integrations.StoppedWorkers(ctx, s.env.ID)
+	s.NoError(err)
+	s.Equal(map[string]bool{"consumer": true}, stopped)
+
+	response = s.request(integrations.WorkerActionStop, "")
+	s.Equal(http.StatusOK, response.Code)
+
+	stopped, err = integrations.StoppedWorkers(ctx, s.env.ID)
+	s.NoError(err)
+	s.Equal(map[string]bool{"consumer": true, "mailer": true}, stopped)
+
+	response = s.request(integrations.WorkerActionStart, "mailer")
+	s.Equal(http.StatusOK, response.Code)
+
+	stopped, err = integrations.StoppedWorkers(ctx, s.env.ID)
+	s.NoError(err)
+	s.Equal(map[string]bool{"consumer": true}, stopped)
+
+	response = s.request(integrations.WorkerActionRestart, "")
+	s.Equal(http.StatusOK, response.Code)
+
+	stopped, err = integrations.StoppedWorkers(ctx, s.env.ID)
+	s.NoError(err)
+	s.Empty(stopped)
+}
+
+func (s *HandlerEnvWorkersSuite) Test_Broadcast() {
+	service := &mocks.MicroServiceInterface{}
+	rediscache.DefaultService = service
+
+	defer func() { rediscache.DefaultService = nil }()
+
+	// All values are sent in a single payload, because Broadcast publishes only one value.
+	payload := rediscache.EncodePayload(integrations.WorkerActionRestart, s.env.ID.String(), s.app.DisplayName, s.env.Name, "mailer")
+	service.On("Broadcast", rediscache.EventWorkers, payload).Return(nil).Once()
+
+	response := s.request(integrations.WorkerActionRestart, "mailer")
+	s.Equal(http.StatusOK, response.Code)
+
+	service.AssertExpectations(s.T())
+}
+
+func (s *HandlerEnvWorkersSuite) Test_NotFound() {
+	response := s.request(integrations.WorkerActionRestart, "unknown")
+	s.Equal(http.StatusNotFound, response.Code)
+}
+
+func TestHandlerEnvWorkers(t *testing.T) {
+	suite.Run(t, &HandlerEnvWorkersSuite{})
+}
@@ -0,0 +1,29 @@
+package buildconfhandlers
+
+import (
+	"net/http"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+)
+
+// DetectFramework is a wrapper around the app method so that it can be mocked in tests.
+var DetectFramework = (*app.App).DetectFramework
+
+// handlerFramework returns the build settings proposed for the framework
+// used by the application. When the branch query parameter is missing,
+// the default branch of the repository is inspected.
+func handlerFramework(req *app.RequestContext) *shttp.Response {
+	preset, err := DetectFramework(req.App, req.Context(), req.Query().Get("branch"))
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	return &shttp.Response{
+		Status: http.StatusOK,
+		Data: map[string]any{
+			"framework": preset,
+		},
+	}
+}
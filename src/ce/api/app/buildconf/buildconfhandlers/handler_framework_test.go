@@ -0,0 +1,98 @@
+package buildconfhandlers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf/buildconfhandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/framework"
+)
+
+type HandlerFrameworkSuite struct {
+	suite.Suite
+	*factory.Factory
+
+	conn databasetest.TestDB
+}
+
+func (s *HandlerFrameworkSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+}
+
+func (s *HandlerFrameworkSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+	buildconfhandlers.DetectFramework = (*app.App).DetectFramework
+}
+
+func (s *HandlerFrameworkSuite) Test_Success() {
+	myApp := s.MockApp(nil)
+	branch := ""
+
+	buildconfhandlers.DetectFramework = func(_ *app.App, _ context.Context, b string) (*framework.Preset, error) {
+		branch = b
+
+		return &framework.Preset{
+			ID:         "vite",
+			Name:       "Vite",
+			BuildCmd:   "npm run build",
+			DistFolder: "dist",
+		}, nil
+	}
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(buildconfhandlers.Services).Router().Handler(),
+		shttp.MethodGet,
+		fmt.Sprintf("/app/%d/framework?branch=feature", myApp.ID),
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(myApp.UserID),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal("feature", branch)
+	s.JSONEq(`{
+		"framework": {
+			"id": "vite",
+			"name": "Vite",
+			"buildCmd": "npm run build",
+			"distFolder": "dist"
+		}
+	}`, response.String())
+}
+
+func (s *HandlerFrameworkSuite) Test_NotDetected() {
+	myApp := s.MockApp(nil)
+
+	buildconfhandlers.DetectFramework = func(*app.App, context.Context, string) (*framework.Preset, error) {
+		return nil, nil
+	}
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(buildconfhandlers.Services).Router().Handler(),
+		shttp.MethodGet,
+		fmt.Sprintf("/app/%d/framework", myApp.ID),
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(myApp.UserID),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{ "framework": null }`, response.String())
+}
+
+func TestHandlerFramework(t *testing.T) {
+	suite.Run(t, &HandlerFrameworkSuite{})
+}
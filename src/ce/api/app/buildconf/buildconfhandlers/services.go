@@ -2,6 +2,7 @@ package buildconfhandlers
 
 import (
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 )
 
@@ -14,7 +15,14 @@ func Services(r *shttp.Router) *shttp.Service {
 		Handler(shttp.MethodGet, "/{env:[0-9a-zA-Z-]+}", app.WithApp(handlerEnv)).
 		Handler(shttp.MethodGet, "", app.WithApp(handlerEnvs))
 
+	s.NewEndpoint("/app/{did:[0-9]+}/framework").
+		Handler(shttp.MethodGet, "", app.WithApp(handlerFramework))
+
 	s.NewEndpoint("/app/env").
+		Handler(shttp.MethodGet, "/services", app.WithApp(handlerEnvServices, &app.Opts{Env: true})).
+		Handler(shttp.MethodPost, "/workers/start", app.WithApp(handlerEnvWorkers(integrations.WorkerActionStart), &app.Opts{Env: true})).
+		Handler(shttp.MethodPost, "/workers/stop", app.WithApp(handlerEnvWorkers(integrations.WorkerActionStop), &app.Opts{Env: true})).
+		Handler(shttp.MethodPost, "/workers/restart", app.WithApp(handlerEnvWorkers(integrations.WorkerActionRestart), &app.Opts{Env: true})).
 		Handler(shttp.MethodDelete, "", app.WithApp(handlerEnvDelete)).
 		Handler(shttp.MethodPost, "", app.WithApp(handlerEnvInsert)).
 		Handler(shttp.MethodPut, "", app.WithApp(handlerEnvUpdate))
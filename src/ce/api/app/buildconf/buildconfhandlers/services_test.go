@@ -17,9 +17,14 @@ func (s *ServicesSuite) Test_Services() {
 
 	handlers := []string{
 		"DELETE:/app/env",
+		"GET:/app/env/services",
 		"GET:/app/{did:[0-9]+}/envs",
 		"GET:/app/{did:[0-9]+}/envs/{env:[0-9a-zA-Z-]+}",
+		"GET:/app/{did:[0-9]+}/framework",
 		"POST:/app/env",
+		"POST:/app/env/workers/restart",
+		"POST:/app/env/workers/start",
+		"POST:/app/env/workers/stop",
 		"PUT:/app/env",
 	}
 
@@ -2,6 +2,7 @@ package buildconf
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"strings"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttperr"
 	"github.com/stormkit-io/stormkit-io/src/lib/types"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
 	null "gopkg.in/guregu/null.v3"
 )
 
@@ -195,6 +197,94 @@ func (env *Env) Validate() *shttperr.ValidationError {
 		}
 	}
 
+	if env.Data != nil {
+		for i, check := range env.Data.HTTPChecks {
+			if rerr := check.Validate(); rerr != nil {
+				err.SetError(fmt.Sprintf("httpChecks.%d", i), rerr.Error())
+			}
+		}
+
+		if env.Data.VulnScan != nil {
+			if rerr := env.Data.VulnScan.Validate(); rerr != nil {
+				err.SetError("vulnScan", rerr.Error())
+			}
+		}
+
+		for i, window := range env.Data.FreezeWindows {
+			if rerr := window.Validate(); rerr != nil {
+				err.SetError(fmt.Sprintf("freezeWindows.%d", i), rerr.Error())
+			}
+		}
+
+		if env.Data.Protection != nil {
+			if rerr := env.Data.Protection.Validate(); rerr != nil {
+				err.SetError("protection", rerr.Error())
+			}
+		}
+
+		if env.Data.AutoRollback != nil {
+			if rerr := env.Data.AutoRollback.Validate(); rerr != nil {
+				err.SetError("autoRollback", rerr.Error())
+			}
+		}
+
+		if env.Data.Retention != nil {
+			if rerr := env.Data.Retention.Validate(); rerr != nil {
+				err.SetError("retention", rerr.Error())
+			}
+		}
+
+		if env.Data.GitCheckout != nil {
+			if rerr := env.Data.GitCheckout.Validate(); rerr != nil {
+				err.SetError("gitCheckout", rerr.Error())
+			}
+		}
+
+		if env.Data.HealthCheck != nil {
+			if rerr := env.Data.HealthCheck.Validate(); rerr != nil {
+				err.SetError("healthCheck", rerr.Error())
+			}
+		}
+
+		if env.Data.RestartPolicy != nil {
+			if rerr := env.Data.RestartPolicy.Validate(); rerr != nil {
+				err.SetError("restartPolicy", rerr.Error())
+			}
+		}
+
+		if env.Data.Replicas != nil {
+			if rerr := env.Data.Replicas.Validate(); rerr != nil {
+				err.SetError("replicas", rerr.Error())
+			}
+		}
+
+		if env.Data.Resources != nil {
+			if rerr := env.Data.Resources.Validate(); rerr != nil {
+				err.SetError("resources", rerr.Error())
+			}
+		}
+
+		if len(env.Data.Workers) > 0 {
+			if rerr := env.Data.Workers.Validate(); rerr != nil {
+				err.SetError("workers", rerr.Error())
+			} else if env.Data.ServerCmd == "" {
+				err.SetError("workers", ErrWorkersRequireServerCmd.Error())
+			}
+		}
+
+		if env.Data.PullRequestEnvs != nil {
+			if rerr := env.Data.PullRequestEnvs.Validate(); rerr != nil {
+				err.SetError("pullRequestEnvs", rerr.Error())
+			}
+		}
+
+		if env.Data.AutoDeployTags != "" {
+			if _, rerr := regexp2.Compile(env.Data.AutoDeployTags, regexp2.IgnoreCase); rerr != nil {
+				err.SetError("autoDeployTags", rerr.Error())
+			}
+		}
+	}
+
 	return err.ToError()
 }
 
@@ -204,6 +294,62 @@ type StatusCheck struct {
 	Description string `json:"description"`
 }
 
+// HTTPCheck is a smoke test that is executed against the deployment
+// preview after the upload. When any of the checks fails, the deployment
+// is not published automatically.
+type HTTPCheck struct {
+	Name          string            `json:"name,omitempty"`
+	Path          string            `json:"path"`                    // Path is the request path, e.g. /api/health
+	Method        string            `json:"method,omitempty"`        // Method is the request method, defaults to GET
+	Headers       map[string]string `json:"headers,omitempty"`       // Headers are the request headers
+	Status        int               `json:"status,omitempty"`        // Status is the expected response status, defaults to 200
+	Body          string            `json:"body,omitempty"`          // Body is a string that the response body has to contain
+	BodyRegex     string            `json:"bodyRegex,omitempty"`     // BodyRegex is a pattern that the response body has to match
+	MaxLatency    int               `json:"maxLatency,omitempty"`    // MaxLatency is the maximum allowed response time in milliseconds
+	ExpectHeaders map[string]string `json:"expectHeaders,omitempty"` // ExpectHeaders are the response headers that have to match
+}
+
+// Validate validates the http check.
+func (c HTTPCheck) Validate() error {
+	if !strings.HasPrefix(c.Path, "/") {
+		return ErrInvalidHTTPCheckPath
+	}
+
+	if c.Status != 0 && (c.Status < 100 || c.Status > 599) {
+		return ErrInvalidHTTPCheckStatus
+	}
+
+	if c.MaxLatency < 0 {
+		return ErrInvalidHTTPCheckLatency
+	}
+
+	if c.BodyRegex != "" {
+		if _, err := regexp.Compile(c.BodyRegex); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// VulnScan configures how the findings of the dependency vulnerability
+// scan are handled. Severities are one of: low | moderate | high | critical.
+type VulnScan struct {
+	FailBuildOn    string `json:"failBuildOn,omitempty"`    // FailBuildOn is the minimum severity that fails the build
+	BlockPublishOn string `json:"blockPublishOn,omitempty"` // BlockPublishOn is the minimum severity that prevents auto publishing the deployment
+}
+
+// Validate validates the vulnerability scan configuration.
+func (v VulnScan) Validate() error {
+	for _, severity := range []string{v.FailBuildOn, v.BlockPublishOn} {
+		if severity != "" && !osv.IsValidSeverity(severity) {
+			return ErrInvalidVulnSeverity
+		}
+	}
+
+	return nil
+}
+
 // BuildConf is the struct that represents the JSON data
 type BuildConf struct {
 	PreviewLinks  null.Bool            `json:"previewLinks,omitempty"`  // Whether preview links are enabled or not.
@@ -222,6 +368,64 @@ type BuildConf struct {
 	ServerCmd     string               `json:"serverCmd,omitempty"`     // The command to spawn the server. This is a self-hosted only feature.
 	Vars          map[string]string    `json:"vars,omitempty"`          // The environment variables that will be injected to the application.
 	StatusChecks  []StatusCheck        `json:"statusChecks,omitempty"`  // StatusChecks is an array of commands that will be executed after the deployment is complete.
+	HTTPChecks    []HTTPCheck          `json:"httpChecks,omitempty"`    // HTTPChecks is an array of smoke tests that will be executed against the deployment preview.
+	SecretVars    []string             `json:"secretVars,omitempty"`    // SecretVars is the list of environment variable names whose values are masked in the logs.
+
+	// VulnScan configures the thresholds of the dependency vulnerability scan.
+	VulnScan *VulnScan `json:"vulnScan,omitempty"`
+
+	// FreezeWindows block auto deploys and publishes during release freezes.
+	FreezeWindows []FreezeWindow `json:"freezeWindows,omitempty"`
+
+	// Protection requires deployments to be approved before they are published.
+	Protection *Protection `json:"protection,omitempty"`
+
+	// AutoRollback republishes the previous deployment when the published one is unhealthy.
+	AutoRollback *AutoRollback `json:"autoRollback,omitempty"`
+
+	// Retention configures when the artifacts of old deployments are removed.
+	Retention *Retention `json:"retention,omitempty"`
+
+	// GitCheckout configures submodules, Git LFS and sparse checkout.
+	GitCheckout *GitCheckout `json:"gitCheckout,omitempty"`
+
+	// AutoDeployTags is a regexp config that specifies which pushed
+	// tags trigger a deployment. When empty, tag pushes are ignored.
+	AutoDeployTags string `json:"autoDeployTags,omitempty"`
+
+	// HealthCheck probes the server started with the server command.
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+
+	// RestartPolicy restarts the server started with the server command when it crashes.
+	RestartPolicy *RestartPolicy `json:"restartPolicy,omitempty"`
+
+	// Replicas spawns multiple processes for the server command and load balances the requests.
+	Replicas *Replicas `json:"replicas,omitempty"`
+
+	// Resources limits the memory and cpu of the processes started with the server command.
+	Resources *Resources `json:"resources,omitempty"`
+
+	// Workers are long-running processes, such as queue consumers, that run alongside the server command.
+	Workers Workers `json:"workers,omitempty"`
+
+	// PullRequestEnvs derives an ephemeral environment for each pull request from this environment.
+	PullRequestEnvs *PullRequestEnvs `json:"pullRequestEnvs,omitempty"`
+
+	// PullRequestNumber is set for the ephemeral environments of pull requests.
+	PullRequestNumber int64 `json:"pullRequestNumber,omitempty"`
+}
+
+// Secrets returns the values of the environment variables that are marked as secret.
+func (bc *BuildConf) Secrets() []string {
+	secrets := []string{}
+
+	for _, name := range bc.SecretVars {
+		if value := bc.Vars[name]; value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+
+	return secrets
 }
 
 type InterpolatedVarsOpts struct {
@@ -47,6 +47,68 @@ func (s *EnvModelSuite) TestConfig_Validation() {
 	s.Equal(res.String(), exp)
 }
 
+func (s *EnvModelSuite) TestConfig_Validation_HTTPChecks() {
+	config := &buildconf.Env{
+		Env:    "staging",
+		Branch: "main",
+		Data: &buildconf.BuildConf{
+			HTTPChecks: []buildconf.HTTPCheck{
+				{Path: "/api/health", Status: 200, BodyRegex: "ok|healthy"},
+				{Path: "api/health"},
+				{Path: "/", Status: 99},
+			},
+		},
+	}
+
+	res := shttp.Error(config.Validate())
+	exp := fmt.Sprintf(
+		`{"errors":{"httpChecks.1":"%s","httpChecks.2":"%s"}}`,
+		buildconf.ErrInvalidHTTPCheckPath.Error(),
+		buildconf.ErrInvalidHTTPCheckStatus.Error(),
+	)
+
+	s.Equal(exp, res.String())
+}
+
+func (s *EnvModelSuite) TestConfig_Validation_VulnScan() {
+	config := &buildconf.Env{
+		Env:    "staging",
+		Branch: "main",
+		Data: &buildconf.BuildConf{
+			VulnScan: &buildconf.VulnScan{
+				FailBuildOn:    "critical",
+				BlockPublishOn: "severe",
+			},
+		},
+	}
+
+	res := shttp.Error(config.Validate())
+	exp := fmt.Sprintf(`{"errors":{"vulnScan":"%s"}}`, buildconf.ErrInvalidVulnSeverity.Error())
+
+	s.Equal(exp, res.String())
+
+	config.Data.VulnScan.BlockPublishOn = "high"
+	s.Nil(config.Validate())
+}
+
+func (s *EnvModelSuite) TestConfig_Validation_Workers() {
+	config := &buildconf.Env{
+		Env:    "staging",
+		Branch: "main",
+		Data: &buildconf.BuildConf{
+			Workers: buildconf.Workers{"consumer": "node consumer.js"},
+		},
+	}
+
+	res := shttp.Error(config.Validate())
+	exp := fmt.Sprintf(`{"errors":{"workers":"%s"}}`, buildconf.ErrWorkersRequireServerCmd.Error())
+
+	s.Equal(exp, res.String())
+
+	config.Data.ServerCmd = "node server.js"
+	s.Nil(config.Validate())
+}
+
 func TestEnvModelSuite(t *testing.T) {
 	suite.Run(t, &EnvModelSuite{})
 }
@@ -17,6 +17,7 @@ type statement struct {
 	markAsDeleted       string
 	insertConfig        string
 	updateConfig        string
+	proposeBuildConf    string
 	isMember            string
 }
 
@@ -116,6 +117,22 @@ var stmt = &statement{
 			env_id = $8;
 	`,
 
+	// proposeBuildConf merges the proposed settings into the build configuration,
+	// unless any of the build settings has been set in the meantime.
+	proposeBuildConf: `
+		UPDATE
+			apps_build_conf
+		SET
+			build_conf = COALESCE(build_conf, '{}'::jsonb) || $2::jsonb ||
+				CASE WHEN COALESCE(build_conf->>'apiFolder', '') = '' THEN $3::jsonb ELSE '{}'::jsonb END
+		WHERE
+			env_id = $1 AND
+			deleted_at IS NULL AND
+			COALESCE(build_conf->>'buildCmd', '') = '' AND
+			COALESCE(build_conf->>'distFolder', '') = '' AND
+			COALESCE(build_conf->>'serverCmd', '') = '';
+	`,
+
 	isMember: fmt.Sprintf(`
 		SELECT COUNT(*) FROM %s e
 		LEFT JOIN %s a ON a.app_id = e.app_id
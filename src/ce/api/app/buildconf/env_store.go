@@ -96,6 +96,51 @@ func (s *Store) Update(ctx context.Context, c *Env) error {
 	return err
 }
 
+// ProposeBuildSettings sets the build settings of the environment, unless the
+// build command, the dist folder or the server command has been set already.
+// The api folder is only set when it is empty. It returns true when the
+// settings were updated.
+func (s *Store) ProposeBuildSettings(ctx context.Context, envID types.ID, buildCmd, distFolder, serverCmd, apiFolder string) (bool, error) {
+	settings := map[string]string{}
+	api := map[string]string{}
+
+	for key, value := range map[string]string{"buildCmd": buildCmd, "distFolder": distFolder, "serverCmd": serverCmd} {
+		if value != "" {
+			settings[key] = value
+		}
+	}
+
+	if apiFolder != "" {
+		api["apiFolder"] = apiFolder
+	}
+
+	settingsData, err := json.Marshal(settings)
+
+	if err != nil {
+		return false, err
+	}
+
+	apiData, err := json.Marshal(api)
+
+	if err != nil {
+		return false, err
+	}
+
+	result, err := s.Exec(ctx, stmt.proposeBuildConf, envID, settingsData, apiData)
+
+	if err != nil {
+		return false, err
+	}
+
+	rows, err := result.RowsAffected()
+
+	if err != nil {
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
 // ListEnvironments list all the configs for the given application id.
 func (s *Store) ListEnvironments(ctx context.Context, appID types.ID) (cnfs []*Env, err error) {
 	rows, err := s.Query(ctx, stmt.selectByAppID, appID)
@@ -20,4 +20,35 @@ var (
 	ErrInvalidPercentage      = shttperr.New(http.StatusBadRequest, "The sum of percentages should be 100 in order to publish.", "invalid-percentage")
 	ErrLambdaAlreadyExists    = shttperr.New(http.StatusBadRequest, "Lambda function name already exists.", "lambda-already-exists")
 	ErrDuplicateEnvName       = shttperr.New(http.StatusBadRequest, "Environment name is duplicate. Choose a different name.", "duplicate-env")
+
+	ErrInvalidHTTPCheckPath    = shttperr.New(http.StatusBadRequest, "HTTP check path has to start with a slash (/).", "invalid-http-check")
+	ErrInvalidHTTPCheckStatus  = shttperr.New(http.StatusBadRequest, "HTTP check status has to be a valid HTTP status code.", "invalid-http-check")
+	ErrInvalidHTTPCheckLatency = shttperr.New(http.StatusBadRequest, "HTTP check max latency cannot be negative.", "invalid-http-check")
+
+	ErrInvalidVulnSeverity = shttperr.New(http.StatusBadRequest, "Vulnerability severity has to be one of: low, moderate, high, critical.", "invalid-vuln-severity")
+
+	ErrInvalidFreezeWindowCron     = shttperr.New(http.StatusBadRequest, "Freeze window requires a valid cron expression and a positive duration.", "invalid-freeze-window")
+	ErrInvalidFreezeWindowRange    = shttperr.New(http.StatusBadRequest, "Freeze window end date has to be after the start date.", "invalid-freeze-window")
+	ErrInvalidFreezeWindowTimezone = shttperr.New(http.StatusBadRequest, "Freeze window timezone is not valid.", "invalid-freeze-window")
+
+	ErrInvalidRequiredApprovals = shttperr.New(http.StatusBadRequest, "Protected environments require at least one approval.", "invalid-protection")
+	ErrInvalidApproverRole      = shttperr.New(http.StatusBadRequest, "Approver role has to be one of: owner, admin, developer.", "invalid-protection")
+
+	ErrInvalidAutoRollback = shttperr.New(http.StatusBadRequest, "Auto rollback thresholds cannot be negative and the error rate cannot exceed 100.", "invalid-auto-rollback")
+
+	ErrInvalidRetention = shttperr.New(http.StatusBadRequest, "Retention values cannot be negative.", "invalid-retention")
+
+	ErrInvalidGitCheckout = shttperr.New(http.StatusBadRequest, "Sparse checkout paths and LFS patterns must be relative paths within the repository.", "invalid-git-checkout")
+
+	ErrInvalidHealthCheck   = shttperr.New(http.StatusBadRequest, "Health check type has to be http or tcp, the path has to start with a slash and the timeout has to be shorter than the interval.", "invalid-health-check")
+	ErrInvalidRestartPolicy = shttperr.New(http.StatusBadRequest, "Restart policy has to be one of: always, on-failure, never. Backoff values cannot be negative.", "invalid-restart-policy")
+
+	ErrInvalidReplicas = shttperr.New(http.StatusBadRequest, "Replica count has to be between 1 and 16 and the strategy has to be one of: round-robin, least-connections.", "invalid-replicas")
+
+	ErrInvalidResources = shttperr.New(http.StatusBadRequest, "Memory limit has to be at least 32 MB and the cpu limit has to be between 0.01 and 64 cores.", "invalid-resources")
+
+	ErrInvalidWorkers          = shttperr.New(http.StatusBadRequest, "Worker names can only contain lowercase letters, numbers, dashes and underscores, commands cannot be empty and at most 10 workers are allowed.", "invalid-workers")
+	ErrWorkersRequireServerCmd = shttperr.New(http.StatusBadRequest, "Workers require a server command.", "workers-require-server-cmd")
+
+	ErrInvalidPullRequestEnvVar = shttperr.New(http.StatusBadRequest, "Pull request environment variable names can only contain alphanumeric characters and underscores.", "invalid-pull-request-env")
 )
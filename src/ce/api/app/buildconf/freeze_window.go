@@ -0,0 +1,77 @@
+package buildconf
+
+import (
+	"time"
+
+	"github.com/adhocore/gronx"
+)
+
+// FreezeWindow is a period of time during which auto deploys and publishes
+// to the environment are blocked. A window is either recurring (Cron and Duration)
+// or a one-off date range (From and To).
+type FreezeWindow struct {
+	Name     string `json:"name,omitempty"`
+	Cron     string `json:"cron,omitempty"`     // Cron marks the start of a recurring window, e.g. 0 14 * * 5 for Friday afternoons
+	Duration int    `json:"duration,omitempty"` // Duration is the length of a recurring window in minutes
+	From     int64  `json:"from,omitempty"`     // From is the unix timestamp that starts a one-off window
+	To       int64  `json:"to,omitempty"`       // To is the unix timestamp that ends a one-off window
+	Timezone string `json:"timezone,omitempty"` // Timezone is used to evaluate the cron expression, defaults to UTC
+}
+
+// Validate validates the freeze window.
+func (w FreezeWindow) Validate() error {
+	if _, err := time.LoadLocation(w.Timezone); err != nil {
+		return ErrInvalidFreezeWindowTimezone
+	}
+
+	if w.Cron != "" {
+		if !gronx.New().IsValid(w.Cron) || w.Duration <= 0 {
+			return ErrInvalidFreezeWindowCron
+		}
+
+		return nil
+	}
+
+	if w.From <= 0 || w.To <= w.From {
+		return ErrInvalidFreezeWindowRange
+	}
+
+	return nil
+}
+
+// IsActive returns true when the given time falls into the freeze window.
+func (w FreezeWindow) IsActive(now time.Time) bool {
+	if w.Cron == "" {
+		return w.From > 0 && now.Unix() >= w.From && now.Unix() < w.To
+	}
+
+	loc, err := time.LoadLocation(w.Timezone)
+
+	if err != nil {
+		return false
+	}
+
+	now = now.In(loc)
+	start, err := gronx.PrevTickBefore(w.Cron, now, true)
+
+	if err != nil {
+		return false
+	}
+
+	return now.Before(start.Add(time.Duration(w.Duration) * time.Minute))
+}
+
+// ActiveFreezeWindow returns the first freeze window that is active at the given time.
+func (bc *BuildConf) ActiveFreezeWindow(now time.Time) *FreezeWindow {
+	if bc == nil {
+		return nil
+	}
+
+	for _, w := range bc.FreezeWindows {
+		if w.IsActive(now) {
+			return &w
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package buildconf_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stretchr/testify/suite"
+)
+
+type FreezeWindowSuite struct {
+	suite.Suite
+}
+
+func (s *FreezeWindowSuite) Test_Validate() {
+	s.NoError(buildconf.FreezeWindow{Cron: "0 14 * * 5", Duration: 600}.Validate())
+	s.NoError(buildconf.FreezeWindow{From: 1766620800, To: 1767225600}.Validate())
+	s.ErrorIs(buildconf.FreezeWindow{Cron: "0 14 * * 5"}.Validate(), buildconf.ErrInvalidFreezeWindowCron)
+	s.ErrorIs(buildconf.FreezeWindow{Cron: "invalid", Duration: 60}.Validate(), buildconf.ErrInvalidFreezeWindowCron)
+	s.ErrorIs(buildconf.FreezeWindow{From: 1767225600, To: 1766620800}.Validate(), buildconf.ErrInvalidFreezeWindowRange)
+	s.ErrorIs(buildconf.FreezeWindow{}.Validate(), buildconf.ErrInvalidFreezeWindowRange)
+	s.ErrorIs(buildconf.FreezeWindow{Cron: "0 14 * * 5", Duration: 60, Timezone: "Mars/Olympus"}.Validate(), buildconf.ErrInvalidFreezeWindowTimezone)
+}
+
+func (s *FreezeWindowSuite) Test_IsActive_Cron() {
+	// Fridays from 14:00 until midnight in Berlin
+	w := buildconf.FreezeWindow{Cron: "0 14 * * 5", Duration: 600, Timezone: "Europe/Berlin"}
+
+	// 2026-10-16 is a Friday, Berlin is UTC+2
+	s.False(w.IsActive(time.Date(2026, 10, 16, 11, 59, 0, 0, time.UTC)))
+	s.True(w.IsActive(time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)))
+	s.True(w.IsActive(time.Date(2026, 10, 16, 21, 59, 0, 0, time.UTC)))
+	s.False(w.IsActive(time.Date(2026, 10, 16, 22, 0, 0, 0, time.UTC)))
+	s.False(w.IsActive(time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)))
+}
+
+func (s *FreezeWindowSuite) Test_IsActive_Range() {
+	w := buildconf.FreezeWindow{From: 1766620800, To: 1767225600}
+
+	s.False(w.IsActive(time.Unix(1766620799, 0)))
+	s.True(w.IsActive(time.Unix(1766620800, 0)))
+	s.False(w.IsActive(time.Unix(1767225600, 0)))
+}
+
+func (s *FreezeWindowSuite) Test_ActiveFreezeWindow() {
+	bc := &buildconf.BuildConf{
+		FreezeWindows: []buildconf.FreezeWindow{
+			{Name: "holidays", From: 1766620800, To: 1767225600},
+			{Name: "friday", Cron: "0 14 * * 5", Duration: 600},
+		},
+	}
+
+	s.Equal("friday", bc.ActiveFreezeWindow(time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)).Name)
+	s.Nil(bc.ActiveFreezeWindow(time.Date(2026, 10, 15, 15, 0, 0, 0, time.UTC)))
+
+	var nilConf *buildconf.BuildConf
+	s.Nil(nilConf.ActiveFreezeWindow(time.Now()))
+}
+
+func TestFreezeWindow(t *testing.T) {
+	suite.Run(t, &FreezeWindowSuite{})
+}
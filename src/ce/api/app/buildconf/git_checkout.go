@@ -0,0 +1,29 @@
+package buildconf
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var gitPathPattern = regexp.MustCompile(`^[a-zA-Z0-9_./*@+-]+$`)
+
+// GitCheckout configures how the runner checks out the repository.
+type GitCheckout struct {
+	Submodules  bool     `json:"submodules,omitempty"`  // Submodules checks out the submodules recursively
+	LFS         bool     `json:"lfs,omitempty"`         // LFS pulls the Git LFS objects after the checkout
+	LFSInclude  []string `json:"lfsInclude,omitempty"`  // LFSInclude limits the pulled LFS objects to the given patterns
+	SparsePaths []string `json:"sparsePaths,omitempty"` // SparsePaths limits the working tree to the given directories
+}
+
+// Validate validates the checkout options. Paths and patterns are passed
+// to git as they are, therefore they have to be plain relative paths.
+func (g *GitCheckout) Validate() error {
+	for _, p := range slices.Concat(g.SparsePaths, g.LFSInclude) {
+		if !gitPathPattern.MatchString(p) || strings.HasPrefix(p, "-") || strings.HasPrefix(p, "/") || strings.Contains(p, "..") {
+			return ErrInvalidGitCheckout
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package buildconf_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stretchr/testify/suite"
+)
+
+type GitCheckoutSuite struct {
+	suite.Suite
+}
+
+func (s *GitCheckoutSuite) Test_Validate() {
+	s.NoError((&buildconf.GitCheckout{}).Validate())
+	s.NoError((&buildconf.GitCheckout{
+		Submodules:  true,
+		LFS:         true,
+		LFSInclude:  []string{"assets/**/*.png", "fonts/*"},
+		SparsePaths: []string{"apps/web", "packages/ui"},
+	}).Validate())
+
+	invalid := []string{"", "../secrets", "/etc", "--upload-pack=touch", "apps/web; rm -rf /", "a,b"}
+
+	for _, p := range invalid {
+		s.ErrorIs((&buildconf.GitCheckout{SparsePaths: []string{p}}).Validate(), buildconf.ErrInvalidGitCheckout, p)
+		s.ErrorIs((&buildconf.GitCheckout{LFSInclude: []string{p}}).Validate(), buildconf.ErrInvalidGitCheckout, p)
+	}
+}
+
+func TestGitCheckout(t *testing.T) {
+	suite.Run(t, &GitCheckoutSuite{})
+}
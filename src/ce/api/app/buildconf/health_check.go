@@ -0,0 +1,119 @@
+package buildconf
+
+import (
+	"strings"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+)
+
+const (
+	defaultHealthCheckInterval  = 10
+	defaultHealthCheckTimeout   = 2
+	defaultHealthCheckThreshold = 3
+	defaultRestartMaxRestarts   = 5
+	defaultRestartBackoff       = 1
+	defaultRestartMaxBackoff    = 60
+)
+
+// HealthCheck configures the liveness probe of the server started with the server command.
+// This is a self-hosted only feature.
+type HealthCheck struct {
+	Type             string `json:"type"`                       // Type is either http or tcp
+	Path             string `json:"path,omitempty"`             // Path is requested by http health checks, defaults to /
+	Interval         int    `json:"interval,omitempty"`         // Interval is the number of seconds between two probes, defaults to 10
+	Timeout          int    `json:"timeout,omitempty"`          // Timeout is the number of seconds after which a probe fails, defaults to 2
+	FailureThreshold int    `json:"failureThreshold,omitempty"` // FailureThreshold is the number of consecutive failures before restarting, defaults to 3
+}
+
+// Validate validates the health check.
+func (hc *HealthCheck) Validate() error {
+	if hc.Type != integrations.HealthCheckHTTP && hc.Type != integrations.HealthCheckTCP {
+		return ErrInvalidHealthCheck
+	}
+
+	if hc.Path != "" && (hc.Type != integrations.HealthCheckHTTP || !strings.HasPrefix(hc.Path, "/")) {
+		return ErrInvalidHealthCheck
+	}
+
+	if hc.Interval < 0 || hc.Timeout < 0 || hc.FailureThreshold < 0 {
+		return ErrInvalidHealthCheck
+	}
+
+	if hc.Interval > 0 && hc.Timeout >= hc.Interval {
+		return ErrInvalidHealthCheck
+	}
+
+	return nil
+}
+
+// Options returns the health check options of the process manager.
+func (hc *HealthCheck) Options() *integrations.HealthCheck {
+	if hc == nil {
+		return nil
+	}
+
+	opts := &integrations.HealthCheck{
+		Type:             hc.Type,
+		Path:             hc.Path,
+		Interval:         time.Duration(withDefault(hc.Interval, defaultHealthCheckInterval)) * time.Second,
+		Timeout:          time.Duration(withDefault(hc.Timeout, defaultHealthCheckTimeout)) * time.Second,
+		FailureThreshold: withDefault(hc.FailureThreshold, defaultHealthCheckThreshold),
+	}
+
+	if opts.Path == "" {
+		opts.Path = "/"
+	}
+
+	return opts
+}
+
+// RestartPolicy configures how the server started with the server command is restarted
+// when it crashes or fails its health checks. This is a self-hosted only feature.
+type RestartPolicy struct {
+	Policy      string `json:"policy"`                // Policy is one of always, on-failure or never
+	MaxRestarts int    `json:"maxRestarts,omitempty"` // MaxRestarts is the number of consecutive restarts before giving up, defaults to 5
+	Backoff     int    `json:"backoff,omitempty"`     // Backoff is the number of seconds before the first restart, defaults to 1
+	MaxBackoff  int    `json:"maxBackoff,omitempty"`  // MaxBackoff is the maximum number of seconds between two restarts, defaults to 60
+}
+
+// Validate validates the restart policy.
+func (rp *RestartPolicy) Validate() error {
+	switch rp.Policy {
+	case integrations.RestartAlways, integrations.RestartOnFailure, integrations.RestartNever:
+	default:
+		return ErrInvalidRestartPolicy
+	}
+
+	if rp.MaxRestarts < 0 || rp.Backoff < 0 || rp.MaxBackoff < 0 {
+		return ErrInvalidRestartPolicy
+	}
+
+	if rp.MaxBackoff > 0 && rp.Backoff > rp.MaxBackoff {
+		return ErrInvalidRestartPolicy
+	}
+
+	return nil
+}
+
+// Options returns the restart policy options of the process manager.
+func (rp *RestartPolicy) Options() *integrations.RestartPolicy {
+	if rp == nil {
+		return nil
+	}
+
+	return &integrations.RestartPolicy{
+		Policy:      rp.Policy,
+		MaxRestarts: withDefault(rp.MaxRestarts, defaultRestartMaxRestarts),
+		Backoff:     time.Duration(withDefault(rp.Backoff, defaultRestartBackoff)) * time.Second,
+		MaxBackoff:  time.Duration(withDefault(rp.MaxBackoff, defaultRestartMaxBackoff)) * time.Second,
+	}
+}
+
+func withDefault(value, def int) int {
+	if value == 0 {
+		return def
+	}
+
+	return value
+}
@@ -0,0 +1,63 @@
+package buildconf_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stretchr/testify/suite"
+)
+
+type HealthCheckSuite struct {
+	suite.Suite
+}
+
+func (s *HealthCheckSuite) Test_Validate() {
+	s.NoError((&buildconf.HealthCheck{Type: "http", Path: "/health"}).Validate())
+	s.NoError((&buildconf.HealthCheck{Type: "tcp", Interval: 5, Timeout: 1}).Validate())
+
+	invalid := []*buildconf.HealthCheck{
+		{Type: "grpc"},
+		{Type: "http", Path: "health"},
+		{Type: "tcp", Path: "/health"},
+		{Type: "http", Interval: 2, Timeout: 2},
+		{Type: "http", FailureThreshold: -1},
+	}
+
+	for _, hc := range invalid {
+		s.ErrorIs(hc.Validate(), buildconf.ErrInvalidHealthCheck)
+	}
+
+	s.NoError((&buildconf.RestartPolicy{Policy: "on-failure"}).Validate())
+	s.ErrorIs((&buildconf.RestartPolicy{Policy: "sometimes"}).Validate(), buildconf.ErrInvalidRestartPolicy)
+	s.ErrorIs((&buildconf.RestartPolicy{Policy: "always", MaxRestarts: -1}).Validate(), buildconf.ErrInvalidRestartPolicy)
+	s.ErrorIs((&buildconf.RestartPolicy{Policy: "always", Backoff: 10, MaxBackoff: 5}).Validate(), buildconf.ErrInvalidRestartPolicy)
+}
+
+func (s *HealthCheckSuite) Test_Options() {
+	var hc *buildconf.HealthCheck
+	var rp *buildconf.RestartPolicy
+
+	s.Nil(hc.Options())
+	s.Nil(rp.Options())
+
+	s.Equal(&integrations.HealthCheck{
+		Type:             "http",
+		Path:             "/",
+		Interval:         10 * time.Second,
+		Timeout:          2 * time.Second,
+		FailureThreshold: 3,
+	}, (&buildconf.HealthCheck{Type: "http"}).Options())
+
+	s.Equal(&integrations.RestartPolicy{
+		Policy:      "always",
+		MaxRestarts: 10,
+		Backoff:     time.Second,
+		MaxBackoff:  time.Minute,
+	}, (&buildconf.RestartPolicy{Policy: "always", MaxRestarts: 10}).Options())
+}
+
+func TestHealthCheck(t *testing.T) {
+	suite.Run(t, &HealthCheckSuite{})
+}
@@ -0,0 +1,54 @@
+package buildconf
+
+// Approver roles ordered from the most to the least privileged.
+// They match the team roles.
+var approverRoles = []string{"owner", "admin", "developer"}
+
+// Protection marks an environment as protected. Deployments have to be approved
+// by the given number of team members before they can be published.
+type Protection struct {
+	RequiredApprovals int    `json:"requiredApprovals"`
+	ApproverRole      string `json:"approverRole"` // ApproverRole is the least privileged team role that can approve, defaults to owner
+}
+
+// Validate validates the protection settings.
+func (p *Protection) Validate() error {
+	if p.RequiredApprovals < 1 {
+		return ErrInvalidRequiredApprovals
+	}
+
+	if p.ApproverRole != "" && roleRank(p.ApproverRole) == -1 {
+		return ErrInvalidApproverRole
+	}
+
+	return nil
+}
+
+// CanApprove returns true when the given team role is allowed to approve deployments.
+func (p *Protection) CanApprove(role string) bool {
+	rank := roleRank(role)
+	return rank != -1 && rank <= roleRank(p.approverRole())
+}
+
+func (p *Protection) approverRole() string {
+	if p.ApproverRole == "" {
+		return approverRoles[0]
+	}
+
+	return p.ApproverRole
+}
+
+func roleRank(role string) int {
+	for i, r := range approverRoles {
+		if r == role {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// IsProtected returns true when deployments require an approval before being published.
+func (bc *BuildConf) IsProtected() bool {
+	return bc != nil && bc.Protection != nil && bc.Protection.RequiredApprovals > 0
+}
@@ -0,0 +1,43 @@
+package buildconf_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProtectionSuite struct {
+	suite.Suite
+}
+
+func (s *ProtectionSuite) Test_Validate() {
+	s.NoError((&buildconf.Protection{RequiredApprovals: 1}).Validate())
+	s.NoError((&buildconf.Protection{RequiredApprovals: 2, ApproverRole: "developer"}).Validate())
+	s.ErrorIs((&buildconf.Protection{}).Validate(), buildconf.ErrInvalidRequiredApprovals)
+	s.ErrorIs((&buildconf.Protection{RequiredApprovals: 1, ApproverRole: "viewer"}).Validate(), buildconf.ErrInvalidApproverRole)
+}
+
+func (s *ProtectionSuite) Test_CanApprove() {
+	p := &buildconf.Protection{RequiredApprovals: 1}
+	s.True(p.CanApprove("owner"))
+	s.False(p.CanApprove("admin"))
+	s.False(p.CanApprove("developer"))
+
+	p.ApproverRole = "admin"
+	s.True(p.CanApprove("owner"))
+	s.True(p.CanApprove("admin"))
+	s.False(p.CanApprove("developer"))
+	s.False(p.CanApprove(""))
+}
+
+func (s *ProtectionSuite) Test_IsProtected() {
+	var bc *buildconf.BuildConf
+	s.False(bc.IsProtected())
+	s.False((&buildconf.BuildConf{}).IsProtected())
+	s.True((&buildconf.BuildConf{Protection: &buildconf.Protection{RequiredApprovals: 1}}).IsProtected())
+}
+
+func TestProtection(t *testing.T) {
+	suite.Run(t, &ProtectionSuite{})
+}
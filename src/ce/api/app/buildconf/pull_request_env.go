@@ -0,0 +1,91 @@
+package buildconf
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+)
+
+// varNamePattern matches valid environment variable names.
+var varNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// PullRequestEnvs turns the environment into a template for pull requests. Each pull
+// request gets an ephemeral environment, named pr-<number>, that is derived from the
+// template and removed when the pull request is closed or merged.
+type PullRequestEnvs struct {
+	Enabled bool              `json:"enabled"`
+	Vars    map[string]string `json:"vars,omitempty"` // Vars override the variables of the template, e.g. a preview database url
+}
+
+// IsEnabled returns true when ephemeral pull request environments are enabled.
+func (p *PullRequestEnvs) IsEnabled() bool {
+	return p != nil && p.Enabled
+}
+
+// Validate validates the pull request environments configuration.
+func (p *PullRequestEnvs) Validate() error {
+	for name := range p.Vars {
+		if !varNamePattern.MatchString(name) {
+			return ErrInvalidPullRequestEnvVar
+		}
+	}
+
+	return nil
+}
+
+// PullRequestEnvName returns the name of the ephemeral environment of the given pull request.
+func PullRequestEnvName(number int64) string {
+	return fmt.Sprintf("pr-%d", number)
+}
+
+// NewPullRequestEnv returns the ephemeral environment of the given pull request. The build
+// configuration is copied from the template and the override variables are applied. Release
+// controls of the template, such as the protection and freeze windows, are not copied.
+func NewPullRequestEnv(template *Env, number int64, branch string) (*Env, error) {
+	data := &BuildConf{}
+
+	if template.Data != nil {
+		raw, err := json.Marshal(template.Data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(raw, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if data.Vars == nil {
+		data.Vars = map[string]string{}
+	}
+
+	if template.Data != nil && template.Data.PullRequestEnvs != nil {
+		maps.Copy(data.Vars, template.Data.PullRequestEnvs.Vars)
+	}
+
+	data.PullRequestEnvs = nil
+	data.Protection = nil
+	data.FreezeWindows = nil
+	data.AutoRollback = nil
+	data.AutoDeployTags = ""
+	data.PullRequestNumber = number
+
+	name := PullRequestEnvName(number)
+
+	return &Env{
+		AppID:       template.AppID,
+		Name:        name,
+		Env:         name,
+		Branch:      branch,
+		Data:        data,
+		AutoPublish: true,
+		AutoDeploy:  false,
+	}, nil
+}
+
+// IsPullRequestEnv returns true when the environment is the ephemeral environment of the given pull request.
+func (env *Env) IsPullRequestEnv(number int64) bool {
+	return env != nil && env.Data != nil && number != 0 && env.Data.PullRequestNumber == number
+}
@@ -0,0 +1,63 @@
+package buildconf_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type PullRequestEnvSuite struct {
+	suite.Suite
+}
+
+func (s *PullRequestEnvSuite) Test_Validate() {
+	s.NoError((&buildconf.PullRequestEnvs{Enabled: true, Vars: map[string]string{"DATABASE_URL": "postgres://preview"}}).Validate())
+	s.ErrorIs((&buildconf.PullRequestEnvs{Vars: map[string]string{"DATABASE URL": "x"}}).Validate(), buildconf.ErrInvalidPullRequestEnvVar)
+	s.ErrorIs((&buildconf.PullRequestEnvs{Vars: map[string]string{"1_VAR": "x"}}).Validate(), buildconf.ErrInvalidPullRequestEnvVar)
+}
+
+func (s *PullRequestEnvSuite) Test_NewPullRequestEnv() {
+	template := &buildconf.Env{
+		AppID: types.ID(5),
+		Name:  "production",
+		Data: &buildconf.BuildConf{
+			BuildCmd:       "npm run build",
+			AutoDeployTags: "v*",
+			Vars: map[string]string{
+				"NODE_ENV":     "production",
+				"DATABASE_URL": "postgres://production",
+			},
+			PullRequestEnvs: &buildconf.PullRequestEnvs{
+				Enabled: true,
+				Vars:    map[string]string{"DATABASE_URL": "postgres://preview"},
+			},
+			FreezeWindows: []buildconf.FreezeWindow{{}},
+		},
+	}
+
+	env, err := buildconf.NewPullRequestEnv(template, 53, "my-pr-branch")
+
+	s.NoError(err)
+	s.Equal(types.ID(5), env.AppID)
+	s.Equal("pr-53", env.Name)
+	s.Equal("my-pr-branch", env.Branch)
+	s.True(env.AutoPublish)
+	s.False(env.AutoDeploy)
+	s.True(env.IsPullRequestEnv(53))
+	s.False(env.IsPullRequestEnv(54))
+	s.Equal("npm run build", env.Data.BuildCmd)
+	s.Equal(map[string]string{"NODE_ENV": "production", "DATABASE_URL": "postgres://preview"}, env.Data.Vars)
+	s.Nil(env.Data.PullRequestEnvs)
+	s.Nil(env.Data.FreezeWindows)
+	s.Empty(env.Data.AutoDeployTags)
+
+	// The template should not be modified
+	s.Equal("postgres://production", template.Data.Vars["DATABASE_URL"])
+	s.Equal(int64(0), template.Data.PullRequestNumber)
+}
+
+func TestPullRequestEnv(t *testing.T) {
+	suite.Run(t, &PullRequestEnvSuite{})
+}
@@ -0,0 +1,44 @@
+package buildconf
+
+import "github.com/stormkit-io/stormkit-io/src/lib/integrations"
+
+// MaxReplicas is the maximum number of processes that can be spawned for an environment.
+const MaxReplicas = 16
+
+// Replicas configures how many processes are spawned for the server command and how
+// the requests are distributed between them. This is a self-hosted only feature.
+type Replicas struct {
+	Count    int    `json:"count"`              // Count is the number of processes, defaults to 1
+	Strategy string `json:"strategy,omitempty"` // Strategy is either round-robin or least-connections, defaults to round-robin
+}
+
+// Validate validates the replicas configuration.
+func (r *Replicas) Validate() error {
+	if r.Count < 0 || r.Count > MaxReplicas {
+		return ErrInvalidReplicas
+	}
+
+	if r.Strategy != "" && r.Strategy != integrations.BalanceRoundRobin && r.Strategy != integrations.BalanceLeastConnections {
+		return ErrInvalidReplicas
+	}
+
+	return nil
+}
+
+// Options returns the replica options of the process manager.
+func (r *Replicas) Options() *integrations.Replicas {
+	if r == nil || r.Count <= 1 {
+		return nil
+	}
+
+	strategy := r.Strategy
+
+	if strategy == "" {
+		strategy = integrations.BalanceRoundRobin
+	}
+
+	return &integrations.Replicas{
+		Count:    r.Count,
+		Strategy: strategy,
+	}
+}
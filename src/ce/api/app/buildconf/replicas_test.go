@@ -0,0 +1,33 @@
+package buildconf_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stretchr/testify/suite"
+)
+
+type ReplicasSuite struct {
+	suite.Suite
+}
+
+func (s *ReplicasSuite) Test_Validate() {
+	s.NoError((&buildconf.Replicas{Count: 3}).Validate())
+	s.NoError((&buildconf.Replicas{Count: 2, Strategy: "least-connections"}).Validate())
+	s.ErrorIs((&buildconf.Replicas{Count: 17}).Validate(), buildconf.ErrInvalidReplicas)
+	s.ErrorIs((&buildconf.Replicas{Count: -1}).Validate(), buildconf.ErrInvalidReplicas)
+	s.ErrorIs((&buildconf.Replicas{Count: 2, Strategy: "random"}).Validate(), buildconf.ErrInvalidReplicas)
+}
+
+func (s *ReplicasSuite) Test_Options() {
+	var r *buildconf.Replicas
+
+	s.Nil(r.Options())
+	s.Nil((&buildconf.Replicas{Count: 1}).Options())
+	s.Equal(&integrations.Replicas{Count: 4, Strategy: "round-robin"}, (&buildconf.Replicas{Count: 4}).Options())
+}
+
+func TestReplicas(t *testing.T) {
+	suite.Run(t, &ReplicasSuite{})
+}
@@ -0,0 +1,56 @@
+package buildconf
+
+import (
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+)
+
+const (
+	// MinMemoryMB is the minimum memory limit of a server process.
+	MinMemoryMB = 32
+
+	// MaxCPU is the maximum number of cpu cores that can be assigned to a server process.
+	MaxCPU = 64
+
+	// IsolatedUIDBase is added to the app id to compute the dedicated user of an app.
+	IsolatedUIDBase = 100000
+)
+
+// Resources configures the memory and cpu limits of the processes started with the
+// server command. This is a self-hosted only feature.
+type Resources struct {
+	Memory   int     `json:"memory,omitempty"`   // Memory is the limit in megabytes, zero means unlimited
+	CPU      float64 `json:"cpu,omitempty"`      // CPU is the number of cores, zero means unlimited
+	Isolated bool    `json:"isolated,omitempty"` // Isolated runs the processes as a dedicated unprivileged user
+}
+
+// Validate validates the resources configuration.
+func (r *Resources) Validate() error {
+	if r.Memory < 0 || (r.Memory > 0 && r.Memory < MinMemoryMB) {
+		return ErrInvalidResources
+	}
+
+	if r.CPU < 0 || r.CPU > MaxCPU || (r.CPU > 0 && r.CPU < 0.01) {
+		return ErrInvalidResources
+	}
+
+	return nil
+}
+
+// Options returns the resource options of the process manager.
+func (r *Resources) Options(appID types.ID) *integrations.Resources {
+	if r == nil || (r.Memory == 0 && r.CPU == 0 && !r.Isolated) {
+		return nil
+	}
+
+	opts := &integrations.Resources{
+		MemoryBytes: int64(r.Memory) * 1024 * 1024,
+		CPU:         r.CPU,
+	}
+
+	if r.Isolated {
+		opts.UID = uint32(IsolatedUIDBase + int64(appID))
+	}
+
+	return opts
+}
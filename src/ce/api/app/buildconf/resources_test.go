@@ -0,0 +1,35 @@
+package buildconf_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stretchr/testify/suite"
+)
+
+type ResourcesSuite struct {
+	suite.Suite
+}
+
+func (s *ResourcesSuite) Test_Validate() {
+	s.NoError((&buildconf.Resources{}).Validate())
+	s.NoError((&buildconf.Resources{Memory: 512, CPU: 0.5}).Validate())
+	s.ErrorIs((&buildconf.Resources{Memory: 16}).Validate(), buildconf.ErrInvalidResources)
+	s.ErrorIs((&buildconf.Resources{Memory: -1}).Validate(), buildconf.ErrInvalidResources)
+	s.ErrorIs((&buildconf.Resources{CPU: 128}).Validate(), buildconf.ErrInvalidResources)
+	s.ErrorIs((&buildconf.Resources{CPU: 0.001}).Validate(), buildconf.ErrInvalidResources)
+}
+
+func (s *ResourcesSuite) Test_Options() {
+	var r *buildconf.Resources
+
+	s.Nil(r.Options(1))
+	s.Nil((&buildconf.Resources{}).Options(1))
+	s.Equal(&integrations.Resources{MemoryBytes: 256 * 1024 * 1024, CPU: 1.5}, (&buildconf.Resources{Memory: 256, CPU: 1.5}).Options(1))
+	s.Equal(&integrations.Resources{UID: 100025}, (&buildconf.Resources{Isolated: true}).Options(25))
+}
+
+func TestResources(t *testing.T) {
+	suite.Run(t, &ResourcesSuite{})
+}
@@ -0,0 +1,30 @@
+package buildconf
+
+// DefaultRetentionDays is the number of days the artifacts of a deployment are
+// kept when the environment does not configure a retention policy.
+const DefaultRetentionDays = 30
+
+// Retention configures when the artifacts of the environment's deployments are removed.
+// Published, last published and pinned deployments are never removed.
+type Retention struct {
+	KeepLast int `json:"keepLast,omitempty"` // KeepLast is the number of most recent deployments that are kept regardless of their age
+	KeepDays int `json:"keepDays,omitempty"` // KeepDays is the number of days a deployment is kept, defaults to 30
+}
+
+// Validate validates the retention policy.
+func (r *Retention) Validate() error {
+	if r.KeepLast < 0 || r.KeepDays < 0 {
+		return ErrInvalidRetention
+	}
+
+	return nil
+}
+
+// Days returns the number of days a deployment is kept.
+func (r *Retention) Days() int {
+	if r == nil || r.KeepDays == 0 {
+		return DefaultRetentionDays
+	}
+
+	return r.KeepDays
+}
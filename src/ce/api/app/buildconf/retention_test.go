@@ -0,0 +1,30 @@
+package buildconf_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stretchr/testify/suite"
+)
+
+type RetentionSuite struct {
+	suite.Suite
+}
+
+func (s *RetentionSuite) Test_Validate() {
+	s.NoError((&buildconf.Retention{}).Validate())
+	s.NoError((&buildconf.Retention{KeepLast: 10, KeepDays: 7}).Validate())
+	s.ErrorIs((&buildconf.Retention{KeepLast: -1}).Validate(), buildconf.ErrInvalidRetention)
+	s.ErrorIs((&buildconf.Retention{KeepDays: -1}).Validate(), buildconf.ErrInvalidRetention)
+}
+
+func (s *RetentionSuite) Test_Days() {
+	var r *buildconf.Retention
+	s.Equal(buildconf.DefaultRetentionDays, r.Days())
+	s.Equal(buildconf.DefaultRetentionDays, (&buildconf.Retention{KeepLast: 5}).Days())
+	s.Equal(7, (&buildconf.Retention{KeepDays: 7}).Days())
+}
+
+func TestRetention(t *testing.T) {
+	suite.Run(t, &RetentionSuite{})
+}
@@ -0,0 +1,45 @@
+package buildconf
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MaxWorkers is the maximum number of worker process types per environment.
+const MaxWorkers = 10
+
+var workerNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// Workers are Procfile-style process types that run alongside the server command, such as
+// queue consumers. The key is the name of the process type and the value is the command.
+// Workers are started when a deployment is published and keep running regardless of traffic.
+// This is a self-hosted only feature.
+type Workers map[string]string
+
+// Validate validates the worker process types.
+func (w Workers) Validate() error {
+	if len(w) > MaxWorkers {
+		return ErrInvalidWorkers
+	}
+
+	for name, cmd := range w {
+		if !workerNamePattern.MatchString(name) || strings.TrimSpace(cmd) == "" {
+			return ErrInvalidWorkers
+		}
+	}
+
+	return nil
+}
+
+// Names returns the sorted names of the worker process types.
+func (w Workers) Names() []string {
+	names := make([]string, 0, len(w))
+
+	for name := range w {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
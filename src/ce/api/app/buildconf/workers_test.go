@@ -0,0 +1,38 @@
+package buildconf_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stretchr/testify/suite"
+)
+
+type WorkersSuite struct {
+	suite.Suite
+}
+
+func (s *WorkersSuite) Test_Validate() {
+	s.NoError(buildconf.Workers{}.Validate())
+	s.NoError(buildconf.Workers{"consumer": "node consumer.js", "mail_sender-2": "npm run mail"}.Validate())
+	s.ErrorIs(buildconf.Workers{"Consumer": "node consumer.js"}.Validate(), buildconf.ErrInvalidWorkers)
+	s.ErrorIs(buildconf.Workers{"1consumer": "node consumer.js"}.Validate(), buildconf.ErrInvalidWorkers)
+	s.ErrorIs(buildconf.Workers{"consumer": "  "}.Validate(), buildconf.ErrInvalidWorkers)
+
+	workers := buildconf.Workers{}
+
+	for i := 0; i <= buildconf.MaxWorkers; i++ {
+		workers[fmt.Sprintf("worker%d", i)] = "node worker.js"
+	}
+
+	s.ErrorIs(workers.Validate(), buildconf.ErrInvalidWorkers)
+}
+
+func (s *WorkersSuite) Test_Names() {
+	s.Equal([]string{"a", "b", "c"}, buildconf.Workers{"c": "c", "a": "a", "b": "b"}.Names())
+	s.Empty(buildconf.Workers(nil).Names())
+}
+
+func TestWorkers(t *testing.T) {
+	suite.Run(t, &WorkersSuite{})
+}
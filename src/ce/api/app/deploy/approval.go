@@ -0,0 +1,189 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"html"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/mailer"
+	"github.com/stormkit-io/stormkit-io/src/ee/api/team"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+	"gopkg.in/guregu/null.v3"
+)
+
+const (
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+)
+
+// Approval is the decision of a team member on publishing a
+// deployment to a protected environment.
+type Approval struct {
+	ID           types.ID    `json:"id,string"`
+	DeploymentID types.ID    `json:"deploymentId,string"`
+	AppID        types.ID    `json:"appId,string"`
+	EnvID        types.ID    `json:"envId,string"`
+	UserID       types.ID    `json:"userId,string"`
+	UserDisplay  string      `json:"userDisplay"`
+	Status       string      `json:"status"` // approved | rejected
+	Comment      null.String `json:"comment"`
+	CreatedAt    utils.Unix  `json:"createdAt"`
+}
+
+// Approvals is the list of decisions given for a deployment.
+type Approvals []*Approval
+
+// Count returns the number of approvals.
+func (a Approvals) Count() int {
+	count := 0
+
+	for _, approval := range a {
+		if approval.Status == ApprovalStatusApproved {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Rejected returns true when any of the approvers rejected the deployment.
+func (a Approvals) Rejected() bool {
+	for _, approval := range a {
+		if approval.Status == ApprovalStatusRejected {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Satisfies returns true when the approvals are enough to publish to the protected environment.
+func (a Approvals) Satisfies(p *buildconf.Protection) bool {
+	return p == nil || (!a.Rejected() && a.Count() >= p.RequiredApprovals)
+}
+
+// PendingApproval is a successful deployment that is waiting for approvals
+// before it can be published to a protected environment.
+type PendingApproval struct {
+	DeploymentID      types.ID   `json:"deploymentId,string"`
+	Branch            string     `json:"branch"`
+	Commit            CommitInfo `json:"commit"`
+	CreatedAt         utils.Unix `json:"createdAt"`
+	ShouldPublish     bool       `json:"shouldPublish"`
+	RequiredApprovals int        `json:"requiredApprovals"`
+	Approvals         Approvals  `json:"approvals"`
+}
+
+// CheckApprovals returns an error when a deployment that is published to a
+// protected environment has been rejected or does not have enough approvals.
+func CheckApprovals(ctx context.Context, settings []*PublishSettings) error {
+	envs := map[types.ID]*buildconf.Env{}
+	store := NewStore()
+
+	for _, s := range settings {
+		if s.Percentage <= 0 {
+			continue
+		}
+
+		env, ok := envs[s.EnvID]
+
+		if !ok {
+			var err error
+
+			if env, err = buildconf.NewStore().EnvironmentByID(ctx, s.EnvID); err != nil {
+				return err
+			}
+
+			envs[s.EnvID] = env
+		}
+
+		if env == nil || !env.Data.IsProtected() {
+			continue
+		}
+
+		approvals, err := store.Approvals(ctx, s.DeploymentID, s.EnvID)
+
+		if err != nil {
+			return err
+		}
+
+		if approvals.Rejected() {
+			return ErrDeploymentRejected
+		}
+
+		if !approvals.Satisfies(env.Data.Protection) {
+			return ErrDeploymentNotApproved
+		}
+	}
+
+	return nil
+}
+
+// RequestApproval notifies the approvers of the protected environment that
+// the deployment is waiting for their approval. Notifications are sent through
+// the outbound webhooks and, if the environment has a mailer configured, by email.
+func RequestApproval(ctx context.Context, d *Deployment, env *buildconf.Env) error {
+	appl, err := app.NewStore().AppByID(ctx, d.AppID)
+
+	if err != nil || appl == nil {
+		return err
+	}
+
+	cnf := admin.MustConfig()
+	previewURL := cnf.PreviewURL(appl.DisplayName, d.ID.String())
+	logsURL := cnf.DeploymentLogsURL(d.AppID, d.ID)
+
+	for _, wh := range app.NewStore().OutboundWebhooks(ctx, d.AppID) {
+		if wh.TriggerOnApprovalRequested() {
+			wh.Dispatch(app.OutboundWebhookSettings{
+				AppID:                  d.AppID,
+				DeploymentID:           d.ID,
+				DeploymentStatus:       "pending_approval",
+				EnvironmentName:        env.Name,
+				DeploymentEndpoint:     previewURL,
+				DeploymentLogsEndpoint: logsURL,
+			})
+		}
+	}
+
+	mailerConfig, err := mailer.Store().Config(ctx, env.ID)
+
+	if err != nil || mailerConfig == nil {
+		return err
+	}
+
+	members, err := team.NewStore().TeamMembers(ctx, appl.TeamID)
+
+	if err != nil {
+		return err
+	}
+
+	to := []string{}
+
+	for _, m := range members {
+		if m.Status && m.Email != "" && env.Data.Protection.CanApprove(m.Role) {
+			to = append(to, m.Email)
+		}
+	}
+
+	if len(to) == 0 {
+		return nil
+	}
+
+	subject := fmt.Sprintf("Deployment %s is waiting for approval", d.ID.String())
+	body := fmt.Sprintf(
+		"<p>Deployment <b>%s</b> of <b>%s</b> has to be approved before it is published to <b>%s</b>.</p><p>Preview: <a href=\"%s\">%s</a><br />Logs: <a href=\"%s\">%s</a></p>",
+		d.ID.String(), html.EscapeString(appl.DisplayName), html.EscapeString(env.Name), previewURL, previewURL, logsURL, logsURL,
+	)
+
+	if err := mailer.Send(mailerConfig, to, subject, body); err != nil {
+		slog.Errorf("error while sending approval request email: %v", err)
+	}
+
+	return nil
+}
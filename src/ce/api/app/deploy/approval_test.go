@@ -0,0 +1,37 @@
+package deploy_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stretchr/testify/suite"
+)
+
+type ApprovalSuite struct {
+	suite.Suite
+}
+
+func (s *ApprovalSuite) Test_Satisfies() {
+	p := &buildconf.Protection{RequiredApprovals: 2}
+	approvals := deploy.Approvals{
+		{Status: deploy.ApprovalStatusApproved},
+	}
+
+	s.Equal(1, approvals.Count())
+	s.False(approvals.Satisfies(p))
+
+	approvals = append(approvals, &deploy.Approval{Status: deploy.ApprovalStatusApproved})
+	s.Equal(2, approvals.Count())
+	s.True(approvals.Satisfies(p))
+	s.True(approvals.Satisfies(nil))
+
+	// A single rejection blocks the publish
+	approvals = append(approvals, &deploy.Approval{Status: deploy.ApprovalStatusRejected})
+	s.True(approvals.Rejected())
+	s.False(approvals.Satisfies(p))
+}
+
+func TestApproval(t *testing.T) {
+	suite.Run(t, &ApprovalSuite{})
+}
@@ -0,0 +1,123 @@
+package deploy
+
+import (
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+	"gopkg.in/guregu/null.v3"
+)
+
+// QueuedDeployment is a deployment that is waiting in the build queue
+// or that has been dispatched to the deployer service.
+type QueuedDeployment struct {
+	DeploymentID types.ID
+	AppID        types.ID
+	TeamID       types.ID
+	EnvID        types.ID
+	Branch       string
+	Payload      string // Payload is the encrypted deployment message
+	DispatchedAt utils.Unix
+	CreatedAt    utils.Unix
+}
+
+// IsDispatched returns true when the deployment has been sent to the deployer service.
+func (q *QueuedDeployment) IsDispatched() bool {
+	return q.DispatchedAt.Valid
+}
+
+// SupersededDeployment is a deployment that has been cancelled because
+// a newer deployment was started for the same environment and branch.
+type SupersededDeployment struct {
+	DeploymentID types.ID
+	GithubRunID  null.Int
+	IsDispatched bool // IsDispatched is true when the build was already running
+}
+
+// NextInQueue returns the queued deployments that can be dispatched without
+// exceeding the instance, team and app limits. The queue is expected to be
+// sorted in the order the deployments were created.
+func NextInQueue(queue []*QueuedDeployment, limits admin.BuildQueueConfig) []*QueuedDeployment {
+	running := 0
+	runningByTeam := map[types.ID]int{}
+	runningByApp := map[types.ID]int{}
+
+	for _, q := range queue {
+		if q.IsDispatched() {
+			running = running + 1
+			runningByTeam[q.TeamID] = runningByTeam[q.TeamID] + 1
+			runningByApp[q.AppID] = runningByApp[q.AppID] + 1
+		}
+	}
+
+	next := []*QueuedDeployment{}
+
+	for _, q := range queue {
+		if q.IsDispatched() {
+			continue
+		}
+
+		if exceedsLimit(limits.MaxConcurrentBuilds, running) {
+			break
+		}
+
+		// Deployments of other teams and apps may still fit into their limits
+		if (q.TeamID != 0 && exceedsLimit(limits.TeamLimit(q.TeamID), runningByTeam[q.TeamID])) ||
+			exceedsLimit(limits.AppLimit(q.AppID), runningByApp[q.AppID]) {
+			continue
+		}
+
+		running = running + 1
+		runningByTeam[q.TeamID] = runningByTeam[q.TeamID] + 1
+		runningByApp[q.AppID] = runningByApp[q.AppID] + 1
+		next = append(next, q)
+	}
+
+	return next
+}
+
+// QueuePositions returns the position of the deployments that wait in the queue, keyed
+// by the deployment id. Deployments that wait for a slot of their team or app are ranked
+// among the deployments of the same team or app, the others among the deployments that
+// wait for a slot of the instance.
+func QueuePositions(queue []*QueuedDeployment, limits admin.BuildQueueConfig) map[types.ID]int {
+	runningByTeam := map[types.ID]int{}
+	runningByApp := map[types.ID]int{}
+
+	for _, q := range queue {
+		if q.IsDispatched() {
+			runningByTeam[q.TeamID] = runningByTeam[q.TeamID] + 1
+			runningByApp[q.AppID] = runningByApp[q.AppID] + 1
+		}
+	}
+
+	waiting := 0
+	waitingByTeam := map[types.ID]int{}
+	waitingByApp := map[types.ID]int{}
+	positions := map[types.ID]int{}
+
+	for _, q := range queue {
+		if q.IsDispatched() {
+			continue
+		}
+
+		waitingByTeam[q.TeamID] = waitingByTeam[q.TeamID] + 1
+		waitingByApp[q.AppID] = waitingByApp[q.AppID] + 1
+
+		switch {
+		case q.TeamID != 0 && exceedsLimit(limits.TeamLimit(q.TeamID), runningByTeam[q.TeamID]):
+			positions[q.DeploymentID] = waitingByTeam[q.TeamID]
+		case exceedsLimit(limits.AppLimit(q.AppID), runningByApp[q.AppID]):
+			positions[q.DeploymentID] = waitingByApp[q.AppID]
+		default:
+			waiting = waiting + 1
+			positions[q.DeploymentID] = waiting
+		}
+	}
+
+	return positions
+}
+
+// exceedsLimit returns true when the count reached the limit. A limit of zero means no limit.
+func exceedsLimit(limit, count int) bool {
+	return limit > 0 && count >= limit
+}
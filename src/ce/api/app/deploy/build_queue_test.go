@@ -0,0 +1,89 @@
+package deploy_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type BuildQueueSuite struct {
+	suite.Suite
+}
+
+func (s *BuildQueueSuite) queued(id, teamID, appID types.ID, dispatched bool) *deploy.QueuedDeployment {
+	q := &deploy.QueuedDeployment{DeploymentID: id, TeamID: teamID, AppID: appID}
+
+	if dispatched {
+		q.DispatchedAt = utils.NewUnix()
+	}
+
+	return q
+}
+
+func (s *BuildQueueSuite) ids(queue []*deploy.QueuedDeployment) []types.ID {
+	ids := []types.ID{}
+
+	for _, q := range queue {
+		ids = append(ids, q.DeploymentID)
+	}
+
+	return ids
+}
+
+func (s *BuildQueueSuite) Test_NextInQueue_InstanceLimit() {
+	queue := []*deploy.QueuedDeployment{
+		s.queued(1, 1, 1, true),
+		s.queued(2, 2, 2, false),
+		s.queued(3, 3, 3, false),
+		s.queued(4, 4, 4, false),
+	}
+
+	s.Equal([]types.ID{2, 3}, s.ids(deploy.NextInQueue(queue, admin.BuildQueueConfig{MaxConcurrentBuilds: 3})))
+	s.Equal([]types.ID{}, s.ids(deploy.NextInQueue(queue, admin.BuildQueueConfig{MaxConcurrentBuilds: 1})))
+	s.Equal([]types.ID{2, 3, 4}, s.ids(deploy.NextInQueue(queue, admin.BuildQueueConfig{})))
+}
+
+func (s *BuildQueueSuite) Test_NextInQueue_TeamAndAppLimits() {
+	queue := []*deploy.QueuedDeployment{
+		s.queued(1, 1, 1, true),
+		s.queued(2, 1, 2, false), // team 1 is at its limit
+		s.queued(3, 2, 3, false),
+		s.queued(4, 2, 3, false), // app 3 is at its limit after dispatching 3
+		s.queued(5, 2, 4, false),
+		s.queued(6, 3, 5, false), // team 3 has no running builds
+	}
+
+	limits := admin.BuildQueueConfig{
+		MaxConcurrentBuildsPerTeam: 1,
+		MaxConcurrentBuildsPerApp:  1,
+		Teams:                      map[string]int{"2": 2},
+	}
+
+	s.Equal([]types.ID{3, 5, 6}, s.ids(deploy.NextInQueue(queue, limits)))
+
+	limits.Apps = map[string]int{"3": 2}
+	s.Equal([]types.ID{3, 4, 6}, s.ids(deploy.NextInQueue(queue, limits)))
+}
+
+func (s *BuildQueueSuite) Test_QueuePositions() {
+	queue := []*deploy.QueuedDeployment{
+		s.queued(1, 1, 1, true),
+		s.queued(2, 1, 2, false), // waits for team 1
+		s.queued(3, 2, 3, false), // waits for the instance
+		s.queued(4, 2, 3, false), // waits for the instance
+		s.queued(5, 1, 4, false), // waits for team 1
+	}
+
+	limits := admin.BuildQueueConfig{MaxConcurrentBuildsPerTeam: 1}
+
+	s.Equal(map[types.ID]int{2: 1, 3: 1, 4: 2, 5: 2}, deploy.QueuePositions(queue, limits))
+	s.Equal(map[types.ID]int{2: 1, 3: 2, 4: 3, 5: 4}, deploy.QueuePositions(queue, admin.BuildQueueConfig{}))
+}
+
+func TestBuildQueue(t *testing.T) {
+	suite.Run(t, &BuildQueueSuite{})
+}
@@ -0,0 +1,218 @@
+package deployhandlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ee/api/audit"
+	"github.com/stormkit-io/stormkit-io/src/ee/api/team"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"gopkg.in/guregu/null.v3"
+)
+
+type approvalRequest struct {
+	DeploymentID types.ID `json:"deploymentId,string"`
+	Status       string   `json:"status"` // approved | rejected
+	Comment      string   `json:"comment"`
+}
+
+// handlerApprovals returns the deployments that are waiting for
+// approvals before they can be published to the protected environment.
+func handlerApprovals(req *app.RequestContext) *shttp.Response {
+	env, res := requestEnv(req)
+
+	if res != nil {
+		return res
+	}
+
+	pending := []*deploy.PendingApproval{}
+
+	if env.Data.IsProtected() {
+		var err error
+		pending, err = deploy.NewStore().PendingApprovals(req.Context(), req.App.ID, env.ID, env.Data.Protection.RequiredApprovals)
+
+		if err != nil {
+			return shttp.Error(err)
+		}
+	}
+
+	return &shttp.Response{
+		Data: map[string]any{
+			"pending": pending,
+		},
+	}
+}
+
+// handlerApprovalsDecide approves or rejects publishing a deployment to the protected
+// environment. Deployments that were supposed to be auto published are published
+// as soon as they receive the required number of approvals. When the final approval
+// arrives during a freeze window, the approval is stored and a freeze error is
+// returned, so that the approver can approve again once the window is over.
+func handlerApprovalsDecide(req *app.RequestContext) *shttp.Response {
+	data := &approvalRequest{}
+
+	if err := req.Post(data); err != nil {
+		return shttp.Error(err)
+	}
+
+	if data.Status != deploy.ApprovalStatusApproved && data.Status != deploy.ApprovalStatusRejected {
+		return shttp.BadRequest(map[string]any{
+			"error": "Status has to be one of: approved, rejected.",
+		})
+	}
+
+	env, res := requestEnv(req)
+
+	if res != nil {
+		return res
+	}
+
+	if !env.Data.IsProtected() {
+		return shttp.BadRequest(map[string]any{
+			"error": "Environment is not protected.",
+		})
+	}
+
+	store := deploy.NewStore()
+	depl, err := store.MyDeployment(req.Context(), &deploy.DeploymentsQueryFilters{
+		AppID:        req.App.ID,
+		DeploymentID: data.DeploymentID,
+	})
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	if depl == nil || depl.EnvID != env.ID {
+		return shttp.NotFound()
+	}
+
+	if !depl.ExitCode.Valid || depl.ExitCode.ValueOrZero() != 0 {
+		return shttp.BadRequest(map[string]any{
+			"error": "Only successful deployments can be approved or rejected.",
+		})
+	}
+
+	if !req.User.IsAdmin {
+		t, err := team.NewStore().Team(req.Context(), req.App.TeamID, req.User.ID)
+
+		if err != nil {
+			return shttp.Error(err)
+		}
+
+		if t == nil || !env.Data.Protection.CanApprove(t.CurrentUserRole) {
+			return &shttp.Response{
+				Status: http.StatusForbidden,
+				Data: map[string]any{
+					"error": "You are not allowed to approve deployments for this environment.",
+				},
+			}
+		}
+	}
+
+	approval := &deploy.Approval{
+		DeploymentID: depl.ID,
+		AppID:        req.App.ID,
+		EnvID:        env.ID,
+		UserID:       req.User.ID,
+		UserDisplay:  req.User.Display(),
+		Status:       data.Status,
+		Comment:      null.NewString(data.Comment, data.Comment != ""),
+	}
+
+	if err := store.UpsertApproval(req.Context(), approval); err != nil {
+		return shttp.Error(err)
+	}
+
+	if req.License().Enterprise {
+		action := audit.ApproveAction
+
+		if data.Status == deploy.ApprovalStatusRejected {
+			action = audit.RejectAction
+		}
+
+		err := audit.FromRequestContext(req).
+			WithAction(action, audit.TypeDeployment).
+			WithEnvID(env.ID).
+			WithDiff(&audit.Diff{
+				New: audit.DiffFields{
+					EnvName:         env.Name,
+					DeploymentID:    depl.ID.String(),
+					ApprovalComment: data.Comment,
+				},
+			}).
+			Insert()
+
+		if err != nil {
+			return shttp.Error(err)
+		}
+	}
+
+	published := false
+
+	// Approved deployments are auto published, unless their vulnerabilities block it.
+	shouldPublish := data.Status == deploy.ApprovalStatusApproved &&
+		depl.ShouldPublish &&
+		len(depl.PublishedV2) == 0 &&
+		len(depl.BlockingVulnerabilities()) == 0
+
+	if shouldPublish {
+		approvals, err := store.Approvals(req.Context(), depl.ID, env.ID)
+
+		if err != nil {
+			return shttp.Error(err)
+		}
+
+		if approvals.Satisfies(env.Data.Protection) {
+			// The approval is kept, but nothing publishes the deployment once the freeze
+			// window ends. The approver is notified so that they can approve again later.
+			if window := env.Data.ActiveFreezeWindow(time.Now()); window != nil {
+				return &shttp.Response{
+					Status: deploy.ErrFreezeWindowActive.Status(),
+					Data: map[string]any{
+						"error":        deploy.ErrFreezeWindowActive.Error(),
+						"freezeWindow": window,
+						"approval":     approval,
+					},
+				}
+			}
+
+			settings := []*deploy.PublishSettings{
+				{EnvID: env.ID, DeploymentID: depl.ID, Percentage: 100},
+			}
+
+			if err := Publish(req.Context(), settings); err != nil {
+				return shttp.Error(err)
+			}
+
+			published = true
+		}
+	}
+
+	return &shttp.Response{
+		Data: map[string]any{
+			"approval":  approval,
+			"published": published,
+		},
+	}
+}
+
+// requestEnv returns the environment of the request. It returns a not found
+// response when the environment does not belong to the app.
+func requestEnv(req *app.RequestContext) (*buildconf.Env, *shttp.Response) {
+	env, err := buildconf.NewStore().EnvironmentByID(req.Context(), req.EnvID)
+
+	if err != nil {
+		return nil, shttp.Error(err)
+	}
+
+	if env == nil || env.AppID != req.App.ID {
+		return nil, shttp.NotFound()
+	}
+
+	return env, nil
+}
@@ -0,0 +1,226 @@
+package deployhandlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/ee/api/audit"
+	"github.com/stormkit-io/stormkit-io/src/ee/api/team"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v3"
+)
+
+type HandlerApprovalsSuite struct {
+	suite.Suite
+	*factory.Factory
+	conn           databasetest.TestDB
+	calledSettings []*deploy.PublishSettings
+}
+
+func (s *HandlerApprovalsSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+	s.calledSettings = nil
+	admin.SetMockLicense()
+
+	deployhandlers.Publish = func(ctx context.Context, settings []*deploy.PublishSettings) error {
+		s.calledSettings = settings
+		return nil
+	}
+}
+
+func (s *HandlerApprovalsSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+	admin.ResetMockLicense()
+	deployhandlers.Publish = deploy.Publish
+}
+
+func (s *HandlerApprovalsSuite) protectedEnv(app *factory.MockApp, required int) *factory.MockEnv {
+	return s.MockEnv(app, map[string]any{
+		"Data": &buildconf.BuildConf{
+			Protection: &buildconf.Protection{RequiredApprovals: required},
+		},
+	})
+}
+
+func (s *HandlerApprovalsSuite) decide(usr *factory.MockUser, envID, deploymentID, status string) shttptest.Response {
+	return shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deployments/approvals",
+		map[string]any{
+			"envId":        envID,
+			"deploymentId": deploymentID,
+			"status":       status,
+			"comment":      "Looks good",
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+}
+
+func (s *HandlerApprovalsSuite) Test_Approve_Publishes() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.protectedEnv(app, 1)
+	depl := s.MockDeployment(env, map[string]any{
+		"ExitCode":      null.IntFrom(0),
+		"ShouldPublish": true,
+	})
+
+	response := s.decide(usr, env.ID.String(), depl.ID.String(), deploy.ApprovalStatusApproved)
+	s.Equal(http.StatusOK, response.Code)
+
+	data := map[string]any{}
+	s.NoError(json.Unmarshal(response.Byte(), &data))
+	s.Equal(true, data["published"])
+	s.Equal([]*deploy.PublishSettings{
+		{EnvID: env.ID, DeploymentID: depl.ID, Percentage: 100},
+	}, s.calledSettings)
+
+	audits, err := audit.NewStore().SelectAudits(context.Background(), audit.AuditFilters{
+		EnvID: env.ID,
+	})
+
+	s.NoError(err)
+	s.Len(audits, 1)
+	s.Equal("APPROVE:DEPLOYMENT", audits[0].Action)
+	s.Equal(depl.ID.String(), audits[0].Diff.New.DeploymentID)
+	s.Equal("Looks good", audits[0].Diff.New.ApprovalComment)
+}
+
+func (s *HandlerApprovalsSuite) Test_Approve_DuringFreezeWindow() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app, map[string]any{
+		"Data": &buildconf.BuildConf{
+			Protection: &buildconf.Protection{RequiredApprovals: 1},
+			FreezeWindows: []buildconf.FreezeWindow{
+				{Name: "Release freeze", From: time.Now().Add(-time.Hour).Unix(), To: time.Now().Add(time.Hour).Unix()},
+			},
+		},
+	})
+
+	depl := s.MockDeployment(env, map[string]any{
+		"ExitCode":      null.IntFrom(0),
+		"ShouldPublish": true,
+	})
+
+	response := s.decide(usr, env.ID.String(), depl.ID.String(), deploy.ApprovalStatusApproved)
+	s.Equal(deploy.ErrFreezeWindowActive.Status(), response.Code)
+	s.Contains(response.String(), deploy.ErrFreezeWindowActive.Error())
+	s.Nil(s.calledSettings)
+
+	// The approval is kept, so that the deployment can be published after the freeze.
+	approvals, err := deploy.NewStore().Approvals(context.Background(), depl.ID, env.ID)
+	s.NoError(err)
+	s.Len(approvals, 1)
+}
+
+func (s *HandlerApprovalsSuite) Test_Approve_WaitsForRequiredApprovals() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.protectedEnv(app, 2)
+	depl := s.MockDeployment(env, map[string]any{
+		"ExitCode":      null.IntFrom(0),
+		"ShouldPublish": true,
+	})
+
+	response := s.decide(usr, env.ID.String(), depl.ID.String(), deploy.ApprovalStatusApproved)
+	s.Equal(http.StatusOK, response.Code)
+	s.Nil(s.calledSettings)
+
+	response = shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodGet,
+		"/app/deployments/approvals?envId="+env.ID.String(),
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+
+	data := struct {
+		Pending []*deploy.PendingApproval `json:"pending"`
+	}{}
+
+	s.NoError(json.Unmarshal(response.Byte(), &data))
+	s.Len(data.Pending, 1)
+	s.Equal(depl.ID, data.Pending[0].DeploymentID)
+	s.Equal(2, data.Pending[0].RequiredApprovals)
+	s.Len(data.Pending[0].Approvals, 1)
+	s.Equal(usr.ID, data.Pending[0].Approvals[0].UserID)
+}
+
+func (s *HandlerApprovalsSuite) Test_Reject() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.protectedEnv(app, 1)
+	depl := s.MockDeployment(env, map[string]any{
+		"ExitCode":      null.IntFrom(0),
+		"ShouldPublish": true,
+	})
+
+	response := s.decide(usr, env.ID.String(), depl.ID.String(), deploy.ApprovalStatusRejected)
+	s.Equal(http.StatusOK, response.Code)
+	s.Nil(s.calledSettings)
+
+	err := deploy.CheckApprovals(context.Background(), []*deploy.PublishSettings{
+		{EnvID: env.ID, DeploymentID: depl.ID, Percentage: 100},
+	})
+
+	s.ErrorIs(err, deploy.ErrDeploymentRejected)
+}
+
+func (s *HandlerApprovalsSuite) Test_Forbidden_Role() {
+	owner := s.MockUser()
+	developer := s.MockUser()
+	app := s.MockApp(owner)
+	env := s.protectedEnv(app, 1)
+	depl := s.MockDeployment(env, map[string]any{
+		"ExitCode": null.IntFrom(0),
+	})
+
+	s.NoError(team.NewStore().AddMemberToTeam(context.Background(), &team.Member{
+		TeamID: app.TeamID,
+		UserID: developer.ID,
+		Role:   team.ROLE_DEVELOPER,
+		Status: true,
+	}))
+
+	response := s.decide(developer, env.ID.String(), depl.ID.String(), deploy.ApprovalStatusApproved)
+	s.Equal(http.StatusForbidden, response.Code)
+	s.JSONEq(`{"error":"You are not allowed to approve deployments for this environment."}`, response.String())
+}
+
+func (s *HandlerApprovalsSuite) Test_NotProtected() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	depl := s.MockDeployment(env, map[string]any{
+		"ExitCode": null.IntFrom(0),
+	})
+
+	response := s.decide(usr, env.ID.String(), depl.ID.String(), deploy.ApprovalStatusApproved)
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.JSONEq(`{"error":"Environment is not protected."}`, response.String())
+}
+
+func TestHandlerApprovals(t *testing.T) {
+	suite.Run(t, &HandlerApprovalsSuite{})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhooks"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
 	"github.com/stormkit-io/stormkit-io/src/lib/config"
 	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
@@ -39,6 +40,12 @@ type deployCallbackRequest struct {
 	Manifest        *deploy.BuildManifest     `json:"manifest"`
 	HasStatusChecks bool                      `json:"hasStatusChecks"`
 
+	// Software bill of materials
+	SBOM *deploy.SBOM `json:"sbom"`
+
+	// Dependency vulnerability scan findings
+	Vulnerabilities deploy.Vulnerabilities `json:"vulnerabilities"`
+
 	// Final call
 	Lock bool `json:"lock"`
 
@@ -92,6 +99,14 @@ func handlerDeployCallback(req *shttp.RequestContext) *shttp.Response {
 		return updateCommit(req, data)
 	}
 
+	if data.SBOM != nil {
+		return updateSBOM(req, data)
+	}
+
+	if data.Vulnerabilities != nil {
+		return updateVulnerabilities(req, data)
+	}
+
 	if data.Logs != "" {
 		if data.deployment.ExitCode.Valid {
 			return updateStatusCheckLogs(req, data)
@@ -115,6 +130,10 @@ func updateLogs(req *shttp.RequestContext, data deployCallbackRequest) *shttp.Re
 		return shttp.Error(err)
 	}
 
+	// The runner sends the whole log buffer, stream only the lines that were not sent yet.
+	streamed := deploy.LogLineCount(data.deployment.Logs.ValueOrZero(), false)
+	publishLogEvents(ctx, data.deployment, deploy.LogEvents(data.Logs, streamed, false))
+
 	return shttp.OK()
 }
 
@@ -129,6 +148,22 @@ func updateStatusCheckLogs(req *shttp.RequestContext, data deployCallbackRequest
 	return shttp.OK()
 }
 
+func updateSBOM(req *shttp.RequestContext, data deployCallbackRequest) *shttp.Response {
+	if err := deploy.NewStore().UpdateSBOM(req.Context(), data.deployment.ID, data.SBOM); err != nil {
+		return shttp.Error(err)
+	}
+
+	return shttp.OK()
+}
+
+func updateVulnerabilities(req *shttp.RequestContext, data deployCallbackRequest) *shttp.Response {
+	if err := deploy.NewStore().UpdateVulnerabilities(req.Context(), data.deployment.ID, data.Vulnerabilities); err != nil {
+		return shttp.Error(err)
+	}
+
+	return shttp.OK()
+}
+
 func updateCommit(req *shttp.RequestContext, data deployCallbackRequest) *shttp.Response {
 	store := deploy.NewStore()
 	ctx := req.Context()
@@ -168,7 +203,14 @@ func updateExit(req *shttp.RequestContext, data deployCallbackRequest) *shttp.Re
 		data.deployment.ExitCode = null.NewInt(1, true)
 	}
 
-	if err := deploy.NewStore().UpdateDeploymentResult(req.Context(), data.deployment, data.Result); err != nil {
+	store := deploy.NewStore()
+
+	// The blobs are tracked before the result is stored, which fails for stopped deployments.
+	if err := store.TrackBlobs(req.Context(), data.deployment.AppID, data.Result.Client); err != nil {
+		return shttp.Error(err, fmt.Sprintf("error while tracking uploaded blobs: %s", err.Error()))
+	}
+
+	if err := store.UpdateDeploymentResult(req.Context(), data.deployment, data.Result); err != nil {
 		return shttp.Error(err, fmt.Sprintf("error while updating deployment result: %s", err.Error()))
 	}
 
@@ -198,6 +240,8 @@ func updateExit(req *shttp.RequestContext, data deployCallbackRequest) *shttp.Re
 		return lockDeployment(req, data)
 	}
 
+	dispatchQueuedDeployments(req.Context())
+
 	return shttp.OK()
 }
 
@@ -210,7 +254,7 @@ func lockDeployment(req *shttp.RequestContext, d deployCallbackRequest) *shttp.R
 	isSuccess := d.Outcome == OutcomeSuccess
 
 	if isSuccess {
-		if err := deploy.AutoPublishIfNecessary(req.Context(), d.deployment); err != nil {
+		if err := deployhooks.AutoPublish(req.Context(), d.deployment); err != nil {
 			return shttp.Error(err)
 		}
 	}
@@ -225,5 +269,31 @@ func lockDeployment(req *shttp.RequestContext, d deployCallbackRequest) *shttp.R
 		return shttp.Error(err)
 	}
 
+	d.deployment.StatusChecksPassed = statusChecksPassed
+
+	// Flush the last line if it did not end with a new line and close the stream.
+	logs := d.deployment.Logs.ValueOrZero()
+	events := deploy.LogEvents(logs, deploy.LogLineCount(logs, false), true)
+	publishLogEvents(req.Context(), d.deployment, append(events, deploy.ExitEvent(d.deployment)))
+
+	// The deployment released its slot in the build queue
+	dispatchQueuedDeployments(req.Context())
+
 	return shttp.OK()
 }
+
+// publishLogEvents forwards the events to the clients that are streaming the deployment logs.
+// Errors are only logged as streaming is not critical for the deployment.
+func publishLogEvents(ctx context.Context, d *deploy.Deployment, events []deploy.LogEvent) {
+	if err := deploy.PublishLogEvents(ctx, d.ID, events); err != nil {
+		slog.Errorf("error while publishing log events for deployment %s: %v", d.ID.String(), err)
+	}
+}
+
+// dispatchQueuedDeployments starts the deployments that are waiting for a free slot.
+// Errors are only logged as the queue is also dispatched periodically by the workerserver.
+func dispatchQueuedDeployments(ctx context.Context) {
+	if err := deployservice.DispatchQueuedDeployments(ctx); err != nil {
+		slog.Errorf("error while dispatching queued deployments: %v", err)
+	}
+}
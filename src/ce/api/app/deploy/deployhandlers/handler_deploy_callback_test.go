@@ -2,12 +2,15 @@ package deployhandlers_test
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/hibiken/asynq"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/appcache"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhandlers"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhooks"
@@ -17,10 +20,13 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
 	"github.com/stormkit-io/stormkit-io/src/lib/factory"
 	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stormkit-io/stormkit-io/src/lib/tasks"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/mise"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
 	"github.com/stormkit-io/stormkit-io/src/mocks"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -135,6 +141,78 @@ func (s *HandlerDeployCallbackSuite) Test_Logs_Success() {
 	s.Equal("Hello world", d.Logs.ValueOrZero())
 }
 
+func (s *HandlerDeployCallbackSuite) Test_Logs_PublishesNewLines() {
+	depl := s.MockDeployment(nil, map[string]any{
+		"Logs": null.StringFrom("[sk-step] npm install [ts:1700000000]\nadded 1 package\n"),
+	})
+
+	sub := rediscache.Client().Subscribe(context.Background(), deploy.LogStreamChannel(depl.ID))
+	defer sub.Close()
+
+	_, err := sub.Receive(context.Background())
+	s.NoError(err)
+
+	response := shttptest.Request(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deploy/callback",
+		map[string]any{
+			"deployId": utils.EncryptID(depl.ID),
+			"logs":     "[sk-step] npm install [ts:1700000000]\nadded 1 package\n[sk-step] npm run build [ts:1700000005]\nbuilding",
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+
+	select {
+	case msg := <-sub.Channel():
+		event := deploy.LogEvent{}
+		s.NoError(json.Unmarshal([]byte(msg.Payload), &event))
+		s.Equal(deploy.LogEvent{ID: 3, Type: deploy.LogEventStep, Title: "npm run build", Timestamp: 1700000005}, event)
+	case <-time.After(time.Second):
+		s.Fail("log event was not published")
+	}
+
+	select {
+	case msg := <-sub.Channel():
+		s.Fail("unexpected log event", msg.Payload)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func (s *HandlerDeployCallbackSuite) Test_SBOM_Success() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	depl := s.MockDeployment(env)
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deploy/callback",
+		map[string]any{
+			"deployId": utils.EncryptID(depl.ID),
+			"sbom": map[string]any{
+				"source": "package-lock.json",
+				"components": []map[string]any{
+					{"name": "react", "version": "18.2.0", "ecosystem": "npm"},
+				},
+			},
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	d, err := deploy.NewStore().SBOMByDeploymentID(context.Background(), depl.ID, depl.AppID)
+	s.NoError(err)
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal("package-lock.json", d.SBOM.Source)
+	s.Equal([]deploy.SBOMComponent{
+		{Name: "react", Version: "18.2.0", Ecosystem: deploy.EcosystemNpm},
+	}, d.SBOM.Components)
+}
+
 func (s *HandlerDeployCallbackSuite) Test_StatusChecksLogs_Success() {
 	usr := s.MockUser()
 	app := s.MockApp(usr)
@@ -276,6 +354,155 @@ func (s *HandlerDeployCallbackSuite) Test_ExitCode_Success_With_StatusChecks() {
 	s.Equal(http.StatusOK, response.Code)
 }
 
+func (s *HandlerDeployCallbackSuite) mockHTTPChecksDeployment(env *factory.MockEnv) *factory.MockDeployment {
+	snapshot, err := json.Marshal(deploy.ConfigSnapshot{
+		BuildConfig: &buildconf.BuildConf{
+			BuildCmd:   "npm run build",
+			DistFolder: "build",
+			HTTPChecks: []buildconf.HTTPCheck{
+				{Path: "/api/health", Status: http.StatusOK},
+			},
+		},
+	})
+
+	s.NoError(err)
+
+	return s.MockDeployment(env, map[string]any{
+		"ShouldPublish": true,
+		"ConfigCopy":    snapshot,
+	})
+}
+
+func (s *HandlerDeployCallbackSuite) Test_ExitCode_Success_With_HTTPChecks() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	depl := s.mockHTTPChecksDeployment(env)
+	client := &mocks.TaskClient{}
+	original := tasks.Client
+
+	defer func() { tasks.Client = original }()
+
+	tasks.Client = func() tasks.TaskClient {
+		return client
+	}
+
+	// The http checks are executed in the background
+	client.On("Enqueue", mock.MatchedBy(func(task *asynq.Task) bool {
+		return task.Type() == tasks.DeploymentPublish && string(task.Payload()) == depl.ID.String()
+	})).Return(nil, nil).Once()
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deploy/callback",
+		map[string]any{
+			"deployId": utils.EncryptID(depl.ID),
+			"manifest": &deploy.BuildManifest{},
+			"outcome":  "success",
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+	client.AssertExpectations(s.T())
+
+	depls, err := deploy.NewStore().MyDeployments(context.Background(), &deploy.DeploymentsQueryFilters{
+		DeploymentID: depl.ID,
+	})
+
+	s.NoError(err)
+	s.Len(depls, 1)
+	s.True(depls[0].IsLocked())
+	s.Empty(depls[0].PublishedV2)
+}
+
+func (s *HandlerDeployCallbackSuite) Test_Vulnerabilities_Success() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	depl := s.MockDeployment(env)
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deploy/callback",
+		map[string]any{
+			"deployId": utils.EncryptID(depl.ID),
+			"vulnerabilities": []map[string]any{
+				{"id": "GHSA-1", "ecosystem": "npm", "name": "lodash", "version": "4.17.20", "severity": "high"},
+			},
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+
+	depls, err := deploy.NewStore().MyDeployments(context.Background(), &deploy.DeploymentsQueryFilters{
+		DeploymentID: depl.ID,
+	})
+
+	s.NoError(err)
+	s.Len(depls, 1)
+	s.Len(depls[0].Vulnerabilities, 1)
+	s.Equal("GHSA-1", depls[0].Vulnerabilities[0].ID)
+	s.Equal(osv.SeverityHigh, depls[0].Vulnerabilities[0].Severity)
+}
+
+func (s *HandlerDeployCallbackSuite) Test_ExitCode_Success_With_BlockingVulnerabilities() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+
+	snapshot, err := json.Marshal(deploy.ConfigSnapshot{
+		BuildConfig: &buildconf.BuildConf{
+			BuildCmd:   "npm run build",
+			DistFolder: "build",
+			VulnScan:   &buildconf.VulnScan{BlockPublishOn: osv.SeverityHigh},
+		},
+	})
+
+	s.NoError(err)
+
+	depl := s.MockDeployment(env, map[string]any{
+		"ShouldPublish": true,
+		"ConfigCopy":    snapshot,
+	})
+
+	s.NoError(deploy.NewStore().UpdateVulnerabilities(context.Background(), depl.ID, deploy.Vulnerabilities{
+		{Package: osv.Package{Ecosystem: "npm", Name: "lodash", Version: "4.17.20"}, ID: "GHSA-1", Severity: osv.SeverityCritical},
+	}))
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deploy/callback",
+		map[string]any{
+			"deployId": utils.EncryptID(depl.ID),
+			"manifest": &deploy.BuildManifest{},
+			"outcome":  "success",
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+
+	depls, err := deploy.NewStore().MyDeployments(context.Background(), &deploy.DeploymentsQueryFilters{
+		DeploymentID: depl.ID,
+	})
+
+	s.NoError(err)
+	s.Len(depls, 1)
+	s.True(depls[0].IsLocked())
+	s.Empty(depls[0].PublishedV2)
+}
+
 func (s *HandlerDeployCallbackSuite) Test_ExitCode_EmptyManifest() {
 	usr := s.MockUser()
 	app := s.MockApp(usr)
@@ -493,6 +720,74 @@ func (s *HandlerDeployCallbackSuite) Test_ShouldNotOverwrite() {
 	s.Equal(true, d.ExitCode.Valid)
 }
 
+func (s *HandlerDeployCallbackSuite) Test_ExitCode_Stopped() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	depl := s.MockDeployment(env, map[string]any{
+		"ShouldPublish": true,
+	})
+
+	s.NoError(deploy.NewStore().StopDeployment(context.Background(), depl.ID))
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deploy/callback",
+		map[string]any{
+			"deployId": utils.EncryptID(depl.ID),
+			"outcome":  "success",
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusConflict, response.Code)
+
+	d, err := deploy.NewStore().DeploymentByID(context.Background(), depl.ID)
+	s.NoError(err)
+	s.Equal(int64(-1), d.ExitCode.ValueOrZero())
+}
+
+func (s *HandlerDeployCallbackSuite) Test_ExitCode_Stopped_TracksBlobs() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	depl := s.MockDeployment(env)
+	location := "aws:my-bucket/1/blobs"
+
+	s.NoError(deploy.NewStore().StopDeployment(context.Background(), depl.ID))
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deploy/callback",
+		map[string]any{
+			"deployId": utils.EncryptID(depl.ID),
+			"outcome":  "success",
+			"result": integrations.UploadResult{
+				Client: integrations.UploadOverview{
+					Location: location,
+					Blobs:    []string{"aaa", "bbb"},
+				},
+			},
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusConflict, response.Code)
+
+	// The uploaded blobs are not referenced, so that they are garbage collected.
+	var count, refs int
+	row := s.conn.QueryRowContext(context.Background(), `SELECT COUNT(*), COALESCE(SUM(ref_count), 0) FROM artifact_blobs WHERE blob_location = $1;`, location)
+	s.NoError(row.Scan(&count, &refs))
+	s.Equal(2, count)
+	s.Equal(0, refs)
+}
+
 func (s *HandlerDeployCallbackSuite) Test_InvalidDeployID() {
 	usr := s.MockUser()
 
@@ -0,0 +1,63 @@
+package deployhandlers
+
+import (
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+)
+
+// handlerDeployCompare returns the difference between the given deployment and the base
+// deployment. When the base is not specified, the deployment that is currently published
+// to the same environment is used, so that the impact of publishing can be reviewed.
+func handlerDeployCompare(req *app.RequestContext) *shttp.Response {
+	store := deploy.NewStore()
+	head, err := store.MyDeployment(req.Context(), &deploy.DeploymentsQueryFilters{
+		AppID:        req.App.ID,
+		DeploymentID: utils.StringToID(req.Vars()["deploymentId"]),
+	})
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	if head == nil || head.AppID != req.App.ID {
+		return shttp.NotFound()
+	}
+
+	baseID := utils.StringToID(req.Query().Get("base"))
+
+	if baseID == 0 {
+		published, err := store.PublishedDeployments(req.Context(), []types.ID{head.EnvID})
+
+		if err != nil {
+			return shttp.Error(err)
+		}
+
+		if baseID = published[head.EnvID]; baseID == 0 {
+			return shttp.BadRequest(map[string]any{
+				"error": "There is no published deployment to compare with. Specify the base deployment using the `base` query parameter.",
+			})
+		}
+	}
+
+	base, err := store.MyDeployment(req.Context(), &deploy.DeploymentsQueryFilters{
+		AppID:        req.App.ID,
+		DeploymentID: baseID,
+	})
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	if base == nil || base.AppID != req.App.ID {
+		return shttp.NotFound()
+	}
+
+	return &shttp.Response{
+		Data: map[string]any{
+			"diff": deploy.Compare(base, head),
+		},
+	}
+}
@@ -0,0 +1,131 @@
+package deployhandlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v3"
+)
+
+type HandlerDeployCompareSuite struct {
+	suite.Suite
+	*factory.Factory
+	conn databasetest.TestDB
+}
+
+func (s *HandlerDeployCompareSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+}
+
+func (s *HandlerDeployCompareSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+}
+
+func (s *HandlerDeployCompareSuite) compare(usr *factory.MockUser, appID, deploymentID, query string) shttptest.Response {
+	return shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodGet,
+		fmt.Sprintf("/app/%s/deploy/%s/compare%s", appID, deploymentID, query),
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+}
+
+func (s *HandlerDeployCompareSuite) Test_Success_PublishedBase() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	base := s.MockDeployment(env, map[string]any{
+		"ExitCode":  null.IntFrom(0),
+		"Published": []deploy.PublishedInfo{{EnvID: env.ID, Percentage: 100}},
+	})
+
+	head := s.MockDeployment(env, map[string]any{
+		"ExitCode": null.IntFrom(0),
+		"Commit":   deploy.CommitInfo{ID: null.StringFrom("28cb15f9")},
+		"BuildManifest": &deploy.BuildManifest{
+			CDNFiles:        []deploy.CDNFile{{Name: "index"}, {Name: "contact"}},
+			FunctionHandler: "server.mjs:handler",
+		},
+	})
+
+	response := s.compare(usr, app.ID.String(), head.ID.String(), "")
+	s.Equal(http.StatusOK, response.Code)
+
+	data := struct {
+		Diff *deploy.DeploymentDiff `json:"diff"`
+	}{}
+
+	s.NoError(json.Unmarshal(response.Byte(), &data))
+	s.Equal(base.ID, data.Diff.BaseID)
+	s.Equal(head.ID, data.Diff.HeadID)
+	s.Equal([]string{"contact"}, data.Diff.StaticFiles.Added)
+	s.Equal([]string{"about"}, data.Diff.StaticFiles.Removed)
+	s.Equal(&deploy.ValueChange{Base: "", Head: "server.mjs:handler"}, data.Diff.FunctionHandler)
+	s.Equal("16ab41e8", data.Diff.Commits.Base)
+	s.Equal("28cb15f9", data.Diff.Commits.Head)
+}
+
+func (s *HandlerDeployCompareSuite) Test_Success_ExplicitBase() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	depls := s.MockDeployments(2, env)
+
+	response := s.compare(usr, app.ID.String(), depls[1].ID.String(), "?base="+depls[0].ID.String())
+	s.Equal(http.StatusOK, response.Code)
+	s.Contains(response.String(), fmt.Sprintf(`"baseId":"%s"`, depls[0].ID.String()))
+}
+
+func (s *HandlerDeployCompareSuite) Test_NoPublishedDeployment() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	depl := s.MockDeployment(env)
+
+	response := s.compare(usr, app.ID.String(), depl.ID.String(), "")
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.JSONEq(`{"error":"There is no published deployment to compare with. Specify the base deployment using the `+"`base`"+` query parameter."}`, response.String())
+}
+
+func (s *HandlerDeployCompareSuite) Test_NotFound() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	depl := s.MockDeployment(env)
+
+	otherApp := s.MockApp(s.MockUser())
+	otherDepl := s.MockDeployment(s.MockEnv(otherApp))
+
+	response := s.compare(usr, app.ID.String(), depl.ID.String(), "?base="+otherDepl.ID.String())
+	s.Equal(http.StatusNotFound, response.Code)
+}
+
+func (s *HandlerDeployCompareSuite) Test_NotFound_HeadFromAnotherApp() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	depl := s.MockDeployment(s.MockEnv(app))
+
+	otherApp := s.MockApp(s.MockUser())
+	otherDepl := s.MockDeployment(s.MockEnv(otherApp))
+
+	response := s.compare(usr, app.ID.String(), otherDepl.ID.String(), "?base="+depl.ID.String())
+	s.Equal(http.StatusNotFound, response.Code)
+}
+
+func TestHandlerDeployCompare(t *testing.T) {
+	suite.Run(t, &HandlerDeployCompareSuite{})
+}
@@ -0,0 +1,179 @@
+package deployhandlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/redis/go-redis/v9"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+)
+
+// LogStreamHeartbeat is the interval to keep the connection alive and to
+// sync with the stored logs, in case a published event has been missed.
+var LogStreamHeartbeat = 15 * time.Second
+
+// handlerDeployLogsStream streams the deployment logs as server-sent events.
+// Clients can resume the stream by providing the Last-Event-ID header or the
+// lastEventId query parameter. The stream is closed with an exit event.
+func handlerDeployLogsStream(req *app.RequestContext) *shttp.Response {
+	store := deploy.NewStore()
+	filters := &deploy.DeploymentsQueryFilters{
+		AppID:        req.App.ID,
+		DeploymentID: utils.StringToID(req.Vars()["deploymentId"]),
+		IncludeLogs:  aws.Bool(true),
+	}
+
+	depl, err := store.MyDeployment(req.Context(), filters)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	if depl == nil || depl.AppID != req.App.ID {
+		return shttp.NotFound()
+	}
+
+	flusher, ok := req.Writer().(http.Flusher)
+
+	if !ok {
+		return shttp.Error(errors.New("streaming is not supported"))
+	}
+
+	var sub *redis.PubSub
+
+	if depl.Status() == "running" {
+		client := rediscache.Client()
+
+		if client == nil {
+			return shttp.Error(errors.New("redis client is not initialized"))
+		}
+
+		// Subscribe before reading the logs once again, otherwise
+		// the lines that are published in between would be lost.
+		sub = client.Subscribe(req.Context(), deploy.LogStreamChannel(depl.ID))
+		defer sub.Close()
+
+		if depl, err = store.MyDeployment(req.Context(), filters); err != nil {
+			return shttp.Error(err)
+		}
+
+		if depl == nil || depl.AppID != req.App.ID {
+			return shttp.NotFound()
+		}
+	}
+
+	w := req.Writer()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	// The stream lives longer than the server write timeout.
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	stream := &logStream{
+		w:       w,
+		flusher: flusher,
+		lastID:  utils.StringToInt(utils.GetString(req.Headers().Get("Last-Event-ID"), req.Query().Get("lastEventId"))),
+	}
+
+	if depl.Status() != "running" {
+		stream.finish(depl)
+		return nil
+	}
+
+	if err := stream.send(deploy.LogEvents(depl.Logs.ValueOrZero(), stream.lastID, false)...); err != nil {
+		return nil
+	}
+
+	messages := sub.Channel()
+	ticker := time.NewTicker(LogStreamHeartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+
+			event := deploy.LogEvent{}
+
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				continue
+			}
+
+			if err := stream.send(event); err != nil || event.Type == deploy.LogEventExit {
+				return nil
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+
+			flusher.Flush()
+
+			depl, err = store.MyDeployment(req.Context(), filters)
+
+			if err != nil || depl == nil || depl.AppID != req.App.ID {
+				return nil
+			}
+
+			if depl.Status() != "running" {
+				stream.finish(depl)
+				return nil
+			}
+
+			// Pub/sub does not guarantee delivery, catch up with the stored logs.
+			if err := stream.send(deploy.LogEvents(depl.Logs.ValueOrZero(), stream.lastID, false)...); err != nil {
+				return nil
+			}
+		}
+	}
+}
+
+type logStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	lastID  int
+}
+
+// send writes the events to the client. Events that have been
+// already sent are skipped, except for the exit event.
+func (s *logStream) send(events ...deploy.LogEvent) error {
+	for _, event := range events {
+		if event.ID <= s.lastID && event.Type != deploy.LogEventExit {
+			continue
+		}
+
+		data, err := json.Marshal(event)
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := fmt.Fprintf(s.w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+			return err
+		}
+
+		s.lastID = max(s.lastID, event.ID)
+	}
+
+	s.flusher.Flush()
+	return nil
+}
+
+// finish sends the remaining lines of a deployment that is no longer running and the exit event.
+func (s *logStream) finish(d *deploy.Deployment) {
+	events := deploy.LogEvents(d.Logs.ValueOrZero(), s.lastID, true)
+	_ = s.send(append(events, deploy.ExitEvent(d))...)
+}
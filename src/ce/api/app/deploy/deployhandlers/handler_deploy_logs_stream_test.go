@@ -0,0 +1,104 @@
+package deployhandlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v3"
+)
+
+type HandlerDeployLogsStreamSuite struct {
+	suite.Suite
+	*factory.Factory
+
+	conn databasetest.TestDB
+}
+
+func (s *HandlerDeployLogsStreamSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+}
+
+func (s *HandlerDeployLogsStreamSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+}
+
+func (s *HandlerDeployLogsStreamSuite) request(path string, headers map[string]string) shttptest.Response {
+	headers["Authorization"] = usertest.Authorization(s.GetUser().ID)
+
+	return shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodGet,
+		path,
+		nil,
+		headers,
+	)
+}
+
+func (s *HandlerDeployLogsStreamSuite) Test_Finished() {
+	depl := s.MockDeployment(nil, map[string]any{
+		"ExitCode": null.IntFrom(1),
+		"Logs":     null.StringFrom("[sk-step] npm run build [ts:1700000000]\nbuild failed"),
+	})
+
+	response := s.request(fmt.Sprintf("/app/%s/deploy/%s/logs/stream", depl.AppID.String(), depl.ID.String()), map[string]string{})
+
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal("text/event-stream", response.Header().Get("Content-Type"))
+	s.Equal(
+		"id: 1\nevent: step\ndata: {\"id\":1,\"type\":\"step\",\"title\":\"npm run build\",\"timestamp\":1700000000}\n\n"+
+			"id: 2\nevent: log\ndata: {\"id\":2,\"type\":\"log\",\"line\":\"build failed\"}\n\n"+
+			"id: 3\nevent: exit\ndata: {\"id\":3,\"type\":\"exit\",\"status\":\"failed\",\"exitCode\":1}",
+		response.String(),
+	)
+}
+
+func (s *HandlerDeployLogsStreamSuite) Test_Resume() {
+	depl := s.MockDeployment(nil, map[string]any{
+		"ExitCode": null.IntFrom(-1),
+		"Logs":     null.StringFrom("[sk-step] npm run build [ts:1700000000]\nbuild stopped\n"),
+	})
+
+	response := s.request(fmt.Sprintf("/app/%s/deploy/%s/logs/stream", depl.AppID.String(), depl.ID.String()), map[string]string{
+		"Last-Event-ID": "1",
+	})
+
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal(
+		"id: 2\nevent: log\ndata: {\"id\":2,\"type\":\"log\",\"line\":\"build stopped\"}\n\n"+
+			"id: 3\nevent: exit\ndata: {\"id\":3,\"type\":\"exit\",\"status\":\"failed\",\"exitCode\":-1}",
+		response.String(),
+	)
+}
+
+func (s *HandlerDeployLogsStreamSuite) Test_NotFound() {
+	depl := s.MockDeployment(nil)
+	response := s.request(fmt.Sprintf("/app/%s/deploy/%d/logs/stream", depl.AppID.String(), depl.ID+1), map[string]string{})
+
+	s.Equal(http.StatusNotFound, response.Code)
+}
+
+func (s *HandlerDeployLogsStreamSuite) Test_NotFound_AnotherApp() {
+	depl := s.MockDeployment(nil)
+	otherDepl := s.MockDeployment(s.MockEnv(s.MockApp(s.MockUser())), map[string]any{
+		"ExitCode": null.IntFrom(0),
+		"Logs":     null.StringFrom("[sk-step] npm run build [ts:1700000000]\nsecret logs"),
+	})
+
+	response := s.request(fmt.Sprintf("/app/%s/deploy/%s/logs/stream", depl.AppID.String(), otherDepl.ID.String()), map[string]string{})
+
+	s.Equal(http.StatusNotFound, response.Code)
+	s.NotContains(response.String(), "secret logs")
+}
+
+func TestHandlerDeployLogsStream(t *testing.T) {
+	suite.Run(t, &HandlerDeployLogsStreamSuite{})
+}
@@ -0,0 +1,31 @@
+package deployhandlers
+
+import (
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+)
+
+// handlerDeployPin pins the deployment so that it is exempted from the
+// retention policy. Use the DELETE method to unpin the deployment.
+func handlerDeployPin(req *app.RequestContext) *shttp.Response {
+	pinned := req.Method != shttp.MethodDelete
+	deploymentID := utils.StringToID(req.Vars()["deploymentId"])
+
+	updated, err := deploy.NewStore().PinDeployment(req.Context(), deploymentID, req.App.ID, pinned)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	if !updated {
+		return shttp.NotFound()
+	}
+
+	return &shttp.Response{
+		Data: map[string]any{
+			"pinned": pinned,
+		},
+	}
+}
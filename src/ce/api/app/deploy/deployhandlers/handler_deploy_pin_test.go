@@ -0,0 +1,81 @@
+package deployhandlers_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stretchr/testify/suite"
+)
+
+type HandlerDeployPinSuite struct {
+	suite.Suite
+	*factory.Factory
+	conn databasetest.TestDB
+}
+
+func (s *HandlerDeployPinSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+}
+
+func (s *HandlerDeployPinSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+}
+
+func (s *HandlerDeployPinSuite) pin(method string, usr *factory.MockUser, appID, deploymentID string) shttptest.Response {
+	return shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		method,
+		fmt.Sprintf("/app/%s/deploy/%s/pin", appID, deploymentID),
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+}
+
+func (s *HandlerDeployPinSuite) Test_PinAndUnpin() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	depl := s.MockDeployment(env)
+
+	response := s.pin(shttp.MethodPost, usr, app.ID.String(), depl.ID.String())
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{ "pinned": true }`, response.String())
+
+	d, err := deploy.NewStore().MyDeployment(context.Background(), &deploy.DeploymentsQueryFilters{DeploymentID: depl.ID})
+	s.NoError(err)
+	s.True(d.IsPinned)
+
+	response = s.pin(shttp.MethodDelete, usr, app.ID.String(), depl.ID.String())
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(`{ "pinned": false }`, response.String())
+
+	d, err = deploy.NewStore().MyDeployment(context.Background(), &deploy.DeploymentsQueryFilters{DeploymentID: depl.ID})
+	s.NoError(err)
+	s.False(d.IsPinned)
+}
+
+func (s *HandlerDeployPinSuite) Test_NotFound() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(s.MockApp(nil))
+	depl := s.MockDeployment(env)
+
+	response := s.pin(shttp.MethodPost, usr, app.ID.String(), depl.ID.String())
+	s.Equal(http.StatusNotFound, response.Code)
+}
+
+func TestHandlerDeployPin(t *testing.T) {
+	suite.Run(t, &HandlerDeployPinSuite{})
+}
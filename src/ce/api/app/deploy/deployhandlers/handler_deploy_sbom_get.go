@@ -0,0 +1,65 @@
+package deployhandlers
+
+import (
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+)
+
+// handlerDeploySBOMGet returns the software bill of materials of the deployment.
+// The format is specified with the `format` query parameter: cyclonedx (default) | spdx.
+func handlerDeploySBOMGet(req *app.RequestContext) *shttp.Response {
+	format := req.Query().Get("format")
+
+	if format != "" && format != deploy.SBOMFormatCycloneDX && format != deploy.SBOMFormatSPDX {
+		return shttp.BadRequest(map[string]any{
+			"error": "Invalid format. Supported formats are: cyclonedx, spdx.",
+		})
+	}
+
+	id := utils.StringToID(req.Vars()["deploymentId"])
+	depl, err := deploy.NewStore().SBOMByDeploymentID(req.Context(), id, req.App.ID)
+
+	if err != nil {
+		return shttp.UnexpectedError(err)
+	}
+
+	if depl == nil || depl.SBOM == nil {
+		return shttp.NotFound()
+	}
+
+	document, err := depl.SBOM.Document(depl, format)
+
+	if err != nil {
+		return shttp.UnexpectedError(err)
+	}
+
+	return &shttp.Response{
+		Data: document,
+	}
+}
+
+// handlerDeploySBOMSearch returns the deployments that ship the given package.
+// This is useful to find out which deployments are affected by a vulnerable package.
+func handlerDeploySBOMSearch(req *app.RequestContext) *shttp.Response {
+	name := req.Query().Get("package")
+
+	if name == "" {
+		return shttp.BadRequest(map[string]any{
+			"error": "Package name is required.",
+		})
+	}
+
+	deployments, err := deploy.NewStore().DeploymentsWithPackage(req.Context(), req.App.ID, name, req.Query().Get("version"))
+
+	if err != nil {
+		return shttp.UnexpectedError(err)
+	}
+
+	return &shttp.Response{
+		Data: map[string]any{
+			"deployments": deployments,
+		},
+	}
+}
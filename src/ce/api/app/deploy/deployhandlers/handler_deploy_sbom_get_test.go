@@ -0,0 +1,129 @@
+package deployhandlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stretchr/testify/suite"
+)
+
+type HandlerDeploySBOMSuite struct {
+	suite.Suite
+	*factory.Factory
+
+	conn databasetest.TestDB
+}
+
+func (s *HandlerDeploySBOMSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+}
+
+func (s *HandlerDeploySBOMSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+}
+
+func (s *HandlerDeploySBOMSuite) mockSBOM() *factory.MockDeployment {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	depl := s.MockDeployment(env)
+
+	s.NoError(deploy.NewStore().UpdateSBOM(context.Background(), depl.ID, &deploy.SBOM{
+		Source: "package-lock.json",
+		Components: []deploy.SBOMComponent{
+			{Name: "@remix-run/node", Version: "2.1.0", Ecosystem: deploy.EcosystemNpm, Bundles: []string{"server"}},
+			{Name: "vite", Version: "5.0.0", Ecosystem: deploy.EcosystemNpm, Dev: true},
+		},
+	}))
+
+	return depl
+}
+
+func (s *HandlerDeploySBOMSuite) request(path string) shttptest.Response {
+	return shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodGet,
+		path,
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(s.GetUser().ID),
+		},
+	)
+}
+
+func (s *HandlerDeploySBOMSuite) Test_CycloneDX() {
+	depl := s.mockSBOM()
+	response := s.request(fmt.Sprintf("/app/%d/sbom/%d", depl.AppID, depl.ID))
+	data := map[string]any{}
+
+	s.Equal(http.StatusOK, response.Code)
+	s.NoError(json.Unmarshal(response.Byte(), &data))
+	s.Equal("CycloneDX", data["bomFormat"])
+
+	components := data["components"].([]any)
+	s.Len(components, 2)
+	s.Equal("pkg:npm/%40remix-run/node@2.1.0", components[0].(map[string]any)["purl"])
+	s.Equal("optional", components[1].(map[string]any)["scope"])
+}
+
+func (s *HandlerDeploySBOMSuite) Test_SPDX() {
+	depl := s.mockSBOM()
+	response := s.request(fmt.Sprintf("/app/%d/sbom/%d?format=spdx", depl.AppID, depl.ID))
+	data := map[string]any{}
+
+	s.Equal(http.StatusOK, response.Code)
+	s.NoError(json.Unmarshal(response.Byte(), &data))
+	s.Equal("SPDX-2.3", data["spdxVersion"])
+	s.Len(data["packages"].([]any), 3)
+}
+
+func (s *HandlerDeploySBOMSuite) Test_InvalidFormat() {
+	depl := s.mockSBOM()
+	response := s.request(fmt.Sprintf("/app/%d/sbom/%d?format=xml", depl.AppID, depl.ID))
+
+	s.Equal(http.StatusBadRequest, response.Code)
+}
+
+func (s *HandlerDeploySBOMSuite) Test_NotFound() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	depl := s.MockDeployment(env)
+
+	response := s.request(fmt.Sprintf("/app/%d/sbom/%d", depl.AppID, depl.ID))
+	s.Equal(http.StatusNotFound, response.Code)
+}
+
+func (s *HandlerDeploySBOMSuite) Test_Search() {
+	depl := s.mockSBOM()
+
+	response := s.request(fmt.Sprintf("/app/%d/sbom?package=vite&version=5.0.0", depl.AppID))
+	data := map[string][]map[string]any{}
+
+	s.Equal(http.StatusOK, response.Code)
+	s.NoError(json.Unmarshal(response.Byte(), &data))
+	s.Len(data["deployments"], 1)
+	s.Equal(depl.ID.String(), data["deployments"][0]["id"])
+
+	response = s.request(fmt.Sprintf("/app/%d/sbom?package=vite&version=4.0.0", depl.AppID))
+	data = map[string][]map[string]any{}
+
+	s.Equal(http.StatusOK, response.Code)
+	s.NoError(json.Unmarshal(response.Byte(), &data))
+	s.Len(data["deployments"], 0)
+}
+
+func TestHandlerDeploySBOM(t *testing.T) {
+	suite.Run(t, &HandlerDeploySBOMSuite{})
+}
@@ -8,21 +8,27 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhooks"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth"
 	"github.com/stormkit-io/stormkit-io/src/ce/runner"
 	"github.com/stormkit-io/stormkit-io/src/lib/config"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/file"
 	"gopkg.in/guregu/null.v3"
 )
 
+var commitShaPattern = regexp.MustCompile(`^[a-fA-F0-9]{40}$`)
+var tagPattern = regexp.MustCompile(`^[a-zA-Z0-9-/+=_\.]+$`)
+
 // handlerDeployStart starts the deployment process for the given app.
 // This handler is triggered when the user submits a deploy request
 // through the user interface.
@@ -38,6 +44,18 @@ func handlerDeployStart(req *app.RequestContext) *shttp.Response {
 		return shttp.Error(err)
 	}
 
+	if data.CommitSha != "" && !commitShaPattern.MatchString(data.CommitSha) {
+		return shttp.BadRequest(map[string]any{
+			"error": "Commit SHA must be a full 40 character hexadecimal hash.",
+		})
+	}
+
+	if data.Tag != "" && !tagPattern.MatchString(data.Tag) {
+		return shttp.BadRequest(map[string]any{
+			"error": "Tag name is invalid.",
+		})
+	}
+
 	env, err := buildconf.NewStore().EnvironmentByID(req.Context(), req.EnvID)
 
 	if err != nil {
@@ -48,6 +66,12 @@ func handlerDeployStart(req *app.RequestContext) *shttp.Response {
 		return shttp.NotFound()
 	}
 
+	if data.Publish {
+		if res := checkFreezeWindow(req, env, data.OverrideFreeze); res != nil {
+			return res
+		}
+	}
+
 	conf := env.Data
 
 	if data.BuildCmd != "" {
@@ -59,13 +83,16 @@ func handlerDeployStart(req *app.RequestContext) *shttp.Response {
 	}
 
 	depl := deploy.New(req.App.ID)
-	depl.Branch = data.Branch
+	depl.Branch = utils.GetString(data.Tag, data.Branch)
+	depl.Tag = null.NewString(data.Tag, data.Tag != "")
+	depl.Commit.ID = null.NewString(data.CommitSha, data.CommitSha != "")
 	depl.Env = env.Name
 	depl.EnvBranchName = env.Branch
 	depl.EnvID = env.ID
 	depl.User = req.User
 	depl.IsFork = false
 	depl.ShouldPublish = data.Publish
+	depl.OverrideFreeze = data.Publish && data.OverrideFreeze
 	depl.CheckoutRepo = req.App.Repo
 	depl.BuildConfig = conf
 
@@ -161,6 +188,19 @@ func deployZip(req *app.RequestContext) *shttp.Response {
 		return shttp.Error(err)
 	}
 
+	if env == nil {
+		return shttp.NotFound()
+	}
+
+	publish := req.FormValue("publish") == "true"
+	overrideFreeze := publish && req.FormValue("overrideFreeze") == "true"
+
+	if publish {
+		if res := checkFreezeWindow(req, env, overrideFreeze); res != nil {
+			return res
+		}
+	}
+
 	manifest := &deploy.BuildManifest{}
 	manifest.Redirects, err = deploy.ParseRedirects([]string{
 		path.Join(unzipDir, utils.GetString(env.Data.RedirectsFile, "redirects.json")),
@@ -187,18 +227,23 @@ func deployZip(req *app.RequestContext) *shttp.Response {
 	}
 
 	d := &deploy.Deployment{
-		AppID:         req.App.ID,
-		EnvID:         req.EnvID,
-		Env:           env.Name,
-		DisplayName:   req.App.DisplayName,
-		BuildManifest: manifest,
-		IsAutoDeploy:  false,
-		ShouldPublish: req.FormValue("publish") == "true",
+		AppID:          req.App.ID,
+		EnvID:          req.EnvID,
+		Env:            env.Name,
+		DisplayName:    req.App.DisplayName,
+		BuildManifest:  manifest,
+		BuildConfig:    env.Data,
+		IsAutoDeploy:   false,
+		ShouldPublish:  publish,
+		OverrideFreeze: overrideFreeze,
 		Commit: deploy.CommitInfo{
 			Author: null.StringFrom(req.User.Display()),
 		},
 	}
 
+	// The snapshot keeps the http checks that gate the publish of the deployment.
+	d.ConfigCopy, _ = d.MarshalConfigSnapshot()
+
 	store := deploy.NewStore()
 
 	if err := store.InsertDeployment(req.Context(), d); err != nil {
@@ -217,10 +262,20 @@ func deployZip(req *app.RequestContext) *shttp.Response {
 		args.ServerZip = zipFilePath
 	} else {
 		args.ClientZip = zipFilePath
+
+		for fileName, headers := range manifest.StaticFiles {
+			args.ClientBlobs = append(args.ClientBlobs, integrations.NewBlob(path.Join(unzipDir, fileName), headers["etag"]))
+		}
 	}
 
 	result, err := runner.NewUploader(config.Get().Runner).Upload(args)
 
+	if result != nil {
+		if err := store.TrackBlobs(req.Context(), d.AppID, result.Client); err != nil {
+			return shttp.Error(err)
+		}
+	}
+
 	if err != nil {
 		return shttp.Error(err)
 	}
@@ -232,10 +287,8 @@ func deployZip(req *app.RequestContext) *shttp.Response {
 		return shttp.Error(err)
 	}
 
-	if d.ShouldPublish {
-		if err := deploy.AutoPublishIfNecessary(req.Context(), d); err != nil {
-			return shttp.Error(err)
-		}
+	if err := deployhooks.AutoPublish(req.Context(), d); err != nil {
+		return shttp.Error(err)
 	}
 
 	return &shttp.Response{
@@ -9,7 +9,9 @@ import (
 	"testing"
 	"text/template"
 
+	"github.com/hibiken/asynq"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/redirects"
@@ -20,11 +22,13 @@ import (
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhandlers"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/ee/api/team"
 	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
 	"github.com/stormkit-io/stormkit-io/src/lib/factory"
 	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stormkit-io/stormkit-io/src/lib/tasks"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 )
 
@@ -121,6 +125,98 @@ func (s *DeployStartTestSuite) Test_Success_Github() {
 	)
 }
 
+func (s *DeployStartTestSuite) Test_OverrideFreeze_Forbidden() {
+	owner := s.MockUser()
+	developer := s.MockUser()
+	appl := s.MockApp(owner)
+	env := s.MockEnv(appl)
+
+	s.NoError(team.NewStore().AddMemberToTeam(context.Background(), &team.Member{
+		TeamID: appl.TeamID,
+		UserID: developer.ID,
+		Role:   team.ROLE_DEVELOPER,
+		Status: true,
+	}))
+
+	// The override is kept for freeze windows that start while the deployment
+	// is building, therefore it is checked even when no window is active.
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deploy",
+		map[string]any{
+			"appId":          appl.ID.String(),
+			"envId":          env.ID.String(),
+			"branch":         "master",
+			"publish":        true,
+			"overrideFreeze": true,
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(developer.ID),
+		},
+	)
+
+	s.Equal(http.StatusForbidden, response.Code)
+	s.mockDeployer.AssertNotCalled(s.T(), "Deploy", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *DeployStartTestSuite) Test_Success_TagAndCommit() {
+	usr := s.MockUser()
+	appl := s.MockApp(usr)
+	env := s.MockEnv(appl)
+	sha := "790dcef2a8c61ff6011a4b595cdcb2f0de6c4e2b"
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deploy",
+		map[string]any{
+			"appId":     appl.ID.String(),
+			"envId":     env.ID.String(),
+			"tag":       "v1.2.0",
+			"commitSha": sha,
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+
+	s.mockDeployer.AssertCalled(s.T(), "Deploy",
+		mock.Anything, mock.Anything,
+		mock.MatchedBy(func(_depl *deploy.Deployment) bool {
+			return s.Equal("v1.2.0", _depl.Branch) &&
+				s.Equal("v1.2.0", _depl.Tag.ValueOrZero()) &&
+				s.Equal(sha, _depl.Commit.ID.ValueOrZero())
+		}),
+	)
+}
+
+func (s *DeployStartTestSuite) Test_InvalidCommitSha() {
+	usr := s.MockUser()
+	appl := s.MockApp(usr)
+	env := s.MockEnv(appl)
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deploy",
+		map[string]any{
+			"appId":     appl.ID.String(),
+			"envId":     env.ID.String(),
+			"commitSha": "790dcef",
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusBadRequest, response.Code)
+	s.JSONEq(`{ "error": "Commit SHA must be a full 40 character hexadecimal hash." }`, response.String())
+	s.mockDeployer.AssertNotCalled(s.T(), "Deploy", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func (s *DeployStartTestSuite) Test_Success_Bitbucket() {
 	usr := s.MockUser()
 	appl := s.MockApp(usr, map[string]any{
@@ -235,6 +331,11 @@ func (s *DeployStartTestSuite) Test_Zip() {
 	  "detailsUrl": "/apps/{{ .appId }}/environments/{{ .envId }}/deployments/{{ .id }}",
 	  "serverPackageSize": 0,
 	  "statusChecksPassed": null,
+	  "httpChecks": null,
+	  "vulnerabilities": null,
+	  "rollback": null,
+	  "queuePosition": null,
+	  "isPinned": false,
 	  "statusChecks": [],
 	  "createdAt": "{{ .createdAt }}",
 	  "stoppedAt": "{{ .stoppedAt }}",
@@ -323,6 +424,73 @@ func (s *DeployStartTestSuite) Test_Zip_AutoPublish() {
 	s.Equal(http.StatusOK, response.Code)
 }
 
+func (s *DeployStartTestSuite) Test_Zip_AutoPublish_WithHTTPChecks() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app, map[string]any{
+		"Data": &buildconf.BuildConf{
+			HTTPChecks: []buildconf.HTTPCheck{{Path: "/", Status: http.StatusOK}},
+		},
+	})
+
+	client := &mocks.TaskClient{}
+	original := tasks.Client
+
+	defer func() { tasks.Client = original }()
+
+	tasks.Client = func() tasks.TaskClient {
+		return client
+	}
+
+	// The deployment is published in the background once the http checks pass
+	client.On("Enqueue", mock.MatchedBy(func(task *asynq.Task) bool {
+		return task.Type() == tasks.DeploymentPublish
+	})).Return(nil, nil).Once()
+
+	s.mockUploader.On("Upload", mock.Anything).Return(&integrations.UploadResult{
+		Client: integrations.UploadOverview{
+			BytesUploaded: 2042,
+			FilesUploaded: 1,
+			Location:      "local:/my/path/sk-client.zip",
+		},
+	}, nil)
+
+	requestBody, contentType, err := shttptest.MultipartForm(map[string][]byte{
+		"appId":   []byte(app.ID.String()),
+		"envId":   []byte(env.ID.String()),
+		"publish": []byte("true"),
+	}, map[string][]shttptest.UploadFile{
+		"files": {
+			{Name: "my.zip", Data: string(s.createZipWithFiles())},
+		},
+	})
+
+	s.NoError(err)
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deploy",
+		requestBody,
+		map[string]string{
+			"Content-Type":  contentType,
+			"Authorization": usertest.Authorization(usr.ID),
+			"X-File-ID":     "my-zip-3",
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+	client.AssertExpectations(s.T())
+
+	depls, err := deploy.NewStore().MyDeployments(context.Background(), &deploy.DeploymentsQueryFilters{
+		EnvID:     env.ID,
+		Published: utils.Ptr(true),
+	})
+
+	s.NoError(err)
+	s.Len(depls, 0)
+}
+
 func (s *DeployStartTestSuite) Test_BadRequest() {
 	app := s.GetApp()
 
@@ -1,11 +1,13 @@
 package deployhandlers
 
 import (
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"gopkg.in/guregu/null.v3"
 )
 
 type deployStopRequest struct {
@@ -21,7 +23,10 @@ func handlerDeployStop(req *app.RequestContext) *shttp.Response {
 	}
 
 	store := deploy.NewStore()
-	depl, err := store.MyDeployment(req.Context(), &deploy.DeploymentsQueryFilters{DeploymentID: dsr.DeploymentID})
+	depl, err := store.MyDeployment(req.Context(), &deploy.DeploymentsQueryFilters{
+		DeploymentID: dsr.DeploymentID,
+		IncludeLogs:  aws.Bool(true),
+	})
 
 	if err != nil {
 		return shttp.Error(err)
@@ -39,14 +44,21 @@ func handlerDeployStop(req *app.RequestContext) *shttp.Response {
 		if err := store.StopDeployment(req.Context(), depl.ID); err != nil {
 			return shttp.Error(err)
 		}
+
+		depl.ExitCode = null.IntFrom(int64(deploy.ExitCodeStopped))
+		dispatchQueuedDeployments(req.Context())
 	}
 
 	if depl.HasStatusChecks() {
 		if err := store.StopStatusChecks(req.Context(), depl.ID); err != nil {
 			return shttp.Error(err)
 		}
+
+		depl.StatusChecksPassed = null.BoolFrom(false)
 	}
 
+	publishLogEvents(req.Context(), depl, []deploy.LogEvent{deploy.ExitEvent(depl)})
+
 	if depl.GithubRunID.ValueOrZero() != 0 {
 		_ = deployservice.Github().StopDeployment(depl.GithubRunID.ValueOrZero())
 	}
@@ -0,0 +1,65 @@
+package deployhandlers
+
+import (
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+)
+
+// handlerDeploymentsRetention is a dry run of the retention policy. It returns the
+// deployments of the environment whose artifacts would be removed by the next cleanup.
+func handlerDeploymentsRetention(req *app.RequestContext) *shttp.Response {
+	env, err := buildconf.NewStore().EnvironmentByID(req.Context(), req.EnvID)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	if env == nil || env.AppID != req.App.ID {
+		return shttp.NotFound()
+	}
+
+	var retention *buildconf.Retention
+	keepLast := 0
+
+	if env.Data != nil && env.Data.Retention != nil {
+		retention = env.Data.Retention
+		keepLast = retention.KeepLast
+	}
+
+	expired, err := deploy.NewStore().ExpiredDeployments(req.Context(), &deploy.ExpiredDeploymentsFilters{
+		AppID:          req.App.ID,
+		EnvID:          env.ID,
+		Limit:          100,
+		ExcludeDeleted: true,
+	})
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	deployments := []map[string]any{}
+
+	for _, d := range expired {
+		deployments = append(deployments, map[string]any{
+			"id":        d.ID.String(),
+			"branch":    d.Branch,
+			"createdAt": d.CreatedAt.UnixStr(),
+			"commit": map[string]any{
+				"sha":     d.Commit.ID.ValueOrZero(),
+				"message": d.Commit.Message.ValueOrZero(),
+			},
+		})
+	}
+
+	return &shttp.Response{
+		Data: map[string]any{
+			"retention": map[string]any{
+				"keepLast": keepLast,
+				"keepDays": retention.Days(),
+			},
+			"deployments": deployments,
+		},
+	}
+}
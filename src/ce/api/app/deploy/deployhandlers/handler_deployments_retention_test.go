@@ -0,0 +1,91 @@
+package deployhandlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhandlers"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v3"
+)
+
+type HandlerDeploymentsRetentionSuite struct {
+	suite.Suite
+	*factory.Factory
+	conn databasetest.TestDB
+}
+
+func (s *HandlerDeploymentsRetentionSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+}
+
+func (s *HandlerDeploymentsRetentionSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+}
+
+func (s *HandlerDeploymentsRetentionSuite) Test_DryRun() {
+	ctx := context.Background()
+	store := deploy.NewStore()
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app, map[string]any{
+		"Data": &buildconf.BuildConf{
+			Retention: &buildconf.Retention{KeepLast: 1, KeepDays: 7},
+		},
+	})
+
+	T10daysAgo := utils.UnixFrom(time.Now().Add(-10 * 24 * time.Hour))
+
+	d1 := s.MockDeployment(env, map[string]any{"CreatedAt": T10daysAgo, "ExitCode": null.IntFrom(0)})
+	d2 := s.MockDeployment(env, map[string]any{"CreatedAt": T10daysAgo, "ExitCode": null.IntFrom(0)})
+	d3 := s.MockDeployment(env, map[string]any{"CreatedAt": T10daysAgo, "ExitCode": null.IntFrom(0)})
+	d4 := s.MockDeployment(env, map[string]any{"ExitCode": null.IntFrom(0)})
+
+	// d1 becomes the last published deployment, d4 is currently published
+	s.NoError(store.Publish(ctx, &deploy.PublishSettings{EnvID: env.ID, DeploymentID: d1.ID, Percentage: 100, NoRollbackWatch: true}))
+	s.NoError(store.Publish(ctx, &deploy.PublishSettings{EnvID: env.ID, DeploymentID: d4.ID, Percentage: 100, NoRollbackWatch: true}))
+
+	pinned, err := store.PinDeployment(ctx, d2.ID, app.ID, true)
+	s.NoError(err)
+	s.True(pinned)
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodGet,
+		"/app/deployments/retention?envId="+env.ID.String(),
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+
+	data := struct {
+		Retention   map[string]int `json:"retention"`
+		Deployments []struct {
+			ID string `json:"id"`
+		} `json:"deployments"`
+	}{}
+
+	s.NoError(json.Unmarshal(response.Byte(), &data))
+	s.Equal(map[string]int{"keepLast": 1, "keepDays": 7}, data.Retention)
+	s.Len(data.Deployments, 1)
+	s.Equal(d3.ID.String(), data.Deployments[0].ID)
+}
+
+func TestHandlerDeploymentsRetention(t *testing.T) {
+	suite.Run(t, &HandlerDeploymentsRetentionSuite{})
+}
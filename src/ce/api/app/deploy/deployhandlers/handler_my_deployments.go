@@ -92,7 +92,12 @@ func jsonResponse(d *deploy.Deployment, withLogs bool) map[string]any {
 		"apiPathPrefix":      d.APIPathPrefix.ValueOrZero(),
 		"statusChecks":       statusChecksLogs,
 		"statusChecksPassed": d.StatusChecksPassed,
+		"httpChecks":         d.HTTPChecks,
+		"vulnerabilities":    d.Vulnerabilities,
+		"rollback":           d.Rollback,
 		"duration":           calculateDuration(d.CreatedAt, d.StoppedAt),
+		"queuePosition":      d.QueuePosition,
+		"isPinned":           d.IsPinned,
 		"commit": map[string]any{
 			"sha":     d.Commit.ID.ValueOrZero(),
 			"author":  d.Commit.Author.ValueOrZero(),
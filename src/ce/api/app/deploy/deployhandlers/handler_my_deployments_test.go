@@ -90,6 +90,11 @@ func (s *HandlerMyDeploymentsSuite) responseTemplate() (*template.Template, erro
 					"apiPathPrefix": "",
 					"published": [],
 					"statusChecksPassed": null,
+					"httpChecks": null,
+					"vulnerabilities": null,
+					"rollback": null,
+					"queuePosition": null,
+					"isPinned": false,
 					"statusChecks": null,
 					"duration": 0
 				}
@@ -1,13 +1,18 @@
 package deployhandlers
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ee/api/team"
 	"github.com/stormkit-io/stormkit-io/src/lib/model"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttperr"
 	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 )
 
 // publishSettings specifies the percentage which
@@ -26,6 +31,13 @@ type publishRequest struct {
 
 	// Publish holds a map of ids with their respective percentage to deploy.
 	Publish []publishSettings `json:"publish"`
+
+	// PublishAt is the unix timestamp to publish the deployments at.
+	// When provided, the publish is scheduled instead of being executed right away.
+	PublishAt int64 `json:"publishAt"`
+
+	// OverrideFreeze allows team owners to publish during a freeze window.
+	OverrideFreeze bool `json:"overrideFreeze"`
 }
 
 // Validate impleents model.Validate interface.
@@ -54,6 +66,10 @@ func (pr *publishRequest) Validate() *shttperr.ValidationError {
 		err.SetError("percentage", buildconf.ErrInvalidPercentage.Error())
 	}
 
+	if pr.PublishAt != 0 && pr.PublishAt <= time.Now().Unix() {
+		err.SetError("publishAt", deploy.ErrPublishAtInPast.Error())
+	}
+
 	return err.ToError()
 }
 
@@ -83,6 +99,14 @@ func handlerPublish(req *app.RequestContext) *shttp.Response {
 		return shttp.NotFound()
 	}
 
+	if data.PublishAt != 0 {
+		return schedulePublish(req, env, data)
+	}
+
+	if res := checkFreezeWindow(req, env, data.OverrideFreeze); res != nil {
+		return res
+	}
+
 	settings := []*deploy.PublishSettings{}
 
 	for _, publishDetails := range data.Publish {
@@ -93,6 +117,14 @@ func handlerPublish(req *app.RequestContext) *shttp.Response {
 		})
 	}
 
+	if err := deploy.CheckApprovals(req.Context(), settings); err != nil {
+		return shttp.Error(err)
+	}
+
+	if err := deploy.CheckVulnerabilities(req.Context(), settings); err != nil {
+		return shttp.Error(err)
+	}
+
 	if err := Publish(req.Context(), settings); err != nil {
 		return shttp.Error(err)
 	}
@@ -120,3 +152,87 @@ func handlerPublish(req *app.RequestContext) *shttp.Response {
 }
 
 var Publish = deploy.Publish
+
+// schedulePublish stores the publish request to be executed by the workerserver.
+func schedulePublish(req *app.RequestContext, env *buildconf.Env, data *publishRequest) *shttp.Response {
+	if data.OverrideFreeze {
+		if res := checkOverridePermission(req); res != nil {
+			return res
+		}
+	}
+
+	sp := &deploy.ScheduledPublish{
+		AppID:          req.App.ID,
+		EnvID:          env.ID,
+		PublishAt:      utils.UnixFrom(time.Unix(data.PublishAt, 0)),
+		OverrideFreeze: data.OverrideFreeze,
+		CreatedBy:      req.User.ID,
+	}
+
+	for _, publishDetails := range data.Publish {
+		sp.Targets = append(sp.Targets, deploy.ScheduledPublishTarget{
+			DeploymentID: publishDetails.DeploymentID,
+			Percentage:   publishDetails.Percentage,
+		})
+	}
+
+	if err := deploy.NewStore().InsertScheduledPublish(req.Context(), sp); err != nil {
+		return shttp.Error(err)
+	}
+
+	return &shttp.Response{
+		Status: http.StatusCreated,
+		Data: map[string]any{
+			"scheduled": sp,
+		},
+	}
+}
+
+// checkFreezeWindow returns an error response when the environment is in a freeze
+// window. Team owners can override the freeze by setting the override flag. The
+// permission is checked even when no window is active, as the override is kept
+// for freeze windows that start later on (e.g. while the deployment is building).
+func checkFreezeWindow(req *app.RequestContext, env *buildconf.Env, override bool) *shttp.Response {
+	if override {
+		return checkOverridePermission(req)
+	}
+
+	window := env.Data.ActiveFreezeWindow(time.Now())
+
+	if window == nil {
+		return nil
+	}
+
+	return &shttp.Response{
+		Status: deploy.ErrFreezeWindowActive.Status(),
+		Data: map[string]any{
+			"error":        deploy.ErrFreezeWindowActive.Error(),
+			"freezeWindow": window,
+		},
+	}
+}
+
+// checkOverridePermission returns an error response when the user
+// is not allowed to override freeze windows.
+func checkOverridePermission(req *app.RequestContext) *shttp.Response {
+	if req.User.IsAdmin {
+		return nil
+	}
+
+	t, err := team.NewStore().Team(req.Context(), req.App.TeamID, req.User.ID)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	if t == nil || t.CurrentUserRole != team.ROLE_OWNER {
+		return &shttp.Response{
+			Status: http.StatusForbidden,
+			Data: map[string]any{
+				"error": "Only team owners can override freeze windows.",
+			},
+		}
+	}
+
+	return nil
+}
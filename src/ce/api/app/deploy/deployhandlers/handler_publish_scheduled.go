@@ -0,0 +1,39 @@
+package deployhandlers
+
+import (
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+)
+
+// handlerPublishScheduled returns the pending scheduled publishes of the environment.
+func handlerPublishScheduled(req *app.RequestContext) *shttp.Response {
+	scheduled, err := deploy.NewStore().ScheduledPublishes(req.Context(), req.App.ID, req.EnvID)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	return &shttp.Response{
+		Data: map[string]any{
+			"scheduled": scheduled,
+		},
+	}
+}
+
+// handlerPublishScheduledDelete cancels a pending scheduled publish of the environment.
+func handlerPublishScheduledDelete(req *app.RequestContext) *shttp.Response {
+	id := utils.StringToID(req.Query().Get("id"))
+	deleted, err := deploy.NewStore().DeleteScheduledPublish(req.Context(), id, req.App.ID, req.EnvID)
+
+	if err != nil {
+		return shttp.Error(err)
+	}
+
+	if !deleted {
+		return shttp.NotFound()
+	}
+
+	return shttp.OK()
+}
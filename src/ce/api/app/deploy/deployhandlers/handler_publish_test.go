@@ -2,21 +2,25 @@ package deployhandlers_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
 
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhandlers"
 	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
 	"github.com/stormkit-io/stormkit-io/src/lib/factory"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
 )
 
 type HandlerPublishDeploymentSuite struct {
@@ -200,6 +204,215 @@ func (s *HandlerPublishDeploymentSuite) Test_BadRequest_PercentageNot100() {
 	a.Nil(s.calledSettings)
 }
 
+func (s *HandlerPublishDeploymentSuite) Test_FreezeWindow() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app, map[string]any{
+		"Data": &buildconf.BuildConf{
+			FreezeWindows: []buildconf.FreezeWindow{
+				{Name: "Release freeze", From: time.Now().Add(-time.Hour).Unix(), To: time.Now().Add(time.Hour).Unix()},
+			},
+		},
+	})
+
+	dpl := s.MockDeployment(env, nil)
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deployments/publish",
+		map[string]any{
+			"appId": app.ID.String(),
+			"envId": env.ID.String(),
+			"publish": []map[string]any{
+				{"percentage": 100, "deploymentId": dpl.ID.String()},
+			},
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	a := assert.New(s.T())
+	a.Equal(http.StatusLocked, response.Code)
+	a.Contains(response.String(), "Release freeze")
+	a.Nil(s.calledSettings)
+}
+
+func (s *HandlerPublishDeploymentSuite) Test_FreezeWindow_Override() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app, map[string]any{
+		"Data": &buildconf.BuildConf{
+			FreezeWindows: []buildconf.FreezeWindow{
+				{Name: "Release freeze", From: time.Now().Add(-time.Hour).Unix(), To: time.Now().Add(time.Hour).Unix()},
+			},
+		},
+	})
+
+	dpl := s.MockDeployment(env, nil)
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deployments/publish",
+		map[string]any{
+			"appId":          app.ID.String(),
+			"envId":          env.ID.String(),
+			"overrideFreeze": true,
+			"publish": []map[string]any{
+				{"percentage": 100, "deploymentId": dpl.ID.String()},
+			},
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	a := assert.New(s.T())
+	a.Equal(http.StatusOK, response.Code)
+	a.Len(s.calledSettings, 1)
+}
+
+func (s *HandlerPublishDeploymentSuite) Test_Schedule() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	dpl := s.MockDeployment(env, nil)
+	publishAt := time.Now().Add(time.Hour).Unix()
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deployments/publish",
+		map[string]any{
+			"appId":     app.ID.String(),
+			"envId":     env.ID.String(),
+			"publishAt": publishAt,
+			"publish": []map[string]any{
+				{"percentage": 100, "deploymentId": dpl.ID.String()},
+			},
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	a := assert.New(s.T())
+	a.Equal(http.StatusCreated, response.Code)
+	a.Nil(s.calledSettings)
+
+	scheduled, err := deploy.NewStore().ScheduledPublishes(context.Background(), app.ID, env.ID)
+	a.NoError(err)
+	a.Len(scheduled, 1)
+	a.Equal(publishAt, scheduled[0].PublishAt.Unix())
+	a.Equal(dpl.ID, scheduled[0].Targets[0].DeploymentID)
+}
+
+func (s *HandlerPublishDeploymentSuite) Test_Schedule_InPast() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	dpl := s.MockDeployment(env, nil)
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deployments/publish",
+		map[string]any{
+			"appId":     app.ID.String(),
+			"envId":     env.ID.String(),
+			"publishAt": time.Now().Add(-time.Hour).Unix(),
+			"publish": []map[string]any{
+				{"percentage": 100, "deploymentId": dpl.ID.String()},
+			},
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	expectedResponse := `{"errors":{"publishAt":"Scheduled publish date has to be in the future."},"ok":false}`
+
+	a := assert.New(s.T())
+	a.Equal(http.StatusBadRequest, response.Code)
+	a.Equal(expectedResponse, response.String())
+}
+
+func (s *HandlerPublishDeploymentSuite) Test_ProtectedEnvironment_RequiresApproval() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app, map[string]any{
+		"Data": &buildconf.BuildConf{
+			Protection: &buildconf.Protection{RequiredApprovals: 1},
+		},
+	})
+
+	dpl := s.MockDeployment(env, nil)
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deployments/publish",
+		map[string]any{
+			"appId": app.ID.String(),
+			"envId": env.ID.String(),
+			"publish": []map[string]any{
+				{"percentage": 100, "deploymentId": dpl.ID.String()},
+			},
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	a := assert.New(s.T())
+	a.Equal(http.StatusForbidden, response.Code)
+	a.JSONEq(`{"error":"Deployment has to be approved before it can be published to this environment.","code":"approval-required"}`, response.String())
+	a.Nil(s.calledSettings)
+}
+
+func (s *HandlerPublishDeploymentSuite) Test_BlockingVulnerabilities() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+
+	snapshot, err := json.Marshal(deploy.ConfigSnapshot{
+		BuildConfig: &buildconf.BuildConf{
+			VulnScan: &buildconf.VulnScan{BlockPublishOn: osv.SeverityHigh},
+		},
+	})
+
+	s.NoError(err)
+
+	dpl := s.MockDeployment(env, map[string]any{"ConfigCopy": snapshot})
+
+	s.NoError(deploy.NewStore().UpdateVulnerabilities(context.Background(), dpl.ID, deploy.Vulnerabilities{
+		{Package: osv.Package{Ecosystem: "npm", Name: "lodash", Version: "4.17.20"}, ID: "GHSA-1", Severity: osv.SeverityCritical},
+	}))
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(deployhandlers.Services).Router().Handler(),
+		shttp.MethodPost,
+		"/app/deployments/publish",
+		map[string]any{
+			"appId": app.ID.String(),
+			"envId": env.ID.String(),
+			"publish": []map[string]any{
+				{"percentage": 100, "deploymentId": dpl.ID.String()},
+			},
+		},
+		map[string]string{
+			"Authorization": usertest.Authorization(usr.ID),
+		},
+	)
+
+	a := assert.New(s.T())
+	a.Equal(http.StatusForbidden, response.Code)
+	a.JSONEq(`{"error":"Deployment has vulnerabilities at or above the blocking severity and cannot be published.","code":"vulnerabilities-found"}`, response.String())
+	a.Nil(s.calledSettings)
+}
+
 func TestHandlerPublishDeployment(t *testing.T) {
 	suite.Run(t, &HandlerPublishDeploymentSuite{})
 }
@@ -21,7 +21,11 @@ func Services(r *shttp.Router) *shttp.Service {
 		Handler(shttp.MethodPost, "/stop", shttp.WithRateLimit(app.WithApp(handlerDeployStop), nil))
 
 	s.NewEndpoint("/app/{did:[0-9]+}/deploy").
-		Handler(shttp.MethodGet, "/{deploymentId:[0-9]+}", app.WithApp(handlerDeployGet))
+		Handler(shttp.MethodGet, "/{deploymentId:[0-9]+}", app.WithApp(handlerDeployGet)).
+		Handler(shttp.MethodGet, "/{deploymentId:[0-9]+}/logs/stream", app.WithApp(handlerDeployLogsStream)).
+		Handler(shttp.MethodGet, "/{deploymentId:[0-9]+}/compare", app.WithApp(handlerDeployCompare)).
+		Handler(shttp.MethodPost, "/{deploymentId:[0-9]+}/pin", app.WithApp(handlerDeployPin)).
+		Handler(shttp.MethodDelete, "/{deploymentId:[0-9]+}/pin", app.WithApp(handlerDeployPin))
 
 	s.NewEndpoint("/app/{did:[0-9]+}/manifest").
 		Handler(shttp.MethodGet, "/{deploymentId:[0-9]+}", shttp.WithRateLimit(
@@ -29,6 +33,13 @@ func Services(r *shttp.Router) *shttp.Service {
 			nil,
 		))
 
+	s.NewEndpoint("/app/{did:[0-9]+}/sbom").
+		Handler(shttp.MethodGet, "", app.WithApp(handlerDeploySBOMSearch)).
+		Handler(shttp.MethodGet, "/{deploymentId:[0-9]+}", shttp.WithRateLimit(
+			app.WithApp(handlerDeploySBOMGet),
+			nil,
+		))
+
 	s.NewEndpoint("/app/deployments").
 		Handler(shttp.MethodPost, "", shttp.WithRateLimit(
 			app.WithApp(handlerDeployments),
@@ -37,7 +48,12 @@ func Services(r *shttp.Router) *shttp.Service {
 		Handler(shttp.MethodPost, "/publish", shttp.WithRateLimit(
 			app.WithApp(handlerPublish),
 			nil,
-		))
+		)).
+		Handler(shttp.MethodGet, "/publish/scheduled", app.WithApp(handlerPublishScheduled, &app.Opts{Env: true})).
+		Handler(shttp.MethodDelete, "/publish/scheduled", app.WithApp(handlerPublishScheduledDelete, &app.Opts{Env: true})).
+		Handler(shttp.MethodGet, "/retention", app.WithApp(handlerDeploymentsRetention, &app.Opts{Env: true})).
+		Handler(shttp.MethodGet, "/approvals", app.WithApp(handlerApprovals, &app.Opts{Env: true})).
+		Handler(shttp.MethodPost, "/approvals", app.WithApp(handlerApprovalsDecide, &app.Opts{Env: true}))
 
 	return s
 }
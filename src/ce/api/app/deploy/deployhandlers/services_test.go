@@ -17,15 +17,26 @@ func (s *ServicesSuite) TestServices() {
 
 	handlers := []string{
 		"DELETE:/app/deploy",
+		"DELETE:/app/deployments/publish/scheduled",
+		"DELETE:/app/{did:[0-9]+}/deploy/{deploymentId:[0-9]+}/pin",
+		"GET:/app/deployments/approvals",
+		"GET:/app/deployments/publish/scheduled",
+		"GET:/app/deployments/retention",
 		"GET:/app/{did:[0-9]+}/deploy/{deploymentId:[0-9]+}",
+		"GET:/app/{did:[0-9]+}/deploy/{deploymentId:[0-9]+}/compare",
+		"GET:/app/{did:[0-9]+}/deploy/{deploymentId:[0-9]+}/logs/stream",
 		"GET:/app/{did:[0-9]+}/manifest/{deploymentId:[0-9]+}",
+		"GET:/app/{did:[0-9]+}/sbom",
+		"GET:/app/{did:[0-9]+}/sbom/{deploymentId:[0-9]+}",
 		"GET:/my/deployments",
 		"POST:/app/deploy",
 		"POST:/app/deploy/callback",
 		"POST:/app/deploy/restart",
 		"POST:/app/deploy/stop",
 		"POST:/app/deployments",
+		"POST:/app/deployments/approvals",
 		"POST:/app/deployments/publish",
+		"POST:/app/{did:[0-9]+}/deploy/{deploymentId:[0-9]+}/pin",
 	}
 
 	s.Equal(handlers, services.HandlerKeys())
@@ -4,13 +4,11 @@ import (
 	"context"
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
-	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/github"
 	"github.com/stormkit-io/stormkit-io/src/lib/config"
 	"github.com/stormkit-io/stormkit-io/src/lib/discord"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
@@ -80,37 +78,13 @@ var Exec = func(ctx context.Context, d *deploy.Deployment) {
 // statusChecks creates a status check for the deployment with a finite
 // status like success or failure.
 func statusChecks(details *AppDetails, d *deploy.Deployment) {
-	if !strings.HasPrefix(details.Repo, "github/") || !admin.MustConfig().IsGithubEnabled() {
-		return
-	}
-
-	var status string
+	state := StatusSuccess
 
-	if d.ExitCode.Valid && d.ExitCode.ValueOrZero() == 0 {
-		status = github.StatusSuccess
-	} else {
-		status = github.StatusFailure
+	if !d.ExitCode.Valid || d.ExitCode.ValueOrZero() != 0 {
+		state = StatusFailure
 	}
 
-	cnf := admin.MustConfig()
-	err := github.CreateStatus(details.Repo, d.Branch, cnf.DeploymentLogsURL(d.AppID, d.ID), status)
-
-	if err != nil {
-		message := err.Error()
-		ignoredErrors := []string{"403", "404", "422", "not accessible"}
-		shouldPrint := true
-
-		for _, ignoredError := range ignoredErrors {
-			if strings.Contains(message, ignoredError) {
-				shouldPrint = false
-				break
-			}
-		}
-
-		if shouldPrint {
-			slog.Errorf("error while creating github status: %v", err)
-		}
-	}
+	logStatusError(ReportStatus(NewDeploymentStatus(details.Repo, details.UserID, d, state)))
 }
 
 // notifies discord channel that a deployment has ended.
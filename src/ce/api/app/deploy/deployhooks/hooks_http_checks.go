@@ -0,0 +1,66 @@
+package deployhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+)
+
+// httpChecksStatusContext is the name of the commit status that
+// reports the outcome of the http checks.
+const httpChecksStatusContext = "Stormkit / HTTP checks"
+
+// HTTPChecksStatus reports the http check results as a commit status.
+var HTTPChecksStatus = func(ctx context.Context, d *deploy.Deployment, results deploy.HTTPCheckResults) {
+	if !StatusChecksEnabled || len(results) == 0 {
+		return
+	}
+
+	details, err := NewStore().AppDetailsForHooks(d.ID)
+
+	if err != nil {
+		slog.Errorf("failed while fetching details for provider: %v", err)
+		return
+	}
+
+	if details == nil {
+		return
+	}
+
+	state := StatusSuccess
+
+	if !results.Passed() {
+		state = StatusFailure
+	}
+
+	logStatusError(ReportStatus(CommitStatus{
+		UserID:      details.UserID,
+		Repo:        details.Repo,
+		Branch:      d.Branch,
+		SHA:         d.Commit.ID.ValueOrZero(),
+		TargetURL:   admin.MustConfig().DeploymentLogsURL(d.AppID, d.ID),
+		State:       state,
+		Context:     httpChecksStatusContext,
+		Description: httpChecksDescription(results),
+	}))
+}
+
+// httpChecksDescription returns a short summary of the results.
+func httpChecksDescription(results deploy.HTTPCheckResults) string {
+	failed := 0
+
+	for _, result := range results {
+		if !result.Passed {
+			failed++
+		}
+	}
+
+	if failed == 0 {
+		return fmt.Sprintf("%d/%d checks passed", len(results), len(results))
+	}
+
+	return fmt.Sprintf("%d/%d checks failed", failed, len(results))
+}
@@ -0,0 +1,51 @@
+package deployhooks
+
+import (
+	"context"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/tasks"
+)
+
+// RunHTTPChecks is a wrapper around deploy.RunHTTPChecks so that it can be mocked in tests.
+var RunHTTPChecks = deploy.RunHTTPChecks
+
+// AutoPublish publishes a successful deployment unless it is blocked by its http checks
+// or by its vulnerabilities. Http checks can take a while to complete, therefore deployments
+// that have http checks are published in the background by the workerserver.
+func AutoPublish(ctx context.Context, d *deploy.Deployment) error {
+	if len(d.HTTPChecksConfig()) > 0 {
+		_, err := tasks.Enqueue(ctx, tasks.DeploymentPublish, d.ID.String(), nil)
+		return err
+	}
+
+	return RunChecksAndPublish(ctx, d)
+}
+
+// RunChecksAndPublish executes the http checks against the deployment preview, stores
+// the results and reports them as a commit status. The deployment is published when
+// all checks pass and no vulnerabilities at or above the configured severity were found.
+func RunChecksAndPublish(ctx context.Context, d *deploy.Deployment) error {
+	if checks := d.HTTPChecksConfig(); len(checks) > 0 {
+		previewURL := admin.MustConfig().PreviewURL(d.DisplayName, d.ID.String())
+		results := RunHTTPChecks(ctx, previewURL, checks)
+
+		if err := deploy.NewStore().UpdateHTTPChecks(ctx, d.ID, results); err != nil {
+			return err
+		}
+
+		d.HTTPChecks = results
+		HTTPChecksStatus(ctx, d, results)
+
+		if !results.Passed() {
+			return nil
+		}
+	}
+
+	if len(d.BlockingVulnerabilities()) > 0 {
+		return nil
+	}
+
+	return deploy.AutoPublishIfNecessary(ctx, d)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/bitbucket"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitea"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/github"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitlab"
 	"github.com/stormkit-io/stormkit-io/src/lib/config"
@@ -29,13 +30,19 @@ func pullRequestPreview(details *AppDetails, d *deploy.Deployment) {
 
 	var body string
 	cnf := admin.MustConfig()
+	previewURL := cnf.PreviewURL(details.DisplayName, d.ID.String())
+
+	// Ephemeral pull request environments have a stable hostname that is kept across deployments.
+	if d.BuildConfig != nil && d.BuildConfig.PullRequestNumber != 0 {
+		previewURL = cnf.PreviewURL(details.DisplayName, d.Env)
+	}
 
 	switch d.ExitCode.ValueOrZero() {
 	case 0:
 		body =
 			"#### Deployment completed\n\n" +
 				"This pull request was successfully built by **[Stormkit](https://www.stormkit.io)**. You can preview it using the following link.\n" +
-				fmt.Sprintf("> %s", cnf.PreviewURL(details.DisplayName, d.ID.String()))
+				fmt.Sprintf("> %s", previewURL)
 	default:
 		body =
 			"#### Deployment failed\n\n" +
@@ -50,6 +57,8 @@ func pullRequestPreview(details *AppDetails, d *deploy.Deployment) {
 		PullRequestPreviewGitlab(details, body)
 	} else if strings.HasPrefix(details.Repo, "bitbucket") {
 		PullRequestPreviewBitbucket(details, body)
+	} else if strings.HasPrefix(details.Repo, "gitea/") {
+		PullRequestPreviewGitea(details, body)
 	}
 }
 
@@ -156,3 +165,17 @@ func PullRequestPreviewBitbucket(details *AppDetails, body string) {
 		}
 	}
 }
+
+// PullRequestPreviewGitea creates a pull request preview for Gitea and Forgejo projects.
+func PullRequestPreviewGitea(details *AppDetails, body string) {
+	client, err := gitea.NewClient(details.UserID)
+
+	if client == nil || err != nil {
+		slog.Errorf("failed while creating gitea client: %v", err)
+		return
+	}
+
+	if _, err := client.PullRequestComment(details.Repo, details.PullRequestNumber, body); err != nil {
+		slog.Errorf("failed creating a gitea preview comment: %v, app=%d, pr=%d", err, details.AppID, details.PullRequestNumber)
+	}
+}
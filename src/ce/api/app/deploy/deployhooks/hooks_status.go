@@ -0,0 +1,180 @@
+package deployhooks
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	gh "github.com/google/go-github/v71/github"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/bitbucket"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitea"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/github"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitlab"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttperr"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	gl "github.com/xanzy/go-gitlab"
+)
+
+// Provider agnostic commit status states. The reporter maps
+// them to the states of the git provider.
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+)
+
+// CommitStatus represents a commit status that is reported to the git provider.
+type CommitStatus struct {
+	UserID      types.ID // UserID is the owner of the oauth2 token for GitLab, Bitbucket and Gitea
+	Repo        string   // Repo is the repository of the app, e.g. gitlab/owner/slug
+	Branch      string   // Branch is used to find the latest commit when SHA is empty
+	SHA         string
+	TargetURL   string
+	State       string // One of StatusPending, StatusSuccess or StatusFailure
+	Context     string // Context differentiates this status from the others, defaults to Stormkit
+	Description string
+}
+
+// NewDeploymentStatus returns the commit status of the given deployment.
+func NewDeploymentStatus(repo string, userID types.ID, d *deploy.Deployment, state string) CommitStatus {
+	description := "Deploying application"
+
+	switch state {
+	case StatusSuccess:
+		description = "Deployment completed"
+	case StatusFailure:
+		description = "Deployment failed"
+	}
+
+	return CommitStatus{
+		UserID:      userID,
+		Repo:        repo,
+		Branch:      d.Branch,
+		SHA:         d.Commit.ID.ValueOrZero(),
+		TargetURL:   admin.MustConfig().DeploymentLogsURL(d.AppID, d.ID),
+		State:       state,
+		Description: description,
+	}
+}
+
+// ReportStatus posts the commit status to the git provider of the repository.
+// Providers that are not configured on this instance are skipped.
+var ReportStatus = func(status CommitStatus) error {
+	cnf := admin.MustConfig()
+
+	switch {
+	case strings.HasPrefix(status.Repo, "github/") && cnf.IsGithubEnabled():
+		return github.CreateCommitStatus(status.Repo, github.StatusOpts{
+			Branch:      status.Branch,
+			SHA:         status.SHA,
+			TargetURL:   status.TargetURL,
+			State:       mapStatus(status.State, github.StatusPending, github.StatusSuccess, github.StatusFailure),
+			Context:     status.Context,
+			Description: status.Description,
+		})
+
+	case strings.HasPrefix(status.Repo, "gitlab/") && cnf.IsGitlabEnabled():
+		client, err := gitlab.NewClient(status.UserID)
+
+		// The user has not connected the provider.
+		if client == nil || err != nil {
+			return nil
+		}
+
+		return client.CreateStatus(status.Repo, gitlab.StatusOpts{
+			Branch:      status.Branch,
+			SHA:         status.SHA,
+			TargetURL:   status.TargetURL,
+			State:       mapStatus(status.State, gitlab.StatusPending, gitlab.StatusSuccess, gitlab.StatusFailed),
+			Name:        status.Context,
+			Description: status.Description,
+		})
+
+	case strings.HasPrefix(status.Repo, "bitbucket/") && cnf.IsBitbucketEnabled():
+		client, err := bitbucket.NewClient(status.UserID)
+
+		// The user has not connected the provider.
+		if client == nil || err != nil {
+			return nil
+		}
+
+		return client.CreateStatus(status.Repo, bitbucket.StatusOpts{
+			Branch:      status.Branch,
+			SHA:         status.SHA,
+			TargetURL:   status.TargetURL,
+			State:       mapStatus(status.State, bitbucket.StatusPending, bitbucket.StatusSuccess, bitbucket.StatusFailed),
+			Name:        status.Context,
+			Description: status.Description,
+		})
+
+	case strings.HasPrefix(status.Repo, "gitea/") && cnf.IsGiteaEnabled():
+		client, err := gitea.NewClient(status.UserID)
+
+		// The user has not connected the provider.
+		if client == nil || err != nil {
+			return nil
+		}
+
+		return client.CreateStatus(status.Repo, gitea.StatusOpts{
+			Branch:      status.Branch,
+			SHA:         status.SHA,
+			TargetURL:   status.TargetURL,
+			State:       mapStatus(status.State, gitea.StatusPending, gitea.StatusSuccess, gitea.StatusFailure),
+			Context:     status.Context,
+			Description: status.Description,
+		})
+	}
+
+	return nil
+}
+
+// logStatusError logs the errors returned by ReportStatus. Missing permissions and
+// repositories that are no longer accessible are usually caused by the configuration
+// of the provider rather than by Stormkit, therefore they are logged as warnings.
+func logStatusError(err error) {
+	if err == nil {
+		return
+	}
+
+	switch statusCode(err) {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusUnprocessableEntity:
+		slog.Warnf("cannot create commit status, check the permissions of the git provider: %v", err)
+	default:
+		slog.Errorf("error while creating commit status: %v", err)
+	}
+}
+
+// statusCode returns the response status code of the error returned by the git provider,
+// or zero when the error does not carry a status code.
+func statusCode(err error) int {
+	var ghErr *gh.ErrorResponse
+	var glErr *gl.ErrorResponse
+	var skErr *shttperr.Error
+
+	switch {
+	case errors.As(err, &ghErr) && ghErr.Response != nil:
+		return ghErr.Response.StatusCode
+	case errors.As(err, &glErr) && glErr.Response != nil:
+		return glErr.Response.StatusCode
+	case errors.As(err, &skErr):
+		return skErr.Status()
+	default:
+		return 0
+	}
+}
+
+// mapStatus maps the provider agnostic state to the state of the provider.
+func mapStatus(state, pending, success, failure string) string {
+	switch state {
+	case StatusSuccess:
+		return success
+	case StatusFailure:
+		return failure
+	default:
+		return pending
+	}
+}
@@ -139,6 +139,41 @@ func (s *HooksSuite) TestShouldNotPublish_WhenDeploymentFailed() {
 	a.Nil(s.calledSettings)
 }
 
+func (s *HooksSuite) TestStatusChecks_ReportsStatus() {
+	original := deployhooks.ReportStatus
+	defer func() { deployhooks.ReportStatus = original }()
+
+	statuses := []deployhooks.CommitStatus{}
+	deployhooks.ReportStatus = func(status deployhooks.CommitStatus) error {
+		statuses = append(statuses, status)
+		return nil
+	}
+
+	appl := s.MockApp(nil, map[string]any{
+		"Repo": "gitlab/stormkit-io/app-stormkit-io",
+	})
+
+	env := s.MockEnv(appl)
+	depl := s.MockDeployment(env, map[string]any{
+		"ExitCode":          null.NewInt(1, true),
+		"PullRequestNumber": null.NewInt(0, true),
+		"Branch":            "main",
+		"Commit": deploy.CommitInfo{
+			ID: null.NewString("7d1ab1c5c6e0b4e1f2a3d4c5b6a7980112233445", true),
+		},
+	})
+
+	deployhooks.Exec(context.Background(), depl.Deployment)
+
+	s.Len(statuses, 1)
+	s.Equal(appl.UserID, statuses[0].UserID)
+	s.Equal("gitlab/stormkit-io/app-stormkit-io", statuses[0].Repo)
+	s.Equal("main", statuses[0].Branch)
+	s.Equal("7d1ab1c5c6e0b4e1f2a3d4c5b6a7980112233445", statuses[0].SHA)
+	s.Equal(deployhooks.StatusFailure, statuses[0].State)
+	s.Equal("Deployment failed", statuses[0].Description)
+}
+
 func TestHooks(t *testing.T) {
 	suite.Run(t, &HooksSuite{})
 }
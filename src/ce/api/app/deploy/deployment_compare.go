@@ -0,0 +1,325 @@
+package deploy
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+)
+
+// FileChanges is the list of files that differ between two deployments.
+type FileChanges struct {
+	Added   []string `json:"added"`
+	Removed []string `json:"removed"`
+	Changed []string `json:"changed"` // Changed contains the files that have a different etag
+}
+
+// SizeDelta is the difference of a package size between two deployments.
+type SizeDelta struct {
+	Base  int64 `json:"base"`
+	Head  int64 `json:"head"`
+	Delta int64 `json:"delta"`
+}
+
+// ValueChange is a single value that differs between two deployments.
+type ValueChange struct {
+	Base any `json:"base"`
+	Head any `json:"head"`
+}
+
+// RedirectChanges is the list of redirect rules that differ between two deployments.
+type RedirectChanges struct {
+	Added   []Redirect `json:"added"`
+	Removed []Redirect `json:"removed"`
+}
+
+// HeaderChanges is the list of header rules that differ between two deployments.
+type HeaderChanges struct {
+	Added   []HeaderRule `json:"added"`
+	Removed []HeaderRule `json:"removed"`
+}
+
+// HeaderRule is the json representation of a custom header.
+type HeaderRule struct {
+	Location string `json:"location"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+}
+
+// CommitRange is the range of commits between the base and head deployments.
+type CommitRange struct {
+	Base       string `json:"base"`
+	Head       string `json:"head"`
+	CompareURL string `json:"compareUrl,omitempty"`
+}
+
+// DeploymentDiff is the difference between two deployments.
+type DeploymentDiff struct {
+	BaseID          types.ID                `json:"baseId,string"`
+	HeadID          types.ID                `json:"headId,string"`
+	StaticFiles     FileChanges             `json:"staticFiles"`
+	APIFiles        FileChanges             `json:"apiFiles"`
+	APIRoutes       FileChanges             `json:"apiRoutes"`
+	FunctionHandler *ValueChange            `json:"functionHandler,omitempty"`
+	APIHandler      *ValueChange            `json:"apiHandler,omitempty"`
+	Redirects       RedirectChanges         `json:"redirects"`
+	Headers         HeaderChanges           `json:"headers"`
+	Sizes           map[string]SizeDelta    `json:"sizes"` // client | server | api
+	Config          map[string]*ValueChange `json:"config"`
+	Commits         CommitRange             `json:"commits"`
+}
+
+// Compare returns the difference between the base and the head deployments.
+func Compare(base, head *Deployment) *DeploymentDiff {
+	baseManifest := base.BuildManifest
+	headManifest := head.BuildManifest
+
+	if baseManifest == nil {
+		baseManifest = &BuildManifest{}
+	}
+
+	if headManifest == nil {
+		headManifest = &BuildManifest{}
+	}
+
+	diff := &DeploymentDiff{
+		BaseID:      base.ID,
+		HeadID:      head.ID,
+		StaticFiles: compareFiles(staticFileEtags(baseManifest), staticFileEtags(headManifest)),
+		APIFiles:    compareFiles(apiFileNames(baseManifest), apiFileNames(headManifest)),
+		APIRoutes:   compareFiles(namesOf(baseManifest.APIRoutes), namesOf(headManifest.APIRoutes)),
+		Redirects:   compareRedirects(baseManifest.Redirects, headManifest.Redirects),
+		Headers:     compareHeaders(baseManifest.Headers, headManifest.Headers),
+		Config:      compareConfig(base.Snapshot(), head.Snapshot()),
+		Sizes: map[string]SizeDelta{
+			"client": newSizeDelta(base.S3TotalSizeInBytes.ValueOrZero(), head.S3TotalSizeInBytes.ValueOrZero()),
+			"server": newSizeDelta(base.ServerPackageSize.ValueOrZero(), head.ServerPackageSize.ValueOrZero()),
+			"api":    newSizeDelta(base.APIPackageSize.ValueOrZero(), head.APIPackageSize.ValueOrZero()),
+		},
+		Commits: CommitRange{
+			Base: base.Commit.ID.ValueOrZero(),
+			Head: head.Commit.ID.ValueOrZero(),
+		},
+	}
+
+	if baseManifest.FunctionHandler != headManifest.FunctionHandler {
+		diff.FunctionHandler = &ValueChange{Base: baseManifest.FunctionHandler, Head: headManifest.FunctionHandler}
+	}
+
+	if baseManifest.APIHandler != headManifest.APIHandler {
+		diff.APIHandler = &ValueChange{Base: baseManifest.APIHandler, Head: headManifest.APIHandler}
+	}
+
+	if diff.Commits.Base != "" && diff.Commits.Head != "" && diff.Commits.Base != diff.Commits.Head {
+		diff.Commits.CompareURL = head.RepoCompareURL(diff.Commits.Base, diff.Commits.Head)
+	}
+
+	return diff
+}
+
+// RepoCompareURL returns the url of the page that displays the commits between base and head
+// in the repository provider. It returns an empty string when the provider is unknown.
+func (d *Deployment) RepoCompareURL(base, head string) string {
+	pieces := strings.Split(d.CheckoutRepo, "/")
+
+	if len(pieces) < 3 {
+		return ""
+	}
+
+	slug := d.RepoSlug()
+
+	switch pieces[0] {
+	case "github":
+		return fmt.Sprintf("https://github.com/%s/compare/%s...%s", slug, base, head)
+	case "gitlab":
+		return fmt.Sprintf("https://gitlab.com/%s/-/compare/%s...%s", slug, base, head)
+	case "bitbucket":
+		return fmt.Sprintf("https://bitbucket.org/%s/branches/compare/%s%%0D%s", slug, head, base)
+	case "gitea":
+		return admin.MustConfig().GiteaURL(fmt.Sprintf("/%s/compare/%s...%s", slug, base, head))
+	default:
+		return ""
+	}
+}
+
+// staticFileEtags returns the static files mapped to their etags. Older manifests
+// only contain the list of cdn files, which may not have an etag. Such files are
+// only detected when they are added or removed.
+func staticFileEtags(bm *BuildManifest) map[string]string {
+	files := map[string]string{}
+
+	for name, headers := range bm.StaticFiles {
+		files[name] = headers["etag"]
+	}
+
+	if len(files) == 0 {
+		for _, f := range bm.CDNFiles {
+			files[f.Name] = f.Headers["etag"]
+		}
+	}
+
+	return files
+}
+
+func apiFileNames(bm *BuildManifest) map[string]string {
+	files := map[string]string{}
+
+	for _, f := range bm.APIFiles {
+		files[f.FileName] = ""
+	}
+
+	return files
+}
+
+func namesOf(names []string) map[string]string {
+	files := map[string]string{}
+
+	for _, name := range names {
+		files[name] = ""
+	}
+
+	return files
+}
+
+func compareFiles(base, head map[string]string) FileChanges {
+	changes := FileChanges{
+		Added:   []string{},
+		Removed: []string{},
+		Changed: []string{},
+	}
+
+	for name, etag := range head {
+		baseEtag, ok := base[name]
+
+		if !ok {
+			changes.Added = append(changes.Added, name)
+		} else if baseEtag != etag {
+			changes.Changed = append(changes.Changed, name)
+		}
+	}
+
+	for name := range base {
+		if _, ok := head[name]; !ok {
+			changes.Removed = append(changes.Removed, name)
+		}
+	}
+
+	sort.Strings(changes.Added)
+	sort.Strings(changes.Removed)
+	sort.Strings(changes.Changed)
+
+	return changes
+}
+
+func compareRedirects(base, head []Redirect) RedirectChanges {
+	changes := RedirectChanges{
+		Added:   []Redirect{},
+		Removed: []Redirect{},
+	}
+
+	contains := func(list []Redirect, r Redirect) bool {
+		for _, item := range list {
+			if reflect.DeepEqual(item, r) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	for _, r := range head {
+		if !contains(base, r) {
+			changes.Added = append(changes.Added, r)
+		}
+	}
+
+	for _, r := range base {
+		if !contains(head, r) {
+			changes.Removed = append(changes.Removed, r)
+		}
+	}
+
+	return changes
+}
+
+func compareHeaders(base, head []CustomHeader) HeaderChanges {
+	changes := HeaderChanges{
+		Added:   []HeaderRule{},
+		Removed: []HeaderRule{},
+	}
+
+	rules := func(headers []CustomHeader) map[HeaderRule]bool {
+		m := map[HeaderRule]bool{}
+
+		for _, h := range headers {
+			m[HeaderRule{Location: h.Location, Key: h.Key, Value: h.Value}] = true
+		}
+
+		return m
+	}
+
+	baseRules, headRules := rules(base), rules(head)
+
+	for _, h := range head {
+		rule := HeaderRule{Location: h.Location, Key: h.Key, Value: h.Value}
+
+		if !baseRules[rule] {
+			changes.Added = append(changes.Added, rule)
+		}
+	}
+
+	for _, h := range base {
+		rule := HeaderRule{Location: h.Location, Key: h.Key, Value: h.Value}
+
+		if !headRules[rule] {
+			changes.Removed = append(changes.Removed, rule)
+		}
+	}
+
+	return changes
+}
+
+// compareConfig returns the config snapshot fields that differ. Nested
+// objects are flattened, e.g. `build.vars.NODE_ENV`.
+func compareConfig(base, head map[string]any) map[string]*ValueChange {
+	flatBase, flatHead := map[string]any{}, map[string]any{}
+	flatten("", base, flatBase)
+	flatten("", head, flatHead)
+
+	changes := map[string]*ValueChange{}
+
+	for key, value := range flatHead {
+		if baseValue, ok := flatBase[key]; !ok || !reflect.DeepEqual(baseValue, value) {
+			changes[key] = &ValueChange{Base: flatBase[key], Head: value}
+		}
+	}
+
+	for key, value := range flatBase {
+		if _, ok := flatHead[key]; !ok {
+			changes[key] = &ValueChange{Base: value, Head: nil}
+		}
+	}
+
+	return changes
+}
+
+func flatten(prefix string, values map[string]any, out map[string]any) {
+	for key, value := range values {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if nested, ok := value.(map[string]any); ok && len(nested) > 0 {
+			flatten(key, nested, out)
+		} else {
+			out[key] = value
+		}
+	}
+}
+
+func newSizeDelta(base, head int64) SizeDelta {
+	return SizeDelta{Base: base, Head: head, Delta: head - base}
+}
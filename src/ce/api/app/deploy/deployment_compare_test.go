@@ -0,0 +1,115 @@
+package deploy_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v3"
+)
+
+type DeploymentCompareSuite struct {
+	suite.Suite
+}
+
+func (s *DeploymentCompareSuite) Test_Compare() {
+	base := &deploy.Deployment{
+		ID:                 1,
+		CheckoutRepo:       "github/stormkit-io/app-stormkit-io",
+		ConfigCopy:         []byte(`{"build":{"vars":{"NODE_ENV":"production","API_URL":"https://api"}}}`),
+		S3TotalSizeInBytes: null.IntFrom(1000),
+		Commit:             deploy.CommitInfo{ID: null.StringFrom("aaa")},
+		BuildManifest: &deploy.BuildManifest{
+			StaticFiles: deploy.StaticFiles{
+				"/index.html": {"etag": "1"},
+				"/about.html": {"etag": "2"},
+				"/old.html":   {"etag": "3"},
+			},
+			APIFiles:        []deploy.APIFile{{FileName: "/api/users.js"}},
+			FunctionHandler: "server.mjs:handler",
+			Redirects:       []deploy.Redirect{{From: "/old", To: "/new"}},
+			Headers:         []deploy.CustomHeader{{Location: "/*", Key: "x-frame-options", Value: "DENY"}},
+		},
+	}
+
+	head := &deploy.Deployment{
+		ID:                 2,
+		CheckoutRepo:       "github/stormkit-io/app-stormkit-io",
+		ConfigCopy:         []byte(`{"build":{"vars":{"NODE_ENV":"production","DEBUG":"1"}}}`),
+		S3TotalSizeInBytes: null.IntFrom(1500),
+		Commit:             deploy.CommitInfo{ID: null.StringFrom("bbb")},
+		BuildManifest: &deploy.BuildManifest{
+			StaticFiles: deploy.StaticFiles{
+				"/index.html": {"etag": "1"},
+				"/about.html": {"etag": "4"},
+				"/new.html":   {"etag": "5"},
+			},
+			APIFiles:        []deploy.APIFile{{FileName: "/api/users.js"}, {FileName: "/api/posts.js"}},
+			FunctionHandler: "server.mjs:handler",
+			Redirects:       []deploy.Redirect{{From: "/old", To: "/newer"}},
+			Headers:         []deploy.CustomHeader{{Location: "/*", Key: "x-frame-options", Value: "DENY"}},
+		},
+	}
+
+	diff := deploy.Compare(base, head)
+
+	s.Equal(deploy.FileChanges{
+		Added:   []string{"/new.html"},
+		Removed: []string{"/old.html"},
+		Changed: []string{"/about.html"},
+	}, diff.StaticFiles)
+
+	s.Equal([]string{"/api/posts.js"}, diff.APIFiles.Added)
+	s.Empty(diff.APIFiles.Removed)
+	s.Nil(diff.FunctionHandler)
+	s.Nil(diff.APIHandler)
+
+	s.Equal([]deploy.Redirect{{From: "/old", To: "/newer"}}, diff.Redirects.Added)
+	s.Equal([]deploy.Redirect{{From: "/old", To: "/new"}}, diff.Redirects.Removed)
+	s.Empty(diff.Headers.Added)
+	s.Empty(diff.Headers.Removed)
+
+	s.Equal(deploy.SizeDelta{Base: 1000, Head: 1500, Delta: 500}, diff.Sizes["client"])
+	s.Equal(deploy.SizeDelta{}, diff.Sizes["server"])
+
+	s.Equal(map[string]*deploy.ValueChange{
+		"build.vars.API_URL": {Base: "https://api", Head: nil},
+		"build.vars.DEBUG":   {Base: nil, Head: "1"},
+	}, diff.Config)
+
+	s.Equal(deploy.CommitRange{
+		Base:       "aaa",
+		Head:       "bbb",
+		CompareURL: "https://github.com/stormkit-io/app-stormkit-io/compare/aaa...bbb",
+	}, diff.Commits)
+}
+
+func (s *DeploymentCompareSuite) Test_Compare_NoManifest() {
+	diff := deploy.Compare(&deploy.Deployment{ID: 1}, &deploy.Deployment{
+		ID: 2,
+		BuildManifest: &deploy.BuildManifest{
+			CDNFiles:   []deploy.CDNFile{{Name: "/index.html"}},
+			APIHandler: "api.mjs:handler",
+		},
+	})
+
+	s.Equal([]string{"/index.html"}, diff.StaticFiles.Added)
+	s.Equal(&deploy.ValueChange{Base: "", Head: "api.mjs:handler"}, diff.APIHandler)
+	s.Empty(diff.Config)
+	s.Equal("", diff.Commits.CompareURL)
+}
+
+func (s *DeploymentCompareSuite) Test_RepoCompareURL() {
+	d := &deploy.Deployment{CheckoutRepo: "gitlab/stormkit-io/app"}
+	s.Equal("https://gitlab.com/stormkit-io/app/-/compare/a...b", d.RepoCompareURL("a", "b"))
+
+	d.CheckoutRepo = "bitbucket/stormkit-io/app"
+	s.Equal("https://bitbucket.org/stormkit-io/app/branches/compare/b%0Da", d.RepoCompareURL("a", "b"))
+
+	d.CheckoutRepo = ""
+	s.Equal("", d.RepoCompareURL("a", "b"))
+}
+
+func TestDeploymentCompare(t *testing.T) {
+	suite.Run(t, &DeploymentCompareSuite{})
+}
@@ -4,12 +4,14 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/user"
 	"github.com/stormkit-io/stormkit-io/src/lib/config"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/types"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"gopkg.in/guregu/null.v3"
@@ -62,6 +64,7 @@ type Deployment struct {
 	EnvID              types.ID       `json:"envId,string,omitempty" db:"env_id"`
 	Env                string         `json:"env,omitempty" db:"env_name"` // @deprecated: Env is the environment name used for this deployment. Will be replaced with EnvID
 	Branch             string         `json:"branch" db:"branch"`          // Branch is name of the branch that was deployed.
+	Tag                null.String    `json:"tag,omitempty" db:"git_tag"`  // Tag is the git tag that was deployed, if any.
 	ConfigCopy         []byte         `json:"-" db:"config_snapshot"`
 	configCopyCached   map[string]any `json:"-"`
 	S3NumberOfFiles    null.Int       `json:"numberOfFiles" db:"s3_number_of_files"`
@@ -76,6 +79,7 @@ type Deployment struct {
 	ShouldPublish      bool           `json:"shouldPublish" db:"auto_publish"` // ShouldPublish is boolean value which stores an overwrite for the AutoPublish field of an environment.
 	IsAutoDeploy       bool           `json:"isAutoDeploy" db:"auto_deploy"`
 	IsImmutable        null.Bool      `json:"-"`
+	IsPinned           bool           `json:"isPinned" db:"is_pinned"` // IsPinned exempts the deployment from the retention policy.
 	StatusChecks       null.String    `json:"-"`
 	StatusChecksPassed null.Bool      `json:"statusChecksPassed,omitempty"`
 	CreatedAt          utils.Unix     `json:"createdAt,omitempty" db:"created_at"`
@@ -95,12 +99,36 @@ type Deployment struct {
 	// This value is used to retrieve the jobs and then the logs.
 	GithubRunID null.Int `json:"-" db:"github_run_id"`
 
+	// OverrideFreeze publishes the deployment even if the environment is in a freeze
+	// window. It is stored in the config snapshot, see FreezeOverridden.
+	OverrideFreeze bool `json:"-"`
+
+	// HTTPChecks are the results of the http checks that were executed
+	// against the deployment preview.
+	HTTPChecks HTTPCheckResults `json:"httpChecks,omitempty" db:"http_checks"`
+
+	// Vulnerabilities are the advisories that affect the dependencies
+	// of the deployment. It is nil when the scan did not run.
+	Vulnerabilities Vulnerabilities `json:"vulnerabilities,omitempty" db:"vulnerabilities"`
+
+	// Rollback is set when the deployment has been rolled back automatically
+	// because its health degraded after it was published.
+	Rollback *RollbackInfo `json:"rollback,omitempty" db:"rollback_info"`
+
+	// SBOM is the software bill of materials generated by the runner.
+	// It is only loaded when explicitly requested.
+	SBOM *SBOM `json:"-" db:"sbom"`
+
 	// Published represents the publish information.
 	// It's a json string fetched from the database that contains
 	// the environment id and the released percentage.
 	Published   []PublishedInfo `json:"published,omitempty"`
 	PublishedV2 PublishedInfoV2 `json:"-"`
 
+	// QueuePosition is the position of the deployment in the build queue.
+	// It is null when the deployment is not waiting to be built.
+	QueuePosition null.Int `json:"-"`
+
 	User          *user.User           `json:"-"`
 	BuildConfig   *buildconf.BuildConf `json:"-"` // ConfigCopy is the snapshot of the environment used during the deployment.
 	EnvBranchName string               `json:"-"` // EnvBranchName represents the branch name that is associated with the given environment.
@@ -178,11 +206,21 @@ type RequestData struct {
 	// Branch is the branch to deploy.
 	Branch string `json:"branch"`
 
+	// CommitSha is the exact commit to deploy. When empty, the
+	// head of the branch is deployed.
+	CommitSha string `json:"commitSha"`
+
+	// Tag is the git tag to deploy. It takes precedence over the branch.
+	Tag string `json:"tag"`
+
 	// The distribution folder.
 	DistFolder string `json:"distFolder"`
 
 	// Cmd is the command to run
 	BuildCmd string `json:"buildCmd"`
+
+	// OverrideFreeze allows team owners to publish during a freeze window.
+	OverrideFreeze bool `json:"overrideFreeze"`
 }
 
 // New returns a new deployment instance.
@@ -260,11 +298,28 @@ func (d *Deployment) Snapshot() map[string]any {
 
 // PrepareConfigSnapshot prepares the deployment config snapshot.
 func (d *Deployment) MarshalConfigSnapshot() ([]byte, error) {
-	return json.Marshal(map[string]any{
+	snapshot := map[string]any{
 		"build": d.BuildConfig,
 		"env":   d.Env,
 		"envId": d.EnvID.String(),
-	})
+	}
+
+	if d.OverrideFreeze {
+		snapshot["overrideFreeze"] = true
+	}
+
+	return json.Marshal(snapshot)
+}
+
+// FreezeOverridden returns true when the deployment was started with
+// a freeze window override, therefore it can be published during a freeze.
+func (d *Deployment) FreezeOverridden() bool {
+	if d.OverrideFreeze {
+		return true
+	}
+
+	overridden, _ := d.Snapshot()["overrideFreeze"].(bool)
+	return overridden
 }
 
 // HasStatusChecks checks the deployment snapshot to determine if it had
@@ -284,6 +339,55 @@ func (d *Deployment) HasStatusChecks() bool {
 	return true
 }
 
+// HTTPChecksConfig returns the http checks that were configured
+// when the deployment was executed.
+func (d *Deployment) HTTPChecksConfig() []buildconf.HTTPCheck {
+	if cnf := d.snapshotBuildConfig(); cnf != nil {
+		return cnf.HTTPChecks
+	}
+
+	return nil
+}
+
+// VulnScanConfig returns the vulnerability scan configuration
+// that was used when the deployment was executed.
+func (d *Deployment) VulnScanConfig() *buildconf.VulnScan {
+	if cnf := d.snapshotBuildConfig(); cnf != nil {
+		return cnf.VulnScan
+	}
+
+	return nil
+}
+
+// BlockingVulnerabilities returns the vulnerabilities that prevent publishing the
+// deployment, according to the vulnerability scan configuration of the deployment.
+func (d *Deployment) BlockingVulnerabilities() Vulnerabilities {
+	if cnf := d.VulnScanConfig(); cnf != nil {
+		return d.Vulnerabilities.AtOrAbove(cnf.BlockPublishOn)
+	}
+
+	return Vulnerabilities{}
+}
+
+// snapshotBuildConfig returns the build configuration at the time of the deployment.
+func (d *Deployment) snapshotBuildConfig() *buildconf.BuildConf {
+	if d.BuildConfig != nil {
+		return d.BuildConfig
+	}
+
+	if len(d.ConfigCopy) == 0 {
+		return nil
+	}
+
+	copy := ConfigSnapshot{}
+
+	if err := json.Unmarshal(d.ConfigCopy, &copy); err != nil {
+		return nil
+	}
+
+	return copy.BuildConfig
+}
+
 // Status returns the deployment status based on the exit code.
 // Possible values are: running | success | failed
 func (d *Deployment) Status() string {
@@ -316,6 +420,11 @@ func (d *Deployment) Status() string {
 
 // RepoCloneURL returns the fully qualified repository name to clone the repository.
 func (d *Deployment) RepoCloneURL() string {
+	// Generic git remotes are stored as git/<clone url>.
+	if address, ok := strings.CutPrefix(d.CheckoutRepo, "git/"); ok {
+		return address
+	}
+
 	pieces := strings.Split(d.CheckoutRepo, "/")
 
 	if len(pieces) >= 3 {
@@ -327,6 +436,8 @@ func (d *Deployment) RepoCloneURL() string {
 			return fmt.Sprintf("git@bitbucket.org:%s/%s.git", owner, slug)
 		} else if provider == "gitlab" {
 			return fmt.Sprintf("https://gitlab.com/%s/%s.git", owner, slug)
+		} else if provider == "gitea" {
+			return admin.MustConfig().GiteaURL(fmt.Sprintf("/%s/%s.git", owner, slug))
 		} else {
 			panic("Unknown provider")
 		}
@@ -348,6 +459,26 @@ func (d *Deployment) RepoSlug() string {
 	return ""
 }
 
+// BlobHashes returns the unique content hashes of the static files of the deployment.
+func (d *Deployment) BlobHashes() []string {
+	if d.BuildManifest == nil {
+		return nil
+	}
+
+	hashes := []string{}
+	seen := map[string]bool{}
+
+	for _, etag := range staticFileEtags(d.BuildManifest) {
+		if hash := integrations.BlobHash(etag); hash != "" && !seen[hash] {
+			seen[hash] = true
+			hashes = append(hashes, hash)
+		}
+	}
+
+	sort.Strings(hashes)
+	return hashes
+}
+
 // PrepareLogs prepares the deployment logs and returns an array of log objects.
 func (d *Deployment) PrepareLogs(rawLogs string, isStatusChecks bool) []*Log {
 	if rawLogs == "" &&
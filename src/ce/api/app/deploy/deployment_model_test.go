@@ -9,6 +9,7 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
 	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/guregu/null.v3"
@@ -61,6 +62,24 @@ func (s *DeploymentModelSuite) Test_EmptyBuildManifest() {
 	s.Nil(d.BuildManifest)
 }
 
+func (s *DeploymentModelSuite) Test_UpdateDeploymentResult_Stopped() {
+	mockDeploy := s.MockDeployment(nil, map[string]any{
+		"ExitCode": null.IntFrom(-1),
+	})
+
+	store := deploy.NewStore()
+	d, err := store.DeploymentByID(context.Background(), mockDeploy.ID)
+	s.NoError(err)
+
+	// The outcome of a stopped deployment is not overwritten.
+	d.ExitCode = null.IntFrom(0)
+	s.ErrorIs(store.UpdateDeploymentResult(context.Background(), d, integrations.UploadResult{}), deploy.ErrDeploymentStopped)
+
+	d, err = store.DeploymentByID(context.Background(), mockDeploy.ID)
+	s.NoError(err)
+	s.Equal(int64(-1), d.ExitCode.ValueOrZero())
+}
+
 func (s *DeploymentModelSuite) Test_RepoCloneURL() {
 	d := &deploy.Deployment{}
 
@@ -16,14 +16,20 @@ type statement struct {
 	selectDeploymentsV2      string
 	selectDeploymentWithLogs string
 	selectBuildManifest      string
+	selectSBOM               string
+	selectDeploymentsWithPkg string
 	insertDeployment         string
 	restartDeployment        string
 	updateExitCode           string
 	updateCommitInfo         string
 	updateLogs               string
 	updateStatusChecks       string
+	updateHTTPChecks         string
+	updateSBOM               string
+	updateVulnerabilities    string
 	lockDeployment           string
 	markDeploymentsAsDeleted string
+	deleteEnvDeployments     string
 	isDeploymentAlreadyBuilt string
 	stopDeployment           string
 	stopStatusChecks         string
@@ -33,6 +39,41 @@ type statement struct {
 	markArtifactsAsDeleted   string
 	publish                  string
 	updateUserMetrics        string
+
+	insertScheduledPublish      string
+	selectScheduledPublishes    string
+	deleteScheduledPublish      string
+	claimDueScheduledPublishes  string
+	updateScheduledPublishError string
+
+	upsertApproval          string
+	selectApprovals         string
+	selectPendingApprovals  string
+	selectApprovalsForDepls string
+
+	selectPublishedDeployments  string
+	insertRollbackWatch         string
+	selectActiveRollbackWatches string
+	selectRollbackWatchHealth   string
+	resolveRollbackWatch        string
+	markRolledBack              string
+
+	addBlobReferences              string
+	trackBlobs                     string
+	markDeploymentArtifactsDeleted string
+	releaseBlobReferences          string
+	claimUnreferencedBlobs         string
+
+	enqueueDeployment                string
+	cancelSupersededDeployments      string
+	releaseFinishedQueueEntries      string
+	selectDeploymentQueue            string
+	lockDeploymentQueue              string
+	markQueuedDeploymentDispatched   string
+	unmarkQueuedDeploymentDispatched string
+
+	pinDeployment            string
+	selectExpiredDeployments string
 }
 
 var stmt = &statement{
@@ -45,7 +86,7 @@ var stmt = &statement{
 			d.env_id, d.env_name, d.error, d.is_auto_deploy, COALESCE(d.branch, ''),
 			d.auto_publish, d.pull_request_number,
 			d.build_manifest, d.function_location, d.storage_location,
-			d.api_location, d.api_package_size,
+			d.api_location, d.api_package_size, d.git_tag,
 			a.display_name, a.runtime
 		FROM %s d
 		LEFT JOIN %s a ON a.app_id = d.app_id
@@ -67,7 +108,8 @@ var stmt = &statement{
 			d.api_location, d.api_package_size, d.server_package_size,
 			d.s3_number_of_files, d.client_package_size,
 			d.api_path_prefix, d.is_immutable,
-			d.status_checks_passed,
+			d.status_checks_passed, d.http_checks, d.vulnerabilities, d.rollback_info,
+			d.is_pinned, d.git_tag,
 			{{ if .logs }} d.status_checks, d.logs {{ else }} '', '' {{ end }},
 			a.display_name, COALESCE(a.repo, ''),
 			(SELECT json_agg(
@@ -75,7 +117,10 @@ var stmt = &statement{
 					'envId', dp.env_id,
 					'percentage', dp.percentage_released)) as published
 			 FROM deployments_published dp
-			 WHERE dp.percentage_released > 0 AND dp.deployment_id = d.deployment_id) as published
+			 WHERE dp.percentage_released > 0 AND dp.deployment_id = d.deployment_id) as published,
+			(SELECT 0
+			 FROM deployment_queue q
+			 WHERE q.dispatched_at IS NULL AND q.deployment_id = d.deployment_id) as queue_position
 		FROM deployments d
 		LEFT JOIN apps a ON a.app_id = d.app_id
 		{{ .joins }}
@@ -127,18 +172,46 @@ var stmt = &statement{
 			d.app_id = $2
 	`, tableDeploys),
 
+	selectSBOM: `
+		SELECT
+			d.deployment_id, d.app_id, COALESCE(d.branch, ''),
+			d.created_at, d.stopped_at, d.commit_id,
+			a.display_name, d.sbom
+		FROM deployments d
+		LEFT JOIN apps a ON a.app_id = d.app_id
+		WHERE
+			d.deployment_id = $1 AND
+			d.app_id = $2 AND
+			d.deleted_at IS NULL;
+	`,
+
+	selectDeploymentsWithPkg: `
+		SELECT
+			d.deployment_id, d.env_name, COALESCE(d.branch, ''),
+			d.created_at, d.commit_id
+		FROM deployments d
+		WHERE
+			d.app_id = $1 AND
+			d.deleted_at IS NULL AND
+			d.sbom->'components' @> $2::jsonb
+		ORDER BY d.deployment_id DESC
+		LIMIT 100;
+	`,
+
 	insertDeployment: `
 		INSERT INTO deployments (
 			app_id, config_snapshot, branch, env_name, env_id,
 			is_auto_deploy, pull_request_number,
 			commit_id, is_fork, auto_publish, checkout_repo,
-			api_path_prefix, webhook_event, commit_author
+			api_path_prefix, webhook_event, commit_author,
+			git_tag
 		)
 		VALUES (
 			$1, $2, $3, $4, $5,
 			$6, $7,
 			$8, $9, $10, $11,
-			$12, $13, $14
+			$12, $13, $14,
+			$15
 		)
 		RETURNING
 			deployment_id,
@@ -154,6 +227,7 @@ var stmt = &statement{
 			logs = NULL,
 			status_checks = NULL,
 			status_checks_passed = NULL,
+			http_checks = NULL,
 			is_immutable = false
 		WHERE
 			deployment_id = $1;
@@ -188,6 +262,388 @@ var stmt = &statement{
 		UPDATE deployments SET status_checks = $1 WHERE deployment_id = $2 AND exit_code = 0 AND is_immutable IS NOT TRUE;
 	`,
 
+	updateHTTPChecks: `
+		UPDATE deployments SET http_checks = $1 WHERE deployment_id = $2 AND is_immutable IS NOT TRUE;
+	`,
+
+	updateSBOM: `
+		UPDATE deployments SET sbom = $1 WHERE deployment_id = $2 AND is_immutable IS NOT TRUE;
+	`,
+
+	updateVulnerabilities: `
+		UPDATE deployments SET vulnerabilities = $1 WHERE deployment_id = $2 AND is_immutable IS NOT TRUE;
+	`,
+
+	insertScheduledPublish: `
+		INSERT INTO scheduled_publishes
+			(app_id, env_id, publish_settings, publish_at, override_freeze, created_by)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+		RETURNING schedule_id, created_at;
+	`,
+
+	selectScheduledPublishes: `
+		SELECT
+			schedule_id, app_id, env_id, publish_settings, publish_at,
+			override_freeze, created_by, executed_at, publish_error, created_at
+		FROM scheduled_publishes
+		WHERE
+			app_id = $1 AND
+			env_id = $2 AND
+			executed_at IS NULL
+		ORDER BY publish_at ASC;
+	`,
+
+	deleteScheduledPublish: `
+		DELETE FROM scheduled_publishes
+		WHERE
+			schedule_id = $1 AND
+			app_id = $2 AND
+			env_id = $3 AND
+			executed_at IS NULL;
+	`,
+
+	// Claiming the records in a single statement makes
+	// sure that a publish is executed only once.
+	claimDueScheduledPublishes: `
+		UPDATE scheduled_publishes
+		SET executed_at = NOW() AT TIME ZONE 'UTC'
+		WHERE
+			executed_at IS NULL AND
+			publish_at <= NOW() AT TIME ZONE 'UTC'
+		RETURNING
+			schedule_id, app_id, env_id, publish_settings, publish_at,
+			override_freeze, created_by, executed_at, publish_error, created_at;
+	`,
+
+	updateScheduledPublishError: `
+		UPDATE scheduled_publishes SET publish_error = $1 WHERE schedule_id = $2;
+	`,
+
+	// A user can change their decision, the latest one wins.
+	upsertApproval: `
+		INSERT INTO deployment_approvals
+			(deployment_id, app_id, env_id, user_id, approval_status, approval_comment)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (deployment_id, env_id, user_id) DO UPDATE SET
+			approval_status = EXCLUDED.approval_status,
+			approval_comment = EXCLUDED.approval_comment,
+			created_at = NOW() AT TIME ZONE 'UTC'
+		RETURNING approval_id, created_at;
+	`,
+
+	selectApprovals: `
+		SELECT
+			da.approval_id, da.deployment_id, da.app_id, da.env_id, da.user_id,
+			COALESCE(u.display_name, ''), da.approval_status, da.approval_comment, da.created_at
+		FROM deployment_approvals da
+		LEFT JOIN users u ON u.user_id = da.user_id
+		WHERE
+			da.deployment_id = $1 AND
+			da.env_id = $2
+		ORDER BY da.approval_id ASC;
+	`,
+
+	selectApprovalsForDepls: `
+		SELECT
+			da.approval_id, da.deployment_id, da.app_id, da.env_id, da.user_id,
+			COALESCE(u.display_name, ''), da.approval_status, da.approval_comment, da.created_at
+		FROM deployment_approvals da
+		LEFT JOIN users u ON u.user_id = da.user_id
+		WHERE
+			da.deployment_id = ANY($1) AND
+			da.env_id = $2
+		ORDER BY da.approval_id ASC;
+	`,
+
+	// Successful deployments which are not published, not rejected
+	// and do not have the required number of approvals yet.
+	selectPendingApprovals: `
+		SELECT
+			d.deployment_id, COALESCE(d.branch, ''), d.commit_id, d.commit_author,
+			d.commit_message, d.created_at, d.auto_publish
+		FROM deployments d
+		WHERE
+			d.app_id = $1 AND
+			d.env_id = $2 AND
+			d.exit_code = 0 AND
+			d.deleted_at IS NULL AND
+			NOT EXISTS (
+				SELECT 1 FROM deployments_published dp
+				WHERE dp.deployment_id = d.deployment_id AND dp.env_id = d.env_id AND dp.percentage_released > 0
+			) AND
+			NOT EXISTS (
+				SELECT 1 FROM deployment_approvals da
+				WHERE da.deployment_id = d.deployment_id AND da.env_id = d.env_id AND da.approval_status = 'rejected'
+			) AND
+			(
+				SELECT COUNT(*) FROM deployment_approvals da
+				WHERE da.deployment_id = d.deployment_id AND da.env_id = d.env_id AND da.approval_status = 'approved'
+			) < $3
+		ORDER BY d.deployment_id DESC
+		LIMIT 25;
+	`,
+
+	// Returns the deployment with the highest percentage for each environment.
+	selectPublishedDeployments: `
+		SELECT DISTINCT ON (dp.env_id)
+			dp.env_id, dp.deployment_id
+		FROM deployments_published dp
+		WHERE
+			dp.env_id = ANY($1) AND
+			dp.percentage_released > 0
+		ORDER BY dp.env_id, dp.percentage_released DESC;
+	`,
+
+	// A new publish supersedes the watches of the environment.
+	insertRollbackWatch: `
+		WITH resolve_previous AS (
+			UPDATE rollback_watches SET resolved_at = NOW() AT TIME ZONE 'UTC'
+			WHERE env_id = $2 AND resolved_at IS NULL
+		)
+		INSERT INTO rollback_watches
+			(app_id, env_id, deployment_id, previous_deployment_id, watch_until)
+		VALUES
+			($1, $2, $3, $4, $5)
+		RETURNING watch_id, created_at;
+	`,
+
+	selectActiveRollbackWatches: `
+		SELECT
+			rw.watch_id, rw.app_id, rw.env_id, rw.deployment_id,
+			rw.previous_deployment_id, rw.watch_until, rw.created_at,
+			EXISTS (
+				SELECT 1 FROM deployments_published dp
+				WHERE
+					dp.env_id = rw.env_id AND
+					dp.deployment_id = rw.deployment_id AND
+					dp.percentage_released > 0
+			)
+		FROM rollback_watches rw
+		WHERE rw.resolved_at IS NULL
+		ORDER BY rw.watch_id ASC
+		LIMIT 100;
+	`,
+
+	// Error rates are computed from the hosting records and
+	// failures from the domain pings that happened after publish.
+	selectRollbackWatchHealth: `
+		SELECT COUNT(*) FROM domains d
+		WHERE
+			d.env_id = $1 AND
+			d.domain_verified IS TRUE AND
+			d.last_ping IS NOT NULL AND
+			(d.last_ping->>'lastPingAt')::bigint >= EXTRACT(EPOCH FROM $2::timestamp) AND
+			(d.last_ping->>'status')::int >= 500;
+	`,
+
+	resolveRollbackWatch: `
+		UPDATE rollback_watches SET
+			resolved_at = NOW() AT TIME ZONE 'UTC',
+			rolled_back = $1
+		WHERE watch_id = $2;
+	`,
+
+	markRolledBack: `
+		UPDATE deployments SET rollback_info = $1 WHERE deployment_id = $2;
+	`,
+
+	addBlobReferences: `
+		INSERT INTO artifact_blobs
+			(blob_location, app_id, blob_hash, ref_count)
+		SELECT $1, $2, UNNEST($3::text[]), 1
+		ON CONFLICT (blob_location, blob_hash) DO UPDATE SET
+			ref_count = artifact_blobs.ref_count + 1,
+			updated_at = NOW() AT TIME ZONE 'UTC';
+	`,
+
+	// Uploaded blobs are tracked without references, so that the blobs
+	// of failed deployments are removed as well.
+	trackBlobs: `
+		INSERT INTO artifact_blobs
+			(blob_location, app_id, blob_hash, ref_count)
+		SELECT $1, $2, UNNEST($3::text[]), 0
+		ON CONFLICT (blob_location, blob_hash) DO UPDATE SET
+			updated_at = NOW() AT TIME ZONE 'UTC';
+	`,
+
+	// The artifacts of a deployment are marked as deleted only once,
+	// so that the blob references are not released multiple times.
+	markDeploymentArtifactsDeleted: fmt.Sprintf(`
+		UPDATE %s
+		SET
+			deleted_at = COALESCE(deleted_at, NOW()),
+			artifacts_deleted = TRUE
+		WHERE
+			deployment_id = $1 AND
+			artifacts_deleted IS NOT TRUE;
+	`, tableDeploys),
+
+	releaseBlobReferences: `
+		UPDATE artifact_blobs SET
+			ref_count = ref_count - 1,
+			updated_at = NOW() AT TIME ZONE 'UTC'
+		WHERE
+			blob_location = $1 AND
+			blob_hash = ANY($2);
+	`,
+
+	// Blobs of apps with running deployments are skipped, as the
+	// runner may have skipped uploading them because they exist.
+	claimUnreferencedBlobs: `
+		DELETE FROM artifact_blobs ab
+		WHERE (ab.blob_location, ab.blob_hash) IN (
+			SELECT b.blob_location, b.blob_hash
+			FROM artifact_blobs b
+			WHERE
+				b.ref_count <= 0 AND
+				b.updated_at < (NOW() AT TIME ZONE 'UTC') - INTERVAL '1 day' AND
+				NOT EXISTS (
+					SELECT 1 FROM deployments d
+					WHERE
+						d.app_id = b.app_id AND
+						d.exit_code IS NULL AND
+						d.deleted_at IS NULL AND
+						d.created_at > (NOW() AT TIME ZONE 'UTC') - INTERVAL '1 day'
+				)
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ab.blob_location, ab.blob_hash;
+	`,
+
+	enqueueDeployment: `
+		INSERT INTO deployment_queue
+			(deployment_id, app_id, team_id, env_id, branch, payload)
+		VALUES
+			($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (deployment_id) DO UPDATE SET
+			payload = EXCLUDED.payload,
+			dispatched_at = NULL,
+			created_at = NOW() AT TIME ZONE 'UTC';
+	`,
+
+	cancelSupersededDeployments: `
+		WITH superseded AS (
+			DELETE FROM deployment_queue q
+			WHERE
+				q.env_id = $1 AND
+				q.branch = $2 AND
+				q.deployment_id < $3
+			RETURNING q.deployment_id, q.dispatched_at IS NOT NULL AS is_dispatched
+		)
+		UPDATE deployments d SET
+			exit_code = -1,
+			stopped_at = NOW() AT TIME ZONE 'UTC',
+			error = $4
+		FROM superseded s
+		WHERE
+			d.deployment_id = s.deployment_id AND
+			d.exit_code IS NULL
+		RETURNING d.deployment_id, d.github_run_id, s.is_dispatched;
+	`,
+
+	// Builds that did not report back for two hours are considered
+	// dead, otherwise they would occupy a slot forever.
+	releaseFinishedQueueEntries: `
+		DELETE FROM deployment_queue q
+		USING deployments d
+		WHERE
+			d.deployment_id = q.deployment_id AND (
+				d.deleted_at IS NOT NULL OR
+				d.is_immutable IS TRUE OR
+				d.exit_code <> 0 OR
+				q.dispatched_at < (NOW() AT TIME ZONE 'UTC') - INTERVAL '2 hours'
+			);
+	`,
+
+	selectDeploymentQueue: `
+		SELECT
+			q.deployment_id, q.app_id, q.team_id, q.env_id,
+			q.branch, q.payload, q.dispatched_at, q.created_at
+		FROM deployment_queue q
+		ORDER BY q.deployment_id ASC;
+	`,
+
+	lockDeploymentQueue: `
+		SELECT
+			q.deployment_id, q.app_id, q.team_id, q.env_id,
+			q.branch, q.payload, q.dispatched_at, q.created_at
+		FROM deployment_queue q
+		ORDER BY q.deployment_id ASC
+		FOR UPDATE;
+	`,
+
+	pinDeployment: `
+		UPDATE deployments SET
+			is_pinned = $1
+		WHERE
+			deployment_id = $2 AND
+			app_id = $3 AND
+			deleted_at IS NULL;
+	`,
+
+	// A deployment is expired when it is deleted or when it is neither kept by the
+	// retention policy of its environment, nor published, last published or pinned.
+	// Deployments are ranked per environment to apply the keepLast setting.
+	selectExpiredDeployments: `
+		WITH ranked AS (
+			SELECT
+				d.deployment_id,
+				ROW_NUMBER() OVER (PARTITION BY d.env_id ORDER BY d.deployment_id DESC) AS position
+			FROM deployments d
+			WHERE
+				d.deleted_at IS NULL
+				{{ if .where }} AND {{ .where }} {{ end }}
+		),
+		last_published AS (
+			SELECT DISTINCT ON (d.env_id) d.deployment_id
+			FROM deployments d
+			WHERE
+				d.published_at IS NOT NULL AND
+				d.deleted_at IS NULL AND
+				NOT EXISTS (SELECT 1 FROM deployments_published dp WHERE dp.deployment_id = d.deployment_id)
+				{{ if .where }} AND {{ .where }} {{ end }}
+			ORDER BY d.env_id, d.published_at DESC
+		)
+		SELECT
+			d.deployment_id, d.app_id, d.env_id, COALESCE(d.branch, ''),
+			d.created_at, d.deleted_at, d.commit_id, d.commit_message,
+			d.storage_location, d.function_location, d.api_location, d.build_manifest
+		FROM deployments d
+		LEFT JOIN apps_build_conf e ON e.env_id = d.env_id
+		LEFT JOIN ranked r ON r.deployment_id = d.deployment_id
+		WHERE
+			d.artifacts_deleted IS NOT TRUE AND
+			NOT EXISTS (SELECT 1 FROM deployments_published dp WHERE dp.deployment_id = d.deployment_id) AND
+			(
+				d.deleted_at IS NOT NULL OR (
+					d.is_pinned IS NOT TRUE AND
+					d.deployment_id NOT IN (SELECT lp.deployment_id FROM last_published lp) AND
+					r.position > COALESCE((e.build_conf->'retention'->>'keepLast')::int, 0) AND
+					d.created_at < (NOW() AT TIME ZONE 'UTC') - make_interval(days => COALESCE(NULLIF((e.build_conf->'retention'->>'keepDays')::int, 0), $2))
+				)
+			)
+			{{ if .where }} AND {{ .where }} {{ end }}
+		ORDER BY d.deployment_id ASC
+		LIMIT $1;
+	`,
+
+	markQueuedDeploymentDispatched: `
+		UPDATE deployment_queue SET
+			dispatched_at = NOW() AT TIME ZONE 'UTC'
+		WHERE
+			deployment_id = $1;
+	`,
+
+	unmarkQueuedDeploymentDispatched: `
+		UPDATE deployment_queue SET
+			dispatched_at = NULL
+		WHERE
+			deployment_id = $1;
+	`,
+
 	lockDeployment: `
 		UPDATE deployments SET
 			is_immutable = TRUE,
@@ -208,6 +664,20 @@ var stmt = &statement{
 			deleted_at IS NULL;
 	`, tableDeploys),
 
+	// The published records are removed as well, otherwise the
+	// artifacts of the published deployments would never expire.
+	deleteEnvDeployments: `
+		WITH delete_published AS (
+			DELETE FROM deployments_published WHERE env_id = $1
+		)
+		UPDATE deployments SET
+			deleted_at = NOW() AT TIME ZONE 'UTC',
+			exit_code = COALESCE(exit_code, -1)
+		WHERE
+			env_id = $1 AND
+			deleted_at IS NULL;
+	`,
+
 	isDeploymentAlreadyBuilt: `
 		SELECT COUNT(*) FROM deployments d WHERE d.commit_id = $1;
 	`,
@@ -254,7 +724,8 @@ var stmt = &statement{
 			api_package_size = $10,
 			stopped_at = NOW() AT TIME ZONE 'UTC'
 		WHERE
-			deployment_id = $11
+			deployment_id = $11 AND
+			exit_code IS NULL
 		RETURNING
 			stopped_at;
 	`, tableDeploys),
@@ -276,6 +747,10 @@ var stmt = &statement{
 		WITH delete_published AS (
 			DELETE FROM deployments_published WHERE env_id = ANY({{ .envIDsParam }})
 		),
+		mark_published AS (
+			UPDATE deployments SET published_at = NOW() AT TIME ZONE 'UTC'
+			WHERE deployment_id = ANY({{ .deploymentIDsParam }})
+		),
 		update_ts AS (
 			UPDATE apps_build_conf e SET updated_at = NOW()
 			WHERE e.env_id = ANY({{ .envIDsParam }})
@@ -5,12 +5,14 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"strings"
 	"text/template"
 
 	"github.com/lib/pq"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/lib/config"
 	"github.com/stormkit-io/stormkit-io/src/lib/database"
@@ -47,6 +49,9 @@ type ConfigSnapshot struct {
 	BuildConfig *buildconf.BuildConf `json:"build"`
 	EnvName     string               `json:"env"`
 	EnvID       string               `json:"envId"`
+
+	// OverrideFreeze is true when the deployment was started with a freeze window override.
+	OverrideFreeze bool `json:"overrideFreeze,omitempty"`
 }
 
 // DeploymentByID returns a deployment.
@@ -70,7 +75,7 @@ func (s *Store) DeploymentByID(ctx context.Context, id types.ID) (*Deployment, e
 		&d.EnvID, &d.Env, &d.Error, &d.IsAutoDeploy, &d.Branch,
 		&d.ShouldPublish, &d.PullRequestNumber,
 		&d.BuildManifest, &d.FunctionLocation, &d.StorageLocation,
-		&d.APILocation, &d.APIPackageSize,
+		&d.APILocation, &d.APIPackageSize, &d.Tag,
 		&displayName, &runtime,
 	)
 
@@ -227,9 +232,10 @@ func (s *Store) scanRows(rows *sql.Rows, err error) ([]*Deployment, error) {
 			&d.FunctionLocation, &d.StorageLocation, &d.APILocation,
 			&d.APIPackageSize, &d.ServerPackageSize, &d.S3NumberOfFiles,
 			&d.S3TotalSizeInBytes, &d.APIPathPrefix, &d.IsImmutable,
-			&d.StatusChecksPassed, &d.StatusChecks, &d.Logs,
+			&d.StatusChecksPassed, &d.HTTPChecks, &d.Vulnerabilities, &d.Rollback,
+			&d.IsPinned, &d.Tag, &d.StatusChecks, &d.Logs,
 			&d.DisplayName, &d.CheckoutRepo,
-			&d.PublishedV2,
+			&d.PublishedV2, &d.QueuePosition,
 		)
 
 		if err != nil {
@@ -243,6 +249,39 @@ func (s *Store) scanRows(rows *sql.Rows, err error) ([]*Deployment, error) {
 	return deployments, nil
 }
 
+// setQueuePositions sets the position of the deployments that wait in the build queue.
+// The position depends on the limits of the build queue, which are not known by the
+// database, so it is computed from the queue.
+func (s *Store) setQueuePositions(ctx context.Context, deployments []*Deployment) error {
+	waiting := false
+
+	for _, d := range deployments {
+		waiting = waiting || d.QueuePosition.Valid
+	}
+
+	if !waiting {
+		return nil
+	}
+
+	queue, err := s.DeploymentQueue(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	positions := QueuePositions(queue, admin.MustConfig().BuildQueue())
+
+	for _, d := range deployments {
+		if position, ok := positions[d.ID]; ok {
+			d.QueuePosition = null.IntFrom(int64(position))
+		} else {
+			d.QueuePosition = null.Int{}
+		}
+	}
+
+	return nil
+}
+
 // MyDeployment returns a deployment based on the given filters.
 func (s *Store) MyDeployment(ctx context.Context, filters *DeploymentsQueryFilters) (*Deployment, error) {
 	ds, err := s.MyDeployments(ctx, filters)
@@ -268,6 +307,11 @@ func (s *Store) MyDeployments(ctx context.Context, filters *DeploymentsQueryFilt
 		joins = append(joins, joinTeams, joinTeamMembers)
 	}
 
+	if filters.AppID != 0 {
+		params = append(params, filters.AppID)
+		where = append(where, fmt.Sprintf("d.app_id = $%d", len(params)))
+	}
+
 	if filters.EnvID != 0 {
 		params = append(params, filters.EnvID)
 		where = append(where, fmt.Sprintf("d.env_id = $%d", len(params)))
@@ -308,7 +352,17 @@ func (s *Store) MyDeployments(ctx context.Context, filters *DeploymentsQueryFilt
 		return nil, err
 	}
 
-	return s.scanRows(s.Query(ctx, query, params...))
+	deployments, err := s.scanRows(s.Query(ctx, query, params...))
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.setQueuePositions(ctx, deployments); err != nil {
+		return nil, err
+	}
+
+	return deployments, nil
 }
 
 // Deployments returns deployments based on the filters.
@@ -413,6 +467,7 @@ func (s *Store) InsertDeployment(ctx context.Context, d *Deployment) error {
 		d.IsAutoDeploy, d.PullRequestNumber,
 		d.Commit.ID, d.IsFork, d.ShouldPublish, repo,
 		d.APIPathPrefix, webhookEvent, d.Commit.Author,
+		d.Tag,
 	}
 
 	row, err := s.QueryRow(ctx, stmt.insertDeployment, params...)
@@ -462,6 +517,13 @@ func (s *Store) MarkDeploymentsAsDeleted(ctx context.Context, ids []types.ID) er
 	return err
 }
 
+// MarkEnvDeploymentsAsDeleted unpublishes and marks the deployments of the environment
+// as deleted, so that their artifacts are removed by the artifact cleanup.
+func (s *Store) MarkEnvDeploymentsAsDeleted(ctx context.Context, envID types.ID) error {
+	_, err := s.Exec(ctx, stmt.deleteEnvDeployments, envID)
+	return err
+}
+
 type DeploymentStats struct {
 	ActiveDeployments             int `json:"activeDeployments"`
 	NumberOfDeploymentsThisMonth  int `json:"numberOfDeploymentsThisMonth"`
@@ -491,6 +553,110 @@ func (s *Store) IsDeploymentAlreadyBuilt(ctx context.Context, commitID string) (
 	return count > 0, nil
 }
 
+// UpdateHTTPChecks stores the http check results of the deployment.
+func (s *Store) UpdateHTTPChecks(ctx context.Context, did types.ID, results HTTPCheckResults) error {
+	_, err := s.Exec(ctx, stmt.updateHTTPChecks, results, did)
+	return err
+}
+
+// UpdateSBOM stores the software bill of materials of the deployment.
+func (s *Store) UpdateSBOM(ctx context.Context, did types.ID, sbom *SBOM) error {
+	_, err := s.Exec(ctx, stmt.updateSBOM, sbom, did)
+	return err
+}
+
+// UpdateVulnerabilities stores the findings of the dependency vulnerability scan.
+func (s *Store) UpdateVulnerabilities(ctx context.Context, did types.ID, findings Vulnerabilities) error {
+	_, err := s.Exec(ctx, stmt.updateVulnerabilities, findings, did)
+	return err
+}
+
+// SBOMByDeploymentID returns the deployment with its software bill of materials.
+// If the deployment is not found, it returns nil.
+func (s *Store) SBOMByDeploymentID(ctx context.Context, deploymentID, appID types.ID) (*Deployment, error) {
+	d := &Deployment{SBOM: &SBOM{}}
+	var displayName null.String
+
+	row, err := s.QueryRow(ctx, stmt.selectSBOM, deploymentID, appID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var sbom []byte
+
+	err = row.Scan(
+		&d.ID, &d.AppID, &d.Branch,
+		&d.CreatedAt, &d.StoppedAt, &d.Commit.ID,
+		&displayName, &sbom,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	d.DisplayName = displayName.ValueOrZero()
+
+	if sbom == nil {
+		d.SBOM = nil
+	} else if err := d.SBOM.Scan(sbom); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DeploymentWithPackage is a summary of a deployment that ships a given package.
+type DeploymentWithPackage struct {
+	ID        types.ID    `json:"id,string"`
+	EnvName   string      `json:"env"`
+	Branch    string      `json:"branch"`
+	CreatedAt utils.Unix  `json:"createdAt"`
+	CommitSha null.String `json:"commitSha"`
+}
+
+// DeploymentsWithPackage returns the latest deployments of the application
+// that ship the given package. When version is empty, any version matches.
+func (s *Store) DeploymentsWithPackage(ctx context.Context, appID types.ID, name, version string) ([]*DeploymentWithPackage, error) {
+	filter := map[string]string{"name": name}
+
+	if version != "" {
+		filter["version"] = version
+	}
+
+	data, err := json.Marshal([]map[string]string{filter})
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Query(ctx, stmt.selectDeploymentsWithPkg, appID, string(data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	deployments := []*DeploymentWithPackage{}
+
+	for rows.Next() {
+		d := &DeploymentWithPackage{}
+
+		if err := rows.Scan(&d.ID, &d.EnvName, &d.Branch, &d.CreatedAt, &d.CommitSha); err != nil {
+			return nil, err
+		}
+
+		deployments = append(deployments, d)
+	}
+
+	return deployments, rows.Err()
+}
+
 // StopDeployment stops a deployment by updating the stopped_at field
 // and setting the exit_code to -1.
 func (s *Store) StopDeployment(ctx context.Context, deploymentID types.ID) error {
@@ -534,6 +700,8 @@ func (s *Store) UpdateGithubRunID(ctx context.Context, deploymentID types.ID, ru
 	return err
 }
 
+// UpdateDeploymentResult updates the outcome of a running deployment. It returns
+// ErrDeploymentStopped when the deployment has been stopped in the meantime.
 func (s *Store) UpdateDeploymentResult(ctx context.Context, d *Deployment, result integrations.UploadResult) error {
 	// these values are int4 in db but int64 in code
 	// sometimes these value is more than int4 and
@@ -579,7 +747,20 @@ func (s *Store) UpdateDeploymentResult(ctx context.Context, d *Deployment, resul
 		return err
 	}
 
-	return row.Scan(&d.StoppedAt)
+	if err := row.Scan(&d.StoppedAt); err != nil {
+		// The deployment was stopped or superseded while it was running.
+		if err == sql.ErrNoRows {
+			return ErrDeploymentStopped
+		}
+
+		return err
+	}
+
+	if location := d.StorageLocation.ValueOrZero(); integrations.IsBlobLocation(location) {
+		return s.AddBlobReferences(ctx, d.AppID, location, d.BlobHashes())
+	}
+
+	return nil
 }
 
 // MarkArtifactsAsDeleted marks artifacts as deleted.
@@ -622,6 +803,7 @@ func (s *Store) Publish(ctx context.Context, settings ...*PublishSettings) error
 
 	checks := map[types.ID]types.ID{}
 	envIDs := []types.ID{}
+	deploymentIDs := []types.ID{}
 
 	tmpl, err := template.New("publish").
 		Funcs(template.FuncMap{"generateValues": utils.GenerateValues}).
@@ -641,14 +823,16 @@ func (s *Store) Publish(ctx context.Context, settings ...*PublishSettings) error
 
 		params = append(params, record.EnvID, record.DeploymentID, record.Percentage)
 		envIDs = append(envIDs, record.EnvID)
+		deploymentIDs = append(deploymentIDs, record.DeploymentID)
 		checks[record.DeploymentID] = record.EnvID
 	}
 
 	var qb strings.Builder
 
 	data := map[string]any{
-		"envIDsParam": fmt.Sprintf("$%d", len(params)+1),
-		"records":     settings,
+		"envIDsParam":        fmt.Sprintf("$%d", len(params)+1),
+		"deploymentIDsParam": fmt.Sprintf("$%d", len(params)+2),
+		"records":            settings,
 	}
 
 	if err = tmpl.Execute(&qb, data); err != nil {
@@ -656,7 +840,605 @@ func (s *Store) Publish(ctx context.Context, settings ...*PublishSettings) error
 		return err
 	}
 
-	params = append(params, pq.Array(envIDs))
+	params = append(params, pq.Array(envIDs), pq.Array(deploymentIDs))
 	_, err = s.Exec(ctx, qb.String(), params...)
 	return err
 }
+
+// InsertScheduledPublish inserts a new scheduled publish.
+func (s *Store) InsertScheduledPublish(ctx context.Context, sp *ScheduledPublish) error {
+	row, err := s.QueryRow(
+		ctx,
+		stmt.insertScheduledPublish,
+		sp.AppID, sp.EnvID, sp.Targets, sp.PublishAt, sp.OverrideFreeze, sp.CreatedBy,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return row.Scan(&sp.ID, &sp.CreatedAt)
+}
+
+// ScheduledPublishes returns the pending scheduled publishes of the environment.
+func (s *Store) ScheduledPublishes(ctx context.Context, appID, envID types.ID) ([]*ScheduledPublish, error) {
+	return s.selectScheduledPublishes(ctx, stmt.selectScheduledPublishes, appID, envID)
+}
+
+// DeleteScheduledPublish removes a pending scheduled publish of the environment.
+// It returns false when no pending scheduled publish is found.
+func (s *Store) DeleteScheduledPublish(ctx context.Context, id, appID, envID types.ID) (bool, error) {
+	result, err := s.Exec(ctx, stmt.deleteScheduledPublish, id, appID, envID)
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// ClaimDueScheduledPublishes marks the scheduled publishes that are due as
+// executed and returns them.
+func (s *Store) ClaimDueScheduledPublishes(ctx context.Context) ([]*ScheduledPublish, error) {
+	return s.selectScheduledPublishes(ctx, stmt.claimDueScheduledPublishes)
+}
+
+// UpdateScheduledPublishError stores the reason why the scheduled publish has failed.
+func (s *Store) UpdateScheduledPublishError(ctx context.Context, id types.ID, msg string) error {
+	_, err := s.Exec(ctx, stmt.updateScheduledPublishError, msg, id)
+	return err
+}
+
+func (s *Store) selectScheduledPublishes(ctx context.Context, query string, params ...any) ([]*ScheduledPublish, error) {
+	rows, err := s.Query(ctx, query, params...)
+
+	if err != nil || rows == nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	records := []*ScheduledPublish{}
+
+	for rows.Next() {
+		sp := &ScheduledPublish{}
+
+		err := rows.Scan(
+			&sp.ID, &sp.AppID, &sp.EnvID, &sp.Targets, &sp.PublishAt,
+			&sp.OverrideFreeze, &sp.CreatedBy, &sp.ExecutedAt, &sp.Error, &sp.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, sp)
+	}
+
+	return records, nil
+}
+
+// UpsertApproval stores the decision of the user. Previous decisions of the
+// same user for the same deployment are overwritten.
+func (s *Store) UpsertApproval(ctx context.Context, a *Approval) error {
+	row, err := s.QueryRow(
+		ctx,
+		stmt.upsertApproval,
+		a.DeploymentID, a.AppID, a.EnvID, a.UserID, a.Status, a.Comment,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return row.Scan(&a.ID, &a.CreatedAt)
+}
+
+// Approvals returns the decisions given for publishing the deployment to the environment.
+func (s *Store) Approvals(ctx context.Context, deploymentID, envID types.ID) (Approvals, error) {
+	return s.selectApprovals(ctx, stmt.selectApprovals, deploymentID, envID)
+}
+
+// PendingApprovals returns the deployments that are waiting for approvals
+// before they can be published to the protected environment.
+func (s *Store) PendingApprovals(ctx context.Context, appID, envID types.ID, required int) ([]*PendingApproval, error) {
+	rows, err := s.Query(ctx, stmt.selectPendingApprovals, appID, envID, required)
+
+	if err != nil || rows == nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	pending := []*PendingApproval{}
+	byID := map[types.ID]*PendingApproval{}
+	ids := []types.ID{}
+
+	for rows.Next() {
+		p := &PendingApproval{RequiredApprovals: required, Approvals: Approvals{}}
+
+		err := rows.Scan(
+			&p.DeploymentID, &p.Branch, &p.Commit.ID, &p.Commit.Author,
+			&p.Commit.Message, &p.CreatedAt, &p.ShouldPublish,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		pending = append(pending, p)
+		byID[p.DeploymentID] = p
+		ids = append(ids, p.DeploymentID)
+	}
+
+	if len(ids) == 0 {
+		return pending, nil
+	}
+
+	approvals, err := s.selectApprovals(ctx, stmt.selectApprovalsForDepls, pq.Array(ids), envID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range approvals {
+		byID[a.DeploymentID].Approvals = append(byID[a.DeploymentID].Approvals, a)
+	}
+
+	return pending, nil
+}
+
+func (s *Store) selectApprovals(ctx context.Context, query string, params ...any) (Approvals, error) {
+	rows, err := s.Query(ctx, query, params...)
+
+	if err != nil || rows == nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	approvals := Approvals{}
+
+	for rows.Next() {
+		a := &Approval{}
+
+		err := rows.Scan(
+			&a.ID, &a.DeploymentID, &a.AppID, &a.EnvID, &a.UserID,
+			&a.UserDisplay, &a.Status, &a.Comment, &a.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		approvals = append(approvals, a)
+	}
+
+	return approvals, nil
+}
+
+// PublishedDeployments returns the deployment with the highest
+// publish percentage for each of the given environments.
+func (s *Store) PublishedDeployments(ctx context.Context, envIDs []types.ID) (map[types.ID]types.ID, error) {
+	rows, err := s.Query(ctx, stmt.selectPublishedDeployments, pq.Array(envIDs))
+
+	if err != nil || rows == nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	published := map[types.ID]types.ID{}
+
+	for rows.Next() {
+		var envID, deploymentID types.ID
+
+		if err := rows.Scan(&envID, &deploymentID); err != nil {
+			return nil, err
+		}
+
+		published[envID] = deploymentID
+	}
+
+	return published, nil
+}
+
+// InsertRollbackWatch starts monitoring a published deployment. Active
+// watches of the same environment are resolved.
+func (s *Store) InsertRollbackWatch(ctx context.Context, w *RollbackWatch) error {
+	row, err := s.QueryRow(
+		ctx,
+		stmt.insertRollbackWatch,
+		w.AppID, w.EnvID, w.DeploymentID, w.PreviousDeploymentID, w.WatchUntil,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return row.Scan(&w.ID, &w.CreatedAt)
+}
+
+// ActiveRollbackWatches returns the watches that are not yet resolved.
+func (s *Store) ActiveRollbackWatches(ctx context.Context) ([]*RollbackWatch, error) {
+	rows, err := s.Query(ctx, stmt.selectActiveRollbackWatches)
+
+	if err != nil || rows == nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	watches := []*RollbackWatch{}
+
+	for rows.Next() {
+		w := &RollbackWatch{}
+
+		err := rows.Scan(
+			&w.ID, &w.AppID, &w.EnvID, &w.DeploymentID,
+			&w.PreviousDeploymentID, &w.WatchUntil, &w.CreatedAt, &w.IsPublished,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		watches = append(watches, w)
+	}
+
+	return watches, nil
+}
+
+// RollbackWatchHealth returns the health of the watched deployment since it was published.
+// The error rate is computed from the status codes of all responses served by the deployment.
+func (s *Store) RollbackWatchHealth(ctx context.Context, w *RollbackWatch) (*WatchHealth, error) {
+	row, err := s.QueryRow(ctx, stmt.selectRollbackWatchHealth, w.EnvID, w.CreatedAt)
+
+	if err != nil {
+		return nil, err
+	}
+
+	h := &WatchHealth{}
+
+	if err := row.Scan(&h.FailedPings); err != nil {
+		return nil, err
+	}
+
+	stats, err := DeploymentResponseStats(ctx, w.DeploymentID, w.CreatedAt.Time)
+
+	if err != nil {
+		return nil, err
+	}
+
+	h.Requests = stats.Requests
+	h.Errors = stats.Errors
+	return h, nil
+}
+
+// ResolveRollbackWatch stops monitoring the deployment.
+func (s *Store) ResolveRollbackWatch(ctx context.Context, id types.ID, rolledBack bool) error {
+	_, err := s.Exec(ctx, stmt.resolveRollbackWatch, rolledBack, id)
+	return err
+}
+
+// MarkRolledBack stores the rollback information on the deployment.
+func (s *Store) MarkRolledBack(ctx context.Context, deploymentID types.ID, info *RollbackInfo) error {
+	_, err := s.Exec(ctx, stmt.markRolledBack, info, deploymentID)
+	return err
+}
+
+// AddBlobReferences increments the reference count of the content-addressed
+// files that are used by a deployment.
+func (s *Store) AddBlobReferences(ctx context.Context, appID types.ID, location string, hashes []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	_, err := s.Exec(ctx, stmt.addBlobReferences, location, appID, pq.Array(hashes))
+	return err
+}
+
+// TrackBlobs stores the content-addressed files that were uploaded for a deployment
+// without referencing them. It is called as soon as the upload finishes, regardless
+// of its outcome, so that blobs which end up not being referenced are removed too.
+func (s *Store) TrackBlobs(ctx context.Context, appID types.ID, overview integrations.UploadOverview) error {
+	if len(overview.Blobs) == 0 || !integrations.IsBlobLocation(overview.Location) {
+		return nil
+	}
+
+	_, err := s.Exec(ctx, stmt.trackBlobs, overview.Location, appID, pq.Array(overview.Blobs))
+	return err
+}
+
+// ReleaseDeploymentArtifacts marks the artifacts of the deployment as deleted and
+// decrements the reference count of the content-addressed files that it used.
+// Both happen in a single transaction, and only when the artifacts are not marked
+// as deleted yet, so that retries do not release the references twice.
+// Returns false when the artifacts were already marked as deleted.
+func (s *Store) ReleaseDeploymentArtifacts(ctx context.Context, d *Deployment) (bool, error) {
+	tx, err := s.Conn.BeginTx(ctx, nil)
+
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	result, err := tx.ExecContext(ctx, stmt.markDeploymentArtifactsDeleted, d.ID)
+
+	if err != nil {
+		return false, err
+	}
+
+	if affected, err := result.RowsAffected(); err != nil || affected == 0 {
+		return false, err
+	}
+
+	location := d.StorageLocation.ValueOrZero()
+
+	if hashes := d.BlobHashes(); len(hashes) > 0 && integrations.IsBlobLocation(location) {
+		if _, err := tx.ExecContext(ctx, stmt.releaseBlobReferences, location, pq.Array(hashes)); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit()
+}
+
+// ClaimUnreferencedBlobs removes the blobs that are no longer referenced by any
+// deployment from the database and returns their hashes grouped by location.
+func (s *Store) ClaimUnreferencedBlobs(ctx context.Context, limit int) (map[string][]string, error) {
+	rows, err := s.Query(ctx, stmt.claimUnreferencedBlobs, limit)
+
+	if err != nil || rows == nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	blobs := map[string][]string{}
+
+	for rows.Next() {
+		var location, hash string
+
+		if err := rows.Scan(&location, &hash); err != nil {
+			return nil, err
+		}
+
+		blobs[location] = append(blobs[location], hash)
+	}
+
+	return blobs, nil
+}
+
+// EnqueueDeployment adds the deployment to the build queue. Restarted deployments
+// are moved to the end of the queue.
+func (s *Store) EnqueueDeployment(ctx context.Context, q *QueuedDeployment) error {
+	_, err := s.Exec(
+		ctx,
+		stmt.enqueueDeployment,
+		q.DeploymentID, q.AppID, null.NewInt(int64(q.TeamID), q.TeamID != 0), q.EnvID, q.Branch, q.Payload,
+	)
+
+	return err
+}
+
+// CancelSupersededDeployments stops the queued and running deployments of the same
+// environment and branch that were started before the given deployment.
+func (s *Store) CancelSupersededDeployments(ctx context.Context, q *QueuedDeployment) ([]*SupersededDeployment, error) {
+	reason := fmt.Sprintf("Superseded by deployment #%s", q.DeploymentID.String())
+	rows, err := s.Query(ctx, stmt.cancelSupersededDeployments, q.EnvID, q.Branch, q.DeploymentID, reason)
+
+	if err != nil || rows == nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	superseded := []*SupersededDeployment{}
+
+	for rows.Next() {
+		sd := &SupersededDeployment{}
+
+		if err := rows.Scan(&sd.DeploymentID, &sd.GithubRunID, &sd.IsDispatched); err != nil {
+			return nil, err
+		}
+
+		superseded = append(superseded, sd)
+	}
+
+	return superseded, rows.Err()
+}
+
+// DispatchQueuedDeployments releases the slots of finished deployments and calls
+// the dispatch function for the queued deployments that fit into the limits.
+// The queue is locked while the deployments are marked as dispatched, so that
+// concurrent calls do not exceed the limits. The deployments are dispatched once
+// the mark is committed, so that a failing commit does not dispatch them twice.
+func (s *Store) DispatchQueuedDeployments(ctx context.Context, limits admin.BuildQueueConfig, dispatch func(*QueuedDeployment) error) error {
+	if _, err := s.Exec(ctx, stmt.releaseFinishedQueueEntries); err != nil {
+		return err
+	}
+
+	tx, err := s.Conn.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	rows, err := tx.QueryContext(ctx, stmt.lockDeploymentQueue)
+
+	if err != nil {
+		return err
+	}
+
+	queue, err := scanDeploymentQueue(rows)
+
+	if err != nil {
+		return err
+	}
+
+	next := NextInQueue(queue, limits)
+
+	for _, q := range next {
+		if _, err := tx.ExecContext(ctx, stmt.markQueuedDeploymentDispatched, q.DeploymentID); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for _, q := range next {
+		if err := dispatch(q); err != nil {
+			errs = append(errs, err)
+
+			// The deployment is dispatched again on the next pass.
+			if _, err := s.Exec(ctx, stmt.unmarkQueuedDeploymentDispatched, q.DeploymentID); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DeploymentQueue returns the deployments that are queued or running,
+// in the order they were created.
+func (s *Store) DeploymentQueue(ctx context.Context) ([]*QueuedDeployment, error) {
+	rows, err := s.Query(ctx, stmt.selectDeploymentQueue)
+
+	if err != nil || rows == nil {
+		return nil, err
+	}
+
+	return scanDeploymentQueue(rows)
+}
+
+func scanDeploymentQueue(rows *sql.Rows) ([]*QueuedDeployment, error) {
+	defer rows.Close()
+
+	queue := []*QueuedDeployment{}
+
+	for rows.Next() {
+		q := &QueuedDeployment{}
+		teamID := null.Int{}
+
+		err := rows.Scan(
+			&q.DeploymentID, &q.AppID, &teamID, &q.EnvID,
+			&q.Branch, &q.Payload, &q.DispatchedAt, &q.CreatedAt,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		q.TeamID = types.ID(teamID.ValueOrZero())
+		queue = append(queue, q)
+	}
+
+	return queue, rows.Err()
+}
+
+// PinDeployment pins or unpins the deployment. Pinned deployments are
+// not removed by the retention policy.
+func (s *Store) PinDeployment(ctx context.Context, deploymentID, appID types.ID, pinned bool) (bool, error) {
+	result, err := s.Exec(ctx, stmt.pinDeployment, pinned, deploymentID, appID)
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// ExpiredDeploymentsFilters defines the filters that are
+// accepted for the ExpiredDeployments query.
+type ExpiredDeploymentsFilters struct {
+	AppID types.ID
+	EnvID types.ID
+
+	// Days is the number of days deployments are kept when
+	// the environment does not configure a retention policy.
+	Days int
+
+	// Limit specifies the number of deployments that can be
+	// returned by a single query.
+	Limit int
+
+	// ExcludeDeleted omits the deployments that are deleted
+	// but whose artifacts are not removed yet.
+	ExcludeDeleted bool
+}
+
+// ExpiredDeployments returns the deployments whose artifacts can be removed
+// according to the retention policies of their environments.
+func (s *Store) ExpiredDeployments(ctx context.Context, filters *ExpiredDeploymentsFilters) ([]*Deployment, error) {
+	params := []any{filters.Limit, utils.GetInt(filters.Days, buildconf.DefaultRetentionDays)}
+	where := []string{}
+
+	if filters.AppID != 0 {
+		params = append(params, filters.AppID)
+		where = append(where, fmt.Sprintf("d.app_id = $%d", len(params)))
+	}
+
+	if filters.EnvID != 0 {
+		params = append(params, filters.EnvID)
+		where = append(where, fmt.Sprintf("d.env_id = $%d", len(params)))
+	}
+
+	if filters.ExcludeDeleted {
+		where = append(where, "d.deleted_at IS NULL")
+	}
+
+	tmpl, err := template.New("expiredDeployments").Parse(stmt.selectExpiredDeployments)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var wr bytes.Buffer
+
+	if err := tmpl.Execute(&wr, map[string]any{"where": strings.Join(where, " AND ")}); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Query(ctx, wr.String(), params...)
+
+	if err != nil || rows == nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	deployments := []*Deployment{}
+
+	for rows.Next() {
+		d := &Deployment{}
+
+		err := rows.Scan(
+			&d.ID, &d.AppID, &d.EnvID, &d.Branch,
+			&d.CreatedAt, &d.DeletedAt, &d.Commit.ID, &d.Commit.Message,
+			&d.StorageLocation, &d.FunctionLocation, &d.APILocation, &d.BuildManifest,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		deployments = append(deployments, d)
+	}
+
+	return deployments, rows.Err()
+}
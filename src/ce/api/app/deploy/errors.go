@@ -8,11 +8,17 @@ import (
 
 // Http errors
 var (
-	ErrMissingEnv          = shttperr.New(http.StatusBadRequest, "Environment name is a required field", "missing-env")
-	ErrMissingEnvID        = shttperr.New(http.StatusBadRequest, "Environment ID is a required field", "missing-env-id")
-	ErrMissingDeploymentID = shttperr.New(http.StatusBadRequest, "Deployment id is a required field", "missing-id")
-	ErrRequestDataMerge    = shttperr.New(http.StatusBadRequest, "Unable to merge request data.", "merge-data")
-	ErrDeployServer        = shttperr.New(http.StatusServiceUnavailable, "Deploy server is not reachable", "deploy-server")
+	ErrMissingEnv            = shttperr.New(http.StatusBadRequest, "Environment name is a required field", "missing-env")
+	ErrMissingEnvID          = shttperr.New(http.StatusBadRequest, "Environment ID is a required field", "missing-env-id")
+	ErrMissingDeploymentID   = shttperr.New(http.StatusBadRequest, "Deployment id is a required field", "missing-id")
+	ErrRequestDataMerge      = shttperr.New(http.StatusBadRequest, "Unable to merge request data.", "merge-data")
+	ErrDeployServer          = shttperr.New(http.StatusServiceUnavailable, "Deploy server is not reachable", "deploy-server")
+	ErrPublishAtInPast       = shttperr.New(http.StatusBadRequest, "Scheduled publish date has to be in the future.", "invalid-publish-at")
+	ErrFreezeWindowActive    = shttperr.New(http.StatusLocked, "Environment is in a freeze window. Deploys and publishes are blocked until the freeze ends.", "freeze-window")
+	ErrDeploymentNotApproved = shttperr.New(http.StatusForbidden, "Deployment has to be approved before it can be published to this environment.", "approval-required")
+	ErrDeploymentRejected    = shttperr.New(http.StatusForbidden, "Deployment has been rejected and cannot be published to this environment.", "approval-rejected")
+	ErrVulnerabilitiesFound  = shttperr.New(http.StatusForbidden, "Deployment has vulnerabilities at or above the blocking severity and cannot be published.", "vulnerabilities-found")
+	ErrDeploymentStopped     = shttperr.New(http.StatusConflict, "Deployment has been stopped and its result can no longer be updated.", "deployment-stopped")
 )
 
 // Deployment errors
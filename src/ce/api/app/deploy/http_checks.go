@@ -0,0 +1,177 @@
+package deploy
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+)
+
+// HTTPCheckTimeout is the maximum duration of a single http check.
+var HTTPCheckTimeout = 30 * time.Second
+
+// maxHTTPCheckBodySize is the maximum number of bytes read from the response body.
+const maxHTTPCheckBodySize = 1 << 20
+
+// HTTPCheckResult is the outcome of a single http check.
+type HTTPCheckResult struct {
+	Name    string `json:"name,omitempty"`
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Status  int    `json:"status"`          // Status is the response status, 0 when the request failed
+	Latency int64  `json:"latency"`         // Latency is the response time in milliseconds
+	Passed  bool   `json:"passed"`          // Passed specifies whether the check succeeded
+	Error   string `json:"error,omitempty"` // Error is the reason of the failure
+}
+
+// HTTPCheckResults is a list of http check results. It is stored
+// as a json column in the deployments table.
+type HTTPCheckResults []HTTPCheckResult
+
+// Passed returns true when all the checks have succeeded.
+func (r HTTPCheckResults) Passed() bool {
+	for _, result := range r {
+		if !result.Passed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Scan implements the Scanner interface.
+func (r *HTTPCheckResults) Scan(value any) error {
+	if value != nil {
+		if b, ok := value.([]byte); ok {
+			return json.Unmarshal(b, r)
+		}
+	}
+
+	return nil
+}
+
+// Value implements the Sql Driver interface.
+func (r HTTPCheckResults) Value() (driver.Value, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(r)
+}
+
+// RunHTTPChecks executes the given checks against the base url and returns the results.
+// Checks do not stop at the first failure so that all results are reported.
+func RunHTTPChecks(ctx context.Context, baseURL string, checks []buildconf.HTTPCheck) HTTPCheckResults {
+	client := &http.Client{
+		Timeout: HTTPCheckTimeout,
+		// Redirects are not followed so that the checks test the preview url itself,
+		// and deployments cannot point the checks to other addresses.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	results := HTTPCheckResults{}
+
+	for _, check := range checks {
+		results = append(results, runHTTPCheck(ctx, client, baseURL, check))
+	}
+
+	return results
+}
+
+func runHTTPCheck(ctx context.Context, client *http.Client, baseURL string, check buildconf.HTTPCheck) HTTPCheckResult {
+	method := strings.ToUpper(check.Method)
+
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	result := HTTPCheckResult{
+		Name:   check.Name,
+		Method: method,
+		Path:   check.Path,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(baseURL, "/")+check.Path, nil)
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	for k, v := range check.Headers {
+		req.Header.Set(k, v)
+	}
+
+	start := time.Now()
+	res, err := client.Do(req)
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, maxHTTPCheckBodySize))
+	result.Latency = time.Since(start).Milliseconds()
+	result.Status = res.StatusCode
+
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	expected := check.Status
+
+	if expected == 0 {
+		expected = http.StatusOK
+	}
+
+	if res.StatusCode != expected {
+		result.Error = fmt.Sprintf("expected status %d, received %d", expected, res.StatusCode)
+		return result
+	}
+
+	if check.MaxLatency > 0 && result.Latency > int64(check.MaxLatency) {
+		result.Error = fmt.Sprintf("response took %dms, max latency is %dms", result.Latency, check.MaxLatency)
+		return result
+	}
+
+	for k, v := range check.ExpectHeaders {
+		if actual := res.Header.Get(k); actual != v {
+			result.Error = fmt.Sprintf("expected header %s to be %q, received %q", k, v, actual)
+			return result
+		}
+	}
+
+	if check.Body != "" && !strings.Contains(string(body), check.Body) {
+		result.Error = fmt.Sprintf("response body does not contain %q", check.Body)
+		return result
+	}
+
+	if check.BodyRegex != "" {
+		re, err := regexp.Compile(check.BodyRegex)
+
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+
+		if !re.Match(body) {
+			result.Error = fmt.Sprintf("response body does not match %q", check.BodyRegex)
+			return result
+		}
+	}
+
+	result.Passed = true
+	return result
+}
@@ -0,0 +1,98 @@
+package deploy_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stretchr/testify/suite"
+)
+
+type HTTPChecksSuite struct {
+	suite.Suite
+
+	server *httptest.Server
+}
+
+func (s *HTTPChecksSuite) BeforeTest(_, _ string) {
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/health":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"status":"ok","version":"1.2.3"}`))
+		case "/private":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			w.Write([]byte("welcome"))
+		case "/redirect":
+			http.Redirect(w, r, "/api/health", http.StatusFound)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func (s *HTTPChecksSuite) AfterTest(_, _ string) {
+	s.server.Close()
+}
+
+func (s *HTTPChecksSuite) Test_Passed() {
+	results := deploy.RunHTTPChecks(context.Background(), s.server.URL+"/", []buildconf.HTTPCheck{
+		{
+			Path:          "/api/health",
+			Body:          `"status":"ok"`,
+			BodyRegex:     `"version":"\d+\.\d+\.\d+"`,
+			MaxLatency:    5000,
+			ExpectHeaders: map[string]string{"Content-Type": "application/json"},
+		},
+		{Path: "/private", Headers: map[string]string{"Authorization": "Bearer token"}},
+		{Path: "/not-found", Status: http.StatusNotFound},
+	})
+
+	s.Len(results, 3)
+	s.True(results.Passed())
+
+	for _, result := range results {
+		s.Equal("GET", result.Method)
+		s.Empty(result.Error)
+	}
+}
+
+func (s *HTTPChecksSuite) Test_Failed() {
+	results := deploy.RunHTTPChecks(context.Background(), s.server.URL, []buildconf.HTTPCheck{
+		{Path: "/private"},
+		{Path: "/api/health", Body: "unavailable"},
+		{Path: "/api/health", BodyRegex: `"status":"down"`},
+		{Path: "/api/health", ExpectHeaders: map[string]string{"Content-Type": "text/html"}},
+		{Path: "/api/health"},
+	})
+
+	s.False(results.Passed())
+	s.Equal(http.StatusUnauthorized, results[0].Status)
+	s.Equal("expected status 200, received 401", results[0].Error)
+	s.Equal(`response body does not contain "unavailable"`, results[1].Error)
+	s.Equal(`response body does not match "\"status\":\"down\""`, results[2].Error)
+	s.Equal(`expected header Content-Type to be "text/html", received "application/json"`, results[3].Error)
+	s.True(results[4].Passed)
+}
+
+func (s *HTTPChecksSuite) Test_Redirect() {
+	results := deploy.RunHTTPChecks(context.Background(), s.server.URL, []buildconf.HTTPCheck{
+		{Path: "/redirect"},
+		{Path: "/redirect", Status: http.StatusFound, ExpectHeaders: map[string]string{"Location": "/api/health"}},
+	})
+
+	s.Equal(http.StatusFound, results[0].Status)
+	s.Equal("expected status 200, received 302", results[0].Error)
+	s.True(results[1].Passed)
+}
+
+func TestHTTPChecks(t *testing.T) {
+	suite.Run(t, &HTTPChecksSuite{})
+}
@@ -0,0 +1,132 @@
+package deploy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/config"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+)
+
+const (
+	LogEventLog  = "log"
+	LogEventStep = "step"
+	LogEventExit = "exit"
+)
+
+// LogEvent is a single message of the deployment log stream. Log and step events
+// use the line number as their id, so that clients can resume the stream.
+type LogEvent struct {
+	ID        int    `json:"id"`
+	Type      string `json:"type"`
+	Line      string `json:"line,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Status    string `json:"status,omitempty"`
+	ExitCode  *int64 `json:"exitCode,omitempty"`
+}
+
+// LogStreamChannel returns the redis pub/sub channel of the deployment log stream.
+func LogStreamChannel(deploymentID types.ID) string {
+	return fmt.Sprintf("deployment_logs:%s", deploymentID.String())
+}
+
+// LogEvents parses the raw logs and returns the events with an id greater than `after`.
+// The last line is considered incomplete unless it ends with a new line or final is true.
+func LogEvents(logs string, after int, final bool) []LogEvent {
+	events := []LogEvent{}
+	lines := logLines(logs, final)
+
+	for i := after; i < len(lines); i++ {
+		line := lines[i]
+		event := LogEvent{ID: i + 1, Type: LogEventLog, Line: line}
+
+		if config.IsStormkitCloud() {
+			event.Line = strings.ReplaceAll(line, "/home/runner/work/deployer-service/deployer-service/repo/", "/stormkit/app")
+		}
+
+		if strings.HasPrefix(line, "[sk-step] ") {
+			// System steps are used to calculate durations, they are not displayed.
+			if strings.HasPrefix(line, "[sk-step] [system] ") {
+				continue
+			}
+
+			pieces := strings.Split(strings.TrimPrefix(line, "[sk-step] "), " [ts:")
+			event = LogEvent{ID: i + 1, Type: LogEventStep, Title: pieces[0]}
+
+			if len(pieces) > 1 {
+				fmt.Sscanf(pieces[1], "%d]", &event.Timestamp)
+			}
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+// logLines splits the logs into lines. The last item is either empty
+// (logs end with a new line) or a line that is still being written.
+func logLines(logs string, final bool) []string {
+	if logs == "" {
+		return nil
+	}
+
+	lines := strings.Split(logs, "\n")
+
+	if last := lines[len(lines)-1]; last == "" || !final {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// LogLineCount returns the number of lines in the logs. When final is false,
+// the line that is still being written is not counted.
+func LogLineCount(logs string, final bool) int {
+	return len(logLines(logs, final))
+}
+
+// ExitEvent returns the event that closes the log stream of the given deployment.
+func ExitEvent(d *Deployment) LogEvent {
+	event := LogEvent{
+		ID:     LogLineCount(d.Logs.ValueOrZero(), true) + 1,
+		Type:   LogEventExit,
+		Status: d.Status(),
+	}
+
+	if d.ExitCode.Valid {
+		event.ExitCode = &d.ExitCode.Int64
+	}
+
+	return event
+}
+
+// PublishLogEvents publishes the events to the log stream of the deployment
+// so that they can be forwarded by any api node serving the stream.
+func PublishLogEvents(ctx context.Context, deploymentID types.ID, events []LogEvent) error {
+	client := rediscache.Client()
+
+	if client == nil || len(events) == 0 {
+		return nil
+	}
+
+	channel := LogStreamChannel(deploymentID)
+
+	for _, event := range events {
+		data, err := json.Marshal(event)
+
+		if err != nil {
+			return err
+		}
+
+		if err := client.Publish(ctx, channel, data).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
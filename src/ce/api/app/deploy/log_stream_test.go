@@ -0,0 +1,54 @@
+package deploy_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v3"
+)
+
+type LogStreamSuite struct {
+	suite.Suite
+}
+
+const streamLogs = "[sk-step] checkout main [ts:1700000000]\n" +
+	"Cloning repository\n" +
+	"[sk-step] [system] building finished [ts:1700000010]\n" +
+	"[sk-step] npm run build [ts:1700000020]\n" +
+	"vite v5.0.0 building"
+
+func (s *LogStreamSuite) Test_LogEvents() {
+	events := deploy.LogEvents(streamLogs, 0, false)
+
+	s.Equal([]deploy.LogEvent{
+		{ID: 1, Type: deploy.LogEventStep, Title: "checkout main", Timestamp: 1700000000},
+		{ID: 2, Type: deploy.LogEventLog, Line: "Cloning repository"},
+		{ID: 4, Type: deploy.LogEventStep, Title: "npm run build", Timestamp: 1700000020},
+	}, events)
+
+	s.Equal([]deploy.LogEvent{
+		{ID: 5, Type: deploy.LogEventLog, Line: "vite v5.0.0 building"},
+	}, deploy.LogEvents(streamLogs, 4, true))
+
+	s.Empty(deploy.LogEvents("", 0, true))
+	s.Equal(4, deploy.LogLineCount(streamLogs, false))
+	s.Equal(5, deploy.LogLineCount(streamLogs, true))
+	s.Equal(1, deploy.LogLineCount("done\n", true))
+}
+
+func (s *LogStreamSuite) Test_ExitEvent() {
+	event := deploy.ExitEvent(&deploy.Deployment{
+		Logs:     null.StringFrom(streamLogs),
+		ExitCode: null.IntFrom(1),
+	})
+
+	s.Equal(6, event.ID)
+	s.Equal(deploy.LogEventExit, event.Type)
+	s.Equal("failed", event.Status)
+	s.Equal(int64(1), *event.ExitCode)
+}
+
+func TestLogStream(t *testing.T) {
+	suite.Run(t, &LogStreamSuite{})
+}
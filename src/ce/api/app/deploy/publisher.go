@@ -2,12 +2,17 @@ package deploy
 
 import (
 	"context"
+	"time"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/appcache"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
 	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 )
 
 // PublishSettings are the settings for publishing a deployment.
@@ -16,12 +21,40 @@ type PublishSettings struct {
 	EnvID        types.ID
 	Percentage   float64
 	NoCacheReset bool
+
+	// NoRollbackWatch skips monitoring the deployment after publish.
+	// It is used when publishing as a result of a rollback.
+	NoRollbackWatch bool
 }
 
 // AutoPublish automatically publishes successful deployments if the
-// auto publish feature is enabled.
+// auto publish feature is enabled. Successful deployments to protected
+// environments are not published, instead their approval is requested.
 func AutoPublishIfNecessary(ctx context.Context, d *Deployment) error {
-	if !d.ShouldPublish || d.ExitCode.ValueOrZero() != 0 || d.Error.ValueOrZero() != "" {
+	if d.ExitCode.ValueOrZero() != 0 || d.Error.ValueOrZero() != "" {
+		return nil
+	}
+
+	env, err := buildconf.NewStore().EnvironmentByID(ctx, d.EnvID)
+
+	if err != nil {
+		return err
+	}
+
+	// Deployments to protected environments are published once they are approved,
+	// whether they are published automatically or manually.
+	if env != nil && env.Data.IsProtected() {
+		return RequestApproval(ctx, d, env)
+	}
+
+	if !d.ShouldPublish {
+		return nil
+	}
+
+	// Deployments that complete during a freeze window are not published,
+	// unless the freeze was overridden when the deployment was started.
+	if env != nil && !d.FreezeOverridden() && env.Data.ActiveFreezeWindow(time.Now()) != nil {
+		slog.Infof("skipping auto publish for deployment id=%d: environment is in a freeze window", d.ID)
 		return nil
 	}
 
@@ -38,6 +71,27 @@ func AutoPublishIfNecessary(ctx context.Context, d *Deployment) error {
 
 // Publish publishes a new deployment.
 func Publish(ctx context.Context, settings []*PublishSettings) error {
+	// Deployments that are fully published are monitored, in case they need to be rolled back.
+	watched := map[types.ID]types.ID{}
+	watchedEnvIDs := []types.ID{}
+
+	for _, s := range settings {
+		if s.Percentage == 100 && !s.NoRollbackWatch {
+			watched[s.EnvID] = s.DeploymentID
+			watchedEnvIDs = append(watchedEnvIDs, s.EnvID)
+		}
+	}
+
+	previous := map[types.ID]types.ID{}
+
+	if len(watchedEnvIDs) > 0 {
+		var err error
+
+		if previous, err = NewStore().PublishedDeployments(ctx, watchedEnvIDs); err != nil {
+			return err
+		}
+	}
+
 	if err := NewStore().Publish(ctx, settings...); err != nil {
 		return err
 	}
@@ -64,12 +118,40 @@ func Publish(ctx context.Context, settings []*PublishSettings) error {
 			return err
 		}
 
+		// Pre-warm the services of the deployment before the traffic is switched to it.
+		// Requests that reach a hosting instance before this event are routed to the
+		// previous deployment by the process manager until the new service is ready.
+		if s.Percentage == 100 && env.Data != nil && env.Data.ServerCmd != "" {
+			// Workers that were stopped manually are started again with the new deployment.
+			if err := integrations.ClearStoppedWorkers(ctx, env.ID); err != nil {
+				slog.Errorf("error while clearing stopped workers for env id=%d: %v", env.ID, err)
+			}
+
+			if err := rediscache.Broadcast(rediscache.EventSwapServices, rediscache.EncodePayload(appl.DisplayName, env.Name)); err != nil {
+				slog.Errorf("error while broadcasting service swap for env id=%d: %v", env.ID, err)
+			}
+		}
+
 		if !s.NoCacheReset {
 			if err := appcache.Service().Reset(env.ID); err != nil {
 				return err
 			}
 		}
 
+		if did, prev := watched[envID], previous[envID]; prev != 0 && prev != did && env.Data.AutoRollback != nil {
+			watch := &RollbackWatch{
+				AppID:                env.AppID,
+				EnvID:                envID,
+				DeploymentID:         did,
+				PreviousDeploymentID: prev,
+				WatchUntil:           utils.UnixFrom(time.Now().Add(env.Data.AutoRollback.Window())),
+			}
+
+			if err := NewStore().InsertRollbackWatch(ctx, watch); err != nil {
+				return err
+			}
+		}
+
 		whs := app.NewStore().OutboundWebhooks(ctx, env.AppID)
 		cnf := admin.MustConfig()
 
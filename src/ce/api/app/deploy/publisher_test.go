@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/appcache"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
 	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/types"
 	"github.com/stormkit-io/stormkit-io/src/mocks"
@@ -166,6 +169,28 @@ func (s *PublisherSuite) Test_PublishOutboundWebhooks() {
 	s.Nil(deploy.Publish(context.Background(), settings))
 }
 
+func (s *PublisherSuite) Test_Publish_SwapServices() {
+	appl := s.MockApp(nil)
+	env := s.MockEnv(appl, map[string]any{
+		"Data": &buildconf.BuildConf{ServerCmd: "node server.js"},
+	})
+	depl := s.MockDeployment(env)
+	service := &mocks.MicroServiceInterface{}
+	rediscache.DefaultService = service
+
+	defer func() { rediscache.DefaultService = nil }()
+
+	// Both values are sent, because Broadcast publishes a single payload.
+	service.On("Broadcast", rediscache.EventSwapServices, rediscache.EncodePayload(appl.DisplayName, env.Name)).Return(nil).Once()
+	s.mockCacheService.On("Reset", env.ID).Return(nil).Once()
+
+	s.Nil(deploy.Publish(context.Background(), []*deploy.PublishSettings{
+		{EnvID: env.ID, DeploymentID: depl.ID, Percentage: 100},
+	}))
+
+	service.AssertExpectations(s.T())
+}
+
 func (s *PublisherSuite) Test_AutoPublish() {
 	app := s.MockApp(nil)
 	env := s.MockEnv(app, nil)
@@ -191,6 +216,98 @@ func (s *PublisherSuite) Test_AutoPublish() {
 	s.Equal(depl.ID, depls[0].ID)
 }
 
+func (s *PublisherSuite) Test_AutoPublish_FreezeWindow() {
+	app := s.MockApp(nil)
+	now := time.Now().Unix()
+	env := s.MockEnv(app, map[string]any{
+		"Data": &buildconf.BuildConf{
+			FreezeWindows: []buildconf.FreezeWindow{{From: now - 3600, To: now + 3600}},
+		},
+	})
+
+	frozen := s.MockDeployment(env, map[string]any{
+		"ExitCode":      null.NewInt(0, true),
+		"ShouldPublish": true,
+	})
+
+	overridden := s.MockDeployment(env, map[string]any{
+		"ExitCode":      null.NewInt(0, true),
+		"ShouldPublish": true,
+		"ConfigCopy":    []byte(`{"overrideFreeze":true}`),
+	})
+
+	s.NoError(deploy.AutoPublishIfNecessary(context.Background(), frozen.Deployment))
+
+	published := func() []*deploy.Deployment {
+		depls, err := deploy.NewStore().MyDeployments(context.Background(), &deploy.DeploymentsQueryFilters{
+			EnvID:     env.ID,
+			Published: aws.Bool(true),
+		})
+
+		s.NoError(err)
+		return depls
+	}
+
+	s.Empty(published())
+
+	// Deployments that were started with a freeze override are published
+	s.mockCacheService.On("Reset", env.ID).Return(nil).Once()
+	s.NoError(deploy.AutoPublishIfNecessary(context.Background(), overridden.Deployment))
+	s.Len(published(), 1)
+	s.Equal(overridden.ID, published()[0].ID)
+}
+
+func (s *PublisherSuite) Test_AutoPublish_ProtectedEnvironment() {
+	appl := s.MockApp(nil)
+	env := s.MockEnv(appl, map[string]any{
+		"Data": &buildconf.BuildConf{
+			Protection: &buildconf.Protection{RequiredApprovals: 1},
+		},
+	})
+
+	headers := map[string]string{
+		"Content-Type": "application/json",
+	}
+
+	err := app.NewStore().InsertOutboundWebhook(context.Background(), appl.ID, app.OutboundWebhook{
+		TriggerWhen:    app.TriggerOnApprovalRequested,
+		RequestURL:     "http://example.org/webhooks/approval",
+		RequestMethod:  shttp.MethodPost,
+		RequestPayload: null.NewString(`{ "deployment_id": "$SK_DEPLOYMENT_ID" }`, true),
+		RequestHeaders: headers,
+	})
+
+	s.NoError(err)
+
+	// Approvers are notified instead of publishing the deployment, also when
+	// the deployment is going to be published manually.
+	for _, shouldPublish := range []bool{true, false} {
+		depl := s.MockDeployment(env, map[string]any{
+			"ExitCode":      null.NewInt(0, true),
+			"ShouldPublish": shouldPublish,
+		})
+
+		s.mockRequest.On("Method", http.MethodPost).Return(s.mockRequest).Once()
+		s.mockRequest.On("URL", "http://example.org/webhooks/approval").Return(s.mockRequest).Once()
+		s.mockRequest.On("Headers", shttp.HeadersFromMap(headers)).Return(s.mockRequest).Once()
+		s.mockRequest.On("Do").Return(nil, nil).Once()
+		s.mockRequest.On("Payload",
+			fmt.Sprintf(`{ "deployment_id": "%s" }`, depl.ID.String()),
+		).Return(s.mockRequest).Once()
+
+		s.NoError(deploy.AutoPublishIfNecessary(context.Background(), depl.Deployment))
+		s.mockRequest.AssertExpectations(s.T())
+	}
+
+	depls, err := deploy.NewStore().MyDeployments(context.Background(), &deploy.DeploymentsQueryFilters{
+		EnvID:     env.ID,
+		Published: aws.Bool(true),
+	})
+
+	s.NoError(err)
+	s.Len(depls, 0)
+}
+
 func TestPublisher(t *testing.T) {
 	suite.Run(t, &PublisherSuite{})
 }
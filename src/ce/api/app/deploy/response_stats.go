@@ -0,0 +1,99 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+)
+
+// ResponseStatsTTL is the duration the response counters of a deployment are kept.
+// Rollback watch windows that are longer than this duration only take the responses
+// of the last ResponseStatsTTL into account.
+var ResponseStatsTTL = 24 * time.Hour
+
+// ResponseStats counts the responses that were served by a deployment.
+type ResponseStats struct {
+	Requests int
+	Errors   int // Errors is the number of 5xx responses
+}
+
+// Add counts the response with the given status code.
+func (rs *ResponseStats) Add(status int) {
+	rs.Requests = rs.Requests + 1
+
+	if status >= 500 {
+		rs.Errors = rs.Errors + 1
+	}
+}
+
+// responseStatsKey returns the redis key that holds the given counter of the deployment
+// for the minute that the timestamp belongs to.
+func responseStatsKey(deploymentID types.ID, ts time.Time, counter string) string {
+	return fmt.Sprintf("response_stats:%s:%d:%s", deploymentID.String(), ts.Unix()/60, counter)
+}
+
+// RecordResponseStats increments the response counters of the deployments for the current minute.
+func RecordResponseStats(ctx context.Context, stats map[types.ID]*ResponseStats) error {
+	if len(stats) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	pipe := rediscache.Client().Pipeline()
+
+	for deploymentID, rs := range stats {
+		for counter, value := range map[string]int{"requests": rs.Requests, "errors": rs.Errors} {
+			key := responseStatsKey(deploymentID, now, counter)
+			pipe.IncrBy(ctx, key, int64(value))
+			pipe.Expire(ctx, key, ResponseStatsTTL)
+		}
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// DeploymentResponseStats returns the responses that were served by the deployment since the given time.
+func DeploymentResponseStats(ctx context.Context, deploymentID types.ID, since time.Time) (*ResponseStats, error) {
+	now := time.Now()
+
+	if oldest := now.Add(-ResponseStatsTTL); since.Before(oldest) {
+		since = oldest
+	}
+
+	keys := []string{}
+
+	for ts := since.Truncate(time.Minute); !ts.After(now); ts = ts.Add(time.Minute) {
+		keys = append(keys, responseStatsKey(deploymentID, ts, "requests"), responseStatsKey(deploymentID, ts, "errors"))
+	}
+
+	values, err := rediscache.Client().MGet(ctx, keys...).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rs := &ResponseStats{}
+
+	for i, value := range values {
+		str, ok := value.(string)
+
+		if !ok {
+			continue
+		}
+
+		count, _ := strconv.Atoi(str)
+
+		if i%2 == 0 {
+			rs.Requests = rs.Requests + count
+		} else {
+			rs.Errors = rs.Errors + count
+		}
+	}
+
+	return rs, nil
+}
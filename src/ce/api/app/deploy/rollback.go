@@ -0,0 +1,143 @@
+package deploy
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+)
+
+// RollbackInfo is stored on deployments that have been rolled back automatically.
+type RollbackInfo struct {
+	Reason               string     `json:"reason"`
+	PreviousDeploymentID types.ID   `json:"previousDeploymentId,string"`
+	RolledBackAt         utils.Unix `json:"rolledBackAt"`
+}
+
+// Scan implements the Scanner interface.
+func (r *RollbackInfo) Scan(value any) error {
+	if value != nil {
+		if b, ok := value.([]byte); ok {
+			return json.Unmarshal(b, r)
+		}
+	}
+
+	return nil
+}
+
+// Value implements the Sql Driver interface.
+func (r *RollbackInfo) Value() (driver.Value, error) {
+	if r == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(r)
+}
+
+// RollbackWatch is a deployment that is monitored during the watch window
+// which starts after the deployment is published.
+type RollbackWatch struct {
+	ID                   types.ID
+	AppID                types.ID
+	EnvID                types.ID
+	DeploymentID         types.ID
+	PreviousDeploymentID types.ID
+	WatchUntil           utils.Unix
+	CreatedAt            utils.Unix
+	IsPublished          bool // IsPublished is false when another deployment has been published in the meantime
+}
+
+// WatchHealth is the health of the watched deployment since it was published.
+type WatchHealth struct {
+	Requests    int
+	Errors      int
+	FailedPings int
+}
+
+// Degraded returns the reason of the rollback when the health exceeds the
+// thresholds of the policy. It returns an empty string otherwise.
+func (h WatchHealth) Degraded(policy *buildconf.AutoRollback) string {
+	if h.FailedPings > policy.MaxPingFailures {
+		return fmt.Sprintf("%d domain ping(s) failed after publish", h.FailedPings)
+	}
+
+	if h.Requests == 0 || h.Requests < policy.RequestsThreshold() {
+		return ""
+	}
+
+	rate := float64(h.Errors) * 100 / float64(h.Requests)
+
+	if rate >= policy.ErrorRateThreshold() {
+		return fmt.Sprintf("%.1f%% of %d requests returned 5xx after publish (threshold: %.1f%%)", rate, h.Requests, policy.ErrorRateThreshold())
+	}
+
+	return ""
+}
+
+// Rollback publishes the deployment that was live before the watched deployment,
+// marks the watched deployment as rolled back and notifies the outbound webhooks.
+func Rollback(ctx context.Context, w *RollbackWatch, reason string) error {
+	settings := []*PublishSettings{
+		{
+			EnvID:           w.EnvID,
+			DeploymentID:    w.PreviousDeploymentID,
+			Percentage:      100,
+			NoRollbackWatch: true,
+		},
+	}
+
+	if err := Publish(ctx, settings); err != nil {
+		return err
+	}
+
+	store := NewStore()
+	info := &RollbackInfo{
+		Reason:               reason,
+		PreviousDeploymentID: w.PreviousDeploymentID,
+		RolledBackAt:         utils.NewUnix(),
+	}
+
+	if err := store.MarkRolledBack(ctx, w.DeploymentID, info); err != nil {
+		return err
+	}
+
+	if err := store.ResolveRollbackWatch(ctx, w.ID, true); err != nil {
+		return err
+	}
+
+	env, err := buildconf.NewStore().EnvironmentByID(ctx, w.EnvID)
+
+	if err != nil || env == nil {
+		return err
+	}
+
+	appl, err := app.NewStore().AppByID(ctx, w.AppID)
+
+	if err != nil || appl == nil {
+		return err
+	}
+
+	cnf := admin.MustConfig()
+
+	for _, wh := range app.NewStore().OutboundWebhooks(ctx, w.AppID) {
+		if wh.TriggerOnRollback() {
+			wh.Dispatch(app.OutboundWebhookSettings{
+				AppID:                  w.AppID,
+				DeploymentID:           w.DeploymentID,
+				DeploymentStatus:       "rolled_back",
+				DeploymentError:        reason,
+				EnvironmentName:        env.Name,
+				DeploymentEndpoint:     cnf.PreviewURL(appl.DisplayName, w.DeploymentID.String()),
+				DeploymentLogsEndpoint: cnf.DeploymentLogsURL(w.AppID, w.DeploymentID),
+			})
+		}
+	}
+
+	return nil
+}
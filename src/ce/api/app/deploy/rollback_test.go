@@ -0,0 +1,30 @@
+package deploy_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stretchr/testify/suite"
+)
+
+type RollbackSuite struct {
+	suite.Suite
+}
+
+func (s *RollbackSuite) Test_Degraded() {
+	policy := &buildconf.AutoRollback{}
+
+	// Not enough requests to evaluate the error rate
+	s.Equal("", deploy.WatchHealth{Requests: 10, Errors: 10}.Degraded(policy))
+	s.Equal("", deploy.WatchHealth{Requests: 100, Errors: 9}.Degraded(policy))
+	s.Equal("10.0% of 100 requests returned 5xx after publish (threshold: 10.0%)", deploy.WatchHealth{Requests: 100, Errors: 10}.Degraded(policy))
+	s.Equal("1 domain ping(s) failed after publish", deploy.WatchHealth{FailedPings: 1}.Degraded(policy))
+
+	policy.MaxPingFailures = 1
+	s.Equal("", deploy.WatchHealth{FailedPings: 1}.Degraded(policy))
+}
+
+func TestRollback(t *testing.T) {
+	suite.Run(t, &RollbackSuite{})
+}
@@ -0,0 +1,225 @@
+package deploy
+
+import (
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	SBOMFormatCycloneDX = "cyclonedx"
+	SBOMFormatSPDX      = "spdx"
+)
+
+const (
+	EcosystemNpm  = "npm"
+	EcosystemPypi = "pypi"
+)
+
+// SBOMComponent is a package that is shipped with the deployment.
+type SBOMComponent struct {
+	Name      string   `json:"name"`
+	Version   string   `json:"version"`
+	Ecosystem string   `json:"ecosystem"`         // npm | pypi
+	Dev       bool     `json:"dev,omitempty"`     // Dev specifies whether the package is a development dependency
+	Bundles   []string `json:"bundles,omitempty"` // Bundles lists the uploaded bundles that include the package: server | api
+}
+
+// PURL returns the package url of the component.
+// See https://github.com/package-url/purl-spec for more details.
+func (c SBOMComponent) PURL() string {
+	name := c.Name
+
+	if c.Ecosystem == EcosystemNpm && strings.HasPrefix(name, "@") {
+		name = "%40" + strings.TrimPrefix(name, "@")
+	}
+
+	return fmt.Sprintf("pkg:%s/%s@%s", c.Ecosystem, name, url.PathEscape(c.Version))
+}
+
+// SBOM is the software bill of materials of a deployment. It is stored in
+// a format agnostic way and converted to CycloneDX or SPDX when requested.
+type SBOM struct {
+	// Source is the lock file that the components are resolved from.
+	Source string `json:"source,omitempty"`
+
+	// Components is the list of packages shipped with the deployment.
+	Components []SBOMComponent `json:"components"`
+}
+
+// Scan implements the Scanner interface.
+func (s *SBOM) Scan(value any) error {
+	if value != nil {
+		if b, ok := value.([]byte); ok {
+			return json.Unmarshal(b, s)
+		}
+	}
+
+	return nil
+}
+
+// Value implements the Sql Driver interface.
+func (s *SBOM) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(s)
+}
+
+// Document returns the SBOM in the given format. Supported formats
+// are SBOMFormatCycloneDX and SBOMFormatSPDX.
+func (s *SBOM) Document(d *Deployment, format string) (map[string]any, error) {
+	switch format {
+	case SBOMFormatCycloneDX, "":
+		return s.cycloneDX(d), nil
+	case SBOMFormatSPDX:
+		return s.spdx(d), nil
+	default:
+		return nil, fmt.Errorf("unsupported sbom format: %s", format)
+	}
+}
+
+func (s *SBOM) cycloneDX(d *Deployment) map[string]any {
+	components := []map[string]any{}
+
+	for _, c := range s.Components {
+		component := map[string]any{
+			"type":    "library",
+			"bom-ref": c.PURL(),
+			"name":    c.Name,
+			"version": c.Version,
+			"purl":    c.PURL(),
+			"scope":   "required",
+		}
+
+		if c.Dev {
+			component["scope"] = "optional"
+		}
+
+		if len(c.Bundles) > 0 {
+			component["properties"] = []map[string]string{
+				{"name": "stormkit:bundles", "value": strings.Join(c.Bundles, ",")},
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	return map[string]any{
+		"bomFormat":    "CycloneDX",
+		"specVersion":  "1.5",
+		"serialNumber": fmt.Sprintf("urn:uuid:%s", sbomUUID(d)),
+		"version":      1,
+		"metadata": map[string]any{
+			"timestamp": sbomTimestamp(d),
+			"tools": []map[string]string{
+				{"vendor": "Stormkit", "name": "stormkit-runner"},
+			},
+			"component": map[string]any{
+				"type":    "application",
+				"name":    d.DisplayName,
+				"version": d.ID.String(),
+				"properties": []map[string]string{
+					{"name": "stormkit:commit", "value": d.Commit.ID.ValueOrZero()},
+					{"name": "stormkit:branch", "value": d.Branch},
+					{"name": "stormkit:source", "value": s.Source},
+				},
+			},
+		},
+		"components": components,
+	}
+}
+
+func (s *SBOM) spdx(d *Deployment) map[string]any {
+	rootID := "SPDXRef-Deployment"
+	packages := []map[string]any{
+		{
+			"SPDXID":           rootID,
+			"name":             d.DisplayName,
+			"versionInfo":      d.ID.String(),
+			"downloadLocation": "NOASSERTION",
+			"filesAnalyzed":    false,
+		},
+	}
+
+	relationships := []map[string]string{
+		{
+			"spdxElementId":      "SPDXRef-DOCUMENT",
+			"relationshipType":   "DESCRIBES",
+			"relatedSpdxElement": rootID,
+		},
+	}
+
+	for i, c := range s.Components {
+		id := fmt.Sprintf("SPDXRef-Package-%d", i+1)
+		relationship := "DEPENDS_ON"
+
+		if c.Dev {
+			relationship = "DEV_DEPENDENCY_OF"
+		}
+
+		packages = append(packages, map[string]any{
+			"SPDXID":           id,
+			"name":             c.Name,
+			"versionInfo":      c.Version,
+			"downloadLocation": "NOASSERTION",
+			"filesAnalyzed":    false,
+			"externalRefs": []map[string]string{
+				{
+					"referenceCategory": "PACKAGE-MANAGER",
+					"referenceType":     "purl",
+					"referenceLocator":  c.PURL(),
+				},
+			},
+		})
+
+		if c.Dev {
+			relationships = append(relationships, map[string]string{
+				"spdxElementId":      id,
+				"relationshipType":   relationship,
+				"relatedSpdxElement": rootID,
+			})
+		} else {
+			relationships = append(relationships, map[string]string{
+				"spdxElementId":      rootID,
+				"relationshipType":   relationship,
+				"relatedSpdxElement": id,
+			})
+		}
+	}
+
+	return map[string]any{
+		"spdxVersion":       "SPDX-2.3",
+		"dataLicense":       "CC0-1.0",
+		"SPDXID":            "SPDXRef-DOCUMENT",
+		"name":              fmt.Sprintf("%s-%s", d.DisplayName, d.ID.String()),
+		"documentNamespace": fmt.Sprintf("https://stormkit.io/spdx/%s", sbomUUID(d)),
+		"creationInfo": map[string]any{
+			"created":  sbomTimestamp(d),
+			"creators": []string{"Tool: stormkit-runner", "Organization: Stormkit"},
+		},
+		"packages":      packages,
+		"relationships": relationships,
+	}
+}
+
+// sbomUUID returns a stable identifier for the deployment document.
+func sbomUUID(d *Deployment) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("sbom-%d-%d", d.AppID, d.ID)))
+	h := hex.EncodeToString(sum[:])
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32])
+}
+
+func sbomTimestamp(d *Deployment) string {
+	if d.StoppedAt.Valid {
+		return d.StoppedAt.Time.UTC().Format(time.RFC3339)
+	}
+
+	return d.CreatedAt.Time.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,49 @@
+package deploy_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stretchr/testify/suite"
+)
+
+type SBOMSuite struct {
+	suite.Suite
+}
+
+func (s *SBOMSuite) Test_PURL() {
+	s.Equal("pkg:npm/react@18.2.0", deploy.SBOMComponent{Name: "react", Version: "18.2.0", Ecosystem: deploy.EcosystemNpm}.PURL())
+	s.Equal("pkg:npm/%40types/node@20.0.0", deploy.SBOMComponent{Name: "@types/node", Version: "20.0.0", Ecosystem: deploy.EcosystemNpm}.PURL())
+	s.Equal("pkg:pypi/flask@3.0.0", deploy.SBOMComponent{Name: "flask", Version: "3.0.0", Ecosystem: deploy.EcosystemPypi}.PURL())
+}
+
+func (s *SBOMSuite) Test_Document() {
+	d := &deploy.Deployment{ID: types.ID(5), AppID: types.ID(1), DisplayName: "my-app", Branch: "main"}
+	sbom := &deploy.SBOM{
+		Source: "package-lock.json",
+		Components: []deploy.SBOMComponent{
+			{Name: "react", Version: "18.2.0", Ecosystem: deploy.EcosystemNpm, Bundles: []string{"server"}},
+			{Name: "vite", Version: "5.0.0", Ecosystem: deploy.EcosystemNpm, Dev: true},
+		},
+	}
+
+	cdx, err := sbom.Document(d, deploy.SBOMFormatCycloneDX)
+	s.NoError(err)
+	s.Equal("CycloneDX", cdx["bomFormat"])
+	s.Len(cdx["components"], 2)
+
+	spdx, err := sbom.Document(d, deploy.SBOMFormatSPDX)
+	s.NoError(err)
+	s.Equal("SPDX-2.3", spdx["spdxVersion"])
+	s.Equal("my-app-5", spdx["name"])
+	s.Len(spdx["packages"], 3)
+	s.Len(spdx["relationships"], 3)
+
+	_, err = sbom.Document(d, "xml")
+	s.Error(err)
+}
+
+func TestSBOM(t *testing.T) {
+	suite.Run(t, &SBOMSuite{})
+}
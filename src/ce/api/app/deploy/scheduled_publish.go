@@ -0,0 +1,102 @@
+package deploy
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+	"gopkg.in/guregu/null.v3"
+)
+
+// ScheduledPublishTarget is a deployment and the percentage it will be published with.
+type ScheduledPublishTarget struct {
+	DeploymentID types.ID `json:"deploymentId,string"`
+	Percentage   float64  `json:"percentage"`
+}
+
+// ScheduledPublishTargets is the list of deployments that are published together.
+type ScheduledPublishTargets []ScheduledPublishTarget
+
+// Value implements the Sql Driver interface.
+func (t ScheduledPublishTargets) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
+// Scan implements the Scanner interface.
+func (t *ScheduledPublishTargets) Scan(value any) error {
+	if value == nil {
+		return nil
+	}
+
+	b, ok := value.([]byte)
+
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+
+	return json.Unmarshal(b, t)
+}
+
+// ScheduledPublish is a publish that is executed by the workerserver at the given time.
+type ScheduledPublish struct {
+	ID             types.ID                `json:"id,string"`
+	AppID          types.ID                `json:"appId,string"`
+	EnvID          types.ID                `json:"envId,string"`
+	Targets        ScheduledPublishTargets `json:"publish"`
+	PublishAt      utils.Unix              `json:"publishAt"`
+	OverrideFreeze bool                    `json:"overrideFreeze"` // OverrideFreeze publishes even if the environment is in a freeze window
+	CreatedBy      types.ID                `json:"createdBy,string"`
+	ExecutedAt     utils.Unix              `json:"executedAt"`
+	Error          null.String             `json:"error"`
+	CreatedAt      utils.Unix              `json:"createdAt"`
+}
+
+// Settings returns the publish settings of the scheduled publish.
+func (sp *ScheduledPublish) Settings() []*PublishSettings {
+	settings := []*PublishSettings{}
+
+	for _, t := range sp.Targets {
+		settings = append(settings, &PublishSettings{
+			EnvID:        sp.EnvID,
+			DeploymentID: t.DeploymentID,
+			Percentage:   t.Percentage,
+		})
+	}
+
+	return settings
+}
+
+// PublishScheduled publishes the deployments of the scheduled publish. The publish
+// fails when the environment is in a freeze window, unless the freeze is overridden,
+// when the deployments are not approved for a protected environment or when they
+// have vulnerabilities that block publishing them.
+func PublishScheduled(ctx context.Context, sp *ScheduledPublish) error {
+	env, err := buildconf.NewStore().EnvironmentByID(ctx, sp.EnvID)
+
+	if err != nil {
+		return err
+	}
+
+	if env == nil {
+		return errors.New("environment not found")
+	}
+
+	if !sp.OverrideFreeze && env.Data.ActiveFreezeWindow(time.Now()) != nil {
+		return ErrFreezeWindowActive
+	}
+
+	if err := CheckApprovals(ctx, sp.Settings()); err != nil {
+		return err
+	}
+
+	if err := CheckVulnerabilities(ctx, sp.Settings()); err != nil {
+		return err
+	}
+
+	return Publish(ctx, sp.Settings())
+}
@@ -0,0 +1,76 @@
+package deploy
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
+)
+
+// Vulnerabilities is the list of advisories that affect the
+// dependencies of the deployment.
+type Vulnerabilities []osv.Finding
+
+// AtOrAbove returns the findings with the given severity or higher.
+// When the severity is empty, no finding is returned.
+func (v Vulnerabilities) AtOrAbove(severity string) Vulnerabilities {
+	found := Vulnerabilities{}
+	threshold := osv.SeverityRank(severity)
+
+	if threshold == 0 {
+		return found
+	}
+
+	for _, finding := range v {
+		if osv.SeverityRank(finding.Severity) >= threshold {
+			found = append(found, finding)
+		}
+	}
+
+	return found
+}
+
+// CheckVulnerabilities returns an error when a deployment that is published has
+// vulnerabilities at or above the severity that blocks publishing the deployment.
+func CheckVulnerabilities(ctx context.Context, settings []*PublishSettings) error {
+	store := NewStore()
+
+	for _, s := range settings {
+		if s.Percentage <= 0 {
+			continue
+		}
+
+		d, err := store.MyDeployment(ctx, &DeploymentsQueryFilters{DeploymentID: s.DeploymentID})
+
+		if err != nil {
+			return err
+		}
+
+		if d != nil && len(d.BlockingVulnerabilities()) > 0 {
+			return ErrVulnerabilitiesFound
+		}
+	}
+
+	return nil
+}
+
+// Scan implements the Scanner interface.
+func (v *Vulnerabilities) Scan(value any) error {
+	if value != nil {
+		if b, ok := value.([]byte); ok {
+			return json.Unmarshal(b, v)
+		}
+	}
+
+	return nil
+}
+
+// Value implements the Sql Driver interface.
+func (v Vulnerabilities) Value() (driver.Value, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	return json.Marshal(v)
+}
@@ -0,0 +1,30 @@
+package deploy_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
+	"github.com/stretchr/testify/suite"
+)
+
+type VulnerabilitiesSuite struct {
+	suite.Suite
+}
+
+func (s *VulnerabilitiesSuite) Test_AtOrAbove() {
+	findings := deploy.Vulnerabilities{
+		{ID: "GHSA-1", Severity: osv.SeverityLow},
+		{ID: "GHSA-2", Severity: osv.SeverityHigh},
+		{ID: "GHSA-3", Severity: osv.SeverityCritical},
+		{ID: "GHSA-4", Severity: osv.SeverityUnknown},
+	}
+
+	s.Len(findings.AtOrAbove(osv.SeverityHigh), 2)
+	s.Len(findings.AtOrAbove(osv.SeverityLow), 3)
+	s.Len(findings.AtOrAbove(""), 0)
+}
+
+func TestVulnerabilities(t *testing.T) {
+	suite.Run(t, &VulnerabilitiesSuite{})
+}
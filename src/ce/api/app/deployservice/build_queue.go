@@ -0,0 +1,70 @@
+package deployservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hibiken/asynq"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+)
+
+// DispatchQueuedDeployments sends the queued deployments to the deployer service
+// as long as the instance, team and app concurrency limits allow it.
+func DispatchQueuedDeployments(ctx context.Context) error {
+	limits := admin.MustConfig().BuildQueue()
+
+	return deploy.NewStore().DispatchQueuedDeployments(ctx, limits, func(q *deploy.QueuedDeployment) error {
+		err := sendPayloadToRedis(ctx, q.DeploymentID, q.Payload)
+
+		// The task id is derived from the deployment id, so a conflict means
+		// that the deployment has already been enqueued.
+		if errors.Is(err, asynq.ErrTaskIDConflict) {
+			return nil
+		}
+
+		return err
+	})
+}
+
+// cancelSupersededDeployments stops the deployments of the same environment and branch
+// that are queued or running, as their result is going to be replaced anyway. Runs on
+// GitHub are cancelled through the API and local runners are killed by the worker that
+// is running them.
+// Errors are only logged, as they should not prevent the new deployment.
+func cancelSupersededDeployments(ctx context.Context, q *deploy.QueuedDeployment) {
+	superseded, err := deploy.NewStore().CancelSupersededDeployments(ctx, q)
+
+	if err != nil {
+		slog.Errorf("error while cancelling superseded deployments: %v", err)
+		return
+	}
+
+	local := []string{}
+
+	for _, sd := range superseded {
+		if !sd.IsDispatched {
+			continue
+		}
+
+		if sd.GithubRunID.ValueOrZero() != 0 {
+			if err := Github().StopDeployment(sd.GithubRunID.ValueOrZero()); err != nil {
+				slog.Errorf("error while stopping superseded deployment %s: %v", sd.DeploymentID.String(), err)
+			}
+
+			continue
+		}
+
+		local = append(local, sd.DeploymentID.String())
+	}
+
+	// The queue entries are removed, so the local runners have to be stopped as
+	// well, otherwise they would keep building outside of the concurrency limits.
+	if len(local) > 0 {
+		if err := rediscache.Broadcast(rediscache.EventStopDeployment, local...); err != nil {
+			slog.Errorf("error while stopping superseded local deployments: %v", err)
+		}
+	}
+}
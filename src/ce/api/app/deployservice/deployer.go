@@ -11,6 +11,7 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/lib/config"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
 	"github.com/stormkit-io/stormkit-io/src/lib/tasks"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"go.uber.org/zap"
 	"gopkg.in/guregu/null.v3"
@@ -72,9 +73,9 @@ func (dd *DefaultDeployer) Deploy(ctx context.Context, a *app.App, d *deploy.Dep
 		true,
 	)
 
-	if !d.IsRestart {
-		store := deploy.NewStore()
+	store := deploy.NewStore()
 
+	if !d.IsRestart {
 		// Insert the deployment first, so we can have an ID.
 		if err := store.InsertDeployment(ctx, d); err != nil {
 			return err
@@ -91,6 +92,8 @@ func (dd *DefaultDeployer) Deploy(ctx context.Context, a *app.App, d *deploy.Dep
 		Build: BuildConfig{
 			Env:           d.Env,
 			Branch:        d.Branch,
+			CommitSha:     d.Commit.ID.ValueOrZero(),
+			Tag:           d.Tag.ValueOrZero(),
 			ShouldPublish: d.ShouldPublish,
 			BuildCmd:      d.BuildConfig.BuildCmd,
 			ServerCmd:     d.BuildConfig.ServerCmd,
@@ -104,6 +107,9 @@ func (dd *DefaultDeployer) Deploy(ctx context.Context, a *app.App, d *deploy.Dep
 			RedirectsFile: d.BuildConfig.RedirectsFile,
 			APIFolder:     utils.GetString(d.BuildConfig.APIFolder, "/api"),
 			StatusChecks:  d.BuildConfig.StatusChecks,
+			SecretVars:    d.BuildConfig.SecretVars,
+			VulnScan:      d.BuildConfig.VulnScan,
+			GitCheckout:   d.BuildConfig.GitCheckout,
 			Vars: d.BuildConfig.InterpolatedVars(
 				buildconf.InterpolatedVarsOpts{
 					DeploymentID: d.ID.String(),
@@ -118,20 +124,39 @@ func (dd *DefaultDeployer) Deploy(ctx context.Context, a *app.App, d *deploy.Dep
 		Config: config.Get().Runner,
 	}
 
-	return dd.sendPayloadToRedis(ctx, payload)
-}
-
-func (dd *DefaultDeployer) sendPayloadToRedis(ctx context.Context, message DeploymentMessage) error {
-	encrypted, err := message.Encrypt()
+	encrypted, err := payload.Encrypt()
 
 	if err != nil {
 		return err
 	}
 
+	queued := &deploy.QueuedDeployment{
+		DeploymentID: d.ID,
+		AppID:        d.AppID,
+		TeamID:       a.TeamID,
+		EnvID:        d.EnvID,
+		Branch:       d.Branch,
+		Payload:      encrypted,
+	}
+
+	if err := store.EnqueueDeployment(ctx, queued); err != nil {
+		return err
+	}
+
+	// Restarting an older deployment should not cancel the newer ones
+	if !d.IsRestart {
+		cancelSupersededDeployments(ctx, queued)
+	}
+
+	return DispatchQueuedDeployments(ctx)
+}
+
+// sendPayloadToRedis enqueues the deployment message which is picked up by the workerserver.
+func sendPayloadToRedis(ctx context.Context, deploymentID types.ID, encrypted string) error {
 	info, err := tasks.Enqueue(ctx, tasks.DeploymentStart, encrypted, &tasks.EnqueueOptions{
 		MaxRetry:  10,
 		QueueName: tasks.QueueDeployService,
-		TaskID:    fmt.Sprintf("deployment-%s", message.Build.DeploymentID),
+		TaskID:    fmt.Sprintf("deployment-%s", deploymentID.String()),
 	})
 
 	if err != nil {
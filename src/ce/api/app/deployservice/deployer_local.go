@@ -7,14 +7,24 @@ import (
 	"os"
 	"path"
 	"strings"
+	"sync"
+	"syscall"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
 	"github.com/stormkit-io/stormkit-io/src/lib/config"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/sys"
 	"go.uber.org/zap"
 )
 
+// localRunners holds the process ids of the local runners that are building
+// on this instance, keyed by the deployment id.
+var localRunners = map[types.ID]int{}
+var localRunnersMux sync.Mutex
+
 type localService struct {
 	tempDir    string // The temporary directory that the deployment will be checked out and built
 	executable string // The path to stormkit-deployer-node repository
@@ -101,8 +111,57 @@ func (ls *localService) runService(args SendPayloadArgs) error {
 			fmt.Sprintf("STORMKIT_DEPLOYER_DIR=%s", deployerDir),
 			fmt.Sprintf("STORMKIT_DEPLOYER_SERVICE=%s", config.DeployerServiceLocal),
 			fmt.Sprintf("STORMKIT_APP_SECRET=%s", config.AppSecret()),
+			// The runner scans the dependencies against the advisories that are downloaded by the workers.
+			fmt.Sprintf("STORMKIT_OSV_DIR=%s", osv.Dir()),
 		},
-	})
+		// The runner gets its own process group, so that the build
+		// commands are stopped together with the runner.
+		SysProcAttr: &syscall.SysProcAttr{Setpgid: true},
+	}).Cmd()
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	localRunnersMux.Lock()
+	localRunners[args.DeploymentID] = cmd.Process.Pid
+	localRunnersMux.Unlock()
+
+	err = cmd.Wait()
+
+	localRunnersMux.Lock()
+	_, running := localRunners[args.DeploymentID]
+	delete(localRunners, args.DeploymentID)
+	localRunnersMux.Unlock()
+
+	// The runner was stopped on purpose, the deployment is already marked as stopped.
+	if !running {
+		return nil
+	}
 
-	return cmd.Run()
+	return err
+}
+
+// StopLocalDeployment kills the local runners of the given deployments, if they are
+// running on this instance. It is called through the EventStopDeployment event,
+// as the runner may be running on any of the worker instances.
+func StopLocalDeployment(ctx context.Context, payload ...string) {
+	for _, id := range payload {
+		deploymentID := utils.StringToID(id)
+
+		localRunnersMux.Lock()
+		pid, ok := localRunners[deploymentID]
+		delete(localRunners, deploymentID)
+		localRunnersMux.Unlock()
+
+		if !ok {
+			continue
+		}
+
+		slog.Infof("stopping local runner of deployment %s", id)
+
+		if err := syscall.Kill(-pid, syscall.SIGKILL); err != nil {
+			slog.Errorf("cannot stop local runner of deployment %s: %v", id, err)
+		}
+	}
 }
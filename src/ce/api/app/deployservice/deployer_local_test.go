@@ -1,16 +1,21 @@
 package deployservice_test
 
 import (
+	"context"
 	"encoding/json"
 	"os"
+	"os/exec"
 	"path"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
 	"github.com/stormkit-io/stormkit-io/src/lib/config"
 	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/mise"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/sys"
 	"github.com/stormkit-io/stormkit-io/src/mocks"
 	"github.com/stretchr/testify/mock"
@@ -75,11 +80,13 @@ func (s *DeployerLocalSuite) Test_StartDeployment() {
 			"STORMKIT_DEPLOYER_DIR=" + config.Get().Deployer.StorageDir,
 			"STORMKIT_DEPLOYER_SERVICE=" + config.DeployerServiceLocal,
 			"STORMKIT_APP_SECRET=" + config.AppSecret(),
+			"STORMKIT_OSV_DIR=" + osv.Dir(),
 		},
-		Stdout: os.Stdout,
-		Stderr: os.Stderr,
+		Stdout:      os.Stdout,
+		Stderr:      os.Stderr,
+		SysProcAttr: &syscall.SysProcAttr{Setpgid: true},
 	}).Return(s.mockExec).Once()
-	s.mockExec.On("Run").Return(nil, nil).Once()
+	s.mockExec.On("Cmd").Return(exec.Command("true")).Once()
 
 	s.NoError(deployer.SendPayload(deployservice.SendPayloadArgs{
 		DeploymentID: utils.StringToID(s.mockDeploymentID),
@@ -87,6 +94,37 @@ func (s *DeployerLocalSuite) Test_StartDeployment() {
 	}))
 }
 
+func (s *DeployerLocalSuite) Test_StopLocalDeployment() {
+	cmd := exec.Command("sh", "-c", "sleep 30")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	s.mockExec.On("SetOpts", mock.Anything).Return(s.mockExec).Once()
+	s.mockExec.On("Cmd").Return(cmd).Once()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- deployservice.Local().SendPayload(deployservice.SendPayloadArgs{
+			DeploymentID: utils.StringToID(s.mockDeploymentID),
+			EncryptedMsg: "some-message",
+		})
+	}()
+
+	// Stopping a deployment that is not running on this instance is a no-op.
+	deployservice.StopLocalDeployment(context.Background(), "1")
+
+	s.Eventually(func() bool {
+		deployservice.StopLocalDeployment(context.Background(), s.mockDeploymentID)
+
+		select {
+		case err := <-done:
+			return s.NoError(err)
+		default:
+			return false
+		}
+	}, 5*time.Second, 100*time.Millisecond)
+}
+
 func TestDeployerLocalSuite(t *testing.T) {
 	suite.Run(t, new(DeployerLocalSuite))
 }
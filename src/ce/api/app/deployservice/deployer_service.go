@@ -48,6 +48,12 @@ type BuildConfig struct {
 	// The branch to deploy.
 	Branch string `json:"branch"`
 
+	// CommitSha is the exact commit to check out. When empty, the head of the branch is built.
+	CommitSha string `json:"commitSha,omitempty"`
+
+	// Tag is the git tag to check out. When provided, it is cloned instead of the branch.
+	Tag string `json:"tag,omitempty"`
+
 	// ShouldPublish specifies whether the deployment should be published to external storages
 	// when they are enabled. If they are not enabled this has no effect.
 	ShouldPublish bool `json:"shouldPublish"`
@@ -93,6 +99,15 @@ type BuildConfig struct {
 
 	// List of status check commands to execute after the deployment is complete.
 	StatusChecks []buildconf.StatusCheck `json:"statusChecks"`
+
+	// Names of the environment variables whose values are masked in the logs.
+	SecretVars []string `json:"secretVars,omitempty"`
+
+	// Severity thresholds of the dependency vulnerability scan.
+	VulnScan *buildconf.VulnScan `json:"vulnScan,omitempty"`
+
+	// Submodules, Git LFS and sparse checkout options.
+	GitCheckout *buildconf.GitCheckout `json:"gitCheckout,omitempty"`
 }
 
 // DeploymentMessage represents a deployment payload.
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
@@ -33,6 +34,7 @@ func (s *DeploySuite) BeforeTest(suiteName, _ string) {
 
 func (s *DeploySuite) AfterTest(_, _ string) {
 	s.conn.CloseTx()
+	admin.ResetCache(context.Background())
 
 	// clean up newly created queue
 	insp := tasks.Inspector()
@@ -151,6 +153,62 @@ func (s *DeploySuite) Test_Deployment_NoMoreBuildMinutes() {
 	s.Equal(deployservice.ErrBuildMinutesExceeded, err)
 }
 
+func (s *DeploySuite) Test_Deployment_SupersedesPreviousBuilds() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	first := s.MockDeployment(env, map[string]any{"Branch": "main"})
+	second := s.MockDeployment(env, map[string]any{"Branch": "main"})
+	other := s.MockDeployment(env, map[string]any{"Branch": "feature"})
+
+	s.NoError(deployservice.New().Deploy(context.Background(), app.App, first.Deployment))
+	s.NoError(deployservice.New().Deploy(context.Background(), app.App, other.Deployment))
+	s.NoError(deployservice.New().Deploy(context.Background(), app.App, second.Deployment))
+
+	d, err := deploy.NewStore().DeploymentByID(context.Background(), first.ID)
+	s.NoError(err)
+	s.Equal(int64(deploy.ExitCodeStopped), d.ExitCode.ValueOrZero())
+	s.Equal(fmt.Sprintf("Superseded by deployment #%s", second.ID.String()), d.Error.ValueOrZero())
+
+	// Deployments of other branches are not affected
+	d, err = deploy.NewStore().DeploymentByID(context.Background(), other.ID)
+	s.NoError(err)
+	s.False(d.ExitCode.Valid)
+}
+
+func (s *DeploySuite) Test_Deployment_ConcurrencyLimit() {
+	vc := admin.MustConfig()
+	vc.BuildQueueConfig = &admin.BuildQueueConfig{MaxConcurrentBuildsPerApp: 1}
+	s.NoError(admin.Store().UpsertConfig(context.Background(), vc))
+
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env1 := s.MockEnv(app)
+	env2 := s.MockEnv(app, map[string]any{"Name": "staging"})
+	first := s.MockDeployment(env1)
+	second := s.MockDeployment(env2)
+
+	s.NoError(deployservice.New().Deploy(context.Background(), app.App, first.Deployment))
+	s.NoError(deployservice.New().Deploy(context.Background(), app.App, second.Deployment))
+
+	insp := tasks.Inspector()
+	_, err := insp.GetTaskInfo(tasks.QueueDeployService, fmt.Sprintf("deployment-%s", first.ID.String()))
+	s.NoError(err)
+	_, err = insp.GetTaskInfo(tasks.QueueDeployService, fmt.Sprintf("deployment-%s", second.ID.String()))
+	s.Error(err)
+
+	d, err := deploy.NewStore().MyDeployment(context.Background(), &deploy.DeploymentsQueryFilters{DeploymentID: second.ID})
+	s.NoError(err)
+	s.Equal(int64(1), d.QueuePosition.ValueOrZero())
+
+	// Finishing the first deployment releases the slot
+	s.NoError(deploy.NewStore().StopDeployment(context.Background(), first.ID))
+	s.NoError(deployservice.DispatchQueuedDeployments(context.Background()))
+
+	_, err = insp.GetTaskInfo(tasks.QueueDeployService, fmt.Sprintf("deployment-%s", second.ID.String()))
+	s.NoError(err)
+}
+
 func TestAppDeploy(t *testing.T) {
 	suite.Run(t, &DeploySuite{})
 }
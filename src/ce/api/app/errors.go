@@ -10,7 +10,7 @@ import (
 var (
 	ErrNoConnection            = shttperr.New(http.StatusServiceUnavailable, "Provider is not reachable.", "no-connection")
 	ErrProviderNotConnected    = shttperr.New(http.StatusForbidden, "Repository is not connected.", "repo-not-connected")
-	ErrRepoInvalidProvider     = shttperr.New(http.StatusBadRequest, "Provider is not supported. Supported providers are Github, Bitbucket and Gitlab.", "invalid-provider")
+	ErrRepoInvalidProvider     = shttperr.New(http.StatusBadRequest, "Provider is not supported. Supported providers are Github, Bitbucket, Gitlab, Gitea and generic git remotes.", "invalid-provider")
 	ErrRepoInvalidFormat       = shttperr.New(http.StatusBadRequest, "Excepting repo to be in :owner/:slug format.", "invalid-repo-format")
 	ErrInvalidAppSecret        = shttperr.New(http.StatusBadRequest, "App secret cannot be decyphered or not found. Please re-install app.", "invalid-app-secret")
 	ErrInvalidStormkitFile     = shttperr.New(http.StatusBadRequest, "Please make sure that stormkit.config.yml file is valid.", "invalid-stormkit-file")
@@ -25,4 +25,10 @@ var (
 	ErrInvalidWebhookURL       = shttperr.New(http.StatusBadRequest, "The Webhook URL is not valid", "invalid-webhook")
 	ErrPaymentRequired         = shttperr.New(http.StatusPaymentRequired, "You need to upgrade before creating new applications", "upgrade-required")
 	ErrMissingEnv              = shttperr.New(http.StatusBadRequest, "Environment does not exist.", "missing-env")
+	ErrInvalidGitRemoteURL     = shttperr.New(http.StatusBadRequest, "The git remote has to be an ssh or https url.", "invalid-git-remote-url")
+	ErrInvalidGitRemoteAuth    = shttperr.New(http.StatusBadRequest, "Authentication type can be one of the following values: ssh, https, none.", "invalid-git-remote-auth")
+	ErrInvalidGitRemoteCreds   = shttperr.New(http.StatusBadRequest, "Username and password are required for https authentication.", "invalid-git-remote-creds")
+	ErrInvalidGitRemotePoll    = shttperr.New(http.StatusBadRequest, "Poll interval cannot be negative.", "invalid-git-remote-poll")
+	ErrInvalidKnownHosts       = shttperr.New(http.StatusBadRequest, "Known hosts have to be in the known_hosts format.", "invalid-known-hosts")
+	ErrGitRemoteHostKeys       = shttperr.New(http.StatusBadRequest, "Cannot retrieve the host keys of the git server. Provide the known_hosts entries manually.", "git-remote-host-keys")
 )
@@ -0,0 +1,23 @@
+package mailer
+
+import (
+	"fmt"
+	"net/smtp"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+)
+
+// SendMail is the function that delivers the emails. It is a variable to be mocked in tests.
+var SendMail = smtp.SendMail
+
+// Send sends an html email to the given recipients using the smtp configuration.
+func Send(cnf *Config, to []string, subject, body string) error {
+	fromHeader := fmt.Sprintf("From: %s\n", cnf.Username)
+	subjectHeader := fmt.Sprintf("Subject: %s\n", subject)
+	mime := "MIME-version: 1.0;\nContent-Type: text/html; charset=\"UTF-8\";\n\n"
+	msg := []byte(fromHeader + subjectHeader + mime + fmt.Sprintf("<html><body>%s</body></html>", body))
+	addr := cnf.Host + ":" + utils.GetString(cnf.Port, "587")
+	auth := smtp.PlainAuth("", cnf.Username, cnf.Password, cnf.Host)
+
+	return SendMail(addr, auth, cnf.Username, to, msg)
+}
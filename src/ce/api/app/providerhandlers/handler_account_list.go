@@ -18,7 +18,7 @@ type Account struct {
 func handlerAccountList(req *user.RequestContext) *shttp.Response {
 	provider := req.Vars()["provider"]
 
-	if provider != github.ProviderName && provider != "gitlab" && provider != "bitbucket" {
+	if provider != github.ProviderName && provider != "gitlab" && provider != "bitbucket" && provider != "gitea" {
 		return shttp.BadRequest()
 	}
 
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitea"
 	gh "github.com/stormkit-io/stormkit-io/src/ce/api/oauth/github"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/user"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
@@ -20,7 +21,7 @@ const maxPerPage = 100
 func handlerRepoList(req *user.RequestContext) *shttp.Response {
 	provider := req.Vars()["provider"]
 
-	if provider != gh.ProviderName && provider != "gitlab" && provider != "bitbucket" {
+	if provider != gh.ProviderName && provider != "gitlab" && provider != "bitbucket" && provider != gitea.ProviderName {
 		return shttp.BadRequest()
 	}
 
@@ -51,6 +52,8 @@ func handlerRepoList(req *user.RequestContext) *shttp.Response {
 			page:           page,
 			perPage:        perPage,
 			search:         search})
+	case gitea.ProviderName:
+		repos, hasNextPage, err = giteaListRepos(req, page, perPage, search)
 	}
 
 	if err != nil {
@@ -122,3 +125,44 @@ func githubListRepos(opts GithubListReposRequest) ([]Repo, bool, error) {
 
 	return repos, hasNextPage, nil
 }
+
+// giteaListRepos lists the repositories of the user. Unlike GitHub, Gitea
+// does not have installations so the repositories are listed using the
+// user's oauth2 token.
+func giteaListRepos(req *user.RequestContext, page, perPage int, search string) ([]Repo, bool, error) {
+	client, err := gitea.NewClient(req.User.ID)
+
+	if client == nil || err != nil {
+		return nil, false, err
+	}
+
+	repos := []Repo{}
+	hasNextPage := false
+
+	for {
+		giteaRepos, total, err := client.ListRepos(page, perPage)
+
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, repo := range giteaRepos {
+			if search == "" || strings.Contains(strings.ToLower(repo.FullName), search) {
+				repos = append(repos, Repo{
+					Name:     repo.Name,
+					FullName: repo.FullName,
+				})
+			}
+		}
+
+		hasNextPage = total > page*perPage
+
+		if search == "" || !hasNextPage {
+			break
+		}
+
+		page = page + 1
+	}
+
+	return repos, hasNextPage, nil
+}
@@ -18,8 +18,8 @@ func (s *ServicesSuite) TestServices() {
 	s.NotNil(s)
 
 	handlers := []string{
-		"GET:/provider/{provider:github|gitlab|bitbucket}/accounts",
-		"GET:/provider/{provider:github|gitlab|bitbucket}/repos",
+		"GET:/provider/{provider:github|gitlab|bitbucket|gitea}/accounts",
+		"GET:/provider/{provider:github|gitlab|bitbucket|gitea}/repos",
 	}
 
 	s.Equal(handlers, services.HandlerKeys())
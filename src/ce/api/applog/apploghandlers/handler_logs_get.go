@@ -29,6 +29,7 @@ func handlerLogsGet(req *app.RequestContext) *shttp.Response {
 		DeploymentID: utils.StringToID(qs.Get("deploymentId")),
 		AfterID:      utils.StringToID(qs.Get("afterId")),
 		BeforeID:     utils.StringToID(qs.Get("beforeId")),
+		Label:        qs.Get("label"),
 		Sort:         strings.ToLower(qs.Get("sort")),
 		Limit:        LogsLimit,
 	}
@@ -158,6 +158,64 @@ func (s *HandlerLogsGetSuite) Test_FetchingLogs_Sort() {
 	s.JSONEq(expected, response.String())
 }
 
+func (s *HandlerLogsGetSuite) Test_FetchingLogs_Label() {
+	deployment := s.MockDeployment(s.env)
+	logs := []*applog.Log{
+		{
+			AppID:         s.app.ID,
+			DeploymentID:  deployment.ID,
+			EnvironmentID: s.env.ID,
+			Label:         "worker:consumer",
+			Data:          "Consumed 5 messages.",
+			Timestamp:     time.Date(2023, 10, 10, 6, 30, 45, 0, &time.Location{}).Unix(),
+		},
+		{
+			AppID:         s.app.ID,
+			DeploymentID:  deployment.ID,
+			EnvironmentID: s.env.ID,
+			Data:          "Server started.",
+			Timestamp:     time.Date(2023, 10, 10, 6, 30, 46, 0, &time.Location{}).Unix(),
+		},
+	}
+
+	s.NoError(applog.NewStore().InsertLogs(context.Background(), logs))
+
+	response := shttptest.RequestWithHeaders(
+		shttp.NewRouter().RegisterService(apploghandlers.Services).Router().Handler(),
+		shttp.MethodGet,
+		fmt.Sprintf(
+			"/app/%s/logs?deploymentId=%s&label=worker:consumer",
+			s.app.ID.String(),
+			deployment.ID.String(),
+		),
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(s.user.ID),
+		},
+	)
+
+	expected := fmt.Sprintf(`{
+		"logs": [
+			{
+				"id": "%s",
+				"appId": "%s",
+				"deploymentId": "%s",
+				"data": "Consumed 5 messages.",
+				"timestamp": "%d"
+			}
+		],
+		"hasNextPage": false
+	}`,
+		logs[0].ID.String(),
+		s.app.ID.String(),
+		deployment.ID.String(),
+		logs[0].Timestamp,
+	)
+
+	s.Equal(http.StatusOK, response.Code)
+	s.JSONEq(expected, response.String())
+}
+
 func (s *HandlerLogsGetSuite) Test_FetchingLogs_Sort_Invalid() {
 	response := shttptest.RequestWithHeaders(
 		shttp.NewRouter().RegisterService(apploghandlers.Services).Router().Handler(),
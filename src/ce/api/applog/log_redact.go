@@ -0,0 +1,48 @@
+package applog
+
+import (
+	"context"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/redact"
+)
+
+// RedactSecrets masks the values of the environment variables that are
+// marked as secret, including their encoded forms, in the log data.
+func RedactSecrets(ctx context.Context, logs []*Log) {
+	redactors := map[types.ID]*redact.Redactor{}
+
+	for _, log := range logs {
+		r, ok := redactors[log.EnvironmentID]
+
+		if !ok {
+			r = envRedactor(ctx, log.EnvironmentID)
+			redactors[log.EnvironmentID] = r
+		}
+
+		log.Data = r.String(log.Data)
+	}
+}
+
+// envRedactor returns the redactor for the given environment.
+// It returns nil when the environment has no secrets.
+func envRedactor(ctx context.Context, envID types.ID) *redact.Redactor {
+	if envID == 0 {
+		return nil
+	}
+
+	env, err := buildconf.NewStore().EnvironmentByID(ctx, envID)
+
+	if err != nil {
+		slog.Errorf("error while fetching environment for log redaction: %v", err)
+		return nil
+	}
+
+	if env == nil || env.Data == nil {
+		return nil
+	}
+
+	return redact.New(env.Data.Secrets())
+}
@@ -3,6 +3,7 @@ package applog
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"strings"
 	"text/template"
 
@@ -27,6 +28,7 @@ var stmt = &statement{
 			deployment_id = $1 AND
 			app_id = $2
 			{{ .pagination }}
+			{{ .label }}
 		ORDER BY
 			id {{ .sort }}
 		LIMIT
@@ -129,6 +131,7 @@ type LogQuery struct {
 	DeploymentID types.ID
 	AfterID      types.ID
 	BeforeID     types.ID
+	Label        string // Filters the logs by label, such as worker:<name>
 	Sort         string // "asc" or "desc"
 	Limit        int
 }
@@ -152,6 +155,7 @@ func (s *Store) Logs(ctx context.Context, query *LogQuery) ([]*Log, error) {
 		"limit":      query.Limit + 1,
 		"sort":       sort,
 		"pagination": "",
+		"label":      "",
 	}
 
 	if query.AfterID > 0 {
@@ -162,6 +166,11 @@ func (s *Store) Logs(ctx context.Context, query *LogQuery) ([]*Log, error) {
 		params = append(params, query.BeforeID)
 	}
 
+	if query.Label != "" {
+		params = append(params, query.Label)
+		data["label"] = fmt.Sprintf(" AND log_label = $%d", len(params))
+	}
+
 	if err := s.selectTmpl.Execute(&wr, data); err != nil {
 		return nil, err
 	}
@@ -168,26 +168,42 @@ func (b *Bitbucket) parse(res *http.Response, into interface{}) error {
 
 // get is a shorthand function to perform get requests.
 func (b *Bitbucket) get(url string) (*http.Response, error) {
-	return b.request(http.MethodGet, url, nil)
+	return b.request(context.Background(), http.MethodGet, url, nil)
+}
+
+// getWithContext is a shorthand function to perform get requests that are canceled with the context.
+func (b *Bitbucket) getWithContext(ctx context.Context, url string) (*http.Response, error) {
+	return b.request(ctx, http.MethodGet, url, nil)
 }
 
 // post is a shorthand function to perform post request.
 func (b *Bitbucket) post(url string, body interface{}) (*http.Response, error) {
-	return b.request(http.MethodPost, url, body)
+	return b.request(context.Background(), http.MethodPost, url, body)
+}
+
+// put is a shorthand function to perform put request.
+func (b *Bitbucket) put(url string, body interface{}) (*http.Response, error) {
+	return b.request(context.Background(), http.MethodPut, url, body)
 }
 
 // delete is a shorthand function to perform delete  request.
 func (b *Bitbucket) delete(url string) (*http.Response, error) {
-	return b.request(http.MethodDelete, url, nil)
+	return b.request(context.Background(), http.MethodDelete, url, nil)
 }
 
 // request performs a new request to the oauth provider.
-func (b *Bitbucket) request(method, url string, body any) (response *http.Response, err error) {
+func (b *Bitbucket) request(ctx context.Context, method, url string, body any) (response *http.Response, err error) {
 	if method == http.MethodGet {
-		response, err = b.client.Get(bitbucketAPIEndpoint + url)
+		request, _ := http.NewRequestWithContext(ctx, http.MethodGet, bitbucketAPIEndpoint+url, nil)
+		response, err = b.client.Do(request)
 	} else if method == http.MethodPost {
 		payload, _ := json.Marshal(body)
 		response, err = b.client.Post(bitbucketAPIEndpoint+url, "application/json", bytes.NewBuffer(payload))
+	} else if method == http.MethodPut {
+		payload, _ := json.Marshal(body)
+		request, _ := http.NewRequest(http.MethodPut, bitbucketAPIEndpoint+url, bytes.NewBuffer(payload))
+		request.Header.Set("Content-Type", "application/json")
+		response, err = b.client.Do(request)
 	} else if method == http.MethodDelete {
 		request, _ := http.NewRequest(http.MethodDelete, bitbucketAPIEndpoint+url, nil)
 		response, err = b.client.Do(request)
@@ -1,10 +1,12 @@
 package bitbucket
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth"
 )
@@ -46,15 +48,48 @@ func (b *Bitbucket) StormkitFile(a *App, branch string) (string, error) {
 }
 
 // ReadFile reads a file content in a bitbucket repository.
-func (b *Bitbucket) ReadFile(a *App, branch, fileNameIncludingPath string) ([]byte, error) {
-	return b.readFile(a, branch, fileNameIncludingPath)
+func (b *Bitbucket) ReadFile(ctx context.Context, a *App, branch, fileNameIncludingPath string) ([]byte, error) {
+	return b.readFile(ctx, a, branch, fileNameIncludingPath)
+}
+
+// ListFiles returns the names of the files and directories located
+// at the root level of the repository.
+func (b *Bitbucket) ListFiles(ctx context.Context, a *App, branch string) ([]string, error) {
+	owner, repo := oauth.ParseRepo(a.Repo)
+	sha, err := b.branchSha(ctx, a, branch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := b.getWithContext(ctx, fmt.Sprintf("/repositories/%s/%s/src/%s/?pagelen=100", owner, repo, sha))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	data := &FilesResponse{}
+
+	if err := json.NewDecoder(response.Body).Decode(data); err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+
+	for _, entry := range data.Values {
+		files = append(files, strings.TrimSuffix(entry.Path, "/"))
+	}
+
+	return files, nil
 }
 
 // branchSha returns the sha of the head commit of the branch. We need this method
 // because Bitbucket does not support slashes in names.
-func (b *Bitbucket) branchSha(a *App, branch string) (string, error) {
+func (b *Bitbucket) branchSha(ctx context.Context, a *App, branch string) (string, error) {
 	owner, repo := oauth.ParseRepo(a.Repo)
-	res, err := b.get(fmt.Sprintf("/repositories/%s/%s/refs/branches/%s", owner, repo, branch))
+	res, err := b.getWithContext(ctx, fmt.Sprintf("/repositories/%s/%s/refs/branches/%s", owner, repo, branch))
 	data := &BranchShaResponse{}
 
 	if err != nil || res == nil {
@@ -66,9 +101,9 @@ func (b *Bitbucket) branchSha(a *App, branch string) (string, error) {
 }
 
 // readFile reads a file content and returns it as a string.
-func (b *Bitbucket) readFile(a *App, branch, fileNameIncludingPath string) ([]byte, error) {
+func (b *Bitbucket) readFile(ctx context.Context, a *App, branch, fileNameIncludingPath string) ([]byte, error) {
 	owner, repo := oauth.ParseRepo(a.Repo)
-	sha, err := b.branchSha(a, branch)
+	sha, err := b.branchSha(ctx, a, branch)
 
 	if err != nil {
 		return nil, err
@@ -76,7 +111,7 @@ func (b *Bitbucket) readFile(a *App, branch, fileNameIncludingPath string) ([]by
 
 	uri := fmt.Sprintf("/repositories/%s/%s/src/%s/%s", owner, repo, sha, fileNameIncludingPath)
 
-	response, err := b.get(uri)
+	response, err := b.getWithContext(ctx, uri)
 
 	if err != nil {
 		if response.StatusCode == http.StatusNotFound {
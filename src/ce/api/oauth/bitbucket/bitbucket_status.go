@@ -0,0 +1,98 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth"
+)
+
+const (
+	StatusPending = "INPROGRESS"
+	StatusSuccess = "SUCCESSFUL"
+	StatusFailed  = "FAILED"
+)
+
+// statusKeyPattern matches the characters that are not allowed in build status keys.
+var statusKeyPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// StatusOpts are the options to create a build status.
+type StatusOpts struct {
+	Branch      string // Branch is used to find the latest commit when SHA is empty
+	SHA         string
+	TargetURL   string
+	State       string // One of StatusPending, StatusSuccess or StatusFailed
+	Name        string // Name differentiates this status from the others, defaults to Stormkit
+	Description string
+}
+
+// StatusRequest represents the payload to create a build status.
+type StatusRequest struct {
+	Key         string `json:"key"`
+	State       string `json:"state"`
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	Description string `json:"description"`
+}
+
+// branchResponse represents a branch returned by the bitbucket api.
+type branchResponse struct {
+	Target struct {
+		Hash string `json:"hash"`
+	} `json:"target"`
+}
+
+// CreateStatus creates a new build status for the given commit. When the
+// commit sha is not provided, the latest commit of the branch is used.
+// Statuses with the same name overwrite each other.
+func (b *Bitbucket) CreateStatus(repo string, opts StatusOpts) error {
+	owner, name := oauth.ParseRepo(repo)
+	sha := opts.SHA
+
+	if sha == "" {
+		res, err := b.get(fmt.Sprintf("/repositories/%s/%s/refs/branches/%s", owner, name, url.PathEscape(opts.Branch)))
+
+		if err != nil {
+			return err
+		}
+
+		defer res.Body.Close()
+
+		branch := &branchResponse{}
+
+		if err := b.parse(res, branch); err != nil || branch.Target.Hash == "" {
+			return err
+		}
+
+		sha = branch.Target.Hash
+	}
+
+	statusName := opts.Name
+
+	if statusName == "" {
+		statusName = "Stormkit"
+	}
+
+	// Keys are limited to 40 characters.
+	key := strings.Trim(statusKeyPattern.ReplaceAllString(strings.ToLower(statusName), "-"), "-")
+
+	if len(key) > 40 {
+		key = key[:40]
+	}
+
+	res, err := b.post(fmt.Sprintf("/repositories/%s/%s/commit/%s/statuses/build", owner, name, sha), StatusRequest{
+		Key:         key,
+		State:       opts.State,
+		Name:        statusName,
+		URL:         opts.TargetURL,
+		Description: opts.Description,
+	})
+
+	if res != nil {
+		res.Body.Close()
+	}
+
+	return err
+}
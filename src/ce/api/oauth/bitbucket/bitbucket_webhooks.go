@@ -2,6 +2,8 @@ package bitbucket
 
 import (
 	"fmt"
+	"net/url"
+	"slices"
 	"strings"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
@@ -11,6 +13,15 @@ import (
 var hooksDescription = "Stormkit Deploy Hook"
 var hooksPath = "/app/webhooks/bitbucket"
 
+// hooksEvents are the events that the hooks are subscribed to. Declined pull
+// requests are delivered as pullrequest:rejected.
+var hooksEvents = []string{
+	"repo:push",
+	"pullrequest:created",
+	"pullrequest:fulfilled",
+	"pullrequest:rejected",
+}
+
 // WebhooksRequest represents a webhooks request.
 type WebhooksRequest struct {
 	Description string   `json:"description"`
@@ -19,14 +30,20 @@ type WebhooksRequest struct {
 	Events      []string `json:"events"`
 }
 
+// Webhook represents a webhook that is installed on a repository.
+type Webhook struct {
+	UUID   string   `json:"uuid"`
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+}
+
 // WebhooksResponse represents a webhooks response payload.
 type WebhooksResponse struct {
-	Values []struct {
-		URL string `json:"url"`
-	} `json:"values"`
+	Values []Webhook `json:"values"`
 }
 
-// InstallWebhooks install webhooks for the given repository.
+// InstallWebhooks install webhooks for the given repository. Hooks that
+// were installed previously are updated to subscribe to the latest events.
 //
 // A successful operation will return an empty error.
 // Error 409: Hooks are already installed
@@ -36,47 +53,59 @@ func (b *Bitbucket) InstallWebhooks(a *App) error {
 		return err
 	}
 
-	if b.isHookInstalled(a) {
-		return nil
-	}
-
 	cnf := admin.MustConfig()
-
-	_, err := b.post(b.hooksEndpoint(a), WebhooksRequest{
+	request := WebhooksRequest{
 		Description: hooksDescription,
 		Active:      true,
 		URL:         cnf.ApiURL(fmt.Sprintf(hooksPath+"/%s", a.Secret)),
-		Events: []string{
-			"repo:push",
-			"pullrequest:created",
-			"pullrequest:fulfilled",
-		},
-	})
+		Events:      hooksEvents,
+	}
+
+	if hook := b.installedHook(a); hook != nil {
+		if hasEvents(hook.Events, hooksEvents) {
+			return nil
+		}
 
+		_, err := b.put(fmt.Sprintf("%s/%s", b.hooksEndpoint(a), url.PathEscape(hook.UUID)), request)
+		return err
+	}
+
+	_, err := b.post(b.hooksEndpoint(a), request)
 	return err
 }
 
-// hooksInstalled checks if the hooks are already installed or not.
-func (b *Bitbucket) isHookInstalled(a *App) bool {
+// installedHook returns the stormkit hook of the repository, or nil when it is not installed.
+func (b *Bitbucket) installedHook(a *App) *Webhook {
 	response, err := b.get(b.hooksEndpoint(a))
 
 	if err != nil {
-		return false
+		return nil
 	}
 
 	hooks := WebhooksResponse{}
 
 	if err := b.parse(response, &hooks); err != nil {
-		return false
+		return nil
 	}
 
 	for _, val := range hooks.Values {
 		if strings.HasPrefix(val.URL, admin.MustConfig().ApiURL(hooksPath)) {
-			return true
+			return &val
+		}
+	}
+
+	return nil
+}
+
+// hasEvents returns true when the hook is subscribed to all of the given events.
+func hasEvents(subscribed, events []string) bool {
+	for _, event := range events {
+		if !slices.Contains(subscribed, event) {
+			return false
 		}
 	}
 
-	return false
+	return true
 }
 
 // hooksEndpoint returns the hooks endpoint.
@@ -0,0 +1,192 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"golang.org/x/oauth2"
+)
+
+// ProviderName represents the provider name. Forgejo instances
+// use the same provider as they are API compatible with Gitea.
+const ProviderName = "gitea"
+
+// Gitea is a client for the Gitea (and Forgejo) REST API. Similar to
+// Bitbucket, all requests are made on behalf of the oauth2 user.
+type Gitea struct {
+	// client represents an http client.
+	client *http.Client
+
+	// user represents the oauth2 user, that is fetched from the database.
+	user *oauth.User
+
+	// apiURL is the base url of the REST API, e.g. https://git.example.org/api/v1
+	apiURL string
+}
+
+// NewClient returns a new client for the given user.
+func NewClient(userID types.ID) (*Gitea, error) {
+	conf := oauth2Config()
+
+	if conf == nil {
+		return nil, nil
+	}
+
+	usr, err := oauth.NewStore().OAuthUser(userID, conf, ProviderName)
+
+	if usr == nil || err != nil {
+		return nil, oauth.ErrProviderNotConnected
+	}
+
+	g := newClientWithToken(conf, usr.Token)
+	g.user = usr
+	return g, nil
+}
+
+// NewClientWithCode returns a new gitea client for the given access code.
+// The code is obtained after a two round shake with the provider.
+func NewClientWithCode(code string) (*Gitea, error) {
+	conf := oauth2Config()
+
+	if conf == nil {
+		return nil, nil
+	}
+
+	token, err := conf.Exchange(context.Background(), code, oauth2.AccessTypeOffline)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return newClientWithToken(conf, token), nil
+}
+
+// AuthCodeURL returns the url for the authentication.
+func AuthCodeURL(token string) string {
+	return oauth2Config().AuthCodeURL(token)
+}
+
+// Token returns the access token of the user.
+func (g *Gitea) Token() string {
+	return g.user.Token.AccessToken
+}
+
+// newClientWithToken returns a new client with given token.
+func newClientWithToken(conf *oauth2.Config, token *oauth2.Token) *Gitea {
+	return &Gitea{
+		user:   &oauth.User{ProviderName: ProviderName, Token: token},
+		client: conf.Client(context.Background(), token),
+		apiURL: admin.MustConfig().GiteaURL("/api/v1"),
+	}
+}
+
+// parse is a shorthand function to parse gitea responses.
+func (g *Gitea) parse(res *http.Response, into any) error {
+	defer res.Body.Close()
+
+	err := json.NewDecoder(res.Body).Decode(into)
+
+	if err != nil {
+		slog.Errorf("gitea cannot parse payload: %v", err)
+	}
+
+	return err
+}
+
+// get is a shorthand function to perform get requests.
+func (g *Gitea) get(url string) (*http.Response, error) {
+	return g.request(context.Background(), http.MethodGet, url, nil)
+}
+
+// getWithContext is a shorthand function to perform get requests that are canceled with the context.
+func (g *Gitea) getWithContext(ctx context.Context, url string) (*http.Response, error) {
+	return g.request(ctx, http.MethodGet, url, nil)
+}
+
+// post is a shorthand function to perform post requests.
+func (g *Gitea) post(url string, body any) (*http.Response, error) {
+	return g.request(context.Background(), http.MethodPost, url, body)
+}
+
+// request performs a new request to the gitea api.
+func (g *Gitea) request(ctx context.Context, method, url string, body any) (*http.Response, error) {
+	var payload io.Reader
+
+	if body != nil {
+		data, err := json.Marshal(body)
+
+		if err != nil {
+			return nil, err
+		}
+
+		payload = bytes.NewBuffer(data)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, g.apiURL+url, payload)
+
+	if err != nil {
+		return nil, err
+	}
+
+	request.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := g.client.Do(request)
+
+	if err != nil {
+		slog.Errorf("error while performing request to: %s, err=%v", url, err)
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusForbidden || response.StatusCode == http.StatusUnauthorized {
+		response.Body.Close()
+		return nil, oauth.ErrCredsInvalidPermissions
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		response.Body.Close()
+		return nil, oauth.ErrRepoNotFound
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		response.Body.Close()
+		return nil, fmt.Errorf("was expecting a 2xx response but received %s for request: %s", response.Status, url)
+	}
+
+	return response, nil
+}
+
+// oauth2Config returns a new oauth2.Config instance.
+func oauth2Config() *oauth2.Config {
+	cnf := admin.MustConfig()
+
+	if !cnf.IsGiteaEnabled() {
+		slog.Info(
+			"Gitea client is not configured and is trying to be accessed. " +
+				"Configure it through the GITEA_* environment variables.",
+		)
+
+		return nil
+	}
+
+	return &oauth2.Config{
+		RedirectURL:  cnf.AuthConfig.Gitea.RedirectURL,
+		ClientID:     cnf.AuthConfig.Gitea.ClientID,
+		ClientSecret: cnf.AuthConfig.Gitea.ClientSecret,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  cnf.GiteaURL("/login/oauth/authorize"),
+			TokenURL: cnf.GiteaURL("/login/oauth/access_token"),
+		},
+	}
+}
@@ -0,0 +1,101 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth"
+)
+
+// RepoResponse represents a repository returned by the gitea api.
+type RepoResponse struct {
+	Name          string `json:"name"`
+	FullName      string `json:"full_name"`
+	DefaultBranch string `json:"default_branch"`
+	Private       bool   `json:"private"`
+}
+
+// ContentsEntry represents an entry of the contents response.
+type ContentsEntry struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// DefaultBranch returns the default branch of a given repository.
+func (g *Gitea) DefaultBranch(repo string) (string, error) {
+	owner, name := oauth.ParseRepo(repo)
+	res, err := g.get(fmt.Sprintf("/repos/%s/%s", owner, name))
+
+	if err != nil {
+		return "", err
+	}
+
+	data := &RepoResponse{}
+
+	if err := g.parse(res, data); err != nil {
+		return "", err
+	}
+
+	return data.DefaultBranch, nil
+}
+
+// ReadFile returns the content of the file in the given branch.
+func (g *Gitea) ReadFile(ctx context.Context, repo, branch, fileName string) ([]byte, error) {
+	owner, name := oauth.ParseRepo(repo)
+	res, err := g.getWithContext(ctx, fmt.Sprintf("/repos/%s/%s/raw/%s?ref=%s", owner, name, fileName, url.QueryEscape(branch)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	return io.ReadAll(res.Body)
+}
+
+// ListFiles returns the names of the files and directories located
+// at the root level of the repository.
+func (g *Gitea) ListFiles(ctx context.Context, repo, branch string) ([]string, error) {
+	owner, name := oauth.ParseRepo(repo)
+	res, err := g.getWithContext(ctx, fmt.Sprintf("/repos/%s/%s/contents?ref=%s", owner, name, url.QueryEscape(branch)))
+
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []ContentsEntry{}
+
+	if err := g.parse(res, &entries); err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+
+	for _, entry := range entries {
+		files = append(files, entry.Name)
+	}
+
+	return files, nil
+}
+
+// ListRepos returns the repositories that the user has access to. The second
+// return value is the total number of repositories.
+func (g *Gitea) ListRepos(page, limit int) ([]RepoResponse, int, error) {
+	res, err := g.get(fmt.Sprintf("/user/repos?page=%d&limit=%d", page, limit))
+
+	if err != nil {
+		return nil, 0, err
+	}
+
+	total, _ := strconv.Atoi(res.Header.Get("X-Total-Count"))
+	repos := []RepoResponse{}
+
+	if err := g.parse(res, &repos); err != nil {
+		return nil, 0, err
+	}
+
+	return repos, total, nil
+}
@@ -0,0 +1,38 @@
+package gitea
+
+import (
+	"fmt"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth"
+)
+
+// CommentRequest represents the payload to create a comment.
+type CommentRequest struct {
+	Body string `json:"body"`
+}
+
+// CommentResponse represents a comment returned by the gitea api.
+type CommentResponse struct {
+	ID int64 `json:"id"`
+}
+
+// PullRequestComment creates a new comment on the pull request. Gitea
+// treats pull requests as issues, hence the issues endpoint.
+func (g *Gitea) PullRequestComment(repo string, prNumber int64, body string) (*CommentResponse, error) {
+	owner, name := oauth.ParseRepo(repo)
+	res, err := g.post(fmt.Sprintf("/repos/%s/%s/issues/%d/comments", owner, name, prNumber), CommentRequest{
+		Body: body,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &CommentResponse{}
+
+	if err := g.parse(res, comment); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
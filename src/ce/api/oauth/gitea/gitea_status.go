@@ -0,0 +1,81 @@
+package gitea
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth"
+)
+
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+)
+
+// StatusOpts are the options to create a commit status.
+type StatusOpts struct {
+	Branch      string // Branch is used to find the latest commit when SHA is empty
+	SHA         string
+	TargetURL   string
+	State       string // One of StatusPending, StatusSuccess or StatusFailure
+	Context     string // Context differentiates this status from the others, defaults to Stormkit
+	Description string
+}
+
+// StatusRequest represents the payload to create a commit status.
+type StatusRequest struct {
+	State       string `json:"state"`
+	TargetURL   string `json:"target_url"`
+	Description string `json:"description"`
+	Context     string `json:"context"`
+}
+
+// BranchResponse represents a branch returned by the gitea api.
+type BranchResponse struct {
+	Commit struct {
+		ID string `json:"id"`
+	} `json:"commit"`
+}
+
+// CreateStatus creates a new status for the given commit. When the
+// commit sha is not provided, the latest commit of the branch is used.
+func (g *Gitea) CreateStatus(repo string, opts StatusOpts) error {
+	owner, name := oauth.ParseRepo(repo)
+	sha := opts.SHA
+
+	if sha == "" {
+		res, err := g.get(fmt.Sprintf("/repos/%s/%s/branches/%s", owner, name, url.PathEscape(opts.Branch)))
+
+		if err != nil {
+			return err
+		}
+
+		branch := &BranchResponse{}
+
+		if err := g.parse(res, branch); err != nil || branch.Commit.ID == "" {
+			return err
+		}
+
+		sha = branch.Commit.ID
+	}
+
+	statusContext := opts.Context
+
+	if statusContext == "" {
+		statusContext = "Stormkit"
+	}
+
+	res, err := g.post(fmt.Sprintf("/repos/%s/%s/statuses/%s", owner, name, sha), StatusRequest{
+		State:       opts.State,
+		TargetURL:   opts.TargetURL,
+		Description: opts.Description,
+		Context:     statusContext,
+	})
+
+	if res != nil {
+		res.Body.Close()
+	}
+
+	return err
+}
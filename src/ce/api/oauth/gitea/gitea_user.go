@@ -0,0 +1,67 @@
+package gitea
+
+import (
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth"
+)
+
+// ProfileResponse represents a user profile response.
+type ProfileResponse struct {
+	Login     string `json:"login"`
+	FullName  string `json:"full_name"`
+	Email     string `json:"email"`
+	AvatarURL string `json:"avatar_url"`
+	HTMLURL   string `json:"html_url"`
+}
+
+// EmailResponse represents an entry in the email list response.
+type EmailResponse struct {
+	Email    string `json:"email"`
+	Verified bool   `json:"verified"`
+	Primary  bool   `json:"primary"`
+}
+
+// UserProfile returns information on the authenticated user.
+func (g *Gitea) UserProfile() (*oauth.User, error) {
+	res, err := g.get("/user")
+
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &ProfileResponse{}
+
+	if err := g.parse(res, profile); err != nil {
+		return nil, err
+	}
+
+	g.user.AccountURI = profile.HTMLURL
+	g.user.AvatarURI = profile.AvatarURL
+	g.user.DisplayName = profile.Login
+	g.user.FullName = profile.FullName
+
+	res, err = g.get("/user/emails")
+
+	if err != nil {
+		return g.user, err
+	}
+
+	emails := []EmailResponse{}
+
+	if err := g.parse(res, &emails); err != nil {
+		return g.user, err
+	}
+
+	g.user.Emails = []oauth.Email{}
+
+	for _, email := range emails {
+		if email.Verified {
+			g.user.Emails = append(g.user.Emails, oauth.Email{
+				Address:    email.Email,
+				IsPrimary:  email.Primary,
+				IsVerified: email.Verified,
+			})
+		}
+	}
+
+	return g.user, nil
+}
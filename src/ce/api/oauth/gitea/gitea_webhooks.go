@@ -0,0 +1,107 @@
+package gitea
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth"
+)
+
+var hooksPath = "/app/webhooks/gitea"
+
+// HookRequest represents the payload to create a repository webhook.
+type HookRequest struct {
+	Type   string            `json:"type"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	Active bool              `json:"active"`
+}
+
+// HookResponse represents a webhook returned by the gitea api.
+type HookResponse struct {
+	ID     int64             `json:"id"`
+	Config map[string]string `json:"config"`
+}
+
+// InstallWebhooks installs the webhooks for the given repository. The webhooks
+// will be used to trigger deployments on push and pull request events.
+// `repo` is the Stormkit formatted repository address.
+func (g *Gitea) InstallWebhooks(repo string) (bool, error) {
+	owner, name := oauth.ParseRepo(repo)
+	endpoint := fmt.Sprintf("/repos/%s/%s/hooks", owner, name)
+
+	if has, _ := g.hooksInstalled(endpoint); has {
+		return false, nil
+	}
+
+	cnf := admin.MustConfig()
+
+	res, err := g.post(endpoint, HookRequest{
+		Type:   "gitea",
+		Active: true,
+		Events: []string{"push", "pull_request"},
+		Config: map[string]string{
+			"url":          cnf.WebhooksURL(hooksPath),
+			"content_type": "json",
+			"secret":       cnf.AuthConfig.Gitea.WebhookSecret,
+		},
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	res.Body.Close()
+	return true, nil
+}
+
+// hooksInstalled checks whether the hooks are already installed or not.
+func (g *Gitea) hooksInstalled(endpoint string) (bool, error) {
+	res, err := g.get(endpoint)
+
+	if err != nil {
+		return false, err
+	}
+
+	hooks := []HookResponse{}
+
+	if err := g.parse(res, &hooks); err != nil {
+		return false, err
+	}
+
+	url := admin.MustConfig().WebhooksURL(hooksPath)
+
+	for _, hook := range hooks {
+		if strings.Contains(hook.Config["url"], url) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// VerifySignature verifies that the payload is signed with the webhook secret.
+// Gitea and Forgejo send the hex encoded HMAC-SHA256 of the body as the signature.
+func VerifySignature(payload []byte, signature string) bool {
+	cnf := admin.MustConfig().AuthConfig
+
+	if cnf == nil {
+		return false
+	}
+
+	secret := cnf.Gitea.WebhookSecret
+
+	if secret == "" || signature == "" {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(strings.TrimPrefix(signature, "sha256=")))
+}
@@ -140,31 +140,8 @@ func DefaultBranch(repo string) (string, error) {
 // StormkitFile returns the contents of the stormkit.config.yml file,
 // that is located at the root level of the repository.
 func StormkitFile(repo, branch string) (string, error) {
-	client, err := NewApp(repo)
-
-	if err != nil || client == nil {
-		return "", err
-	}
-
-	fc, _, res, err := client.Repositories.GetContents(
-		context.Background(),
-		client.Owner,
-		client.Repo,
-		"stormkit.config.yml",
-		&github.RepositoryContentGetOptions{
-			Ref: branch,
-		},
-	)
-
-	if res != nil && res.StatusCode == http.StatusNotFound {
-		return "", nil
-	}
-
-	if fc == nil || err != nil {
-		return "", err
-	}
-
-	return fc.GetContent()
+	content, err := ReadFile(context.Background(), repo, branch, "stormkit.config.yml")
+	return string(content), err
 }
 
 // installationID returns the installation id for the given repository, if any.
@@ -0,0 +1,75 @@
+package github
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/go-github/v71/github"
+)
+
+// ReadFile returns the contents of the given file in the repository.
+// It returns nil when the file does not exist.
+func ReadFile(ctx context.Context, repo, branch, fileName string) ([]byte, error) {
+	client, err := NewApp(repo)
+
+	if err != nil || client == nil {
+		return nil, err
+	}
+
+	fc, _, res, err := client.Repositories.GetContents(
+		ctx,
+		client.Owner,
+		client.Repo,
+		fileName,
+		&github.RepositoryContentGetOptions{
+			Ref: branch,
+		},
+	)
+
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if fc == nil || err != nil {
+		return nil, err
+	}
+
+	content, err := fc.GetContent()
+	return []byte(content), err
+}
+
+// ListFiles returns the names of the files and directories located
+// at the root level of the repository.
+func ListFiles(ctx context.Context, repo, branch string) ([]string, error) {
+	client, err := NewApp(repo)
+
+	if err != nil || client == nil {
+		return nil, err
+	}
+
+	_, dc, res, err := client.Repositories.GetContents(
+		ctx,
+		client.Owner,
+		client.Repo,
+		"",
+		&github.RepositoryContentGetOptions{
+			Ref: branch,
+		},
+	)
+
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+
+	for _, content := range dc {
+		files = append(files, content.GetName())
+	}
+
+	return files, nil
+}
@@ -7,8 +7,39 @@ import (
 	"github.com/google/go-github/v71/github"
 )
 
+// StatusOpts are the options to create a commit status.
+type StatusOpts struct {
+	Branch      string // Branch is used to find the latest commit when SHA is empty
+	SHA         string
+	TargetURL   string
+	State       string // One of StatusPending, StatusSuccess or StatusFailure
+	Context     string // Context differentiates this status from the others, defaults to Stormkit
+	Description string
+}
+
 // CreateStatus creates a new status
 func CreateStatus(repo, branch, url, status string) error {
+	var text string
+
+	if status == StatusFailure {
+		text = "Deployment failed"
+	} else if status == StatusSuccess {
+		text = "Deployment completed"
+	} else {
+		text = "Deploying application"
+	}
+
+	return CreateCommitStatus(repo, StatusOpts{
+		Branch:      branch,
+		TargetURL:   url,
+		State:       status,
+		Description: text,
+	})
+}
+
+// CreateCommitStatus creates a new status for the given commit. When the
+// commit sha is not provided, the latest commit of the branch is used.
+func CreateCommitStatus(repo string, opts StatusOpts) error {
 	gh, err := NewApp(repo)
 
 	if err != nil || gh == nil {
@@ -16,40 +47,39 @@ func CreateStatus(repo, branch, url, status string) error {
 	}
 
 	ctx := context.Background()
+	sha := opts.SHA
 
-	// Grab the latest commit for the branch
-	ghBranch, _, err := gh.Repositories.GetBranch(ctx, gh.Owner, gh.Repo, branch, 0)
+	if sha == "" {
+		// Grab the latest commit for the branch
+		ghBranch, _, err := gh.Repositories.GetBranch(ctx, gh.Owner, gh.Repo, opts.Branch, 0)
 
-	if err != nil || ghBranch == nil || ghBranch.Commit == nil {
-		return err
-	}
+		if err != nil || ghBranch == nil || ghBranch.Commit == nil {
+			return err
+		}
 
-	sha := ghBranch.Commit.SHA
+		if ghBranch.Commit.SHA == nil {
+			return nil
+		}
 
-	if sha == nil {
-		return nil
+		sha = *ghBranch.Commit.SHA
 	}
 
-	var text string
+	statusContext := opts.Context
 
-	if status == StatusFailure {
-		text = "Deployment failed"
-	} else if status == StatusSuccess {
-		text = "Deployment completed"
-	} else {
-		text = "Deploying application"
+	if statusContext == "" {
+		statusContext = "Stormkit"
 	}
 
 	_, _, err = gh.Repositories.CreateStatus(
 		ctx,
 		gh.Owner,
 		gh.Repo,
-		*sha,
+		sha,
 		&github.RepoStatus{
-			Description: aws.String(text),
-			TargetURL:   aws.String(url),
-			State:       aws.String(status),
-			Context:     aws.String("Stormkit"),
+			Description: aws.String(opts.Description),
+			TargetURL:   aws.String(opts.TargetURL),
+			State:       aws.String(opts.State),
+			Context:     aws.String(statusContext),
 		})
 
 	return err
@@ -1,6 +1,7 @@
 package gitlab
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth"
@@ -21,11 +22,11 @@ func (g *Gitlab) DefaultBranch(repo string) (string, error) {
 }
 
 // Files returns the list of files in a gitlab repository.
-func (g *Gitlab) ReadFile(repo, branch, fileName string) ([]byte, error) {
+func (g *Gitlab) ReadFile(ctx context.Context, repo, branch, fileName string) ([]byte, error) {
 	owner, project := oauth.ParseRepo(repo)
 	pid := fmt.Sprintf("%s/%s", owner, project)
 	options := &gitlab.GetRawFileOptions{Ref: &branch}
-	content, _, err := g.RepositoryFiles.GetRawFile(pid, fileName, options)
+	content, _, err := g.RepositoryFiles.GetRawFile(pid, fileName, options, gitlab.WithContext(ctx))
 
 	if err != nil {
 		return nil, err
@@ -33,3 +34,24 @@ func (g *Gitlab) ReadFile(repo, branch, fileName string) ([]byte, error) {
 
 	return content, err
 }
+
+// ListFiles returns the names of the files and directories located
+// at the root level of the repository.
+func (g *Gitlab) ListFiles(ctx context.Context, repo, branch string) ([]string, error) {
+	owner, project := oauth.ParseRepo(repo)
+	pid := fmt.Sprintf("%s/%s", owner, project)
+	options := &gitlab.ListTreeOptions{Ref: &branch, ListOptions: gitlab.ListOptions{PerPage: 100}}
+	nodes, _, err := g.Repositories.ListTree(pid, options, gitlab.WithContext(ctx))
+
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+
+	for _, node := range nodes {
+		files = append(files, node.Name)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,57 @@
+package gitlab
+
+import (
+	"fmt"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth"
+	"github.com/xanzy/go-gitlab"
+)
+
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+)
+
+// StatusOpts are the options to create a commit status.
+type StatusOpts struct {
+	Branch      string // Branch is used to find the latest commit when SHA is empty
+	SHA         string
+	TargetURL   string
+	State       string // One of StatusPending, StatusSuccess or StatusFailed
+	Name        string // Name differentiates this status from the others, defaults to Stormkit
+	Description string
+}
+
+// CreateStatus creates a new status for the given commit. When the
+// commit sha is not provided, the latest commit of the branch is used.
+func (g *Gitlab) CreateStatus(repo string, opts StatusOpts) error {
+	owner, project := oauth.ParseRepo(repo)
+	pid := fmt.Sprintf("%s/%s", owner, project)
+	sha := opts.SHA
+
+	if sha == "" {
+		branch, _, err := g.Branches.GetBranch(pid, opts.Branch)
+
+		if err != nil || branch == nil || branch.Commit == nil {
+			return err
+		}
+
+		sha = branch.Commit.ID
+	}
+
+	name := opts.Name
+
+	if name == "" {
+		name = "Stormkit"
+	}
+
+	_, _, err := g.Commits.SetCommitStatus(pid, sha, &gitlab.SetCommitStatusOptions{
+		State:       gitlab.BuildStateValue(opts.State),
+		Name:        &name,
+		TargetURL:   &opts.TargetURL,
+		Description: &opts.Description,
+	})
+
+	return err
+}
@@ -14,7 +14,7 @@ import (
 var hooksPath = "/app/webhooks/gitlab"
 
 // InstallWebhooks installs the webhooks for the given repository. The webhooks
-// will be used to trigger deployments on push and merge request events.
+// will be used to trigger deployments on push, tag push and merge request events.
 // `repo` is the Stormkit formatted repository address.
 func (g *Gitlab) InstallWebhooks(repo string) (bool, error) {
 	owner, project := oauth.ParseRepo(repo)
@@ -27,6 +27,7 @@ func (g *Gitlab) InstallWebhooks(repo string) (bool, error) {
 	hook, res, err := g.Projects.AddProjectHook(repository, &gl.AddProjectHookOptions{
 		URL:                 utils.Ptr(admin.MustConfig().WebhooksURL(hooksPath)),
 		PushEvents:          utils.Ptr(true),
+		TagPushEvents:       utils.Ptr(true),
 		MergeRequestsEvents: utils.Ptr(true),
 		NoteEvents:          utils.Ptr(true),
 	})
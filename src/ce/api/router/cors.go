@@ -78,8 +78,26 @@ func ResetCors() {
 	Cors()
 }
 
+// timeoutExemptPaths are the endpoints that are not wrapped with a timeout. http.TimeoutHandler
+// buffers the response, therefore it does not support streaming server-sent events and it
+// would close long-lived connections.
+var timeoutExemptPaths = []*regexp.Regexp{
+	regexp.MustCompile(`^/app/[0-9]+/deploy/[0-9]+/logs/stream$`),
+}
+
 func WithTimeout(h http.Handler) http.Handler {
-	return http.TimeoutHandler(h, config.Get().DbConfigTimeouts.ConnectTimeout, "timeout")
+	timeout := http.TimeoutHandler(h, config.Get().DbConfigTimeouts.ConnectTimeout, "timeout")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, path := range timeoutExemptPaths {
+			if path.MatchString(r.URL.Path) {
+				h.ServeHTTP(w, r)
+				return
+			}
+		}
+
+		timeout.ServeHTTP(w, r)
+	})
 }
 
 // withCors enables cors headers for the api.
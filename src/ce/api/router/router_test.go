@@ -0,0 +1,58 @@
+package router_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/router"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/user/usertest"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp/shttptest"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v3"
+)
+
+type RouterSuite struct {
+	suite.Suite
+	*factory.Factory
+
+	conn databasetest.TestDB
+}
+
+func (s *RouterSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+}
+
+func (s *RouterSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+}
+
+func (s *RouterSuite) Test_LogsStream_WithoutTimeout() {
+	depl := s.MockDeployment(nil, map[string]any{
+		"ExitCode": null.IntFrom(0),
+		"Logs":     null.StringFrom("build completed"),
+	})
+
+	response := shttptest.RequestWithHeaders(
+		router.Get().Handler(),
+		shttp.MethodGet,
+		fmt.Sprintf("/app/%s/deploy/%s/logs/stream", depl.AppID.String(), depl.ID.String()),
+		nil,
+		map[string]string{
+			"Authorization": usertest.Authorization(s.GetUser().ID),
+		},
+	)
+
+	// The timeout handler does not support flushing, the stream would fail otherwise.
+	s.Equal(http.StatusOK, response.Code)
+	s.Equal("text/event-stream", response.Header().Get("Content-Type"))
+	s.Contains(response.String(), "event: exit")
+}
+
+func TestRouterSuite(t *testing.T) {
+	suite.Run(t, &RouterSuite{})
+}
@@ -11,6 +11,7 @@ import (
 	jwt "github.com/golang-jwt/jwt/v5"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/bitbucket"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitea"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/github"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/oauth/gitlab"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/user"
@@ -226,6 +227,18 @@ func authUser(providerName, code string) (*oauth.User, error) {
 			return nil, err
 		}
 
+		return client.UserProfile()
+	case gitea.ProviderName:
+		client, err := gitea.NewClientWithCode(code)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if client == nil {
+			return nil, user.ErrInvalidProvider
+		}
+
 		return client.UserProfile()
 	default:
 		return nil, user.ErrInvalidProvider
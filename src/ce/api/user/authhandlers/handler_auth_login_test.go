@@ -130,6 +130,33 @@ func (s *HandlerAuthLoginSuite) Test_BitbucketRedirectContainsParameters() {
 	s.Contains(loc, "response_type=code")
 }
 
+func (s *HandlerAuthLoginSuite) Test_GiteaRedirectsToInstance() {
+	cnf := admin.MustConfig()
+	cnf.AuthConfig = &admin.AuthConfig{
+		Gitea: admin.GiteaConfig{
+			BaseURL:      "https://git.example.org",
+			ClientID:     "test-client-id",
+			ClientSecret: "test-client-secret",
+		},
+	}
+
+	s.NoError(admin.Store().UpsertConfig(context.TODO(), cnf))
+
+	response := shttptest.Request(
+		shttp.NewRouter().RegisterService(authhandlers.Services).Router().Handler(),
+		shttp.MethodGet,
+		"/auth/gitea",
+		nil,
+	)
+
+	s.Equal(http.StatusTemporaryRedirect, response.Code)
+
+	loc := response.Header().Get("Location")
+	s.True(strings.HasPrefix(loc, "https://git.example.org/login/oauth/authorize?"))
+	s.Contains(loc, "client_id=test-client-id")
+	s.Contains(loc, "response_type=code")
+}
+
 func TestHandlerAuthLoginSuite(t *testing.T) {
 	suite.Run(t, &HandlerAuthLoginSuite{})
 }
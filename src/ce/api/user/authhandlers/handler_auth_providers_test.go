@@ -49,6 +49,11 @@ func (s *HandlerAuthProvidersSuite) Test_Providers() {
 			ClientID:     "my-client-id",
 			ClientSecret: "my-secret",
 		},
+		Gitea: admin.GiteaConfig{
+			BaseURL:      "https://git.example.org",
+			ClientID:     "my-client-id",
+			ClientSecret: "my-secret",
+		},
 	}
 
 	s.NoError(admin.Store().UpsertConfig(context.Background(), cnf))
@@ -64,7 +69,8 @@ func (s *HandlerAuthProvidersSuite) Test_Providers() {
 	expected := `{
 		"github": true,
 		"gitlab": true,
-		"bitbucket": true
+		"bitbucket": true,
+		"gitea": true
 	}`
 
 	s.Equal(http.StatusOK, response.Code)
@@ -93,6 +99,7 @@ func (s *HandlerAuthProvidersSuite) Test_Provider_BasicAuthEnabled() {
 		"github": false,
 		"gitlab": false,
 		"bitbucket": false,
+		"gitea": false,
 		"basicAuth": "enabled"
 	}`
 
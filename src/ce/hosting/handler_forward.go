@@ -107,6 +107,13 @@ func (r *RequestServer) artifacts() {
 		data, _ = r.res.Data.([]byte)
 	}
 
+	status := r.res.Status
+
+	// The response is written with 200 when no status is set.
+	if status == 0 {
+		status = http.StatusOK
+	}
+
 	Queue(&jobs.HostingRecord{
 		AppID:           r.req.Host.Config.AppID,
 		EnvID:           r.req.Host.Config.EnvID,
@@ -117,6 +124,7 @@ func (r *RequestServer) artifacts() {
 		Logs:            r.logs,
 		Analytics:       r.record,
 		TotalBandwidth:  int64(len(data)) + headersSize(r.res.Headers),
+		StatusCode:      status,
 	})
 }
 
@@ -251,34 +259,17 @@ func (r *RequestServer) Dynamic() *shttp.Response {
 		arn = cnf.APILocation
 	}
 
-	result, err := integrations.Client().Invoke(integrations.InvokeArgs{
-		URL:          url,
-		ARN:          arn,
-		Body:         r.req.Body,
-		Method:       r.req.Method,
-		Headers:      r.req.Headers(),
-		HostName:     r.req.Host.Name,
-		AppID:        cnf.AppID,
-		EnvID:        cnf.EnvID,
-		DeploymentID: cnf.DeploymentID,
-		Command:      cnf.ServerCmd,
-		EnvVariables: cnf.EnvVariables,
-		IsPublished:  cnf.Percentage > 0,
-		CaptureLogs:  true,
-		QueueLog: func(log *integrations.Log) {
-			Queue(&jobs.HostingRecord{
-				AppID:         r.req.Host.Config.AppID,
-				EnvID:         r.req.Host.Config.EnvID,
-				DeploymentID:  r.req.Host.Config.DeploymentID,
-				HostName:      r.req.Host.Name,
-				BillingUserID: r.req.Host.Config.BillingUserID,
-				Logs:          []integrations.Log{*log},
-			})
-		},
-		Context: map[string]any{
-			"apiPrefix": cnf.APIPathPrefix,
-		},
-	})
+	args := serviceArgs(cnf, r.req.Host.Name)
+	args.URL = url
+	args.ARN = arn
+	args.Body = r.req.Body
+	args.Method = r.req.Method
+	args.Headers = r.req.Headers()
+	args.Context = map[string]any{
+		"apiPrefix": cnf.APIPathPrefix,
+	}
+
+	result, err := integrations.Client().Invoke(args)
 
 	r.fnInvoked = true
 
@@ -308,6 +299,36 @@ func (r *RequestServer) Dynamic() *shttp.Response {
 	return r.res
 }
 
+// serviceArgs returns the invoke arguments that are shared by all requests
+// to the server of the given configuration.
+func serviceArgs(cnf *appconf.Config, hostName string) integrations.InvokeArgs {
+	return integrations.InvokeArgs{
+		HostName:      hostName,
+		AppID:         cnf.AppID,
+		EnvID:         cnf.EnvID,
+		DeploymentID:  cnf.DeploymentID,
+		Command:       cnf.ServerCmd,
+		HealthCheck:   cnf.HealthCheck,
+		RestartPolicy: cnf.RestartPolicy,
+		Replicas:      cnf.Replicas,
+		Resources:     cnf.Resources,
+		EnvVariables:  cnf.EnvVariables,
+		IsPublished:   cnf.Percentage > 0,
+		FullTraffic:   cnf.Percentage >= 100,
+		CaptureLogs:   true,
+		QueueLog: func(log *integrations.Log) {
+			Queue(&jobs.HostingRecord{
+				AppID:         cnf.AppID,
+				EnvID:         cnf.EnvID,
+				DeploymentID:  cnf.DeploymentID,
+				HostName:      hostName,
+				BillingUserID: cnf.BillingUserID,
+				Logs:          []integrations.Log{*log},
+			})
+		},
+	}
+}
+
 func (r *RequestServer) Error(requestErr error) *shttp.Response {
 	cnf := r.req.Host.Config
 	r.res = &shttp.Response{
@@ -334,6 +355,7 @@ func (r *RequestServer) Error(requestErr error) *shttp.Response {
 	file, err := r.client.GetFile(integrations.GetFileArgs{
 		Location:     cnf.StorageLocation,
 		FileName:     customErrorFile.FileName,
+		ETag:         blobETag(cnf.StorageLocation, shttp.HeadersFromMap(customErrorFile.Headers)),
 		DeploymentID: cnf.DeploymentID,
 	})
 
@@ -384,6 +406,7 @@ func (r *RequestServer) NotFound() *shttp.Response {
 	file, err := r.client.GetFile(integrations.GetFileArgs{
 		Location:     cnf.StorageLocation,
 		FileName:     customNotFound.FileName,
+		ETag:         blobETag(cnf.StorageLocation, shttp.HeadersFromMap(customNotFound.Headers)),
 		DeploymentID: cnf.DeploymentID,
 	})
 
@@ -417,6 +440,7 @@ func (r *RequestServer) fileContent(headers http.Header) ([]byte, error) {
 		Location:     r.req.Host.Config.StorageLocation,
 		DeploymentID: r.req.Host.Config.DeploymentID,
 		FileName:     r.fileMeta.Name,
+		ETag:         blobETag(r.req.Host.Config.StorageLocation, headers),
 	})
 
 	if err != nil {
@@ -688,6 +712,16 @@ func ErrorFile(cnf *appconf.Config) *appconf.StaticFile {
 	return nil
 }
 
+// blobETag returns the etag of the file when the deployment stores its
+// files by their content hash. It returns an empty string otherwise.
+func blobETag(location string, headers http.Header) string {
+	if integrations.IsBlobLocation(location) {
+		return headers.Get("ETag")
+	}
+
+	return ""
+}
+
 // headersSize calculates the approximate memory size of HTTP headers.
 func headersSize(m map[string][]string) int64 {
 	var size int64
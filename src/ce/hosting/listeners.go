@@ -6,8 +6,10 @@ import (
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/router"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/mise"
 	"go.uber.org/zap"
 )
@@ -21,6 +23,9 @@ func RegisterListeners() {
 		rediscache.EventInvalidateAdminCache:   invalidateAdminCache,
 		rediscache.EventRuntimesInstall:        admin.InstallDependencies,
 		rediscache.EventMiseUpdate:             mise.AutoUpdate,
+		rediscache.EventStopEnvServices:        stopEnvServices,
+		rediscache.EventSwapServices:           swapServices,
+		rediscache.EventWorkers:                handleWorkers,
 	}
 
 	for event, handler := range handlers {
@@ -28,6 +33,12 @@ func RegisterListeners() {
 			slog.Errorf("failed to register event %s: %v", event, err)
 		}
 	}
+
+	integrations.Filesys().ProcessManager().OnStatusChange(serviceStatusChanged)
+
+	go saveServiceStatuses()
+	go reportServiceUsage()
+	go syncWorkersPeriodically()
 }
 
 func invalidateAdminCache(ctx context.Context, payload ...string) {
@@ -46,6 +57,17 @@ func invalidateAdminCache(ctx context.Context, payload ...string) {
 	appCacheMu.Unlock()
 }
 
+// stopEnvServices kills the process manager services of the given environments.
+func stopEnvServices(ctx context.Context, payload ...string) {
+	pm := integrations.Filesys().ProcessManager()
+
+	for _, id := range payload {
+		if envID := utils.StringToID(id); envID != 0 {
+			pm.KillEnv(envID)
+		}
+	}
+}
+
 // InvalidateCache is a function that invalidates the domain configuration cache.
 func InvalidateCache(ctx context.Context, payload ...string) {
 	slog.Debug(slog.LogOpts{
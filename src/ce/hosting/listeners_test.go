@@ -0,0 +1,159 @@
+package hosting
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v3"
+)
+
+type ListenersSuite struct {
+	suite.Suite
+	*factory.Factory
+
+	conn   databasetest.TestDB
+	tmpdir string
+}
+
+func (s *ListenersSuite) SetupSuite() {
+	s.conn = databasetest.InitTx("listeners_suite")
+	s.Factory = factory.New(s.conn)
+
+	tmpdir, err := os.MkdirTemp("", "tmp-hosting-listeners-")
+	s.NoError(err)
+	s.tmpdir = tmpdir
+
+	integrations.SetDefaultClient(integrations.Filesys())
+
+	service := rediscache.Service()
+	s.NoError(service.SubscribeAsync(rediscache.EventSwapServices, swapServices))
+	s.NoError(service.SubscribeAsync(rediscache.EventWorkers, handleWorkers))
+}
+
+func (s *ListenersSuite) TearDownSuite() {
+	s.conn.CloseTx()
+	integrations.SetDefaultClient(nil)
+	os.RemoveAll(s.tmpdir)
+}
+
+// mockDeployment writes a server and a worker script into the directory of the deployment.
+func (s *ListenersSuite) mockDeployment(env *factory.MockEnv, version string) (*factory.MockDeployment, string) {
+	dir := path.Join(s.tmpdir, version)
+
+	s.NoError(os.MkdirAll(dir, 0755))
+	s.NoError(os.WriteFile(path.Join(dir, "server.js"), []byte(`
+		require('http').createServer((req, res) => res.end('`+version+`')).listen(process.env.PORT, '127.0.0.1');
+	`), 0664))
+	s.NoError(os.WriteFile(path.Join(dir, "worker.js"), []byte(`
+		setInterval(() => console.log("consuming"), 1000);
+	`), 0664))
+
+	location := fmt.Sprintf("local:%s/server.js", dir)
+
+	depl := s.MockDeployment(env, map[string]any{
+		"FunctionLocation": null.NewString(location, true),
+		"PublishedV2": deploy.PublishedInfoV2{
+			{EnvID: env.ID, Percentage: 100},
+		},
+	})
+
+	return depl, location
+}
+
+// broadcast keeps publishing the event until the condition is met, as the
+// subscription may not be ready when the first event is published.
+func (s *ListenersSuite) broadcast(event, payload string, condition func() bool) {
+	s.Eventually(func() bool {
+		if condition() {
+			return true
+		}
+
+		s.NoError(rediscache.Service().Broadcast(event, payload))
+		return false
+	}, 15*time.Second, 500*time.Millisecond)
+}
+
+func (s *ListenersSuite) Test_SwapServices() {
+	app := s.MockApp(nil, map[string]any{"DisplayName": "swap-app"})
+	env := s.MockEnv(app, map[string]any{
+		"Data": &buildconf.BuildConf{ServerCmd: "node server.js"},
+	})
+
+	prev, prevLocation := s.mockDeployment(env, "v1")
+	pm := integrations.Filesys().ProcessManager()
+
+	_, err := integrations.Filesys().Invoke(integrations.InvokeArgs{
+		URL:          &url.URL{},
+		Method:       shttp.MethodGet,
+		ARN:          prevLocation,
+		AppID:        app.ID,
+		EnvID:        env.ID,
+		DeploymentID: prev.ID,
+		Command:      "node server.js",
+		IsPublished:  true,
+	})
+
+	s.NoError(err)
+
+	_, location := s.mockDeployment(env, "v2")
+	payload := rediscache.EncodePayload(app.DisplayName, env.Name)
+
+	// The listener pre-warms the service of the published deployment.
+	s.broadcast(rediscache.EventSwapServices, payload, func() bool {
+		return pm.GetService(location) != nil
+	})
+
+	s.Eventually(func() bool {
+		return pm.GetService(prevLocation) == nil
+	}, 15*time.Second, 100*time.Millisecond)
+
+	pm.KillEnv(env.ID)
+}
+
+func (s *ListenersSuite) Test_HandleWorkers() {
+	app := s.MockApp(nil, map[string]any{"DisplayName": "workers-app"})
+	env := s.MockEnv(app, map[string]any{
+		"Data": &buildconf.BuildConf{
+			ServerCmd: "node server.js",
+			Workers:   buildconf.Workers{"consumer": "node worker.js"},
+		},
+	})
+
+	_, _ = s.mockDeployment(env, "workers")
+	pm := integrations.Filesys().ProcessManager()
+
+	payload := func(action string) string {
+		return rediscache.EncodePayload(action, env.ID.String(), app.DisplayName, env.Name, "consumer")
+	}
+
+	s.broadcast(rediscache.EventWorkers, payload(integrations.WorkerActionStart), func() bool {
+		return len(pm.Workers(env.ID)) == 1
+	})
+
+	pid := pm.Workers(env.ID)[0].Pid()
+
+	s.broadcast(rediscache.EventWorkers, payload(integrations.WorkerActionRestart), func() bool {
+		workers := pm.Workers(env.ID)
+		return len(workers) == 1 && workers[0].Pid() != pid
+	})
+
+	s.broadcast(rediscache.EventWorkers, payload(integrations.WorkerActionStop), func() bool {
+		return len(pm.Workers(env.ID)) == 0
+	})
+}
+
+func TestListenersSuite(t *testing.T) {
+	suite.Run(t, &ListenersSuite{})
+}
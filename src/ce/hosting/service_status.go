@@ -0,0 +1,96 @@
+package hosting
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+)
+
+// serviceStatuses queues the status changes of the process manager services. The
+// changes are persisted in order by a single goroutine, see saveServiceStatuses.
+var serviceStatuses = make(chan integrations.ServiceStatus, 1024)
+
+// serviceStatusChanged queues the status of a process manager service so that it can be
+// persisted without blocking the process manager. Changes are dropped when the queue is full.
+func serviceStatusChanged(status integrations.ServiceStatus) {
+	select {
+	case serviceStatuses <- status:
+	default:
+		slog.Errorf("service status queue is full, dropping status of %s", status.ARN)
+	}
+}
+
+// saveServiceStatuses persists the queued service statuses so that they can be retrieved
+// through the api, and notifies the app when a service is crash looping.
+func saveServiceStatuses() {
+	ctx := context.Background()
+
+	for status := range serviceStatuses {
+		if err := integrations.SaveServiceStatus(ctx, status); err != nil {
+			slog.Errorf("error while saving service status: %v", err)
+		}
+
+		if status.State == integrations.ServiceStateCrashLoop {
+			dispatchCrashLoopWebhooks(ctx, status)
+		}
+	}
+}
+
+// serviceUsageInterval is the duration between two resource usage reports.
+var serviceUsageInterval = 30 * time.Second
+
+// reportServiceUsage periodically stores the resource usage of the services that are
+// running on this instance, so that administrators can inspect it through the api.
+func reportServiceUsage() {
+	instance, err := os.Hostname()
+
+	if err != nil {
+		slog.Errorf("cannot determine hostname for service usage reports: %v", err)
+		return
+	}
+
+	pm := integrations.Filesys().ProcessManager()
+	ticker := time.NewTicker(serviceUsageInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := integrations.SaveServiceUsage(context.Background(), instance, pm.Statuses()); err != nil {
+			slog.Errorf("error while saving service usage: %v", err)
+		}
+	}
+}
+
+// dispatchCrashLoopWebhooks dispatches the outbound webhooks of the app that are
+// triggered when a service is crash looping.
+func dispatchCrashLoopWebhooks(ctx context.Context, status integrations.ServiceStatus) {
+	env, err := buildconf.NewStore().EnvironmentByID(ctx, status.EnvID)
+
+	if err != nil || env == nil {
+		if err != nil {
+			slog.Errorf("error while fetching environment for crash loop webhooks: %v", err)
+		}
+
+		return
+	}
+
+	cnf := admin.MustConfig()
+
+	for _, wh := range app.NewStore().OutboundWebhooks(ctx, status.AppID) {
+		if wh.TriggerOnServiceCrashLoop() {
+			wh.Dispatch(app.OutboundWebhookSettings{
+				AppID:                  status.AppID,
+				DeploymentID:           status.DeploymentID,
+				DeploymentStatus:       integrations.ServiceStateCrashLoop,
+				DeploymentError:        status.LastError,
+				EnvironmentName:        env.Name,
+				DeploymentLogsEndpoint: cnf.RuntimeLogsURL(status.AppID, status.EnvID, status.DeploymentID),
+			})
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package hosting
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/appconf"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+)
+
+// swapServices pre-warms the services of the deployment that was published to the
+// environment. The payload encodes the display name of the app and the environment name.
+func swapServices(ctx context.Context, payload ...string) {
+	payload = rediscache.DecodePayload(payload...)
+
+	if len(payload) < 2 {
+		return
+	}
+
+	client, ok := integrations.Client().(*integrations.FilesysClient)
+
+	// Server commands are only supported by the process manager.
+	if !ok {
+		return
+	}
+
+	configs, err := appconf.NewStore().Configs(ctx, appconf.ConfigFilters{
+		DisplayName: payload[0],
+		EnvName:     payload[1],
+	})
+
+	if err != nil {
+		slog.Errorf("error while fetching config for service swap: %v", err)
+		return
+	}
+
+	for _, cnf := range configs {
+		if cnf.ServerCmd == "" || cnf.Percentage < 100 {
+			continue
+		}
+
+		hostName := serviceHostName(cnf, payload[0], payload[1])
+
+		for _, arn := range []string{cnf.FunctionLocation, cnf.APILocation} {
+			if arn == "" {
+				continue
+			}
+
+			args := serviceArgs(cnf, hostName)
+			args.ARN = arn
+
+			if err := client.Swap(args); err != nil {
+				slog.Errorf("error while pre-warming service %s: %v", arn, err)
+			}
+		}
+	}
+
+	if _, err := syncEnvWorkers(ctx, client, payload[0], payload[1]); err != nil {
+		slog.Errorf("error while synchronizing workers after service swap: %v", err)
+	}
+}
+
+// serviceHostName returns the host name that is passed to the services of the configuration.
+func serviceHostName(cnf *appconf.Config, displayName, envName string) string {
+	if len(cnf.Domains) > 0 {
+		return cnf.Domains[0]
+	}
+
+	if u, err := url.Parse(admin.MustConfig().PreviewURL(displayName, envName)); err == nil {
+		return u.Host
+	}
+
+	return ""
+}
@@ -0,0 +1,201 @@
+package hosting
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/appconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+)
+
+// workerSyncInterval is the duration between two synchronizations of the workers.
+// It has to be shorter than integrations.WorkerClaimTTL so that claims are renewed.
+var workerSyncInterval = time.Minute
+
+// workerInstance returns the name of this instance, which is used to claim workers.
+func workerInstance() string {
+	instance, err := os.Hostname()
+
+	if err != nil {
+		slog.Errorf("cannot determine hostname for workers: %v", err)
+	}
+
+	return instance
+}
+
+// syncWorkersPeriodically starts the workers of the published deployments and keeps
+// them running. Workers of environments that are claimed by other instances, or that
+// no longer have workers, are stopped.
+func syncWorkersPeriodically() {
+	client, ok := integrations.Client().(*integrations.FilesysClient)
+
+	// Workers are only supported by the process manager.
+	if !ok {
+		return
+	}
+
+	syncWorkers(context.Background(), client)
+
+	ticker := time.NewTicker(workerSyncInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		syncWorkers(context.Background(), client)
+	}
+}
+
+// syncWorkers synchronizes the workers of all environments.
+func syncWorkers(ctx context.Context, client *integrations.FilesysClient) {
+	envs, err := appconf.NewStore().WorkerEnvs(ctx)
+
+	if err != nil {
+		slog.Errorf("error while fetching worker environments: %v", err)
+		return
+	}
+
+	keep := map[types.ID]bool{}
+
+	for _, env := range envs {
+		owned, err := syncEnvWorkers(ctx, client, env.DisplayName, env.EnvName)
+
+		// Running workers are kept when the ownership cannot be determined,
+		// they are synchronized again on the next tick.
+		if err != nil {
+			slog.Errorf("error while synchronizing workers for env id=%d: %v", env.EnvID, err)
+		}
+
+		if owned || err != nil {
+			keep[env.EnvID] = true
+		}
+	}
+
+	pm := client.ProcessManager()
+
+	for _, envID := range pm.WorkerEnvs() {
+		if keep[envID] {
+			continue
+		}
+
+		pm.StopWorkers(envID, "")
+
+		if err := integrations.ReleaseWorkers(ctx, envID, workerInstance()); err != nil {
+			slog.Errorf("error while releasing workers for env id=%d: %v", envID, err)
+		}
+	}
+}
+
+// syncEnvWorkers starts the workers of the published deployment of the environment,
+// unless they were stopped manually or exited per their restart policy, and stops the workers of previous deployments
+// or of removed process types. It returns false when the environment has no workers
+// or when its workers are run by another instance. When an error is returned, the
+// running workers are left untouched.
+func syncEnvWorkers(ctx context.Context, client *integrations.FilesysClient, displayName, envName string) (bool, error) {
+	configs, err := appconf.NewStore().Configs(ctx, appconf.ConfigFilters{
+		DisplayName: displayName,
+		EnvName:     envName,
+	})
+
+	if err != nil {
+		return false, err
+	}
+
+	var cnf *appconf.Config
+
+	for _, c := range configs {
+		if c.ServerCmd != "" && c.Percentage >= 100 && len(c.Workers) > 0 {
+			cnf = c
+			break
+		}
+	}
+
+	if cnf == nil {
+		return false, nil
+	}
+
+	claimed, err := integrations.ClaimWorkers(ctx, cnf.EnvID, workerInstance())
+
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	stopped, err := integrations.StoppedWorkers(ctx, cnf.EnvID)
+
+	if err != nil {
+		return true, err
+	}
+
+	location := utils.GetString(cnf.FunctionLocation, cnf.APILocation)
+	hostName := serviceHostName(cnf, displayName, envName)
+
+	for _, name := range buildconf.Workers(cnf.Workers).Names() {
+		if stopped[name] || location == "" {
+			continue
+		}
+
+		args := serviceArgs(cnf, hostName)
+		args.ARN = location
+		args.Command = cnf.Workers[name]
+		args.Worker = name
+
+		if err := client.StartWorker(args); err != nil {
+			slog.Errorf("error while starting worker %s for env id=%d: %v", name, cnf.EnvID, err)
+		}
+	}
+
+	// Stop the workers of previous deployments once the new ones are started.
+	for _, worker := range client.ProcessManager().Workers(cnf.EnvID) {
+		name := worker.Name()
+
+		if worker.DeploymentID() != cnf.DeploymentID || cnf.Workers[name] == "" || stopped[name] {
+			worker.Kill()
+		}
+	}
+
+	return true, nil
+}
+
+// handleWorkers starts, stops or restarts the workers of an environment. The payload encodes
+// the action, the environment id, the display name of the app, the environment name and
+// optionally the name of the worker. When the name is empty, all workers are affected.
+func handleWorkers(ctx context.Context, payload ...string) {
+	payload = rediscache.DecodePayload(payload...)
+
+	if len(payload) < 4 {
+		return
+	}
+
+	client, ok := integrations.Client().(*integrations.FilesysClient)
+
+	if !ok {
+		return
+	}
+
+	action, envID, displayName, envName := payload[0], utils.StringToID(payload[1]), payload[2], payload[3]
+	name := ""
+
+	if len(payload) > 4 {
+		name = payload[4]
+	}
+
+	switch action {
+	case integrations.WorkerActionStop:
+		client.ProcessManager().StopWorkers(envID, name)
+		return
+	case integrations.WorkerActionRestart:
+		client.ProcessManager().StopWorkers(envID, name)
+	case integrations.WorkerActionStart:
+		// The workers are started below.
+	default:
+		return
+	}
+
+	if _, err := syncEnvWorkers(ctx, client, displayName, envName); err != nil {
+		slog.Errorf("error while starting workers for env id=%d: %v", envID, err)
+	}
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/redirects"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/file"
@@ -67,6 +68,7 @@ type Bundler struct {
 	headersFile   string   // Relative path to the headers file (from working dir)
 	redirectsFile string   // Relative path to the redirects file (from working dir)
 	apiFolder     string   // Relative path to the api dir (from working dir)
+	isPython      bool     // Whether the virtual environment needs to be bundled with the server
 	packageJson   *PackageJson
 	reporter      *ReporterModel
 }
@@ -214,6 +216,29 @@ func (a *Artifacts) CDNFiles() []deploy.CDNFile {
 	return files
 }
 
+// ClientBlobs returns the client files that are going to be stored by their content
+// hash. The hash is derived from the etag that is included in the manifest.
+func (a *Artifacts) ClientBlobs(files []deploy.CDNFile) []integrations.Blob {
+	var blobs []integrations.Blob
+
+	if a == nil {
+		return blobs
+	}
+
+	for _, f := range files {
+		for _, dir := range a.ClientDirs {
+			fullPath := path.Join(a.workDir, dir, f.Name)
+
+			if file.Exists(fullPath) {
+				blobs = append(blobs, integrations.NewBlob(fullPath, f.Headers["etag"]))
+				break
+			}
+		}
+	}
+
+	return blobs
+}
+
 func findDistDir(opts RunnerOpts) string {
 	if opts.Build.DistFolder != "" {
 		return opts.Build.DistFolder
@@ -331,6 +356,7 @@ func NewBundler(opts RunnerOpts) BundlerInterface {
 		redirectsFile: opts.Build.RedirectsFile,
 		serverCmd:     opts.Build.ServerCmd,
 		apiFolder:     opts.Build.APIFolder,
+		isPython:      opts.Repo.IsPython,
 		packageJson:   opts.Repo.PackageJson,
 		reporter:      opts.Reporter,
 	}
@@ -452,6 +478,12 @@ func (b Bundler) bundleServerSide() ([]string, string, error) {
 
 	// Handle bundling the whole folder case (go, python, ruby, etc...)
 	if len(b.serverDirs) == 1 && b.serverDirs[0] == "" {
+		if b.isPython {
+			if _, err := b.bundlePythonVenv(); err != nil {
+				return nil, "", err
+			}
+		}
+
 		return []string{"."}, functionHandler, nil
 	}
 
@@ -517,9 +549,73 @@ func (b Bundler) bundleServerSide() ([]string, string, error) {
 		serverDirs = append(serverDirs, trim(dir))
 	}
 
+	if b.isPython {
+		venv, err := b.bundlePythonVenv()
+
+		if err != nil {
+			return nil, "", err
+		}
+
+		serverDirs = append(serverDirs, venv...)
+	}
+
 	return serverDirs, functionHandler, nil
 }
 
+// bundlePythonVenv prepares the virtual environment to be shipped with the server files.
+// Console scripts inside `.venv/bin` (gunicorn, uvicorn, flask...) point to the interpreter
+// with an absolute path that does not exist once the bundle is extracted. We rewrite these
+// shebangs so that python is resolved through the PATH, which the process manager prefixes
+// with the virtual environment. The home key of pyvenv.cfg refers to the interpreter of the
+// runner as well, the process manager points it to the interpreter of the hosting instance.
+func (b Bundler) bundlePythonVenv() ([]string, error) {
+	venvDir := path.Join(b.workDir, PythonVenvFolder)
+	binDir := path.Join(venvDir, "bin")
+
+	if !file.Exists(binDir) {
+		return nil, nil
+	}
+
+	b.reporter.AddStep("bundling python virtual environment")
+
+	entries, err := os.ReadDir(binDir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		script := path.Join(binDir, entry.Name())
+		content, err := os.ReadFile(script)
+
+		if err != nil {
+			return nil, err
+		}
+
+		shebang, rest, _ := strings.Cut(string(content), "\n")
+
+		if !strings.HasPrefix(shebang, "#!") || !strings.Contains(shebang, venvDir) {
+			continue
+		}
+
+		info, err := entry.Info()
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := os.WriteFile(script, []byte("#!/usr/bin/env python\n"+rest), info.Mode()); err != nil {
+			return nil, err
+		}
+	}
+
+	return []string{PythonVenvFolder}, nil
+}
+
 // bundleApiFolder will look at <relative-dist>/api folder
 // and return the necessary information when the folder is found. This function
 // also bundles dependencies by looking at the top-level api dir.
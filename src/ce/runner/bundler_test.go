@@ -13,6 +13,7 @@ import (
 
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/ce/runner"
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/sys"
 	"github.com/stormkit-io/stormkit-io/src/mocks"
 	"github.com/stretchr/testify/suite"
@@ -180,6 +181,46 @@ func (s *BundlerSuite) Test_Bundle_AllRepo() {
 	s.Equal([]string{"."}, artifacts.ServerDirs)
 }
 
+func (s *BundlerSuite) Test_Bundle_PythonServer() {
+	venvBin := path.Join(s.config.Repo.Dir, ".venv", "bin")
+	uvicorn := path.Join(venvBin, "uvicorn")
+
+	// Sample fastapi application
+	s.NoError(os.MkdirAll(path.Join(s.config.Repo.Dir, "app"), 0755))
+	s.NoError(os.MkdirAll(venvBin, 0755))
+	s.NoError(os.WriteFile(path.Join(s.config.Repo.Dir, "requirements.txt"), []byte("fastapi\nuvicorn\n"), 0644))
+	s.NoError(os.WriteFile(path.Join(s.config.Repo.Dir, "app", "main.py"), []byte("from fastapi import FastAPI\napp = FastAPI()\n"), 0644))
+	s.NoError(os.WriteFile(uvicorn, []byte(fmt.Sprintf("#!%s/python\nfrom uvicorn.main import main\n", venvBin)), 0755))
+	s.NoError(os.Symlink("/usr/bin/python3", path.Join(venvBin, "python")))
+
+	s.config.Repo.IsPython = true
+	s.config.Build.ServerCmd = "uvicorn app.main:app"
+	s.config.Build.ServerFolder = "app"
+
+	bundler := runner.NewBundler(s.config)
+	artifacts, err := bundler.Bundle(context.Background())
+
+	s.NoError(err)
+	s.Empty(artifacts.ClientDirs)
+	s.Equal(".:server", artifacts.FunctionHandler)
+	s.Equal([]string{"app", ".venv"}, artifacts.ServerDirs)
+	s.Contains(s.config.Reporter.Logs(), "[sk-step] bundling python virtual environment")
+
+	// Shebangs should no longer point to the absolute path of the build folder
+	content, err := os.ReadFile(uvicorn)
+	s.NoError(err)
+	s.Equal("#!/usr/bin/env python\nfrom uvicorn.main import main\n", string(content))
+
+	stat, err := os.Stat(uvicorn)
+	s.NoError(err)
+	s.Equal(os.FileMode(0755), stat.Mode().Perm())
+
+	// Symlinks are left untouched
+	target, err := os.Readlink(path.Join(venvBin, "python"))
+	s.NoError(err)
+	s.Equal("/usr/bin/python3", target)
+}
+
 func (s *BundlerSuite) Test_Bundle_NextServer_AlternativeSyntax() {
 	s.config.Build.ServerCmd = "npm run start"
 	s.config.Build.ServerFolder = ".next"
@@ -512,6 +553,14 @@ func (s *BundlerSuite) Test_CDNFiles() {
 			},
 		},
 	}, cdnFiles)
+
+	clientDir := path.Join(s.config.Repo.Dir, "dist", "client")
+
+	s.Equal([]integrations.Blob{
+		{Hash: "fbb969117edfa916b86dfb67fd11decf1e336df0", FullPath: path.Join(clientDir, "templates", "index.html")},
+		{Hash: "3d0b9a7a7d1882824e95fcc401aedf12d9fc7106", FullPath: path.Join(clientDir, "templates", "index2.html")},
+		{Hash: "9f879f26935916f6950366bc0bbd977effaded83", FullPath: path.Join(clientDir, "templates", "my.jpg")},
+	}, artifacts.ClientBlobs(cdnFiles))
 }
 
 func (s *BundlerSuite) Test_RegexpPattern() {
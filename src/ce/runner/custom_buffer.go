@@ -7,16 +7,23 @@ import (
 	"sync"
 
 	"github.com/stormkit-io/stormkit-io/src/lib/config"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/redact"
 )
 
 const ansi = "[\u001B\u009B][[\\]()#;?]*(?:(?:(?:[a-zA-Z\\d]*(?:;[a-zA-Z\\d]*)*)?\u0007)|(?:(?:\\d{1,4}(?:;\\d{0,4})*)?[\\dA-PRZcf-ntqry=><~]))"
 
+// maxPendingBytes is the maximum size of an incomplete line that is kept until
+// the rest of the line is written.
+const maxPendingBytes = 64 * 1024
+
 type CustomBuffer struct {
 	output          []byte
+	pending         []byte // The incomplete last line, written once the line is complete
 	readIndex       int
 	ansiRegexp      *regexp.Regexp
 	mu              sync.Mutex
 	isStormkitCloud bool
+	redactor        *redact.Redactor
 }
 
 func NewCustomBuffer() *CustomBuffer {
@@ -27,11 +34,51 @@ func NewCustomBuffer() *CustomBuffer {
 	}
 }
 
+// Write processes the complete lines of p. The incomplete last line is kept until
+// the rest of it is written or Flush is called, so that secrets which are split
+// across several writes are still redacted.
 func (cb *CustomBuffer) Write(p []byte) (n int, err error) {
 	cb.mu.Lock()
 	defer cb.mu.Unlock()
 
+	cb.pending = append(cb.pending, p...)
+
+	if i := bytes.LastIndexByte(cb.pending, '\n'); i > -1 {
+		cb.write(cb.pending[:i+1])
+		cb.pending = append([]byte{}, cb.pending[i+1:]...)
+	}
+
+	// Only the text after the last carriage return is displayed. A trailing carriage
+	// return is kept, as it may be the first half of a CRLF split across writes.
+	if i := bytes.LastIndexByte(cb.pending, '\r'); i > -1 && i < len(cb.pending)-1 {
+		cb.pending = append([]byte{}, cb.pending[i+1:]...)
+	}
+
+	if len(cb.pending) > maxPendingBytes {
+		cb.write(cb.pending)
+		cb.pending = nil
+	}
+
+	// We always need to return the length of `p`:
+	// See https://www.reddit.com/r/golang/comments/2xufjb/cmdrun_returning_short_write_as_error_why_does
+	return len(p), nil
+}
+
+// Flush writes the incomplete last line.
+func (cb *CustomBuffer) Flush() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if len(cb.pending) > 0 {
+		cb.write(cb.pending)
+		cb.pending = nil
+	}
+}
+
+// write cleans the given lines and appends them to the output.
+func (cb *CustomBuffer) write(p []byte) {
 	input := cb.ansiRegexp.ReplaceAll(p, []byte(""))
+	input = cb.redactor.Bytes(input)
 
 	if cb.isStormkitCloud {
 		input = bytes.Replace(input, []byte("/home/runner/work/deployer-service/deployer-service"), []byte("/home/app"), -1)
@@ -41,6 +88,8 @@ func (cb *CustomBuffer) Write(p []byte) (n int, err error) {
 
 	// Clean carriage returns
 	for _, line := range lines {
+		line = bytes.TrimSuffix(line, []byte("\r"))
+
 		if crIndex := bytes.LastIndex(line, []byte("\r")); crIndex > -1 {
 			line = line[crIndex+1:]
 		}
@@ -53,10 +102,14 @@ func (cb *CustomBuffer) Write(p []byte) (n int, err error) {
 		line = bytes.ReplaceAll(line, []byte("\x00"), []byte("")) // Remove null bytes
 		cb.output = append(cb.output, line...)
 	}
+}
 
-	// We always need to return the length of `p`:
-	// See https://www.reddit.com/r/golang/comments/2xufjb/cmdrun_returning_short_write_as_error_why_does
-	return len(p), nil
+// SetRedactor sets the redactor that masks the secrets before they are written.
+func (cb *CustomBuffer) SetRedactor(r *redact.Redactor) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.redactor = r
 }
 
 func (cb *CustomBuffer) Read(p []byte) (int, error) {
@@ -1,9 +1,12 @@
 package runner_test
 
 import (
+	"encoding/base64"
+	"io"
 	"testing"
 
 	"github.com/stormkit-io/stormkit-io/src/ce/runner"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/redact"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -46,9 +49,16 @@ func (s *CustomBufferSuite) Test_Read_Write() {
 	s.Nil(err)
 	s.Equal(11, written)
 
-	// Read hello world
+	// Incomplete lines are written once they are flushed
 	buffer := make([]byte, 11)
 	read, err := cb.Read(buffer)
+	s.Equal(io.EOF, err)
+	s.Equal(0, read)
+
+	cb.Flush()
+
+	// Read hello world
+	read, err = cb.Read(buffer)
 	s.Nil(err)
 	s.Equal(11, read)
 	s.Equal("Hello World", string(buffer))
@@ -61,6 +71,7 @@ func (s *CustomBufferSuite) Test_GitLogs() {
 	written, err := cb.Write([]byte(gitLogs))
 	s.Nil(err)
 	s.Equal(len(gitLogs), written)
+	cb.Flush()
 
 	// Read the whole lorem ipsum
 	buffer := make([]byte, len(expectedGitLogs))
@@ -78,6 +89,7 @@ func (s *CustomBufferSuite) Test_Write_CarriageReturn() {
 	written, err := cb.Write(text)
 	s.Nil(err)
 	s.Equal(len(text), written)
+	cb.Flush()
 
 	// Read all
 	expected := "Let's see\nMy World"
@@ -89,6 +101,58 @@ func (s *CustomBufferSuite) Test_Write_CarriageReturn() {
 	s.Equal(expected, string(buffer))
 }
 
+func (s *CustomBufferSuite) Test_Write_CRLFAcrossWrites() {
+	cb := runner.NewCustomBuffer()
+
+	for _, chunk := range []string{"foo\r", "\nbar\r\n", "progress\rdone\r", "\n"} {
+		_, err := cb.Write([]byte(chunk))
+		s.Nil(err)
+	}
+
+	expected := "foo\nbar\ndone\n"
+
+	buffer := make([]byte, 100)
+	read, err := cb.Read(buffer)
+	s.Nil(err)
+	s.Equal(expected, string(buffer[:read]))
+}
+
+func (s *CustomBufferSuite) Test_Write_Redacted() {
+	cb := runner.NewCustomBuffer()
+	cb.SetRedactor(redact.New([]string{"my-api-token"}))
+
+	text := []byte("echo $API_TOKEN\nmy-api-token\nencoded: " + base64.StdEncoding.EncodeToString([]byte("my-api-token")))
+
+	written, err := cb.Write(text)
+	s.Nil(err)
+	s.Equal(len(text), written)
+	cb.Flush()
+
+	expected := "echo $API_TOKEN\n***\nencoded: ***\n"
+
+	buffer := make([]byte, 100)
+	read, err := cb.Read(buffer)
+	s.Nil(err)
+	s.Equal(expected, string(buffer[:read]))
+}
+
+func (s *CustomBufferSuite) Test_Write_RedactedAcrossWrites() {
+	cb := runner.NewCustomBuffer()
+	cb.SetRedactor(redact.New([]string{"my-api-token"}))
+
+	for _, chunk := range []string{"token: my-a", "pi-to", "ken\nsecond ", "line\n"} {
+		_, err := cb.Write([]byte(chunk))
+		s.Nil(err)
+	}
+
+	expected := "token: ***\nsecond line\n"
+
+	buffer := make([]byte, 100)
+	read, err := cb.Read(buffer)
+	s.Nil(err)
+	s.Equal(expected, string(buffer[:read]))
+}
+
 func TestCustomBufferSuite(t *testing.T) {
 	suite.Run(t, &CustomBufferSuite{})
 }
@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"slices"
 	"strings"
 
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/mise"
@@ -70,6 +71,8 @@ type Installer struct {
 	isBun              bool
 	isYarn             bool
 	isPnpm             bool
+	isPython           bool
+	isUv               bool
 	workDir            string
 	buildCmd           string
 	hasPackageLockFile bool
@@ -96,6 +99,8 @@ func NewInstaller(opts RunnerOpts) InstallerInterface {
 		isPnpm:             opts.Repo.IsPnpm,
 		isYarn:             opts.Repo.IsYarn,
 		isBun:              opts.Repo.IsBun,
+		isPython:           opts.Repo.IsPython,
+		isUv:               opts.Repo.IsUv,
 		runtime:            opts.Repo.Runtime,
 	}
 
@@ -118,6 +123,8 @@ func (p Installer) RuntimeVersion(ctx context.Context) error {
 		} else {
 			runtimeCmd = "node"
 		}
+	} else if p.isPython {
+		runtimeCmd = "python"
 	} else if p.hasGoMod() {
 		runtimeCmd = "go"
 		versionArg = "version"
@@ -170,26 +177,35 @@ func (p *Installer) InstallRuntimeDependencies(ctx context.Context) ([]string, e
 		return nil, err
 	}
 
-	// If not found, let's install it manually
-	found := false
+	required := []string{p.runtime}
 
-	for _, rt := range runtimes {
-		pieces := strings.Split(rt, "@")
-
-		if pieces[0] == p.runtime {
-			found = true
-			break
-		}
+	// uv is not shipped with python, so we install it alongside the runtime
+	if p.isUv {
+		required = append(required, "uv")
 	}
 
-	if !found && p.runtime != "" {
-		opts.Runtime = p.runtime
+	for _, runtime := range required {
+		// If not found, let's install it manually
+		found := false
+
+		for _, rt := range runtimes {
+			pieces := strings.Split(rt, "@")
 
-		if err := m.InstallLocal(ctx, opts); err != nil {
-			return nil, err
+			if pieces[0] == runtime {
+				found = true
+				break
+			}
 		}
 
-		runtimes = append(runtimes, p.runtime)
+		if !found && runtime != "" {
+			opts.Runtime = runtime
+
+			if err := m.InstallLocal(ctx, opts); err != nil {
+				return nil, err
+			}
+
+			runtimes = append(runtimes, runtime)
+		}
 	}
 
 	// Make sure to update the PATH
@@ -206,6 +222,10 @@ func (p *Installer) Install(ctx context.Context) error {
 		return p.installCustom(ctx)
 	}
 
+	if p.isPython {
+		return p.installPython(ctx)
+	}
+
 	// noop
 	if p.packageJson == nil {
 		return nil
@@ -268,6 +288,58 @@ func (p *Installer) installPnpm(ctx context.Context) error {
 	}).Run()
 }
 
+// installPython creates a virtual environment in the working directory and
+// installs the dependencies into it. Projects with a uv.lock file are installed
+// through uv, otherwise pip is used with requirements.txt or pyproject.toml.
+func (p *Installer) installPython(ctx context.Context) error {
+	file := p.reporter.File()
+
+	if p.isUv {
+		p.reporter.AddStep("uv sync")
+
+		return sys.Command(ctx, sys.CommandOpts{
+			Name:   "uv",
+			Args:   []string{"sync", "--frozen", "--no-dev"},
+			Dir:    p.workDir,
+			Env:    append(slices.Clone(p.envVars), fmt.Sprintf("UV_PROJECT_ENVIRONMENT=%s", PythonVenvFolder)),
+			Stdout: file,
+			Stderr: file,
+		}).Run()
+	}
+
+	p.reporter.AddStep(fmt.Sprintf("python -m venv %s", PythonVenvFolder))
+
+	err := sys.Command(ctx, sys.CommandOpts{
+		Name:   "python",
+		Args:   []string{"-m", "venv", PythonVenvFolder},
+		Dir:    p.workDir,
+		Env:    p.envVars,
+		Stdout: file,
+		Stderr: file,
+	}).Run()
+
+	if err != nil {
+		return err
+	}
+
+	target := []string{"."}
+
+	if _, err := os.Stat(path.Join(p.workDir, "requirements.txt")); err == nil {
+		target = []string{"-r", "requirements.txt"}
+	}
+
+	p.reporter.AddStep(fmt.Sprintf("pip install %s", strings.Join(target, " ")))
+
+	return sys.Command(ctx, sys.CommandOpts{
+		Name:   path.Join(PythonVenvFolder, "bin", "python"),
+		Args:   append([]string{"-m", "pip", "install", "--disable-pip-version-check"}, target...),
+		Dir:    p.workDir,
+		Env:    p.envVars,
+		Stdout: file,
+		Stderr: file,
+	}).Run()
+}
+
 type Version struct {
 	Major string
 }
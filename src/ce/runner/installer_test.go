@@ -287,6 +287,103 @@ func (s *InstallerSuite) TestInstall_Custom() {
 	}
 }
 
+func (s *InstallerSuite) Test_Install_Python_Pip() {
+	// Sample flask application
+	s.NoError(os.WriteFile(path.Join(s.config.Repo.Dir, "requirements.txt"), []byte("flask==3.0.3\ngunicorn==22.0.0\n"), 0664))
+	s.NoError(os.WriteFile(path.Join(s.config.Repo.Dir, "app.py"), []byte("from flask import Flask\napp = Flask(__name__)\n"), 0664))
+
+	s.config.Repo.IsPython = true
+
+	p := runner.NewInstaller(s.config)
+
+	s.mockCmd.On("SetOpts", sys.CommandOpts{
+		Name:   "python",
+		Args:   []string{"-m", "venv", ".venv"},
+		Dir:    s.config.Repo.Dir,
+		Env:    s.config.Build.EnvVarsRaw,
+		Stdout: s.config.Reporter.File(),
+		Stderr: s.config.Reporter.File(),
+	}).Return(s.mockCmd).Once()
+
+	s.mockCmd.On("Run").Return(nil, nil).Once()
+
+	s.mockCmd.On("SetOpts", sys.CommandOpts{
+		Name:   ".venv/bin/python",
+		Args:   []string{"-m", "pip", "install", "--disable-pip-version-check", "-r", "requirements.txt"},
+		Dir:    s.config.Repo.Dir,
+		Env:    s.config.Build.EnvVarsRaw,
+		Stdout: s.config.Reporter.File(),
+		Stderr: s.config.Reporter.File(),
+	}).Return(s.mockCmd).Once()
+
+	s.mockCmd.On("Run").Return(nil, nil).Once()
+
+	s.NoError(p.Install(context.Background()))
+
+	logs := s.config.Reporter.Logs()
+
+	s.Contains(logs, "[sk-step] python -m venv .venv")
+	s.Contains(logs, "[sk-step] pip install -r requirements.txt")
+}
+
+func (s *InstallerSuite) Test_Install_Python_PyProject() {
+	// Sample fastapi application without requirements.txt
+	s.NoError(os.WriteFile(path.Join(s.config.Repo.Dir, "pyproject.toml"), []byte("[project]\nname = \"app\"\ndependencies = [\"fastapi\", \"uvicorn\"]\n"), 0664))
+
+	s.config.Repo.IsPython = true
+
+	p := runner.NewInstaller(s.config)
+
+	s.mockCmd.On("SetOpts", sys.CommandOpts{
+		Name:   "python",
+		Args:   []string{"-m", "venv", ".venv"},
+		Dir:    s.config.Repo.Dir,
+		Env:    s.config.Build.EnvVarsRaw,
+		Stdout: s.config.Reporter.File(),
+		Stderr: s.config.Reporter.File(),
+	}).Return(s.mockCmd).Once()
+
+	s.mockCmd.On("Run").Return(nil, nil).Once()
+
+	s.mockCmd.On("SetOpts", sys.CommandOpts{
+		Name:   ".venv/bin/python",
+		Args:   []string{"-m", "pip", "install", "--disable-pip-version-check", "."},
+		Dir:    s.config.Repo.Dir,
+		Env:    s.config.Build.EnvVarsRaw,
+		Stdout: s.config.Reporter.File(),
+		Stderr: s.config.Reporter.File(),
+	}).Return(s.mockCmd).Once()
+
+	s.mockCmd.On("Run").Return(nil, nil).Once()
+
+	s.NoError(p.Install(context.Background()))
+	s.Contains(s.config.Reporter.Logs(), "[sk-step] pip install .")
+}
+
+func (s *InstallerSuite) Test_Install_Python_Uv() {
+	s.NoError(os.WriteFile(path.Join(s.config.Repo.Dir, "pyproject.toml"), []byte("[project]\nname = \"app\"\n"), 0664))
+	s.NoError(os.WriteFile(path.Join(s.config.Repo.Dir, "uv.lock"), []byte(""), 0664))
+
+	s.config.Repo.IsPython = true
+	s.config.Repo.IsUv = true
+
+	p := runner.NewInstaller(s.config)
+
+	s.mockCmd.On("SetOpts", sys.CommandOpts{
+		Name:   "uv",
+		Args:   []string{"sync", "--frozen", "--no-dev"},
+		Dir:    s.config.Repo.Dir,
+		Env:    append(s.config.Build.EnvVarsRaw, "UV_PROJECT_ENVIRONMENT=.venv"),
+		Stdout: s.config.Reporter.File(),
+		Stderr: s.config.Reporter.File(),
+	}).Return(s.mockCmd).Once()
+
+	s.mockCmd.On("Run").Return(nil, nil).Once()
+
+	s.NoError(p.Install(context.Background()))
+	s.Contains(s.config.Reporter.Logs(), "[sk-step] uv sync")
+}
+
 func (s *InstallerSuite) Test_Runtimes() {
 	type Runtime struct {
 		ExpectedMessage       string
@@ -305,6 +402,11 @@ func (s *InstallerSuite) Test_Runtimes() {
 			DependencyFile:        "go.mod",
 			DependencyFileContent: `module test`,
 		},
+		"python3.12": {
+			ExpectedMessage:       "python --version",
+			DependencyFile:        "requirements.txt",
+			DependencyFileContent: `flask`,
+		},
 	}
 
 	for _, expected := range runtimes {
@@ -314,6 +416,8 @@ func (s *InstallerSuite) Test_Runtimes() {
 			s.config.Repo.PackageJson = nil
 		}
 
+		s.config.Repo.IsPython = expected.DependencyFile == "requirements.txt"
+
 		r := runner.NewInstaller(s.config)
 
 		s.mockCmd.On("SetOpts", sys.CommandOpts{
@@ -370,6 +474,30 @@ func (s *InstallerSuite) Test_InstallingRuntimeDeps() {
 	s.Contains(s.config.Reporter.Logs(), "[sk-step] mise install")
 }
 
+func (s *InstallerSuite) Test_InstallingRuntimeDeps_PythonUv() {
+	ctx := context.Background()
+	stdout := s.config.Reporter.File()
+	workDir := s.config.WorkDir
+
+	s.mockMise.On("InstallMise", ctx).Return(nil).Once()
+	s.mockMise.On("InstallLocal", ctx, mise.LocalOpts{Dir: workDir, Stdout: stdout, Stderr: stdout}).Return(nil).Once()
+	s.mockMise.On("ListLocal", ctx, mise.LocalOpts{Dir: workDir}).Return([]string{"python@3.12.4"}, nil).Once()
+
+	// Python is already listed, only uv needs to be installed
+	s.mockMise.On("InstallLocal", ctx, mise.LocalOpts{Dir: workDir, Stdout: stdout, Stderr: stdout, Runtime: "uv"}).Return(nil).Once()
+
+	s.config.Repo.Runtime = "python"
+	s.config.Repo.IsPython = true
+	s.config.Repo.IsUv = true
+
+	p := runner.NewInstaller(s.config)
+
+	installed, err := p.InstallRuntimeDependencies(ctx)
+	s.NoError(err)
+	s.Equal([]string{"python@3.12.4", "uv"}, installed)
+	s.mockMise.AssertExpectations(s.T())
+}
+
 func TestInstallerSuite(t *testing.T) {
 	suite.Run(t, &InstallerSuite{})
 }
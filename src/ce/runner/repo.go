@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path"
+	"slices"
 	"strings"
 
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/sys"
 )
@@ -28,6 +31,9 @@ type Repo struct {
 	accessToken string
 	provider    string
 	branch      string // The branch to checkout
+	tag         string // The tag to checkout, takes precedence over the branch
+	commitSha   string // The exact commit to checkout
+	checkout    *buildconf.GitCheckout
 	workDir     string
 	vars        map[string]string
 	varsRaw     []string
@@ -38,6 +44,12 @@ const REPO_TYPE_GITHUB = "github"
 const REPO_TYPE_GITLAB = "gitlab"
 const REPO_TYPE_BITBUCKET = "bitbucket"
 
+// BitbucketKnownHosts are the host keys of bitbucket.org, which are published at
+// https://bitbucket.org/site/ssh. They are used to verify the server when the
+// repository is cloned with the deploy key of the app.
+var BitbucketKnownHosts = "bitbucket.org ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIIazEu89wgQZ4bqs3d63QSMzYVa0MuJ2e2gKTKqu+UUO\n" +
+	"bitbucket.org ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBPIQmuzMBuKdWeF4+a2sjSSpBK0iqitSQ+5BM9KhpexuGt20JpTVM7u5BDZngncgrqDMbWdxMWWOGtZ9UgbqgZE=\n"
+
 var DefaultRepo RepoInterface
 
 // NewRepo creates a new repo instance from the given address and access token.
@@ -52,6 +64,9 @@ func NewRepo(opts RunnerOpts) RepoInterface {
 		address:     opts.Repo.Address,
 		accessToken: opts.Repo.AccessToken,
 		branch:      opts.Repo.Branch,
+		tag:         opts.Repo.Tag,
+		commitSha:   opts.Repo.CommitSha,
+		checkout:    opts.Repo.Checkout,
 		workDir:     opts.WorkDir,
 		vars:        opts.Build.EnvVars,
 		varsRaw:     opts.Build.EnvVarsRaw,
@@ -77,7 +92,7 @@ func (r Repo) Address() string {
 // Checkout checks out the repository
 func (r Repo) Checkout(ctx context.Context) error {
 	var addr string
-	var ssh string
+	var env []string
 
 	addr = r.address
 
@@ -93,51 +108,174 @@ func (r Repo) Checkout(ctx context.Context) error {
 			// a custom access token.
 			if err != nil {
 				addr = strings.Replace(r.address, "git@bitbucket.org:", fmt.Sprintf("https://x-token-auth:%s@bitbucket.org/", r.accessToken), 1)
-			} else {
-				ssh = fmt.Sprintf(`GIT_SSH_COMMAND="ssh -o UserKnownHostsFile=/dev/null -o StrictHostKeyChecking=no -i %s/id_rsa"`, r.keysDir)
+			} else if env, err = r.sshEnv(); err != nil {
+				return err
+			}
+		} else if creds := r.credentials(); len(creds) > 0 {
+			// Generic git remotes use either the deploy key of the app or https credentials.
+			switch creds[0] {
+			case "git":
+				if err := r.createSSHKeys(); err != nil {
+					return err
+				}
+
+				var err error
+
+				if env, err = r.sshEnv(); err != nil {
+					return err
+				}
+			case "https":
+				username, _ := url.QueryUnescape(creds[1])
+				password, _ := url.QueryUnescape(creds[2])
+
+				if u, err := url.Parse(r.address); err == nil {
+					u.User = url.UserPassword(username, password)
+					addr = u.String()
+				}
 			}
+		} else if strings.HasPrefix(r.address, "https://") {
+			// Self-hosted providers, such as Gitea and Forgejo, accept the oauth2 token as the password.
+			addr = strings.Replace(r.address, "https://", fmt.Sprintf("https://oauth2:%s@", r.accessToken), 1)
 		}
 	}
 
-	r.reporter.AddStep(fmt.Sprintf("checkout %s", r.branch))
+	ref := utils.GetString(r.tag, r.branch)
+
+	r.reporter.AddStep(fmt.Sprintf("checkout %s", ref))
 
 	// Add this system variable
 	r.vars["SK_BRANCH_NAME"] = r.branch
 
+	if r.tag != "" {
+		r.vars["SK_TAG_NAME"] = r.tag
+	}
+
+	args := []string{"clone", addr, "--depth", "1", "--progress", "--single-branch", "--branch", ref}
+
+	// Blobs outside of the sparse paths are not downloaded at all.
+	if r.isSparse() {
+		args = append(args, "--filter=blob:none", "--sparse")
+	}
+
 	// See https://github.com/golang/go/issues/38268#issuecomment-609562062 for the progress flag
-	cmd := sys.Command(
-		ctx,
-		sys.CommandOpts{
-			Name: "git",
-			Args: []string{
-				"clone", addr,
-				"--depth", "1",
-				"--progress", "--single-branch",
-				"--branch", r.branch, r.dir,
-			},
-			Env:    r.varsRaw,
-			Stdout: r.reporter.File(),
-			Stderr: r.reporter.File(),
-		},
-	)
+	if err := r.git(ctx, env, "", append(args, r.dir)...); err != nil {
+		return err
+	}
 
-	if ssh != "" {
-		cmd = sys.Command(ctx, sys.CommandOpts{
-			Name:   "sh",
-			Args:   []string{"-c", fmt.Sprintf("%s %s", ssh, cmd.String())},
-			Env:    r.varsRaw,
-			Stdout: r.reporter.File(),
-			Stderr: r.reporter.File(),
-		})
+	if r.isSparse() {
+		r.reporter.AddStep("sparse checkout")
+
+		if err := r.git(ctx, env, r.dir, append([]string{"sparse-checkout", "set"}, r.checkout.SparsePaths...)...); err != nil {
+			return err
+		}
 	}
 
-	if err := cmd.Run(); err != nil {
+	if err := r.checkoutCommit(ctx, env); err != nil {
 		return err
 	}
 
+	if r.checkout != nil && r.checkout.Submodules {
+		r.reporter.AddStep("submodules")
+
+		args := []string{"submodule", "update", "--init", "--recursive", "--depth", "1"}
+
+		if err := r.git(ctx, append(credentialRewrites(addr), env...), r.dir, args...); err != nil {
+			return err
+		}
+	}
+
+	if r.checkout != nil && r.checkout.LFS {
+		r.reporter.AddStep("git lfs pull")
+
+		args := []string{"lfs", "pull"}
+
+		if len(r.checkout.LFSInclude) > 0 {
+			args = append(args, fmt.Sprintf("--include=%s", strings.Join(r.checkout.LFSInclude, ",")))
+		}
+
+		if err := r.git(ctx, env, r.dir, args...); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// checkoutCommit checks out the requested commit, if any, and verifies
+// that the working tree points to it.
+func (r Repo) checkoutCommit(ctx context.Context, env []string) error {
+	if r.commitSha == "" {
+		return nil
+	}
+
+	// The branch may have moved since the deployment was requested,
+	// in which case we fetch the requested commit explicitly.
+	if !strings.EqualFold(r.HeadSHA(), r.commitSha) {
+		if err := r.git(ctx, env, r.dir, "fetch", "--depth", "1", "origin", r.commitSha); err != nil {
+			return err
+		}
+
+		if err := r.git(ctx, nil, r.dir, "checkout", "--detach", "FETCH_HEAD"); err != nil {
+			return err
+		}
+	}
+
+	if head := r.HeadSHA(); !strings.EqualFold(head, r.commitSha) {
+		return fmt.Errorf("checked out commit %s does not match the requested commit %s", head, r.commitSha)
+	}
+
+	return nil
+}
+
+// isSparse returns true when the working tree is limited to the configured paths.
+func (r Repo) isSparse() bool {
+	return r.checkout != nil && len(r.checkout.SparsePaths) > 0
+}
+
+// credentialRewrites returns the environment variables that configure git to rewrite the
+// urls of the submodules hosted on the same provider, so that they are cloned with the same
+// credentials. The configuration is passed through the environment rather than the command
+// line, so that the credentials are not visible in the process list.
+func credentialRewrites(addr string) []string {
+	u, err := url.Parse(addr)
+
+	if err != nil || u.User == nil {
+		return []string{}
+	}
+
+	authed := fmt.Sprintf("%s://%s@%s/", u.Scheme, u.User.String(), u.Host)
+
+	return []string{
+		"GIT_CONFIG_COUNT=2",
+		fmt.Sprintf("GIT_CONFIG_KEY_0=url.%s.insteadOf", authed),
+		fmt.Sprintf("GIT_CONFIG_VALUE_0=https://%s/", u.Host),
+		fmt.Sprintf("GIT_CONFIG_KEY_1=url.%s.insteadOf", authed),
+		fmt.Sprintf("GIT_CONFIG_VALUE_1=git@%s:", u.Host),
+	}
+}
+
+// git runs a git command in the given directory. The given environment
+// variables are added to the environment of the build.
+func (r Repo) git(ctx context.Context, env []string, dir string, args ...string) error {
+	vars := r.varsRaw
+
+	if len(env) > 0 {
+		vars = append(slices.Clone(r.varsRaw), env...)
+	}
+
+	return sys.Command(
+		ctx,
+		sys.CommandOpts{
+			Name:   "git",
+			Args:   args,
+			Dir:    dir,
+			Env:    vars,
+			Stdout: r.reporter.File(),
+			Stderr: r.reporter.File(),
+		},
+	).Run()
+}
+
 // HeadSHA returns information on the latest commit's SHA.
 func (r Repo) HeadSHA() string {
 	cmd := sys.Command(context.Background(), sys.CommandOpts{
@@ -204,22 +342,47 @@ func (r Repo) IsBitbucket() bool {
 	return r.provider == REPO_TYPE_BITBUCKET
 }
 
-func (r Repo) createSSHKeys() error {
+// credentials decodes the credentials that are provided in the following format:
+// <type>|<public key or username>|<private key or password>|<known hosts>
+// Returns nil when the access token is not in this format.
+func (r Repo) credentials() []string {
 	creds, err := utils.DecodeString(r.accessToken)
 
 	if err != nil {
-		return err
+		return nil
+	}
+
+	pieces := strings.SplitN(string(creds), "|", 4)
+
+	if len(pieces) < 3 {
+		return nil
+	}
+
+	return pieces
+}
+
+// sshEnv returns the environment variable that sets the ssh command used by git. Host keys
+// are always verified: an error is returned when the host keys of the server are not pinned.
+func (r Repo) sshEnv() ([]string, error) {
+	if _, err := os.Stat(path.Join(r.keysDir, "known_hosts")); err != nil {
+		return nil, errors.New("the host keys of the git server are not pinned, set up the git remote again to pin them")
 	}
 
-	pieces := strings.Split(string(creds), "|")
+	return []string{
+		fmt.Sprintf("GIT_SSH_COMMAND=ssh -o IdentitiesOnly=yes -o UserKnownHostsFile=%s/known_hosts -o StrictHostKeyChecking=yes -i %s/id_rsa", r.keysDir, r.keysDir),
+	}, nil
+}
 
-	if len(pieces) < 2 {
+func (r Repo) createSSHKeys() error {
+	pieces := r.credentials()
+
+	if len(pieces) < 3 {
 		return errors.New("invalid access token given")
 	}
 
-	_, publicKey, privateKey := pieces[0], pieces[1], pieces[2]
+	publicKey, privateKey := pieces[1], pieces[2]
 
-	err = os.WriteFile(path.Join(r.keysDir, "id_rsa.pub"), []byte(publicKey), 0644)
+	err := os.WriteFile(path.Join(r.keysDir, "id_rsa.pub"), []byte(publicKey), 0644)
 
 	if err != nil {
 		return err
@@ -231,5 +394,18 @@ func (r Repo) createSSHKeys() error {
 		return err
 	}
 
-	return nil
+	knownHosts := ""
+
+	// Pinned host keys of the ssh server.
+	if len(pieces) == 4 && strings.TrimSpace(pieces[3]) != "" {
+		knownHosts = pieces[3]
+	} else if r.IsBitbucket() {
+		knownHosts = BitbucketKnownHosts
+	}
+
+	if knownHosts == "" {
+		return nil
+	}
+
+	return os.WriteFile(path.Join(r.keysDir, "known_hosts"), []byte(knownHosts), 0600)
 }
@@ -11,6 +11,8 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/redact"
 )
 
 type ReporterModel struct {
@@ -20,6 +22,7 @@ type ReporterModel struct {
 	done        chan struct{}
 	isDone      bool
 	mux         sync.Mutex
+	redactor    *redact.Redactor
 }
 
 // These are manipulated by external packages
@@ -35,6 +38,16 @@ func NewReporter(baseURL string) *ReporterModel {
 	}
 }
 
+// SetSecrets masks the given values, including their encoded forms,
+// in all the logs that are written after this call.
+func (r *ReporterModel) SetSecrets(secrets []string) {
+	r.redactor = redact.New(secrets)
+
+	if r.file != nil {
+		r.file.SetRedactor(r.redactor)
+	}
+}
+
 func (r *ReporterModel) request(payload map[string]any) error {
 	res, err := shttp.NewRequestV2(shttp.MethodPost, r.CallbackURL).
 		WithExponentialBackoff(time.Second*30, 5).
@@ -117,6 +130,30 @@ func (r *ReporterModel) SendCommitInfo(info map[string]string) error {
 	})
 }
 
+// SendSBOM sends the software bill of materials of the deployment.
+func (r *ReporterModel) SendSBOM(sbom *deploy.SBOM) error {
+	if r.baseURL == "" || sbom == nil {
+		return nil
+	}
+
+	return r.request(map[string]any{
+		"deployId": DeploymentIDEnc,
+		"sbom":     sbom,
+	})
+}
+
+// SendVulnerabilities sends the findings of the dependency vulnerability scan.
+func (r *ReporterModel) SendVulnerabilities(findings []osv.Finding) error {
+	if r.baseURL == "" || findings == nil {
+		return nil
+	}
+
+	return r.request(map[string]any{
+		"deployId":        DeploymentIDEnc,
+		"vulnerabilities": findings,
+	})
+}
+
 // LockDeployment should be called only after status checks are called.
 // If a deployment has no status checks, the exit callback will lock
 // the deployment automatically.
@@ -128,6 +165,7 @@ func (r *ReporterModel) LockDeployment(isSuccess bool) error {
 	}
 
 	// Send remaining logs
+	r.file.Flush()
 	r.sendLogs()
 
 	return r.request(map[string]any{
@@ -156,10 +194,12 @@ func (r *ReporterModel) SendExit(manifest *deploy.BuildManifest, result *integra
 	}
 
 	// Send last remaining bits
+	r.file.Flush()
 	r.sendLogs()
 
 	// Create a new buffer for status checks
 	r.file = NewCustomBuffer()
+	r.file.SetRedactor(r.redactor)
 
 	err := r.request(map[string]any{
 		"deployId":        DeploymentIDEnc,
@@ -182,6 +222,9 @@ func (r *ReporterModel) AddStep(title string) {
 		return
 	}
 
+	// The output of the previous command may not end with a new line.
+	r.file.Flush()
+
 	_, err := r.file.Write([]byte(fmt.Sprintf("[sk-step] %s [ts:%d]\n", title, time.Now().Unix())))
 
 	if err != nil {
@@ -191,6 +234,8 @@ func (r *ReporterModel) AddStep(title string) {
 
 func (r *ReporterModel) AddLine(text string) {
 	if r.file != nil {
+		r.file.Flush()
+
 		_, err := r.file.Write([]byte(fmt.Sprintf("%s\n", text)))
 
 		if err != nil {
@@ -8,6 +8,7 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -19,6 +20,7 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/file"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/framework"
 )
 
 const StatusChecksPending = "pending"
@@ -26,6 +28,11 @@ const StatusChecksSuccess = "complete"
 
 const RuntimeNode = "node"
 const RuntimeBun = "bun"
+const RuntimePython = "python"
+
+// PythonVenvFolder is the folder, relative to the working directory, where
+// the python dependencies are installed.
+const PythonVenvFolder = ".venv"
 
 func normalizeEnvVars(vars map[string]string) map[string]string {
 	envVars := map[string]string{}
@@ -62,7 +69,7 @@ func printEnvVariables(opts RunnerOpts) error {
 	obfuscate := regexp.MustCompile("(?i)secret|_key|_token|password")
 
 	for k, v := range opts.Build.EnvVars {
-		if obfuscate.Match([]byte(k)) {
+		if obfuscate.Match([]byte(k)) || slices.Contains(opts.Build.SecretVars, k) {
 			vars = append(vars, fmt.Sprintf("%s=***************", k))
 		} else {
 			vars = append(vars, fmt.Sprintf("%s=%s", k, v))
@@ -80,6 +87,32 @@ func printEnvVariables(opts RunnerOpts) error {
 	return nil
 }
 
+// printFramework logs the detected framework along with the build settings
+// that are suggested for it but not configured in the environment.
+func printFramework(opts RunnerOpts) {
+	preset := framework.DetectDir(opts.WorkDir)
+
+	if preset == nil {
+		return
+	}
+
+	opts.Reporter.AddStep("framework detection")
+	opts.Reporter.AddLine(fmt.Sprintf("detected %s", preset.Name))
+
+	suggestions := [][3]string{
+		{"build command", opts.Build.BuildCmd, preset.BuildCmd},
+		{"output folder", opts.Build.DistFolder, preset.DistFolder},
+		{"server command", opts.Build.ServerCmd, preset.ServerCmd},
+		{"api folder", opts.Build.APIFolder, preset.APIFolder},
+	}
+
+	for _, suggestion := range suggestions {
+		if suggestion[1] == "" && suggestion[2] != "" {
+			opts.Reporter.AddLine(fmt.Sprintf("%s is not configured, suggested value: %s", suggestion[0], suggestion[2]))
+		}
+	}
+}
+
 type Payload struct {
 	BaseURL       string `json:"baseUrl"` // https://api.stormkit.io
 	RootDir       string `json:"rootDir"`
@@ -91,6 +124,9 @@ type RepoOpts struct {
 	Dir             string
 	Address         string
 	Branch          string
+	Tag             string // Tag is cloned instead of the branch when provided
+	CommitSha       string // CommitSha is the exact commit to check out
+	Checkout        *buildconf.GitCheckout
 	AccessToken     string
 	PackageJson     *PackageJson
 	PackageLockFile bool
@@ -99,6 +135,8 @@ type RepoOpts struct {
 	IsNpm           bool
 	IsPnpm          bool
 	IsBun           bool
+	IsPython        bool
+	IsUv            bool // Whether the python project is managed by uv (uv.lock)
 }
 
 func parsePackageJson(packageJsonPath string) *PackageJson {
@@ -143,6 +181,8 @@ type BuildOpts struct {
 	AppID         string
 	EnvID         string
 	StatusChecks  []buildconf.StatusCheck
+	SecretVars    []string // Names of the environment variables whose values are masked in the logs
+	VulnScan      *buildconf.VulnScan
 }
 
 type RunnerOpts struct {
@@ -156,6 +196,24 @@ type RunnerOpts struct {
 	Reporter       *ReporterModel
 }
 
+// Secrets returns the values that should never be visible in the logs:
+// the environment variables marked as secret and the repository access token.
+func (o RunnerOpts) Secrets() []string {
+	secrets := []string{}
+
+	for _, name := range o.Build.SecretVars {
+		if value := o.Build.EnvVars[name]; value != "" {
+			secrets = append(secrets, value)
+		}
+	}
+
+	if o.Repo.AccessToken != "" {
+		secrets = append(secrets, o.Repo.AccessToken)
+	}
+
+	return secrets
+}
+
 func (o RunnerOpts) MkdirAll() error {
 	for _, dir := range []string{o.RootDir, o.KeysDir, o.Repo.Dir} {
 		if dir == "" {
@@ -212,6 +270,9 @@ func Start(payload, rootDir string) error {
 			Dir:         repoDir,
 			Address:     msg.Client.Repo,
 			Branch:      msg.Build.Branch,
+			Tag:         msg.Build.Tag,
+			CommitSha:   msg.Build.CommitSha,
+			Checkout:    msg.Build.GitCheckout,
 			AccessToken: msg.Client.AccessToken,
 			PackageJson: nil, // will be determined later
 		},
@@ -228,6 +289,8 @@ func Start(payload, rootDir string) error {
 			APIFolder:     trim(msg.Build.APIFolder),
 			DistFolder:    trim(msg.Build.DistFolder),
 			StatusChecks:  msg.Build.StatusChecks,
+			SecretVars:    msg.Build.SecretVars,
+			VulnScan:      msg.Build.VulnScan,
 			EnvVars:       msg.Build.Vars,
 			EnvVarsRaw: []string{
 				"CI=true",
@@ -246,6 +309,8 @@ func Start(payload, rootDir string) error {
 		opts.Build.EnvVars = make(map[string]string)
 	}
 
+	opts.Reporter.SetSecrets(opts.Secrets())
+
 	if err := opts.MkdirAll(); err != nil {
 		return err
 	}
@@ -320,6 +385,15 @@ func Run(opts RunnerOpts) *RunResult {
 	} else if opts.Repo.PackageJson != nil {
 		opts.PackageManager = "npm"
 		opts.Repo.Runtime = RuntimeNode
+	} else if isPythonProject(opts.WorkDir) {
+		opts.Repo.IsPython = true
+		opts.Repo.Runtime = RuntimePython
+		opts.PackageManager = "pip"
+
+		if file.Exists(path.Join(opts.WorkDir, "uv.lock")) {
+			opts.Repo.IsUv = true
+			opts.PackageManager = "uv"
+		}
 	}
 
 	if err := opts.Reporter.SendCommitInfo(repo.CommitInfo()); err != nil {
@@ -349,10 +423,16 @@ func Run(opts RunnerOpts) *RunResult {
 		return &RunResult{opts: opts, err: err}
 	}
 
+	printFramework(opts)
+
 	if err := installer.Install(ctx); err != nil {
 		return &RunResult{opts: opts, err: err}
 	}
 
+	if err := ScanVulnerabilities(opts); err != nil {
+		return &RunResult{opts: opts, err: err}
+	}
+
 	builder := NewBuilder(opts)
 
 	if err := builder.ExecCommands(ctx); err != nil {
@@ -385,6 +465,8 @@ func Run(opts RunnerOpts) *RunResult {
 		return &RunResult{opts: opts, err: err}
 	}
 
+	reportSBOM(opts, artifacts)
+
 	opts.Reporter.AddStep("[system] building finished")
 
 	manifest = &deploy.BuildManifest{
@@ -401,6 +483,7 @@ func Run(opts RunnerOpts) *RunResult {
 
 		result, err = NewUploader(opts.Uploader).Upload(UploadArgs{
 			ClientZip:     artifacts.clientZip,
+			ClientBlobs:   artifacts.ClientBlobs(manifest.CDNFiles),
 			ServerZip:     artifacts.serverZip,
 			ApiZip:        artifacts.apiZip,
 			ServerHandler: artifacts.FunctionHandler,
@@ -421,6 +504,31 @@ func Run(opts RunnerOpts) *RunResult {
 	return &RunResult{opts: opts, result: result, manifest: manifest}
 }
 
+// reportSBOM generates the software bill of materials and sends it to the api.
+// Failing to generate the sbom does not fail the deployment.
+func reportSBOM(opts RunnerOpts, artifacts *Artifacts) {
+	opts.Reporter.AddStep("software bill of materials")
+
+	sbom, err := GenerateSBOM(opts.WorkDir, artifacts)
+
+	if err != nil {
+		opts.Reporter.AddLine(fmt.Sprintf("could not generate software bill of materials: %s", err.Error()))
+		return
+	}
+
+	if sbom.Source == "" {
+		opts.Reporter.AddLine("no supported lock file found")
+	} else {
+		opts.Reporter.AddLine(fmt.Sprintf("resolved from %s", sbom.Source))
+	}
+
+	opts.Reporter.AddLine(fmt.Sprintf("found %d components", len(sbom.Components)))
+
+	if err := opts.Reporter.SendSBOM(sbom); err != nil {
+		slog.Errorf("error while sending sbom: %v", err)
+	}
+}
+
 // GetRuntimeStringForLambdas returns the runtime string for the uploader based on
 // the given runtime and mise output.
 func GetRuntimeStringForLambdas(runtime string, miseOutput []string) string {
@@ -445,6 +553,9 @@ func GetRuntimeStringForLambdas(runtime string, miseOutput []string) string {
 			switch runtime {
 			case RuntimeNode:
 				return fmt.Sprintf("nodejs%s.x", major)
+			case RuntimePython:
+				_, minor, _ := utils.ParseSemver(version)
+				return fmt.Sprintf("python%s.%s", major, minor)
 			}
 		}
 	}
@@ -452,6 +563,18 @@ func GetRuntimeStringForLambdas(runtime string, miseOutput []string) string {
 	return ""
 }
 
+// isPythonProject returns true when the given directory contains
+// one of the python dependency files.
+func isPythonProject(dir string) bool {
+	for _, name := range []string{"uv.lock", "pyproject.toml", "requirements.txt"} {
+		if file.Exists(path.Join(dir, name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
 type RunResult struct {
 	opts     RunnerOpts
 	result   *integrations.UploadResult
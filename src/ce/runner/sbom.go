@@ -0,0 +1,453 @@
+package runner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/file"
+)
+
+const (
+	bundleServer = "server"
+	bundleAPI    = "api"
+)
+
+// lockFileParsers maps the supported lock files to their parsers.
+// The order matters: components found in the earlier files take precedence.
+var lockFileParsers = []struct {
+	name  string
+	parse func([]byte) ([]deploy.SBOMComponent, error)
+}{
+	{name: "package-lock.json", parse: parsePackageLock},
+	{name: "yarn.lock", parse: parseYarnLock},
+	{name: "pnpm-lock.yaml", parse: parsePnpmLock},
+	{name: "uv.lock", parse: parseUvLock},
+	{name: "requirements.txt", parse: parseRequirements},
+}
+
+// GenerateSBOM generates the software bill of materials for the deployment.
+// Components are resolved from the lock files found in the working directory
+// and are marked with the bundle they are shipped with (server or api).
+func GenerateSBOM(workDir string, artifacts *Artifacts) (*deploy.SBOM, error) {
+	sbom := &deploy.SBOM{Components: []deploy.SBOMComponent{}}
+	sources := []string{}
+	index := map[string]int{}
+
+	add := func(c deploy.SBOMComponent) int {
+		key := c.Ecosystem + ":" + c.Name + "@" + c.Version
+
+		if i, ok := index[key]; ok {
+			return i
+		}
+
+		sbom.Components = append(sbom.Components, c)
+		index[key] = len(sbom.Components) - 1
+		return index[key]
+	}
+
+	for _, lockFile := range lockFileParsers {
+		data, err := os.ReadFile(path.Join(workDir, lockFile.name))
+
+		if os.IsNotExist(err) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		components, err := lockFile.parse(data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		sources = append(sources, lockFile.name)
+
+		for _, c := range components {
+			add(c)
+		}
+	}
+
+	if artifacts != nil {
+		bundles := map[string][]string{
+			bundleServer: artifacts.ServerDirs,
+			bundleAPI:    artifacts.ApiDirs,
+		}
+
+		for _, bundle := range []string{bundleServer, bundleAPI} {
+			for _, c := range bundledNodeModules(workDir, bundles[bundle]) {
+				i := add(c)
+
+				if !slices.Contains(sbom.Components[i].Bundles, bundle) {
+					sbom.Components[i].Bundles = append(sbom.Components[i].Bundles, bundle)
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(sbom.Components, func(i, j int) bool {
+		if sbom.Components[i].Name == sbom.Components[j].Name {
+			return sbom.Components[i].Version < sbom.Components[j].Version
+		}
+
+		return sbom.Components[i].Name < sbom.Components[j].Name
+	})
+
+	sbom.Source = strings.Join(sources, ", ")
+	return sbom, nil
+}
+
+// parsePackageLock parses package-lock.json files. Version 1 lists
+// the dependencies in a nested tree, version 2 and 3 in a flat map.
+func parsePackageLock(data []byte) ([]deploy.SBOMComponent, error) {
+	type dependency struct {
+		Version      string                `json:"version"`
+		Dev          bool                  `json:"dev"`
+		Link         bool                  `json:"link"`
+		Dependencies map[string]dependency `json:"dependencies"`
+	}
+
+	lock := struct {
+		Packages     map[string]dependency `json:"packages"`
+		Dependencies map[string]dependency `json:"dependencies"`
+	}{}
+
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	components := []deploy.SBOMComponent{}
+
+	if len(lock.Packages) > 0 {
+		for key, pkg := range lock.Packages {
+			i := strings.LastIndex(key, "node_modules/")
+
+			if i == -1 || pkg.Link || pkg.Version == "" {
+				continue
+			}
+
+			components = append(components, deploy.SBOMComponent{
+				Name:      key[i+len("node_modules/"):],
+				Version:   pkg.Version,
+				Ecosystem: deploy.EcosystemNpm,
+				Dev:       pkg.Dev,
+			})
+		}
+
+		return components, nil
+	}
+
+	var walk func(deps map[string]dependency)
+
+	walk = func(deps map[string]dependency) {
+		for name, dep := range deps {
+			if dep.Version != "" && !strings.HasPrefix(dep.Version, "file:") {
+				components = append(components, deploy.SBOMComponent{
+					Name:      name,
+					Version:   dep.Version,
+					Ecosystem: deploy.EcosystemNpm,
+					Dev:       dep.Dev,
+				})
+			}
+
+			walk(dep.Dependencies)
+		}
+	}
+
+	walk(lock.Dependencies)
+
+	return components, nil
+}
+
+// parseYarnLock parses both yarn v1 and yarn berry lock files.
+func parseYarnLock(data []byte) ([]deploy.SBOMComponent, error) {
+	components := []deploy.SBOMComponent{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	name := ""
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Entry header: "@scope/name@^1.0.0", name@npm:^1.1.0:
+		if !strings.HasPrefix(line, " ") {
+			spec := strings.TrimSpace(strings.SplitN(strings.TrimSuffix(line, ":"), ",", 2)[0])
+			spec = strings.Trim(spec, `"`)
+			name = ""
+
+			if strings.Contains(spec, "@workspace:") || strings.Contains(spec, "@patch:") {
+				continue
+			}
+
+			if i := strings.LastIndex(spec, "@"); i > 0 {
+				name = spec[:i]
+
+				// Berry aliases: name@npm:other-name@^1.0.0
+				if j := strings.Index(name, "@npm:"); j > 0 {
+					name = name[:j]
+				}
+			}
+
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+
+		if name == "" || !strings.HasPrefix(trimmed, "version") {
+			continue
+		}
+
+		version := strings.TrimPrefix(trimmed, "version")
+		version = strings.Trim(strings.TrimSpace(strings.TrimPrefix(version, ":")), `"`)
+
+		components = append(components, deploy.SBOMComponent{
+			Name:      name,
+			Version:   version,
+			Ecosystem: deploy.EcosystemNpm,
+		})
+
+		name = ""
+	}
+
+	return components, scanner.Err()
+}
+
+// parsePnpmLock parses pnpm-lock.yaml files. Package keys are in
+// one of the following formats depending on the lock file version:
+// /name/1.0.0 (v5), /name@1.0.0 (v6) and name@1.0.0(peer@1.0.0) (v9).
+func parsePnpmLock(data []byte) ([]deploy.SBOMComponent, error) {
+	lock := struct {
+		LockfileVersion any `yaml:"lockfileVersion"`
+		Packages        map[string]struct {
+			Dev bool `yaml:"dev"`
+		} `yaml:"packages"`
+	}{}
+
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, err
+	}
+
+	// Lock files before v6 use the /name/version_peer@version format,
+	// later ones use the name@version(peer@version) format.
+	major, _ := strconv.Atoi(strings.Split(fmt.Sprint(lock.LockfileVersion), ".")[0])
+	legacy := major > 0 && major < 6
+
+	components := []deploy.SBOMComponent{}
+
+	for key, pkg := range lock.Packages {
+		key = strings.TrimPrefix(key, "/")
+
+		var name, version string
+
+		if legacy {
+			if i := strings.LastIndex(key, "/"); i > 0 {
+				name, version = key[:i], key[i+1:]
+			}
+
+			if i := strings.Index(version, "_"); i > -1 {
+				version = version[:i]
+			}
+		} else {
+			if i := strings.Index(key, "("); i > 0 {
+				key = key[:i]
+			}
+
+			if i := strings.LastIndex(key, "@"); i > 0 {
+				name, version = key[:i], key[i+1:]
+			} else if i := strings.LastIndex(key, "/"); i > 0 {
+				name, version = key[:i], key[i+1:]
+			}
+		}
+
+		if name == "" || version == "" {
+			continue
+		}
+
+		components = append(components, deploy.SBOMComponent{
+			Name:      name,
+			Version:   version,
+			Ecosystem: deploy.EcosystemNpm,
+			Dev:       pkg.Dev,
+		})
+	}
+
+	return components, nil
+}
+
+// parseUvLock parses uv.lock files. The project itself and the
+// local packages (editable, virtual) are not included.
+func parseUvLock(data []byte) ([]deploy.SBOMComponent, error) {
+	components := []deploy.SBOMComponent{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	var current *deploy.SBOMComponent
+	isLocal := false
+
+	flush := func() {
+		if current != nil && current.Name != "" && current.Version != "" && !isLocal {
+			components = append(components, *current)
+		}
+
+		current = nil
+		isLocal = false
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "[") {
+			flush()
+
+			if line == "[[package]]" {
+				current = &deploy.SBOMComponent{Ecosystem: deploy.EcosystemPypi}
+			}
+
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+
+		if !ok {
+			continue
+		}
+
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "name":
+			current.Name = strings.Trim(value, `"`)
+		case "version":
+			current.Version = strings.Trim(value, `"`)
+		case "source":
+			isLocal = strings.Contains(value, "editable") || strings.Contains(value, "virtual")
+		}
+	}
+
+	flush()
+
+	return components, scanner.Err()
+}
+
+var requirementRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)(\[[^\]]*\])?\s*===?\s*([^\s;#]+)`)
+
+// parseRequirements parses the pinned dependencies from requirements.txt.
+// Dependencies without an exact version are ignored as they cannot be resolved offline.
+func parseRequirements(data []byte) ([]deploy.SBOMComponent, error) {
+	components := []deploy.SBOMComponent{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	for scanner.Scan() {
+		matches := requirementRegex.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+
+		if matches == nil {
+			continue
+		}
+
+		components = append(components, deploy.SBOMComponent{
+			Name:      strings.ToLower(matches[1]),
+			Version:   matches[3],
+			Ecosystem: deploy.EcosystemPypi,
+		})
+	}
+
+	return components, scanner.Err()
+}
+
+// bundledNodeModules returns the node modules that are shipped with the given directories.
+// The directories are either node modules themselves (node_modules/<name>) or
+// folders that contain a node_modules folder.
+func bundledNodeModules(workDir string, dirs []string) []deploy.SBOMComponent {
+	components := []deploy.SBOMComponent{}
+
+	for _, dir := range dirs {
+		if strings.HasPrefix(dir, "node_modules/") {
+			if c := readNodeModule(path.Join(workDir, dir)); c != nil {
+				components = append(components, *c)
+			}
+
+			continue
+		}
+
+		nodeModules := path.Join(workDir, dir, "node_modules")
+		entries, err := os.ReadDir(nodeModules)
+
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+				continue
+			}
+
+			pkgDirs := []string{path.Join(nodeModules, entry.Name())}
+
+			// Scoped packages: node_modules/@scope/name
+			if strings.HasPrefix(entry.Name(), "@") {
+				pkgDirs = []string{}
+				scoped, _ := os.ReadDir(path.Join(nodeModules, entry.Name()))
+
+				for _, s := range scoped {
+					pkgDirs = append(pkgDirs, path.Join(nodeModules, entry.Name(), s.Name()))
+				}
+			}
+
+			for _, pkgDir := range pkgDirs {
+				if c := readNodeModule(pkgDir); c != nil {
+					components = append(components, *c)
+				}
+			}
+		}
+	}
+
+	return components
+}
+
+func readNodeModule(dir string) *deploy.SBOMComponent {
+	packageJson := path.Join(dir, "package.json")
+
+	if !file.Exists(packageJson) {
+		return nil
+	}
+
+	data, err := os.ReadFile(packageJson)
+
+	if err != nil {
+		return nil
+	}
+
+	pkg := struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}{}
+
+	if err := json.Unmarshal(data, &pkg); err != nil || pkg.Name == "" || pkg.Version == "" {
+		return nil
+	}
+
+	return &deploy.SBOMComponent{
+		Name:      pkg.Name,
+		Version:   pkg.Version,
+		Ecosystem: deploy.EcosystemNpm,
+	}
+}
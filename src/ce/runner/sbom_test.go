@@ -0,0 +1,218 @@
+package runner_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/runner"
+	"github.com/stretchr/testify/suite"
+)
+
+type SBOMSuite struct {
+	suite.Suite
+	workDir string
+}
+
+func (s *SBOMSuite) BeforeTest(_, _ string) {
+	s.workDir = s.T().TempDir()
+}
+
+func (s *SBOMSuite) write(name, content string) {
+	s.NoError(os.MkdirAll(path.Dir(path.Join(s.workDir, name)), 0755))
+	s.NoError(os.WriteFile(path.Join(s.workDir, name), []byte(content), 0644))
+}
+
+func (s *SBOMSuite) Test_PackageLock() {
+	s.write("package-lock.json", `{
+		"lockfileVersion": 3,
+		"packages": {
+			"": { "name": "my-app", "version": "1.0.0" },
+			"node_modules/react": { "version": "18.2.0" },
+			"node_modules/@types/react": { "version": "18.2.1", "dev": true },
+			"node_modules/a/node_modules/react": { "version": "17.0.0" },
+			"node_modules/my-lib": { "resolved": "packages/my-lib", "link": true }
+		}
+	}`)
+
+	sbom, err := runner.GenerateSBOM(s.workDir, nil)
+	s.NoError(err)
+	s.Equal("package-lock.json", sbom.Source)
+	s.Equal([]deploy.SBOMComponent{
+		{Name: "@types/react", Version: "18.2.1", Ecosystem: deploy.EcosystemNpm, Dev: true},
+		{Name: "react", Version: "17.0.0", Ecosystem: deploy.EcosystemNpm},
+		{Name: "react", Version: "18.2.0", Ecosystem: deploy.EcosystemNpm},
+	}, sbom.Components)
+}
+
+func (s *SBOMSuite) Test_PackageLockV1() {
+	s.write("package-lock.json", `{
+		"lockfileVersion": 1,
+		"dependencies": {
+			"express": {
+				"version": "4.18.2",
+				"dependencies": {
+					"debug": { "version": "2.6.9" }
+				}
+			}
+		}
+	}`)
+
+	sbom, err := runner.GenerateSBOM(s.workDir, nil)
+	s.NoError(err)
+	s.Equal([]deploy.SBOMComponent{
+		{Name: "debug", Version: "2.6.9", Ecosystem: deploy.EcosystemNpm},
+		{Name: "express", Version: "4.18.2", Ecosystem: deploy.EcosystemNpm},
+	}, sbom.Components)
+}
+
+func (s *SBOMSuite) Test_YarnLock() {
+	s.write("yarn.lock", `# THIS IS AN AUTOGENERATED FILE. DO NOT EDIT THIS FILE DIRECTLY.
+# yarn lockfile v1
+
+
+"@babel/core@^7.0.0", "@babel/core@^7.1.0":
+  version "7.23.0"
+  resolved "https://registry.yarnpkg.com/@babel/core/-/core-7.23.0.tgz"
+  dependencies:
+    debug "^4.1.0"
+
+debug@^4.1.0:
+  version "4.3.4"
+`)
+
+	sbom, err := runner.GenerateSBOM(s.workDir, nil)
+	s.NoError(err)
+	s.Equal([]deploy.SBOMComponent{
+		{Name: "@babel/core", Version: "7.23.0", Ecosystem: deploy.EcosystemNpm},
+		{Name: "debug", Version: "4.3.4", Ecosystem: deploy.EcosystemNpm},
+	}, sbom.Components)
+}
+
+func (s *SBOMSuite) Test_YarnBerryLock() {
+	s.write("yarn.lock", `__metadata:
+  version: 8
+  cacheKey: 10
+
+"my-app@workspace:.":
+  version: 0.0.0-use.local
+  resolution: "my-app@workspace:."
+
+"lodash@npm:^4.17.21":
+  version: 4.17.21
+  resolution: "lodash@npm:4.17.21"
+`)
+
+	sbom, err := runner.GenerateSBOM(s.workDir, nil)
+	s.NoError(err)
+	s.Equal([]deploy.SBOMComponent{
+		{Name: "lodash", Version: "4.17.21", Ecosystem: deploy.EcosystemNpm},
+	}, sbom.Components)
+}
+
+func (s *SBOMSuite) Test_PnpmLock() {
+	s.write("pnpm-lock.yaml", `lockfileVersion: '9.0'
+
+packages:
+  '@vitejs/plugin-react@4.2.0':
+    resolution: {integrity: sha512-abc}
+
+  react-dom@18.2.0(react@18.2.0):
+    resolution: {integrity: sha512-def}
+`)
+
+	sbom, err := runner.GenerateSBOM(s.workDir, nil)
+	s.NoError(err)
+	s.Equal([]deploy.SBOMComponent{
+		{Name: "@vitejs/plugin-react", Version: "4.2.0", Ecosystem: deploy.EcosystemNpm},
+		{Name: "react-dom", Version: "18.2.0", Ecosystem: deploy.EcosystemNpm},
+	}, sbom.Components)
+}
+
+func (s *SBOMSuite) Test_PnpmLock_V5() {
+	s.write("pnpm-lock.yaml", `lockfileVersion: 5.4
+
+packages:
+  /@emotion/react/11.10.5_4bmk2rvi6ecihn4mtmlgbnqt4i:
+    resolution: {integrity: sha512-abc}
+
+  /react-dom/17.0.2_react@17.0.2:
+    resolution: {integrity: sha512-def}
+
+  /typescript/4.9.4:
+    resolution: {integrity: sha512-ghi}
+    dev: true
+`)
+
+	sbom, err := runner.GenerateSBOM(s.workDir, nil)
+	s.NoError(err)
+	s.Equal([]deploy.SBOMComponent{
+		{Name: "@emotion/react", Version: "11.10.5", Ecosystem: deploy.EcosystemNpm},
+		{Name: "react-dom", Version: "17.0.2", Ecosystem: deploy.EcosystemNpm},
+		{Name: "typescript", Version: "4.9.4", Ecosystem: deploy.EcosystemNpm, Dev: true},
+	}, sbom.Components)
+}
+
+func (s *SBOMSuite) Test_PythonLocks() {
+	s.write("uv.lock", `version = 1
+
+[[package]]
+name = "my-app"
+version = "0.1.0"
+source = { virtual = "." }
+
+[[package]]
+name = "flask"
+version = "3.0.0"
+source = { registry = "https://pypi.org/simple" }
+`)
+
+	s.write("requirements.txt", `# comment
+Django[argon2]==5.0.1 ; python_version >= "3.10"
+requests>=2.0
+`)
+
+	sbom, err := runner.GenerateSBOM(s.workDir, nil)
+	s.NoError(err)
+	s.Equal("uv.lock, requirements.txt", sbom.Source)
+	s.Equal([]deploy.SBOMComponent{
+		{Name: "django", Version: "5.0.1", Ecosystem: deploy.EcosystemPypi},
+		{Name: "flask", Version: "3.0.0", Ecosystem: deploy.EcosystemPypi},
+	}, sbom.Components)
+}
+
+func (s *SBOMSuite) Test_BundledNodeModules() {
+	s.write("package-lock.json", `{
+		"lockfileVersion": 3,
+		"packages": {
+			"node_modules/express": { "version": "4.18.2" }
+		}
+	}`)
+
+	s.write(".stormkit/server/node_modules/express/package.json", `{ "name": "express", "version": "4.18.2" }`)
+	s.write(".stormkit/server/node_modules/@scope/util/package.json", `{ "name": "@scope/util", "version": "1.0.0" }`)
+	s.write("api/node_modules/express/package.json", `{ "name": "express", "version": "4.18.2" }`)
+
+	sbom, err := runner.GenerateSBOM(s.workDir, &runner.Artifacts{
+		ServerDirs: []string{".stormkit/server"},
+		ApiDirs:    []string{"api"},
+	})
+
+	s.NoError(err)
+	s.Equal([]deploy.SBOMComponent{
+		{Name: "@scope/util", Version: "1.0.0", Ecosystem: deploy.EcosystemNpm, Bundles: []string{"server"}},
+		{Name: "express", Version: "4.18.2", Ecosystem: deploy.EcosystemNpm, Bundles: []string{"server", "api"}},
+	}, sbom.Components)
+}
+
+func (s *SBOMSuite) Test_NoLockFile() {
+	sbom, err := runner.GenerateSBOM(s.workDir, nil)
+	s.NoError(err)
+	s.Equal("", sbom.Source)
+	s.Empty(sbom.Components)
+}
+
+func TestSBOM(t *testing.T) {
+	suite.Run(t, &SBOMSuite{})
+}
@@ -25,6 +25,7 @@ type Uploader struct {
 
 type UploadArgs struct {
 	ClientZip     string
+	ClientBlobs   []integrations.Blob
 	ServerZip     string
 	ServerHandler string
 	ApiZip        string
@@ -131,6 +132,7 @@ func (u *Uploader) Upload(args UploadArgs) (*integrations.UploadResult, error) {
 		}).
 		Upload(integrations.UploadArgs{
 			ClientZip:     args.ClientZip,
+			ClientBlobs:   args.ClientBlobs,
 			ServerZip:     args.ServerZip,
 			ServerHandler: args.ServerHandler,
 			APIZip:        args.ApiZip,
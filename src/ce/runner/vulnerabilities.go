@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
+)
+
+// ScanVulnerabilities checks the resolved dependencies against the locally stored
+// advisory database. The findings are reported to the api and the build fails
+// when a finding has the configured severity or higher.
+func ScanVulnerabilities(opts RunnerOpts) error {
+	sbom, err := GenerateSBOM(opts.WorkDir, nil)
+
+	if err != nil {
+		slog.Errorf("cannot resolve dependencies for vulnerability scan: %v", err)
+		return nil
+	}
+
+	// The ecosystems of the sbom and their names in the advisory database.
+	ecosystems := map[string]string{
+		deploy.EcosystemNpm:  osv.EcosystemNpm,
+		deploy.EcosystemPypi: osv.EcosystemPyPI,
+	}
+
+	packages := []osv.Package{}
+
+	for _, c := range sbom.Components {
+		if ecosystem, ok := ecosystems[c.Ecosystem]; ok {
+			packages = append(packages, osv.Package{
+				Ecosystem: ecosystem,
+				Name:      c.Name,
+				Version:   c.Version,
+			})
+		}
+	}
+
+	if len(packages) == 0 {
+		return nil
+	}
+
+	opts.Reporter.AddStep("vulnerability scan")
+
+	findings, err := osv.Scan(osv.Dir(), packages)
+
+	if errors.Is(err, osv.ErrDatabaseNotFound) {
+		opts.Reporter.AddLine("advisory database is not available, skipping")
+		return nil
+	}
+
+	if err != nil {
+		opts.Reporter.AddLine(fmt.Sprintf("could not scan dependencies: %s", err.Error()))
+		return nil
+	}
+
+	opts.Reporter.AddLine(fmt.Sprintf("scanned %d packages, found %d vulnerabilities", len(packages), len(findings)))
+
+	for _, finding := range findings {
+		line := fmt.Sprintf("[%s] %s@%s: %s", finding.Severity, finding.Name, finding.Version, finding.ID)
+
+		if finding.Summary != "" {
+			line = fmt.Sprintf("%s %s", line, finding.Summary)
+		}
+
+		if finding.FixedIn != "" {
+			line = fmt.Sprintf("%s (fixed in %s)", line, finding.FixedIn)
+		}
+
+		opts.Reporter.AddLine(line)
+	}
+
+	if err := opts.Reporter.SendVulnerabilities(findings); err != nil {
+		slog.Errorf("error while sending vulnerabilities: %v", err)
+	}
+
+	if opts.Build.VulnScan != nil {
+		threshold := opts.Build.VulnScan.FailBuildOn
+
+		if blocking := deploy.Vulnerabilities(findings).AtOrAbove(threshold); len(blocking) > 0 {
+			return fmt.Errorf("found %d vulnerabilities with %s severity or higher", len(blocking), threshold)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package runner_test
+
+import (
+	"archive/zip"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/runner"
+	"github.com/stretchr/testify/suite"
+)
+
+type VulnerabilitiesSuite struct {
+	suite.Suite
+	opts runner.RunnerOpts
+}
+
+func (s *VulnerabilitiesSuite) BeforeTest(_, _ string) {
+	workDir := s.T().TempDir()
+	osvDir := s.T().TempDir()
+
+	s.T().Setenv("STORMKIT_OSV_DIR", osvDir)
+
+	s.opts = runner.RunnerOpts{
+		WorkDir:  workDir,
+		Reporter: runner.NewReporter(""),
+	}
+
+	s.NoError(os.WriteFile(path.Join(workDir, "package-lock.json"), []byte(`{
+		"lockfileVersion": 3,
+		"packages": {
+			"node_modules/lodash": { "version": "4.17.20" }
+		}
+	}`), 0644))
+
+	s.writeExport(osvDir, "npm", `{
+		"id": "GHSA-1",
+		"summary": "Prototype pollution",
+		"affected": [{
+			"package": { "ecosystem": "npm", "name": "lodash" },
+			"ranges": [{ "type": "SEMVER", "events": [{ "introduced": "0" }, { "fixed": "4.17.21" }] }]
+		}],
+		"database_specific": { "severity": "HIGH" }
+	}`)
+}
+
+// writeExport stores an advisory database export with the given advisory.
+func (s *VulnerabilitiesSuite) writeExport(osvDir, ecosystem, advisory string) {
+	s.NoError(os.MkdirAll(path.Join(osvDir, ecosystem), 0755))
+
+	f, err := os.Create(path.Join(osvDir, ecosystem, "all.zip"))
+	s.NoError(err)
+
+	w := zip.NewWriter(f)
+	fw, err := w.Create("advisory.json")
+	s.NoError(err)
+
+	_, err = fw.Write([]byte(advisory))
+
+	s.NoError(err)
+	s.NoError(w.Close())
+	s.NoError(f.Close())
+}
+
+func (s *VulnerabilitiesSuite) Test_ReportsFindings() {
+	s.NoError(runner.ScanVulnerabilities(s.opts))
+	s.Contains(s.opts.Reporter.Logs(), "scanned 1 packages, found 1 vulnerabilities")
+	s.Contains(s.opts.Reporter.Logs(), "[high] lodash@4.17.20: GHSA-1 Prototype pollution (fixed in 4.17.21)")
+}
+
+func (s *VulnerabilitiesSuite) Test_ReportsPythonFindings() {
+	s.NoError(os.WriteFile(path.Join(s.opts.WorkDir, "requirements.txt"), []byte("Django==5.0.1\n"), 0644))
+
+	s.writeExport(os.Getenv("STORMKIT_OSV_DIR"), "PyPI", `{
+		"id": "PYSEC-1",
+		"summary": "SQL injection",
+		"affected": [{
+			"package": { "ecosystem": "PyPI", "name": "django" },
+			"ranges": [{ "type": "ECOSYSTEM", "events": [{ "introduced": "0" }, { "fixed": "5.0.2" }] }]
+		}],
+		"database_specific": { "severity": "CRITICAL" }
+	}`)
+
+	s.NoError(runner.ScanVulnerabilities(s.opts))
+	s.Contains(s.opts.Reporter.Logs(), "scanned 2 packages, found 2 vulnerabilities")
+	s.Contains(s.opts.Reporter.Logs(), "[critical] django@5.0.1: PYSEC-1 SQL injection (fixed in 5.0.2)")
+}
+
+func (s *VulnerabilitiesSuite) Test_FailsBuildAboveThreshold() {
+	s.opts.Build.VulnScan = &buildconf.VulnScan{FailBuildOn: "critical"}
+	s.NoError(runner.ScanVulnerabilities(s.opts))
+
+	s.opts.Build.VulnScan = &buildconf.VulnScan{FailBuildOn: "moderate"}
+	s.EqualError(runner.ScanVulnerabilities(s.opts), "found 1 vulnerabilities with moderate severity or higher")
+}
+
+func (s *VulnerabilitiesSuite) Test_DatabaseNotFound() {
+	s.T().Setenv("STORMKIT_OSV_DIR", s.T().TempDir())
+	s.opts.Build.VulnScan = &buildconf.VulnScan{FailBuildOn: "low"}
+
+	s.NoError(runner.ScanVulnerabilities(s.opts))
+	s.Contains(s.opts.Reporter.Logs(), "advisory database is not available, skipping")
+}
+
+func TestVulnerabilities(t *testing.T) {
+	suite.Run(t, &VulnerabilitiesSuite{})
+}
@@ -0,0 +1,36 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/hibiken/asynq"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhooks"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+)
+
+// HandleDeploymentPublish runs the http checks of a completed deployment
+// and publishes it when the checks pass.
+func HandleDeploymentPublish(ctx context.Context, t *asynq.Task) error {
+	d, err := deploy.NewStore().MyDeployment(ctx, &deploy.DeploymentsQueryFilters{
+		DeploymentID: utils.StringToID(string(t.Payload())),
+	})
+
+	if err != nil {
+		slog.Errorf("cannot retrieve deployment to publish: %v", err)
+		return err
+	}
+
+	// The deployment has been deleted in the meantime
+	if d == nil {
+		return nil
+	}
+
+	if err := deployhooks.RunChecksAndPublish(ctx, d); err != nil {
+		slog.Errorf("error while publishing deployment %s: %v", d.ID.String(), err)
+		return err
+	}
+
+	return nil
+}
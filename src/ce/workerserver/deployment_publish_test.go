@@ -0,0 +1,119 @@
+package jobs_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/hibiken/asynq"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/appcache"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy/deployhooks"
+	jobs "github.com/stormkit-io/stormkit-io/src/ce/workerserver"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/tasks"
+	"github.com/stormkit-io/stormkit-io/src/mocks"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/guregu/null.v3"
+)
+
+type DeploymentPublishSuite struct {
+	suite.Suite
+	*factory.Factory
+
+	conn             databasetest.TestDB
+	mockCacheService *mocks.CacheInterface
+}
+
+func (s *DeploymentPublishSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+	s.mockCacheService = &mocks.CacheInterface{}
+	appcache.DefaultCacheService = s.mockCacheService
+	deployhooks.StatusChecksEnabled = false
+}
+
+func (s *DeploymentPublishSuite) AfterTest(_, _ string) {
+	appcache.DefaultCacheService = nil
+	deployhooks.StatusChecksEnabled = true
+	deployhooks.RunHTTPChecks = deploy.RunHTTPChecks
+	s.conn.CloseTx()
+}
+
+func (s *DeploymentPublishSuite) mockDeployment(env *factory.MockEnv) *factory.MockDeployment {
+	snapshot, err := json.Marshal(deploy.ConfigSnapshot{
+		BuildConfig: &buildconf.BuildConf{
+			BuildCmd:   "npm run build",
+			DistFolder: "build",
+			HTTPChecks: []buildconf.HTTPCheck{
+				{Path: "/api/health", Status: http.StatusOK},
+			},
+		},
+	})
+
+	s.NoError(err)
+
+	return s.MockDeployment(env, map[string]any{
+		"ShouldPublish": true,
+		"ConfigCopy":    snapshot,
+		"ExitCode":      null.IntFrom(0),
+	})
+}
+
+func (s *DeploymentPublishSuite) publishedDeployments(depl *factory.MockDeployment) []*deploy.Deployment {
+	depls, err := deploy.NewStore().MyDeployments(context.Background(), &deploy.DeploymentsQueryFilters{
+		DeploymentID: depl.ID,
+		Published:    aws.Bool(true),
+	})
+
+	s.NoError(err)
+	return depls
+}
+
+func (s *DeploymentPublishSuite) Test_ChecksPassed() {
+	env := s.MockEnv(nil)
+	depl := s.mockDeployment(env)
+
+	deployhooks.RunHTTPChecks = func(_ context.Context, _ string, checks []buildconf.HTTPCheck) deploy.HTTPCheckResults {
+		s.Equal("/api/health", checks[0].Path)
+		return deploy.HTTPCheckResults{{Method: "GET", Path: "/api/health", Status: 200, Latency: 15, Passed: true}}
+	}
+
+	s.mockCacheService.On("Reset", env.ID).Return(nil).Once()
+
+	s.NoError(jobs.HandleDeploymentPublish(context.Background(), asynq.NewTask(tasks.DeploymentPublish, []byte(depl.ID.String()))))
+
+	depls := s.publishedDeployments(depl)
+	s.Len(depls, 1)
+	s.True(depls[0].HTTPChecks.Passed())
+	s.Equal(int64(15), depls[0].HTTPChecks[0].Latency)
+}
+
+func (s *DeploymentPublishSuite) Test_ChecksFailed() {
+	env := s.MockEnv(nil)
+	depl := s.mockDeployment(env)
+
+	deployhooks.RunHTTPChecks = func(context.Context, string, []buildconf.HTTPCheck) deploy.HTTPCheckResults {
+		return deploy.HTTPCheckResults{{Method: "GET", Path: "/api/health", Status: 500, Error: "expected status 200, received 500"}}
+	}
+
+	s.NoError(jobs.HandleDeploymentPublish(context.Background(), asynq.NewTask(tasks.DeploymentPublish, []byte(depl.ID.String()))))
+	s.Empty(s.publishedDeployments(depl))
+
+	depls, err := deploy.NewStore().MyDeployments(context.Background(), &deploy.DeploymentsQueryFilters{
+		DeploymentID: depl.ID,
+	})
+
+	s.NoError(err)
+	s.Len(depls, 1)
+	s.False(depls[0].HTTPChecks.Passed())
+	s.Equal("expected status 200, received 500", depls[0].HTTPChecks[0].Error)
+}
+
+func TestDeploymentPublish(t *testing.T) {
+	suite.Run(t, &DeploymentPublishSuite{})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/hibiken/asynq"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
@@ -19,8 +20,17 @@ func HandleDeploymentStart(ctx context.Context, t *asynq.Task) error {
 		return err
 	}
 
+	deploymentID := utils.StringToID(message.Build.DeploymentID)
+
+	// The deployment may have been stopped or superseded while it was waiting in the queue
+	if stopped, err := deploy.NewStore().IsDeploymentStopped(ctx, deploymentID); err != nil {
+		slog.Errorf("cannot retrieve deployment status: %v", err)
+	} else if stopped {
+		return nil
+	}
+
 	args := deployservice.SendPayloadArgs{
-		DeploymentID: utils.StringToID(message.Build.DeploymentID),
+		DeploymentID: deploymentID,
 		EncryptedMsg: string(payload),
 	}
 
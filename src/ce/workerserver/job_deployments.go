@@ -3,18 +3,22 @@ package jobs
 import (
 	"context"
 	"strings"
+	"time"
 
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
 	"github.com/stormkit-io/stormkit-io/src/lib/config"
 	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
-	"github.com/stormkit-io/stormkit-io/src/lib/types"
 )
 
 type KeyContextNumberOfDeploymentsToDelete struct{}
 
 // RemoveDeploymentArtifactsManually removes the artifacts of expired deployments.
-// An expired deployment is a deployment that has not been used for more than 30 days.
-// Overwrite the numberOfDays to set a custom expiration time.
+// Deployments expire according to the retention policy of their environment, which
+// always takes precedence. The numberOfDays is the expiration time of deployments
+// whose environment has no policy, zero defaults to buildconf.DefaultRetentionDays.
 func RemoveDeploymentArtifactsManually(ctx context.Context, numberOfDays int) ([]string, error) {
 	limit, _ := ctx.Value(KeyContextNumberOfDeploymentsToDelete{}).(int)
 
@@ -22,12 +26,11 @@ func RemoveDeploymentArtifactsManually(ctx context.Context, numberOfDays int) ([
 		limit = 100
 	}
 
-	if numberOfDays == 0 {
-		numberOfDays = 30
-	}
-
-	store := NewStore()
-	deployments, err := store.DeploymentsOlderThan30Days(ctx, numberOfDays, limit)
+	store := deploy.NewStore()
+	deployments, err := store.ExpiredDeployments(ctx, &deploy.ExpiredDeploymentsFilters{
+		Days:  numberOfDays,
+		Limit: limit,
+	})
 
 	if err != nil {
 		return nil, err
@@ -38,8 +41,7 @@ func RemoveDeploymentArtifactsManually(ctx context.Context, numberOfDays int) ([
 	}
 
 	client := integrations.Client()
-	idsToBeMarked := []types.ID{}
-	idsToBeMarkedStr := []string{}
+	idsToBeMarked := []string{}
 
 	for _, d := range deployments {
 		args := integrations.DeleteArtifactsArgs{
@@ -53,21 +55,26 @@ func RemoveDeploymentArtifactsManually(ctx context.Context, numberOfDays int) ([
 			continue
 		}
 
-		idsToBeMarked = append(idsToBeMarked, d.ID)
-		idsToBeMarkedStr = append(idsToBeMarkedStr, d.ID.String())
-	}
+		// Content-addressed files are shared with other deployments, they are
+		// removed by RemoveUnreferencedBlobs once they are no longer used.
+		released, err := store.ReleaseDeploymentArtifacts(ctx, d)
 
-	if err = store.MarkDeploymentArtifactsDeleted(ctx, idsToBeMarked); err != nil {
-		slog.Errorf("error while marking artifacts deleted: %s", strings.Join(idsToBeMarkedStr, ", "))
-		return nil, err
+		if err != nil {
+			slog.Errorf("error while marking artifacts deleted: %s", d.ID.String())
+			return nil, err
+		}
+
+		if released {
+			idsToBeMarked = append(idsToBeMarked, d.ID.String())
+		}
 	}
 
-	return idsToBeMarkedStr, nil
+	return idsToBeMarked, nil
 }
 
 // RemoveDeploymentArtifacts is a job to remove the artifacts of expired deployments.
 func RemoveDeploymentArtifacts(ctx context.Context) error {
-	idsToBeMarked, err := RemoveDeploymentArtifactsManually(ctx, 30)
+	idsToBeMarked, err := RemoveDeploymentArtifactsManually(ctx, 0)
 
 	if err != nil {
 		return err
@@ -79,3 +86,114 @@ func RemoveDeploymentArtifacts(ctx context.Context) error {
 
 	return nil
 }
+
+// RemoveUnreferencedBlobs is a job to remove the content-addressed files
+// that are no longer used by any deployment.
+func RemoveUnreferencedBlobs(ctx context.Context) error {
+	blobs, err := deploy.NewStore().ClaimUnreferencedBlobs(ctx, 1000)
+
+	if err != nil {
+		return err
+	}
+
+	client := integrations.Client()
+
+	for location, hashes := range blobs {
+		err := client.DeleteArtifacts(ctx, integrations.DeleteArtifactsArgs{
+			StorageLocation: location,
+			Blobs:           hashes,
+		})
+
+		if err != nil {
+			slog.Errorf("error while deleting blobs from %s: %s", location, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// PublishScheduledDeployments is a job to execute the scheduled publishes that are due.
+// Failed publishes are not retried, the reason is stored instead.
+func PublishScheduledDeployments(ctx context.Context) error {
+	store := deploy.NewStore()
+	scheduled, err := store.ClaimDueScheduledPublishes(ctx)
+
+	if err != nil {
+		slog.Errorf("error while claiming scheduled publishes: %v", err)
+		return err
+	}
+
+	for _, sp := range scheduled {
+		if err := deploy.PublishScheduled(ctx, sp); err != nil {
+			slog.Errorf("error while executing scheduled publish id=%s: %v", sp.ID.String(), err)
+
+			if err := store.UpdateScheduledPublishError(ctx, sp.ID, err.Error()); err != nil {
+				slog.Errorf("error while updating scheduled publish error: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// DispatchQueuedDeployments is a job to start the queued deployments whose slots were
+// released without notifying the api, for instance when a build has timed out.
+func DispatchQueuedDeployments(ctx context.Context) error {
+	if err := deployservice.DispatchQueuedDeployments(ctx); err != nil {
+		slog.Errorf("error while dispatching queued deployments: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// WatchPublishedDeployments is a job to monitor the health of the deployments that were
+// published recently. Deployments are rolled back when their health degrades during the
+// watch window of the environment's auto rollback policy.
+func WatchPublishedDeployments(ctx context.Context) error {
+	store := deploy.NewStore()
+	watches, err := store.ActiveRollbackWatches(ctx)
+
+	if err != nil {
+		slog.Errorf("error while fetching rollback watches: %v", err)
+		return err
+	}
+
+	for _, w := range watches {
+		if err := watchPublishedDeployment(ctx, store, w); err != nil {
+			slog.Errorf("error while watching deployment id=%s: %v", w.DeploymentID.String(), err)
+		}
+	}
+
+	return nil
+}
+
+func watchPublishedDeployment(ctx context.Context, store *deploy.Store, w *deploy.RollbackWatch) error {
+	env, err := buildconf.NewStore().EnvironmentByID(ctx, w.EnvID)
+
+	if err != nil {
+		return err
+	}
+
+	// The policy has been removed or another deployment has been published in the meantime.
+	if env == nil || env.Data.AutoRollback == nil || !w.IsPublished {
+		return store.ResolveRollbackWatch(ctx, w.ID, false)
+	}
+
+	health, err := store.RollbackWatchHealth(ctx, w)
+
+	if err != nil {
+		return err
+	}
+
+	if reason := health.Degraded(env.Data.AutoRollback); reason != "" {
+		slog.Infof("rolling back deployment id=%s: %s", w.DeploymentID.String(), reason)
+		return deploy.Rollback(ctx, w, reason)
+	}
+
+	if time.Now().After(w.WatchUntil.Time) {
+		return store.ResolveRollbackWatch(ctx, w.ID, false)
+	}
+
+	return nil
+}
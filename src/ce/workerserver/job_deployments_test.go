@@ -2,8 +2,13 @@ package jobs_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/appcache"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	jobs "github.com/stormkit-io/stormkit-io/src/ce/workerserver"
 	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
@@ -11,6 +16,7 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/types"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
 	"github.com/stormkit-io/stormkit-io/src/mocks"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -92,6 +98,261 @@ func (s *JobDeploymentsSuite) Test_RemoveDeploymentsArtifacts() {
 	s.Equal(ids[1], deployments[1].ID)
 }
 
+func (s *JobDeploymentsSuite) Test_RemoveDeploymentsArtifacts_RetentionPolicyTakesPrecedence() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	retained := s.MockEnv(app, map[string]any{
+		"Name": "staging",
+		"Data": &buildconf.BuildConf{Retention: &buildconf.Retention{KeepDays: 60}},
+	})
+
+	T45daysAgo := utils.NewUnix()
+	T45daysAgo.Time = T45daysAgo.AddDate(0, 0, -45)
+
+	s.MockDeployment(env, map[string]any{"CreatedAt": T45daysAgo, "StorageLocation": null.StringFrom("local:/d-1")})
+	s.MockDeployment(retained, map[string]any{"CreatedAt": T45daysAgo, "StorageLocation": null.StringFrom("local:/d-2")})
+
+	// The number of days applies only to environments without a retention policy
+	ids, err := jobs.RemoveDeploymentArtifactsManually(context.Background(), 50)
+	s.NoError(err)
+	s.Empty(ids)
+
+	s.mockClient.On("DeleteArtifacts", mock.Anything, integrations.DeleteArtifactsArgs{StorageLocation: "local:/d-1"}).Return(nil).Once()
+
+	ids, err = jobs.RemoveDeploymentArtifactsManually(context.Background(), 10)
+	s.NoError(err)
+	s.Len(ids, 1)
+	s.mockClient.AssertExpectations(s.T())
+}
+
+func (s *JobDeploymentsSuite) Test_RemoveUnreferencedBlobs() {
+	ctx := context.Background()
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	location := fmt.Sprintf("local:/storage/%s/blobs", app.ID.String())
+
+	T45daysAgo := utils.NewUnix()
+	T45daysAgo.Time = T45daysAgo.AddDate(0, 0, -45)
+
+	manifest := func(etags ...string) *deploy.BuildManifest {
+		files := []deploy.CDNFile{}
+
+		for i, etag := range etags {
+			files = append(files, deploy.CDNFile{Name: fmt.Sprintf("/file-%d", i), Headers: map[string]string{"etag": etag}})
+		}
+
+		return &deploy.BuildManifest{CDNFiles: files}
+	}
+
+	// The expired deployment shares the `aaa` blob with the fresh deployment
+	expired := s.MockDeployment(env, map[string]any{
+		"CreatedAt":       T45daysAgo,
+		"StorageLocation": null.StringFrom(location),
+		"BuildManifest":   manifest(`"20-aaa"`, `"20-bbb"`),
+	})
+
+	fresh := s.MockDeployment(env, map[string]any{
+		"ExitCode":        null.IntFrom(0),
+		"StorageLocation": null.StringFrom(location),
+		"BuildManifest":   manifest(`"20-aaa"`),
+	})
+
+	store := deploy.NewStore()
+	s.NoError(store.AddBlobReferences(ctx, app.ID, location, expired.BlobHashes()))
+	s.NoError(store.AddBlobReferences(ctx, app.ID, location, fresh.BlobHashes()))
+
+	s.mockClient.On("DeleteArtifacts", mock.Anything, integrations.DeleteArtifactsArgs{StorageLocation: location}).Return(nil).Once()
+	s.NoError(jobs.RemoveDeploymentArtifacts(ctx))
+
+	refs := map[string]int{}
+	rows, err := s.conn.QueryContext(ctx, `SELECT blob_hash, ref_count FROM artifact_blobs WHERE blob_location = $1;`, location)
+	s.NoError(err)
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var hash string
+		var count int
+		s.NoError(rows.Scan(&hash, &count))
+		refs[hash] = count
+	}
+
+	s.Equal(map[string]int{"aaa": 1, "bbb": 0}, refs)
+
+	// Blobs are collected only after the grace period
+	s.NoError(jobs.RemoveUnreferencedBlobs(ctx))
+
+	_, err = s.conn.ExecContext(ctx, `UPDATE artifact_blobs SET updated_at = NOW() - INTERVAL '2 days' WHERE blob_location = $1;`, location)
+	s.NoError(err)
+
+	s.mockClient.On("DeleteArtifacts", mock.Anything, integrations.DeleteArtifactsArgs{StorageLocation: location, Blobs: []string{"bbb"}}).Return(nil).Once()
+	s.NoError(jobs.RemoveUnreferencedBlobs(ctx))
+	s.mockClient.AssertExpectations(s.T())
+}
+
+func (s *JobDeploymentsSuite) Test_ReleaseDeploymentArtifacts_Retry() {
+	ctx := context.Background()
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+	location := fmt.Sprintf("local:/storage/%s/blobs", app.ID.String())
+	manifest := &deploy.BuildManifest{CDNFiles: []deploy.CDNFile{{Name: "/index.html", Headers: map[string]string{"etag": `"20-aaa"`}}}}
+
+	expired := s.MockDeployment(env, map[string]any{"StorageLocation": null.StringFrom(location), "BuildManifest": manifest})
+	fresh := s.MockDeployment(env, map[string]any{"StorageLocation": null.StringFrom(location), "BuildManifest": manifest})
+
+	store := deploy.NewStore()
+	s.NoError(store.AddBlobReferences(ctx, app.ID, location, expired.BlobHashes()))
+	s.NoError(store.AddBlobReferences(ctx, app.ID, location, fresh.BlobHashes()))
+
+	released, err := store.ReleaseDeploymentArtifacts(ctx, expired.Deployment)
+	s.NoError(err)
+	s.True(released)
+
+	// A retry does not release the references of the same deployment again
+	released, err = store.ReleaseDeploymentArtifacts(ctx, expired.Deployment)
+	s.NoError(err)
+	s.False(released)
+
+	var count int
+	row := s.conn.QueryRowContext(ctx, `SELECT ref_count FROM artifact_blobs WHERE blob_location = $1 AND blob_hash = 'aaa';`, location)
+	s.NoError(row.Scan(&count))
+	s.Equal(1, count)
+}
+
+func (s *JobDeploymentsSuite) Test_PublishScheduledDeployments_FreezeWindow() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app, map[string]any{
+		"Data": &buildconf.BuildConf{
+			FreezeWindows: []buildconf.FreezeWindow{
+				{From: time.Now().Add(-time.Hour).Unix(), To: time.Now().Add(time.Hour).Unix()},
+			},
+		},
+	})
+
+	depl := s.MockDeployment(env)
+	store := deploy.NewStore()
+	sp := &deploy.ScheduledPublish{
+		AppID:     app.ID,
+		EnvID:     env.ID,
+		Targets:   deploy.ScheduledPublishTargets{{DeploymentID: depl.ID, Percentage: 100}},
+		PublishAt: utils.UnixFrom(time.Now().Add(-time.Minute)),
+		CreatedBy: usr.ID,
+	}
+
+	s.NoError(store.InsertScheduledPublish(context.Background(), sp))
+	s.NoError(jobs.PublishScheduledDeployments(context.Background()))
+
+	// Executed publishes are no longer pending
+	pending, err := store.ScheduledPublishes(context.Background(), app.ID, env.ID)
+	s.NoError(err)
+	s.Empty(pending)
+
+	var msg null.String
+	row := s.conn.QueryRowContext(context.Background(), `SELECT publish_error FROM scheduled_publishes WHERE schedule_id = $1;`, sp.ID)
+	s.NoError(row.Scan(&msg))
+	s.Equal(deploy.ErrFreezeWindowActive.Error(), msg.ValueOrZero())
+}
+
+func (s *JobDeploymentsSuite) Test_PublishScheduledDeployments_BlockingVulnerabilities() {
+	ctx := context.Background()
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app)
+
+	snapshot, err := json.Marshal(deploy.ConfigSnapshot{
+		BuildConfig: &buildconf.BuildConf{
+			VulnScan: &buildconf.VulnScan{BlockPublishOn: osv.SeverityHigh},
+		},
+	})
+
+	s.NoError(err)
+
+	depl := s.MockDeployment(env, map[string]any{"ConfigCopy": snapshot})
+	store := deploy.NewStore()
+
+	s.NoError(store.UpdateVulnerabilities(ctx, depl.ID, deploy.Vulnerabilities{
+		{Package: osv.Package{Ecosystem: "npm", Name: "lodash", Version: "4.17.20"}, ID: "GHSA-1", Severity: osv.SeverityHigh},
+	}))
+
+	sp := &deploy.ScheduledPublish{
+		AppID:     app.ID,
+		EnvID:     env.ID,
+		Targets:   deploy.ScheduledPublishTargets{{DeploymentID: depl.ID, Percentage: 100}},
+		PublishAt: utils.UnixFrom(time.Now().Add(-time.Minute)),
+		CreatedBy: usr.ID,
+	}
+
+	s.NoError(store.InsertScheduledPublish(ctx, sp))
+	s.NoError(jobs.PublishScheduledDeployments(ctx))
+
+	published, err := store.PublishedDeployments(ctx, []types.ID{env.ID})
+	s.NoError(err)
+	s.Empty(published)
+
+	var msg null.String
+	row := s.conn.QueryRowContext(ctx, `SELECT publish_error FROM scheduled_publishes WHERE schedule_id = $1;`, sp.ID)
+	s.NoError(row.Scan(&msg))
+	s.Equal(deploy.ErrVulnerabilitiesFound.Error(), msg.ValueOrZero())
+}
+
+func (s *JobDeploymentsSuite) Test_WatchPublishedDeployments_RollsBack() {
+	usr := s.MockUser()
+	app := s.MockApp(usr)
+	env := s.MockEnv(app, map[string]any{
+		"Data": &buildconf.BuildConf{
+			AutoRollback: &buildconf.AutoRollback{MinRequests: 2, ErrorRate: 50},
+		},
+	})
+
+	depls := s.MockDeployments(2, env)
+	cache := &mocks.CacheInterface{}
+	cache.On("Reset", env.ID).Return(nil)
+	appcache.DefaultCacheService = cache
+	defer func() { appcache.DefaultCacheService = nil }()
+
+	publish := func(did types.ID) {
+		s.NoError(deploy.Publish(context.Background(), []*deploy.PublishSettings{
+			{EnvID: env.ID, DeploymentID: did, Percentage: 100},
+		}))
+	}
+
+	publish(depls[0].ID)
+	publish(depls[1].ID)
+
+	// Responses that are not recorded as page views are counted as well.
+	stats := &deploy.ResponseStats{}
+
+	for _, code := range []int{200, 500, 502} {
+		stats.Add(code)
+	}
+
+	s.NoError(deploy.RecordResponseStats(context.Background(), map[types.ID]*deploy.ResponseStats{depls[1].ID: stats}))
+	s.NoError(jobs.WatchPublishedDeployments(context.Background()))
+
+	published, err := deploy.NewStore().PublishedDeployments(context.Background(), []types.ID{env.ID})
+	s.NoError(err)
+	s.Equal(depls[0].ID, published[env.ID])
+
+	depl, err := deploy.NewStore().MyDeployment(context.Background(), &deploy.DeploymentsQueryFilters{
+		AppID:        app.ID,
+		DeploymentID: depls[1].ID,
+	})
+
+	s.NoError(err)
+	s.NotNil(depl.Rollback)
+	s.Equal(depls[0].ID, depl.Rollback.PreviousDeploymentID)
+	s.Equal("66.7% of 3 requests returned 5xx after publish (threshold: 50.0%)", depl.Rollback.Reason)
+
+	// The rollback itself is not watched
+	watches, err := deploy.NewStore().ActiveRollbackWatches(context.Background())
+	s.NoError(err)
+	s.Empty(watches)
+}
+
 func TestJobDeploymentsSuite(t *testing.T) {
 	suite.Run(t, &JobDeploymentsSuite{})
 }
@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"maps"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/autodeploy"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+)
+
+// PollGitRemotes lists the refs of generic git remotes that are due for polling and
+// triggers a deployment for every branch or tag that points to a new commit. It is the
+// fallback for git servers that cannot send webhooks.
+func PollGitRemotes(ctx context.Context) error {
+	store := app.NewStore()
+	remotes, err := store.ClaimDueGitRemotes(ctx)
+
+	if err != nil {
+		slog.Errorf("error while claiming git remotes: %v", err)
+		return err
+	}
+
+	for _, remote := range remotes {
+		a, err := store.AppByID(ctx, remote.AppID)
+
+		if err != nil || a == nil || !a.IsGitRemote() {
+			continue
+		}
+
+		refs, err := remote.RemoteRefs(ctx, a.GitRemoteURL(), a.PrivateKey(ctx))
+
+		if err != nil {
+			slog.Errorf("error while polling git remote for app id=%d: %v", a.ID, err)
+			continue
+		}
+
+		if maps.Equal(refs, remote.LastRefs) {
+			continue
+		}
+
+		// The first poll only records the refs, otherwise all branches would be deployed.
+		if remote.LastRefs != nil {
+			for ref, sha := range refs {
+				if remote.LastRefs[ref] == sha {
+					continue
+				}
+
+				input := autodeploy.NewGitRemoteTriggerInput(a, ref, sha, "")
+
+				if input == nil {
+					continue
+				}
+
+				if res := autodeploy.TriggerDeploy(ctx, *input); res != nil && res.Error != nil {
+					slog.Errorf("error while deploying %s for app id=%d: %v", ref, a.ID, res.Error)
+				}
+			}
+		}
+
+		if err := store.UpdateGitRemoteRefs(ctx, a.ID, refs); err != nil {
+			slog.Errorf("error while updating git remote refs for app id=%d: %v", a.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package jobs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
+	jobs "github.com/stormkit-io/stormkit-io/src/ce/workerserver"
+	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
+	"github.com/stormkit-io/stormkit-io/src/lib/factory"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/sys"
+	"github.com/stormkit-io/stormkit-io/src/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type JobGitRemotesSuite struct {
+	suite.Suite
+	*factory.Factory
+	conn         databasetest.TestDB
+	mockCmd      *mocks.CommandInterface
+	mockDeployer *mocks.Deployer
+}
+
+func (s *JobGitRemotesSuite) BeforeTest(suiteName, _ string) {
+	s.conn = databasetest.InitTx(suiteName)
+	s.Factory = factory.New(s.conn)
+	s.mockCmd = &mocks.CommandInterface{}
+	s.mockDeployer = &mocks.Deployer{}
+	s.mockDeployer.On("Deploy", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	sys.DefaultCommand = s.mockCmd
+	deployservice.MockDeployer = s.mockDeployer
+}
+
+func (s *JobGitRemotesSuite) AfterTest(_, _ string) {
+	s.conn.CloseTx()
+	sys.DefaultCommand = nil
+	deployservice.MockDeployer = nil
+}
+
+func (s *JobGitRemotesSuite) Test_PollGitRemotes() {
+	ctx := context.Background()
+	store := app.NewStore()
+	appl := s.MockApp(nil, map[string]any{
+		"Repo": "git/https://git.example.org/stormkit-test-acc/test-repo.git",
+	})
+
+	s.MockEnv(appl, map[string]any{"AutoDeploy": true})
+
+	s.NoError(store.UpsertGitRemote(ctx, &app.GitRemote{
+		AppID:         appl.ID,
+		AuthType:      app.GitRemoteAuthNone,
+		WebhookSecret: "webhook-secret",
+		PollInterval:  5,
+	}))
+
+	s.NoError(store.UpdateGitRemoteRefs(ctx, appl.ID, map[string]string{
+		"refs/heads/main": "1111111111111111111111111111111111111111",
+		"refs/heads/dev":  "2222222222222222222222222222222222222222",
+	}))
+
+	s.mockCmd.On("SetOpts", mock.Anything).Return(s.mockCmd)
+	s.mockCmd.On("Output").Return([]byte(
+		"3333333333333333333333333333333333333333\trefs/heads/main\n"+
+			"2222222222222222222222222222222222222222\trefs/heads/dev\n",
+	), nil)
+
+	s.NoError(jobs.PollGitRemotes(ctx))
+
+	// Only the branch that moved is deployed.
+	s.mockDeployer.AssertNumberOfCalls(s.T(), "Deploy", 1)
+	s.mockDeployer.AssertCalled(s.T(), "Deploy", mock.Anything, mock.Anything, mock.MatchedBy(func(depl *deploy.Deployment) bool {
+		return s.Equal("main", depl.Branch) &&
+			s.Equal("3333333333333333333333333333333333333333", depl.Commit.ID.ValueOrZero())
+	}))
+
+	remote, err := store.GitRemote(ctx, appl.ID)
+	s.NoError(err)
+	s.Equal("3333333333333333333333333333333333333333", remote.LastRefs["refs/heads/main"])
+	s.False(remote.LastPolledAt.IsZero())
+
+	// The remote is not due until the poll interval passes.
+	s.NoError(jobs.PollGitRemotes(ctx))
+	s.mockCmd.AssertNumberOfCalls(s.T(), "Output", 1)
+}
+
+func TestJobGitRemotes(t *testing.T) {
+	suite.Run(t, &JobGitRemotesSuite{})
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/applog"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/user"
 	"github.com/stormkit-io/stormkit-io/src/ee/api/analytics"
@@ -30,6 +31,7 @@ type HostingRecord struct {
 	Analytics       *analytics.Record  `json:"analytics"`
 	TotalBandwidth  int64              `json:"totalBandwidth"`
 	FunctionInvoked bool               `json:"functionInvoked"`
+	StatusCode      int                `json:"statusCode,omitempty"` // StatusCode is set for records of served responses
 }
 
 // IngestHandlerForward reads last 100 rows from redis, and inserts them into the database.
@@ -47,6 +49,7 @@ func IngestHandlerForward(ctx context.Context) error {
 	analyticsRecords := []analytics.Record{}
 	logRecords := []*applog.Log{}
 	stats := map[string]map[string]int64{} // userId -> metric -> value
+	responses := map[types.ID]*deploy.ResponseStats{}
 	rows := 100
 
 	for i := 0; i < rows; i = i + 1 {
@@ -76,6 +79,14 @@ func IngestHandlerForward(ctx context.Context) error {
 			analyticsRecords = append(analyticsRecords, *record.Analytics)
 		}
 
+		if record.StatusCode != 0 && record.DeploymentID != 0 {
+			if responses[record.DeploymentID] == nil {
+				responses[record.DeploymentID] = &deploy.ResponseStats{}
+			}
+
+			responses[record.DeploymentID].Add(record.StatusCode)
+		}
+
 		if len(record.Logs) > 0 {
 			for _, log := range record.Logs {
 				logRecords = append(logRecords, &applog.Log{
@@ -112,7 +123,13 @@ func IngestHandlerForward(ctx context.Context) error {
 		}
 	}
 
+	if err := deploy.RecordResponseStats(ingestContext, responses); err != nil {
+		slog.Errorf("error while recording response stats: %v", err)
+	}
+
 	if len(logRecords) > 0 {
+		applog.RedactSecrets(ingestContext, logRecords)
+
 		if err := applog.NewStore().InsertLogs(ingestContext, logRecords); err != nil {
 			slog.Errorf("error while batch inserting log records: %v", err)
 		}
@@ -7,6 +7,9 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/buildconf"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/applog"
 	jobs "github.com/stormkit-io/stormkit-io/src/ce/workerserver"
 	"github.com/stormkit-io/stormkit-io/src/ee/api/analytics"
 	"github.com/stormkit-io/stormkit-io/src/lib/database/databasetest"
@@ -140,6 +143,25 @@ func (s *JobHandlerForwardTest) Test_IngestHandlerForward_BatchLimit() {
 	s.Equal(int64(50), remainingLength)
 }
 
+func (s *JobHandlerForwardTest) Test_IngestHandlerForward_ResponseStats() {
+	since := time.Now()
+
+	for _, code := range []int{200, 404, 500, 503} {
+		record := s.createTestRecord()
+		record.DeploymentID = types.ID(790)
+		record.Analytics = nil
+		record.StatusCode = code
+		s.pushToQueue(record)
+	}
+
+	s.NoError(jobs.IngestHandlerForward(s.ctx))
+
+	stats, err := deploy.DeploymentResponseStats(s.ctx, types.ID(790), since)
+	s.NoError(err)
+	s.Equal(4, stats.Requests)
+	s.Equal(2, stats.Errors)
+}
+
 func (s *JobHandlerForwardTest) Test_IngestHandlerForward_RecordWithoutAnalytics() {
 	// Create record without analytics
 	record := s.createTestRecord()
@@ -171,6 +193,44 @@ func (s *JobHandlerForwardTest) Test_IngestHandlerForward_RecordWithoutLogs() {
 	s.Equal(int64(0), length)
 }
 
+func (s *JobHandlerForwardTest) Test_IngestHandlerForward_RedactsSecrets() {
+	env := s.MockEnv(nil, map[string]any{
+		"Data": &buildconf.BuildConf{
+			Vars: map[string]string{
+				"API_TOKEN": "my-api-token",
+				"NODE_ENV":  "production",
+			},
+			SecretVars: []string{"API_TOKEN"},
+		},
+	})
+
+	depl := s.MockDeployment(env)
+
+	s.pushToQueue(jobs.HostingRecord{
+		AppID:        env.AppID,
+		EnvID:        env.ID,
+		DeploymentID: depl.ID,
+		Logs: []integrations.Log{
+			{Timestamp: time.Now().Unix(), Level: "info", Message: "token=my-api-token env=production"},
+			{Timestamp: time.Now().Unix(), Level: "info", Message: "encoded=bXktYXBpLXRva2Vu"},
+		},
+	})
+
+	s.NoError(jobs.IngestHandlerForward(s.ctx))
+
+	logs, err := applog.NewStore().Logs(s.ctx, &applog.LogQuery{
+		AppID:        env.AppID,
+		DeploymentID: depl.ID,
+		Sort:         "asc",
+		Limit:        10,
+	})
+
+	s.NoError(err)
+	s.Len(logs, 2)
+	s.Equal("token=*** env=production", logs[0].Data)
+	s.Equal("encoded=***", logs[1].Data)
+}
+
 func TestJobHandlerForwardTest(t *testing.T) {
 	suite.Run(t, &JobHandlerForwardTest{})
 }
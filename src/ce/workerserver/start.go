@@ -5,11 +5,13 @@ import (
 
 	"github.com/hibiken/asynq"
 	"github.com/stormkit-io/stormkit-io/src/ce/api/admin"
+	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deployservice"
 	"github.com/stormkit-io/stormkit-io/src/lib/config"
 	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
 	"github.com/stormkit-io/stormkit-io/src/lib/tasks"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/mise"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
 )
 
 func scheduler() {
@@ -65,6 +67,8 @@ func Server(serverOpts ...ServerOpts) (*asynq.Server, *asynq.ServeMux) {
 		rediscache.EventMiseUpdate:           mise.AutoUpdate,
 		rediscache.EventInvalidateAdminCache: admin.ResetCache,
 		rediscache.EventRuntimesInstall:      admin.InstallDependencies,
+		rediscache.EventOSVUpdate:            osv.AutoUpdate,
+		rediscache.EventStopDeployment:       deployservice.StopLocalDeployment,
 	}
 
 	for event, handler := range handlers {
@@ -76,6 +80,7 @@ func Server(serverOpts ...ServerOpts) (*asynq.Server, *asynq.ServeMux) {
 	mux := asynq.NewServeMux()
 
 	mux.HandleFunc(tasks.DeploymentStart, HandleDeploymentStart)
+	mux.HandleFunc(tasks.DeploymentPublish, HandleDeploymentPublish)
 	mux.HandleFunc(tasks.TriggerFunctionHttp, HandleFunctionTrigger)
 
 	priority := 10
@@ -79,15 +79,20 @@ func (s *Scheduler) RegisterMasterTasks(ctx context.Context) {
 
 	tasks := []TaskDefinition{
 		{Handler: InvokeDueFunctionTriggers, Def: dj(EVERY_MINUTE), Opt: immediate},
+		{Handler: PublishScheduledDeployments, Def: dj(EVERY_MINUTE), Opt: immediate},
+		{Handler: DispatchQueuedDeployments, Def: dj(EVERY_MINUTE), Opt: immediate},
+		{Handler: WatchPublishedDeployments, Def: dj(EVERY_MINUTE), Opt: immediate},
 		{Handler: RemoveOldLogs, Def: dj(EVERY_HOUR * 2), Opt: immediate},
 		{Handler: RemoveStaleEnvironments, Def: dj(EVERY_6_HOURS), Opt: immediate},
 		{Handler: RemoveDeploymentArtifacts, Def: dj(EVERY_6_HOURS), Opt: immediate},
+		{Handler: RemoveUnreferencedBlobs, Def: dj(EVERY_6_HOURS), Opt: immediate},
 		{Handler: SyncAnalyticsVisitorsHourly, Def: dj(EVERY_MINUTE * 5), Opt: immediate},
 		{Handler: SyncAnalyticsVisitorsDaily, Def: daily, Opt: immediate},
 		{Handler: SyncAnalyticsReferrers, Def: dj(EVERY_HOUR), Opt: immediate},
 		{Handler: SyncAnalyticsByCountries, Def: dj(EVERY_HOUR), Opt: immediate},
 		{Handler: CleanupDeletedTeams, Def: dj(EVERY_HOUR), Opt: immediate},
 		{Handler: PingDomains, Def: dj(EVERY_MINUTE), Opt: immediate},
+		{Handler: PollGitRemotes, Def: dj(EVERY_MINUTE), Opt: immediate},
 	}
 
 	s.masterTasks = s.registerTasks(ctx, tasks)
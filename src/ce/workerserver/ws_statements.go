@@ -10,10 +10,8 @@ var (
 
 type statement struct {
 	markDeploymentsSoftDeleted      string
-	markDeploymentArtifactsDeleted  string
 	markStaleAppsAndEnvsSoftDeleted string
 	deleteStaleEnvironments         string
-	selectOldOrDeletedDeployments   string
 	removeOldLogs                   string
 	syncAnalyticsVisitors           string
 	syncAnalyticsReferrers          string
@@ -54,35 +52,6 @@ var stmt = &statement{
 			LIMIT 50
 	);`, tableEnvs, tableEnvs, tableDeploys),
 
-	selectOldOrDeletedDeployments: `
-		SELECT
-			d.deployment_id, d.app_id, d.storage_location,
-			d.function_location, d.api_location
-		FROM
-			deployments d
-		LEFT JOIN
-			deployments_published dp ON dp.deployment_id = d.deployment_id
-		WHERE
-			(
-				d.created_at < NOW() - INTERVAL '{{ .days }} days' OR 
-				d.deleted_at IS NOT NULL
-			)
-			AND
-			dp.deployment_id IS NULL AND
-			d.artifacts_deleted != TRUE
-		LIMIT $1;
-	`,
-
-	markDeploymentArtifactsDeleted: `
-		UPDATE
-			deployments
-		SET
-			deleted_at = COALESCE(deleted_at, NOW()),
-			artifacts_deleted = TRUE
-		WHERE
-			deployment_id = ANY($1);
-	`,
-
 	markStaleAppsAndEnvsSoftDeleted: `
 		WITH
 			updated_apps AS (
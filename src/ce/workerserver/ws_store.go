@@ -1,12 +1,8 @@
 package jobs
 
 import (
-	"bytes"
 	"context"
-	"text/template"
 
-	"github.com/lib/pq"
-	"github.com/stormkit-io/stormkit-io/src/ce/api/app/deploy"
 	"github.com/stormkit-io/stormkit-io/src/lib/database"
 	"github.com/stormkit-io/stormkit-io/src/lib/types"
 )
@@ -26,53 +22,6 @@ func (s *Store) RemoveOldLogs(ctx context.Context) error {
 	return err
 }
 
-// MarkDeploymentArtifactsDeleted marks the deployment and its artifacts as deleted.
-func (s *Store) MarkDeploymentArtifactsDeleted(ctx context.Context, ids []types.ID) error {
-	_, err := s.Exec(ctx, stmt.markDeploymentArtifactsDeleted, pq.Array(ids))
-	return err
-}
-
-// DeploymentsOlderThan30Days returns 100 deployments older than 30 days.
-// Returned deployments are not published.
-func (s *Store) DeploymentsOlderThan30Days(ctx context.Context, numberOfDays, limit int) ([]*deploy.Deployment, error) {
-	var wr bytes.Buffer
-	tmpl := template.Must(template.New("old_deployments").Parse(stmt.selectOldOrDeletedDeployments))
-
-	if err := tmpl.Execute(&wr, map[string]any{"days": numberOfDays}); err != nil {
-		return nil, err
-	}
-
-	rows, err := s.Query(ctx, wr.String(), limit)
-
-	if rows == nil || err != nil {
-		return nil, err
-	}
-
-	defer rows.Close()
-
-	var deploys []*deploy.Deployment
-
-	for rows.Next() {
-		if deploys == nil {
-			deploys = []*deploy.Deployment{}
-		}
-
-		d := &deploy.Deployment{}
-
-		err := rows.Scan(
-			&d.ID, &d.AppID, &d.StorageLocation, &d.FunctionLocation, &d.APILocation,
-		)
-
-		if err != nil {
-			return nil, err
-		}
-
-		deploys = append(deploys, d)
-	}
-
-	return deploys, nil
-}
-
 func (s *Store) UserIDsWithoutAPIKeys(ctx context.Context) ([]types.ID, error) {
 	rows, err := s.Query(ctx, stmt.selectUserIDsWithoutAPIKeys)
 
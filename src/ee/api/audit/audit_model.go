@@ -18,6 +18,9 @@ const (
 	CreateAction string = "CREATE"
 	UpdateAction string = "UPDATE"
 	DeleteAction string = "DELETE"
+
+	ApproveAction string = "APPROVE"
+	RejectAction  string = "REJECT"
 )
 
 const (
@@ -28,6 +31,8 @@ const (
 	TypeDomain   string = "DOMAIN"
 	TypeSnippet  string = "SNIPPET"
 	TypeAuthWall string = "AUTHWALL"
+
+	TypeDeployment string = "DEPLOYMENT"
 )
 
 type DiffFields struct {
@@ -55,6 +60,8 @@ type DiffFields struct {
 	AuthWallCreateLoginEmail string                 `json:"authWallCreateLoginEmail,omitempty"`
 	AuthWallCreateLoginID    string                 `json:"authWallCreateLoginId,omitempty"`
 	AuthWallDeleteLoginIDs   string                 `json:"authWallDeleteLoginIds,omitempty"`
+	DeploymentID             string                 `json:"deploymentId,omitempty"`
+	ApprovalComment          string                 `json:"approvalComment,omitempty"`
 }
 
 type Diff struct {
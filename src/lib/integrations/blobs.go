@@ -0,0 +1,118 @@
+package integrations
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/config"
+)
+
+// BlobsFolder is the folder under the app's storage root where
+// content-addressed artifacts are stored.
+const BlobsFolder = "blobs"
+
+// Blob is a client-side file that is stored by its content hash. Blobs are shared
+// across deployments of the same app, so identical files are uploaded only once.
+type Blob struct {
+	Hash     string // Hash is the content hash of the file, derived from the etag
+	FullPath string // FullPath is the absolute path to the file on disk
+}
+
+// NewBlob returns a new blob for the given file and etag.
+func NewBlob(fullPath, etag string) Blob {
+	return Blob{Hash: BlobHash(etag), FullPath: fullPath}
+}
+
+// BlobHash returns the content hash from the etag that is computed by the bundler.
+// For instance: `"20-2ef7bde608ce5404e97d5f042f95f89f1c232871"` => `2ef7bde608ce5404e97d5f042f95f89f1c232871`
+func BlobHash(etag string) string {
+	hash := strings.Trim(strings.TrimPrefix(etag, "W/"), `"`)
+
+	if i := strings.Index(hash, "-"); i > -1 {
+		hash = hash[i+1:]
+	}
+
+	return hash
+}
+
+// IsBlobLocation returns true when the storage location points to the
+// content-addressed storage rather than to a deployment specific folder.
+func IsBlobLocation(location string) bool {
+	return strings.HasSuffix(location, "/"+BlobsFolder)
+}
+
+// resolveBlob replaces the file name with the content hash when
+// the file is stored in the content-addressed storage.
+func (args GetFileArgs) resolveBlob() GetFileArgs {
+	if IsBlobLocation(args.Location) && args.ETag != "" {
+		args.FileName = BlobHash(args.ETag)
+	}
+
+	return args
+}
+
+// uploadBlobs uploads the blobs that do not exist in the storage yet. The overview
+// contains the total number of files and bytes that the deployment consists of, and
+// the hashes of the uploaded blobs, which are returned when the upload fails as well.
+func uploadBlobs(blobs []Blob, exists func(Blob) (bool, error), upload func(Blob, os.FileInfo) error) (UploadOverview, error) {
+	result := UploadOverview{}
+	uploaded := map[string]bool{}
+
+	var wg sync.WaitGroup
+	var mux sync.Mutex
+	var errs []error
+
+	semaphore := make(chan struct{}, max(config.Get().Runner.MaxGoRoutines, 1))
+
+	for _, blob := range blobs {
+		stat, err := os.Stat(blob.FullPath)
+
+		if err != nil {
+			return result, err
+		}
+
+		result.FilesUploaded = result.FilesUploaded + 1
+		result.BytesUploaded = result.BytesUploaded + stat.Size()
+
+		// The same content may be used by multiple files of the deployment
+		if blob.Hash == "" || uploaded[blob.Hash] {
+			continue
+		}
+
+		uploaded[blob.Hash] = true
+		semaphore <- struct{}{}
+		wg.Add(1)
+
+		go func(b Blob, info os.FileInfo) {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			ok, err := exists(b)
+
+			if err == nil && !ok {
+				err = upload(b, info)
+			}
+
+			mux.Lock()
+			defer mux.Unlock()
+
+			if err != nil {
+				errs = append(errs, err)
+			} else if ok {
+				result.FilesSkipped = result.FilesSkipped + 1
+			} else {
+				result.Blobs = append(result.Blobs, b.Hash)
+			}
+		}(blob, stat)
+	}
+
+	wg.Wait()
+
+	sort.Strings(result.Blobs)
+	return result, errors.Join(errs...)
+}
@@ -0,0 +1,29 @@
+package integrations_test
+
+import (
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
+	"github.com/stretchr/testify/suite"
+)
+
+type BlobsSuite struct {
+	suite.Suite
+}
+
+func (s *BlobsSuite) Test_BlobHash() {
+	s.Equal("2ef7bde608ce5404e97d5f042f95f89f1c232871", integrations.BlobHash(`"20-2ef7bde608ce5404e97d5f042f95f89f1c232871"`))
+	s.Equal("2ef7bde608ce5404e97d5f042f95f89f1c232871", integrations.BlobHash(`W/"20-2ef7bde608ce5404e97d5f042f95f89f1c232871"`))
+	s.Equal("", integrations.BlobHash(""))
+}
+
+func (s *BlobsSuite) Test_IsBlobLocation() {
+	s.True(integrations.IsBlobLocation("aws:my-bucket/1/blobs"))
+	s.True(integrations.IsBlobLocation("local:/tmp/storage/1/blobs"))
+	s.False(integrations.IsBlobLocation("aws:my-bucket/1/15/sk-client.zip"))
+	s.False(integrations.IsBlobLocation("local:/tmp/storage/deployment-15/client"))
+}
+
+func TestBlobs(t *testing.T) {
+	suite.Run(t, &BlobsSuite{})
+}
@@ -15,6 +15,7 @@ import (
 type GetFileArgs struct {
 	Location     string
 	FileName     string
+	ETag         string // ETag is used to resolve the file from the content-addressed storage
 	DeploymentID types.ID
 }
 
@@ -35,11 +36,19 @@ type InvokeArgs struct {
 	CaptureLogs  bool              // Whether to tell the handlers to capture logs
 	EnvVariables map[string]string // This is required for server actions
 	IsPublished  bool              // Whether the deployment is published or not
+	FullTraffic  bool              // Whether the deployment receives all the traffic of the environment
 	AppID        types.ID
 	EnvID        types.ID
 	DeploymentID types.ID
 	Context      map[string]any // Additional context to pass to the function
 	QueueLog     func(*Log)     // Queue logs for later processing
+
+	HealthCheck   *HealthCheck   // Probes the service started by the process manager, if provided
+	RestartPolicy *RestartPolicy // Restarts the service started by the process manager when it crashes, if provided
+	Replicas      *Replicas      // Spawns multiple processes for the service started by the process manager, if provided
+	Resources     *Resources     // Limits the resources of the service started by the process manager, if provided
+	Replica       int            // The index of the replica that handles the request, set by the process manager
+	Worker        string         // The name of the worker process type, set for background workers
 }
 
 type Log struct {
@@ -84,6 +93,7 @@ type DeleteArtifactsArgs struct {
 	APILocation      string
 	FunctionLocation string
 	StorageLocation  string
+	Blobs            []string // Blobs are the hashes to delete when the storage location is content-addressed
 }
 
 type ClientInterface interface {
@@ -131,7 +131,7 @@ func (a AlibabaClient) Upload(args UploadArgs) (*UploadResult, error) {
 	var result *UploadResult
 	var err error
 
-	if args.ClientZip != "" {
+	if args.ClientZip != "" || len(args.ClientBlobs) > 0 {
 		result, err = a.awsClient.Upload(args)
 
 		if err != nil || result == nil {
@@ -200,7 +200,15 @@ func (a AlibabaClient) DeleteArtifacts(ctx context.Context, args DeleteArtifacts
 		}
 	}
 
-	if args.StorageLocation != "" {
+	if IsBlobLocation(args.StorageLocation) {
+		// Content-addressed files are shared across deployments, therefore
+		// only the given blobs are removed.
+		bucketName, keyPrefix := a.awsClient.parseS3Location(strings.TrimPrefix(args.StorageLocation, "alibaba:"))
+
+		if err := a.awsClient.deleteS3Blobs(ctx, bucketName, keyPrefix, args.Blobs); err != nil {
+			return err
+		}
+	} else if args.StorageLocation != "" {
 		// alibaba:<bucket-name>/<app-id>/<deployment-id>
 		location := strings.TrimPrefix(args.StorageLocation, "alibaba:")
 
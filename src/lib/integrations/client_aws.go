@@ -120,7 +120,11 @@ func (c *AWSClient) Upload(args UploadArgs) (*UploadResult, error) {
 	var err error
 	result := &UploadResult{}
 
-	if args.ClientZip != "" {
+	if len(args.ClientBlobs) > 0 {
+		if result.Client, err = c.uploadBlobsToS3(args); err != nil {
+			return result, err
+		}
+	} else if args.ClientZip != "" {
 		if result.Client, err = c.uploadZipToS3(args.ClientZip, args); err != nil {
 			return nil, err
 		}
@@ -189,7 +193,15 @@ func (a *AWSClient) DeleteArtifacts(ctx context.Context, args DeleteArtifactsArg
 		}
 	}
 
-	if args.StorageLocation != "" {
+	if IsBlobLocation(args.StorageLocation) {
+		// Content-addressed files are shared across deployments, therefore
+		// only the given blobs are removed.
+		bucketName, keyPrefix := a.parseS3Location(args.StorageLocation)
+
+		if err := a.deleteS3Blobs(ctx, bucketName, keyPrefix, args.Blobs); err != nil {
+			return err
+		}
+	} else if args.StorageLocation != "" {
 		// aws:<bucket-name>/<app-id>/<deployment-id>
 		location := strings.TrimPrefix(args.StorageLocation, "aws:")
 
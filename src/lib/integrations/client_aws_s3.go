@@ -146,10 +146,22 @@ func (a *AWSClient) GetFile(args GetFileArgs) (*GetFileResult, error) {
 		return a.serveFromZip(args)
 	}
 
+	// Blobs are shared across files with the same content, the content type
+	// stored with the object belongs to the file that uploaded it first.
+	fileName := args.FileName
+	isBlob := IsBlobLocation(args.Location)
+	args = args.resolveBlob()
+
 	// This is required to make old-style locations work.
 	args.Location = path.Join(args.Location, args.FileName)
 
-	return a.getFile(args)
+	result, err := a.getFile(args)
+
+	if result != nil && isBlob {
+		result.ContentType = DetectContentType(fileName, result.Content)
+	}
+
+	return result, err
 }
 
 func (a *AWSClient) bucketName(args UploadArgs) string {
@@ -210,6 +222,59 @@ func (a *AWSClient) uploadZipToS3(pathToZip string, args UploadArgs) (UploadOver
 	return result, err
 }
 
+// uploadBlobsToS3 uploads the client files which do not exist yet in the bucket.
+// Blobs are stored under the <app-id>/blobs/<hash> key.
+func (a *AWSClient) uploadBlobsToS3(args UploadArgs) (UploadOverview, error) {
+	keyPrefix := fmt.Sprintf("%d/%s", args.AppID, BlobsFolder)
+	bucketName := a.bucketName(args)
+
+	result, err := uploadBlobs(
+		args.ClientBlobs,
+		func(b Blob) (bool, error) {
+			_, err := a.S3Client.HeadObject(context.Background(), &s3.HeadObjectInput{
+				Bucket: aws.String(bucketName),
+				Key:    aws.String(path.Join(keyPrefix, b.Hash)),
+			})
+
+			if err != nil {
+				var nf *s3types.NotFound
+
+				if errors.As(err, &nf) {
+					return false, nil
+				}
+
+				return false, err
+			}
+
+			return true, nil
+		},
+		func(b Blob, info os.FileInfo) error {
+			f, err := os.Open(b.FullPath)
+
+			if err != nil {
+				return err
+			}
+
+			defer f.Close()
+
+			return a.UploadFile(File{
+				Pointer:      f,
+				Size:         info.Size(),
+				RelativePath: b.Hash,
+				ContentType:  DetectContentType(b.FullPath, nil),
+			}, S3Args{
+				BucketName: bucketName,
+				KeyPrefix:  keyPrefix,
+				ACL:        s3types.ObjectCannedACLPrivate,
+			})
+		},
+	)
+
+	// The location is returned on failure as well, so that the uploaded blobs are tracked.
+	result.Location = fmt.Sprintf("aws:%s/%s", bucketName, keyPrefix)
+	return result, err
+}
+
 // UploadFile uploads a single file to S3 destination.
 func (a *AWSClient) UploadFile(file File, s3args any) error {
 	opts := s3args.(S3Args)
@@ -278,6 +343,34 @@ func (a *AWSClient) deleteS3Folder(ctx context.Context, bucketName, keyPrefix st
 	return err
 }
 
+// deleteS3Blobs deletes the given blobs from the bucket in batches.
+func (a *AWSClient) deleteS3Blobs(ctx context.Context, bucketName, keyPrefix string, hashes []string) error {
+	// DeleteObjects accepts at most 1000 keys per request
+	for start := 0; start < len(hashes); start += 1000 {
+		objects := []s3types.ObjectIdentifier{}
+
+		for _, hash := range hashes[start:min(start+1000, len(hashes))] {
+			objects = append(objects, s3types.ObjectIdentifier{
+				Key: aws.String(path.Join(keyPrefix, hash)),
+			})
+		}
+
+		_, err := a.S3Client.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucketName),
+			Delete: &s3types.Delete{
+				Objects: objects,
+				Quiet:   aws.Bool(true),
+			},
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // parseS3Location parses a string in the following format:
 // aws:/bucket-name/path-to-file
 func (a *AWSClient) parseS3Location(location string) (string, string) {
@@ -191,6 +191,59 @@ func (s *AwsS3Suite) Test_GetFile() {
 	s.Equal("text/html; charset=utf-8", result.ContentType)
 }
 
+func (s *AwsS3Suite) Test_GetFile_Blob() {
+	aws, err := integrations.AWS(integrations.ClientArgs{
+		SessionToken: "my-session",
+		AccessKey:    "my-access-key",
+		SecretKey:    "my-secret-key",
+		Middlewares: []func(stack *middleware.Stack) error{
+			func(stack *middleware.Stack) error {
+				return stack.Initialize.Add(
+					middleware.InitializeMiddlewareFunc("GetObject", func(ctx context.Context, fi middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+						switch v := fi.Parameters.(type) {
+						case *s3.GetObjectInput:
+							s.Equal("my-s3-bucket", *v.Bucket)
+							s.Equal("1/blobs/2ef7bde608ce5404e97d5f042f95f89f1c232871", *v.Key)
+						default:
+							s.NoError(errors.New("unknown call"))
+						}
+
+						return next.HandleInitialize(ctx, fi)
+					}),
+					middleware.Before,
+				)
+			},
+			func(stack *middleware.Stack) error {
+				return stack.Finalize.Add(
+					middleware.FinalizeMiddlewareFunc("GetObject", func(ctx context.Context, fi middleware.FinalizeInput, fh middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+						// The blob was first uploaded by a file with a different extension.
+						return middleware.FinalizeOutput{
+							Result: &s3.GetObjectOutput{
+								Body:          io.NopCloser(bytes.NewReader([]byte("Hello world"))),
+								ContentType:   aws.String("text/plain; charset=utf-8"),
+								ContentLength: aws.Int64(int64(len("Hello world"))),
+							},
+						}, middleware.Metadata{}, nil
+					}),
+					middleware.Before,
+				)
+			},
+		},
+	}, nil)
+
+	s.NoError(err)
+
+	result, err := aws.GetFile(integrations.GetFileArgs{
+		Location: "aws:my-s3-bucket/1/blobs",
+		FileName: "/index.html",
+		ETag:     `"20-2ef7bde608ce5404e97d5f042f95f89f1c232871"`,
+	})
+
+	s.NoError(err)
+	s.Equal("Hello world", string(result.Content))
+	s.Equal("text/html; charset=utf-8", result.ContentType)
+}
+
 func (s *AwsS3Suite) Test_ZipDownloader() {
 	zip, err := os.ReadFile(path.Join(s.tmpdir, "sk-client.zip"))
 	s.NoError(err)
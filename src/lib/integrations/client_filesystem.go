@@ -52,6 +52,19 @@ func (c *FilesysClient) ProcessManager() *ProcessManager {
 	return c.pm
 }
 
+// Swap pre-warms the service of a published deployment, see ProcessManager.Swap.
+func (c *FilesysClient) Swap(args InvokeArgs) error {
+	fnPath, _ := c.parseFunctionLocation(args.ARN)
+	return c.ProcessManager().Swap(args, path.Dir(fnPath))
+}
+
+// StartWorker starts a background worker of a published deployment, see ProcessManager.StartWorker.
+// The ARN is the function location of the deployment, which is used to resolve the working directory.
+func (c *FilesysClient) StartWorker(args InvokeArgs) error {
+	fnPath, _ := c.parseFunctionLocation(args.ARN)
+	return c.ProcessManager().StartWorker(args, path.Dir(fnPath))
+}
+
 func (c *FilesysClient) Invoke(args InvokeArgs) (*InvokeResult, error) {
 	fnPath, fnHandler := c.parseFunctionLocation(args.ARN)
 
@@ -134,6 +147,18 @@ func (c *FilesysClient) DeleteArtifacts(ctx context.Context, args DeleteArtifact
 	// <path>/deployment-29/client
 	//
 	// To delete artifacts, it's enough to delete the parent folder.
+	// Content-addressed files are shared across deployments, therefore
+	// only the given blobs are removed from the blobs folder.
+	if IsBlobLocation(args.StorageLocation) {
+		for _, hash := range args.Blobs {
+			if err := os.Remove(path.Join(strings.TrimPrefix(args.StorageLocation, "local:"), hash)); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+
+		args.StorageLocation = ""
+	}
+
 	location := utils.GetString(args.StorageLocation, args.FunctionLocation, args.APILocation)
 
 	// Nothing to delete
@@ -159,7 +184,11 @@ func (c *FilesysClient) Upload(args UploadArgs) (*UploadResult, error) {
 	depl := fmt.Sprintf("deployment-%d", args.DeploymentID)
 	root := path.Join(dir, depl)
 
-	if args.ClientZip != "" {
+	if len(args.ClientBlobs) > 0 {
+		if result.Client, err = c.uploadBlobs(args, path.Join(dir, args.AppID.String(), BlobsFolder)); err != nil {
+			return result, err
+		}
+	} else if args.ClientZip != "" {
 		copy := args
 		copy.zip = args.ClientZip
 		copy.handler = ""
@@ -194,7 +223,14 @@ func (c *FilesysClient) Upload(args UploadArgs) (*UploadResult, error) {
 
 // GetFile returns a file from the Filesystem.
 func (c *FilesysClient) GetFile(args GetFileArgs) (*GetFileResult, error) {
+	// Blobs do not have an extension, use the original file name to detect the content type
+	contentTypePath := args.FileName
+	args = args.resolveBlob()
 	filePath := path.Join(strings.TrimPrefix(args.Location, "local:"), args.FileName)
+
+	if !IsBlobLocation(args.Location) {
+		contentTypePath = filePath
+	}
 	stat, err := os.Stat(filePath)
 
 	if os.IsNotExist(err) {
@@ -212,7 +248,7 @@ func (c *FilesysClient) GetFile(args GetFileArgs) (*GetFileResult, error) {
 	}
 
 	return &GetFileResult{
-		ContentType: DetectContentType(filePath, data),
+		ContentType: DetectContentType(contentTypePath, data),
 		Size:        stat.Size(),
 		Content:     data,
 	}, nil
@@ -250,6 +286,35 @@ func (c *FilesysClient) uploadZip(args UploadArgs, to string) (UploadOverview, e
 	}, nil
 }
 
+// uploadBlobs copies the client files which do not exist yet into the blobs folder.
+func (c *FilesysClient) uploadBlobs(args UploadArgs, to string) (UploadOverview, error) {
+	if err := os.MkdirAll(to, 0774); err != nil {
+		return UploadOverview{}, err
+	}
+
+	result, err := uploadBlobs(
+		args.ClientBlobs,
+		func(b Blob) (bool, error) {
+			return file.Exists(path.Join(to, b.Hash)), nil
+		},
+		func(b Blob, info os.FileInfo) error {
+			// Write to a temporary file first, so that partially copied
+			// blobs are never considered as existing.
+			tmp := path.Join(to, fmt.Sprintf(".%s-%d", b.Hash, args.DeploymentID))
+
+			if err := file.Copy(b.FullPath, tmp, 0664); err != nil {
+				return err
+			}
+
+			return os.Rename(tmp, path.Join(to, b.Hash))
+		},
+	)
+
+	// The location is returned on failure as well, so that the uploaded blobs are tracked.
+	result.Location = fmt.Sprintf("local:%s", to)
+	return result, err
+}
+
 func (c *FilesysClient) parseFunctionLocation(location string) (string, string) {
 	pieces := strings.Split(strings.TrimPrefix(location, "local:"), ":")
 
@@ -78,6 +78,66 @@ func (s *FilesysSuite) Test_DeleteArtifacts() {
 	s.NoDirExists(s.tmpdir)
 }
 
+func (s *FilesysSuite) Test_Upload_Blobs() {
+	client := integrations.Filesys()
+	dist := path.Join(s.tmpdir, "dist")
+	index := path.Join(s.tmpdir, "client", "index.html")
+	about := path.Join(s.tmpdir, "client", "about.html")
+
+	s.NoError(os.WriteFile(about, []byte("About us"), 0664))
+
+	args := integrations.UploadArgs{
+		DistDir:      dist,
+		AppID:        232,
+		DeploymentID: 50919,
+		ClientBlobs: []integrations.Blob{
+			integrations.NewBlob(index, `"20-aaa"`),
+			integrations.NewBlob(about, `"20-bbb"`),
+		},
+	}
+
+	result, err := client.Upload(args)
+	s.NoError(err)
+	s.Equal(int64(2), result.Client.FilesUploaded)
+	s.Equal(int64(0), result.Client.FilesSkipped)
+	s.Equal(int64(19), result.Client.BytesUploaded)
+	s.Equal([]string{"aaa", "bbb"}, result.Client.Blobs)
+	s.Equal(fmt.Sprintf("local:%s/232/blobs", dist), result.Client.Location)
+	s.FileExists(path.Join(dist, "232", "blobs", "aaa"))
+
+	// The next deployment only uploads the new blob
+	contact := path.Join(s.tmpdir, "client", "contact.html")
+	s.NoError(os.WriteFile(contact, []byte("Contact"), 0664))
+
+	args.DeploymentID = 50920
+	args.ClientBlobs = append(args.ClientBlobs, integrations.NewBlob(contact, `"20-ccc"`))
+
+	result, err = client.Upload(args)
+	s.NoError(err)
+	s.Equal(int64(3), result.Client.FilesUploaded)
+	s.Equal(int64(2), result.Client.FilesSkipped)
+	s.Equal([]string{"ccc"}, result.Client.Blobs)
+
+	file, err := client.GetFile(integrations.GetFileArgs{
+		Location: result.Client.Location,
+		FileName: "/contact.html",
+		ETag:     `"20-ccc"`,
+	})
+
+	s.NoError(err)
+	s.Equal("Contact", string(file.Content))
+	s.Equal("text/html; charset=utf-8", file.ContentType)
+
+	// Deleting artifacts only removes the given blobs
+	s.NoError(client.DeleteArtifacts(context.Background(), integrations.DeleteArtifactsArgs{
+		StorageLocation: result.Client.Location,
+		Blobs:           []string{"ccc"},
+	}))
+
+	s.NoFileExists(path.Join(dist, "232", "blobs", "ccc"))
+	s.FileExists(path.Join(dist, "232", "blobs", "aaa"))
+}
+
 func (s *FilesysSuite) Test_GetFile() {
 	client := integrations.Filesys()
 	filePath := path.Join(s.tmpdir, "client", "index.html")
@@ -2,6 +2,7 @@ package integrations
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -11,8 +12,10 @@ import (
 	"os"
 	"os/exec"
 	"path"
+	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -21,6 +24,7 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
 	"github.com/stormkit-io/stormkit-io/src/lib/shutdown"
 	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/file"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/sys"
@@ -38,6 +42,10 @@ type ProcessManager struct {
 	services      map[string]*Service
 	waitGroup     map[string]*sync.WaitGroup
 	customPortMap map[int]*Service
+	statusHook    func(ServiceStatus)
+	nextReplica   map[string]int      // The next replica that receives a request, keyed by the function ARN
+	swaps         map[string]*Service // The services that serve the requests of pre-warming services, keyed by their ARN
+	replicaMux    sync.Mutex
 }
 
 type Service struct {
@@ -51,14 +59,31 @@ type Service struct {
 	serverConfig *ServerConfig
 	filePointer  int64
 	port         int
-	isCustomPort bool // Whether the service is using a custom port from environment variables
-	maxIdle      int  // The max idle time in minutes
-	killed       bool // Whether the service has been killed
-	started      bool // Whether the service has been started
-	isSettingUp  bool // Whether the service is currently setting up (running setup script)
+	isCustomPort bool         // Whether the service is using a custom port from environment variables
+	maxIdle      int          // The max idle time in minutes
+	killed       atomic.Bool  // Whether the service has been killed
+	started      atomic.Bool  // Whether the service has been started
+	isSettingUp  atomic.Bool  // Whether the service is currently setting up (running setup script)
+	active       atomic.Int64 // The number of requests that are being processed
+	cgroup       atomic.Bool  // Whether the processes of the service are placed in a cgroup
+	oomKills     int64        // The number of processes of the service that were killed due to memory limits
+
+	statusMux           sync.Mutex
+	state               string    // The state of the service, see ServiceState constants
+	healthy             bool      // Whether the service passed its last health checks
+	exited              bool      // Whether the process exited at least once
+	lastExitCode        int       // The exit code of the last process
+	lastError           string    // The reason of the last restart
+	restarts            int       // The total number of restarts
+	consecutiveRestarts int       // The number of restarts within the crash loop window
+	startedAt           time.Time // The time the last process started
+	lastRestartAt       time.Time // The time of the last restart
 }
 
 func (s *Service) Pid() int {
+	s.statusMux.Lock()
+	defer s.statusMux.Unlock()
+
 	if s.cmd != nil && s.cmd.Process != nil {
 		return s.cmd.Process.Pid
 	}
@@ -67,16 +92,25 @@ func (s *Service) Pid() int {
 }
 
 func (s *Service) Kill() {
-	if s.cmd == nil || s.killed {
+	// The service is marked as killed under the same lock that run holds while starting
+	// a process, so that a process is either not started or killed here.
+	// Services that are not started yet, for instance while their setup script is
+	// running, are marked as killed as well so that their process is never started.
+	s.statusMux.Lock()
+	cmd := s.cmd
+	killed := !s.killed.CompareAndSwap(false, true)
+	s.statusMux.Unlock()
+
+	if killed {
 		slog.Debug(slog.LogOpts{
-			Msg:     "service is already killed or not started yet",
+			Msg:     "service is already killed",
 			Level:   slog.DL2,
 			Payload: []zap.Field{zap.String("arn", s.arn)},
 		})
 		return
 	}
 
-	if s.serverConfig != nil && s.serverConfig.Stop != nil {
+	if cmd != nil && s.serverConfig != nil && s.serverConfig.Stop != nil {
 		for _, script := range s.serverConfig.Stop {
 			slog.Debug(slog.LogOpts{
 				Msg:     "running stop script for service",
@@ -84,21 +118,23 @@ func (s *Service) Kill() {
 				Payload: []zap.Field{zap.String("arn", s.arn)},
 			})
 
-			cmd := sys.Command(s.ctx, sys.CommandOpts{
+			stop := sys.Command(s.ctx, sys.CommandOpts{
 				String: script,
-				Dir:    s.cmd.Dir,
-				Env:    s.cmd.Env,
-				Stdout: s.cmd.Stdout,
-				Stderr: s.cmd.Stderr,
+				Dir:    cmd.Dir,
+				Env:    cmd.Env,
+				Stdout: cmd.Stdout,
+				Stderr: cmd.Stderr,
+				// Stop scripts run as the same user as the service.
+				SysProcAttr: &syscall.SysProcAttr{Credential: s.credential()},
 			})
 
-			if err := cmd.Run(); err != nil {
+			if err := stop.Run(); err != nil {
 				slog.Errorf("error while running stop script: %s", err.Error())
 			}
 		}
 
-		if s.cmd != nil && !utils.IsPortInUse(s.port) {
-			s.cmd.Process = nil
+		if s.port == 0 || !utils.IsPortInUse(s.port) {
+			cmd.Process = nil
 		}
 
 		slog.Debug(slog.LogOpts{
@@ -108,17 +144,17 @@ func (s *Service) Kill() {
 		})
 	}
 
-	if s.cmd.Process != nil {
+	if cmd != nil && cmd.Process != nil {
 		slog.Debug(slog.LogOpts{
 			Msg:   "killing service",
 			Level: slog.DL2,
 			Payload: []zap.Field{
 				zap.String("arn", s.arn),
-				zap.Int("pid", s.cmd.Process.Pid),
+				zap.Int("pid", cmd.Process.Pid),
 			},
 		})
 
-		pgid, err := syscall.Getpgid(s.cmd.Process.Pid)
+		pgid, err := syscall.Getpgid(cmd.Process.Pid)
 
 		// Stop children processes
 		if err == nil {
@@ -145,10 +181,16 @@ func (s *Service) Kill() {
 	s.pm.mux.Lock()
 	delete(s.pm.services, s.arn)
 	delete(s.pm.waitGroup, s.arn)
-	delete(s.pm.customPortMap, s.port)
+
+	// The port may have been handed off to the service of a new deployment.
+	if s.pm.customPortMap[s.port] == s {
+		delete(s.pm.customPortMap, s.port)
+	}
+
 	s.pm.mux.Unlock()
 
-	s.killed = true
+	s.removeCgroup()
+	s.setState(ServiceStateStopped)
 }
 
 func (s *Service) processLogs(input io.ReadSeeker, start int64) error {
@@ -205,6 +247,12 @@ func (s *Service) logger() {
 				slog.Errorf("error while processing logs: %s", err.Error())
 			}
 
+			// Long running services, such as workers, are not bound to a request context.
+			if s.killed.Load() {
+				input.Close()
+				return
+			}
+
 			time.Sleep(1 * time.Second) // Simulate work with a sleep
 		}
 	}
@@ -215,6 +263,8 @@ func NewProcessManager() *ProcessManager {
 		services:      map[string]*Service{},
 		waitGroup:     map[string]*sync.WaitGroup{},
 		customPortMap: map[int]*Service{},
+		nextReplica:   map[string]int{},
+		swaps:         map[string]*Service{},
 	}
 
 	shutdown.Subscribe(pm.KillAll)
@@ -227,10 +277,17 @@ func (pm *ProcessManager) QueueLog(args *InvokeArgs, data string) {
 		return
 	}
 
-	args.QueueLog(&Log{
+	log := &Log{
 		Timestamp: time.Now().UTC().Unix(),
 		Message:   data,
-	})
+	}
+
+	// Worker logs are labeled so that they can be told apart from the server logs.
+	if args.Worker != "" {
+		log.Level = WorkerLabel(args.Worker)
+	}
+
+	args.QueueLog(log)
 }
 
 func (pm *ProcessManager) hasSetupScript(workDir string) bool {
@@ -243,6 +300,7 @@ type RunSetupScriptArgs struct {
 	Vars       []string
 	LogFile    *os.File
 	Config     *ServerConfig
+	Credential *syscall.Credential // The user the setup script runs as, nil for the current user
 }
 
 // runSetupScript runs the setup script if it exists in the given work directory.
@@ -264,6 +322,7 @@ func (pm *ProcessManager) runSetupScript(ctx context.Context, args RunSetupScrip
 			String: os.Expand(script, func(name string) string {
 				return args.InvokeArgs.EnvVariables[name]
 			}),
+			SysProcAttr: &syscall.SysProcAttr{Credential: args.Credential},
 		})
 
 		if err := cmd.Run(); err != nil {
@@ -290,7 +349,23 @@ func (pm *ProcessManager) runSetupScript(ctx context.Context, args RunSetupScrip
 // If the command fails to start, it returns an error.
 // The service is automatically killed when the context is canceled or when the command finishes.
 func (pm *ProcessManager) Start(ctx context.Context, args *InvokeArgs, workDir string) (*Service, error) {
-	outfile, err := os.Create(path.Join(os.TempDir(), fmt.Sprintf("logs-d-%s.txt", args.DeploymentID.String())))
+	return pm.start(ctx, args, workDir, nil)
+}
+
+// start starts the service. When handoff is provided, it is the service that holds the custom
+// port of the new service: it keeps running until the setup script of the new service finishes.
+func (pm *ProcessManager) start(ctx context.Context, args *InvokeArgs, workDir string, handoff *Service) (*Service, error) {
+	logFile := fmt.Sprintf("logs-d-%s.txt", args.DeploymentID.String())
+
+	if args.Replica > 0 {
+		logFile = fmt.Sprintf("logs-d-%s-%d.txt", args.DeploymentID.String(), args.Replica)
+	}
+
+	if args.Worker != "" {
+		logFile = fmt.Sprintf("logs-d-%s-%s.txt", args.DeploymentID.String(), args.Worker)
+	}
+
+	outfile, err := os.Create(path.Join(os.TempDir(), logFile))
 
 	if err != nil {
 		slog.Errorf("cannot open log file: %s", err.Error())
@@ -301,17 +376,30 @@ func (pm *ProcessManager) Start(ctx context.Context, args *InvokeArgs, workDir s
 		return nil, fmt.Errorf("custom ports are only available for published deployments, please remove the PORT environment variable to use dynamic ports")
 	}
 
-	port, err := findAvailablePort(args)
+	port := 0
+	maxIdleInMinutes := 0
 
-	if err != nil {
-		return nil, fmt.Errorf("cannot find an available port: %s", err.Error())
+	// Workers do not serve requests, so they neither listen to a port nor become idle.
+	if args.Worker == "" {
+		port, err = findAvailablePort(args)
+
+		if err != nil {
+			return nil, fmt.Errorf("cannot find an available port: %s", err.Error())
+		}
+
+		maxIdleInMinutes = 10
+
+		if maxIdle, ok := args.EnvVariables["STORMKIT_MAX_IDLE"]; ok {
+			maxIdleInMinutes = utils.StringToInt(maxIdle)
+		}
 	}
 
+	venv := path.Join(workDir, ".venv")
 	vars := prepareEnvironmentVariables(args, port)
-	maxIdleInMinutes := 10
+	vars = preparePythonEnvironmentVariables(vars, venv)
 
-	if maxIdle, ok := args.EnvVariables["STORMKIT_MAX_IDLE"]; ok {
-		maxIdleInMinutes = utils.StringToInt(maxIdle)
+	if err := relocatePythonVirtualEnv(venv); err != nil {
+		return nil, err
 	}
 
 	service := &Service{
@@ -322,7 +410,8 @@ func (pm *ProcessManager) Start(ctx context.Context, args *InvokeArgs, workDir s
 		ctx:          ctx,
 		args:         args,
 		maxIdle:      maxIdleInMinutes,
-		isCustomPort: args.EnvVariables["PORT"] != "",
+		isCustomPort: args.Worker == "" && args.EnvVariables["PORT"] != "",
+		healthy:      true,
 	}
 
 	if service.isCustomPort {
@@ -337,7 +426,7 @@ func (pm *ProcessManager) Start(ctx context.Context, args *InvokeArgs, workDir s
 		pm.mux.Unlock()
 
 		// Kill the previous service on the same port if it exists.
-		if prev != nil && prev.arn != service.arn {
+		if prev != nil && prev.arn != service.arn && prev != handoff {
 			slog.Debug(slog.LogOpts{
 				Msg:   "found previous service on the same port, killing it",
 				Level: slog.DL2,
@@ -368,7 +457,7 @@ func (pm *ProcessManager) Start(ctx context.Context, args *InvokeArgs, workDir s
 		}
 
 		service.serverConfig = &config
-		service.isSettingUp = true
+		service.isSettingUp.Store(true)
 
 		if config.WorkDir != "" {
 			workDir = path.Join(workDir, config.WorkDir)
@@ -380,6 +469,8 @@ func (pm *ProcessManager) Start(ctx context.Context, args *InvokeArgs, workDir s
 		}
 	}
 
+	vars = service.isolate(path.Dir(lockFile), vars)
+
 	go func(s *Service) {
 		if s.serverConfig != nil && !file.Exists(lockFile) {
 			// We need a different context because the request context is canceled as soon as the response is sent.
@@ -390,9 +481,10 @@ func (pm *ProcessManager) Start(ctx context.Context, args *InvokeArgs, workDir s
 				WorkDir:    workDir,
 				LogFile:    s.file,
 				Config:     s.serverConfig,
+				Credential: s.credential(),
 			})
 
-			s.isSettingUp = false
+			s.isSettingUp.Store(false)
 
 			slog.Debug(slog.LogOpts{
 				Msg:     "finished running setup script for service",
@@ -410,50 +502,26 @@ func (pm *ProcessManager) Start(ctx context.Context, args *InvokeArgs, workDir s
 			}
 		}
 
-		s.isSettingUp = false
+		s.isSettingUp.Store(false)
 
-		service.cmd = sys.Command(ctx, sys.CommandOpts{
-			String:      args.Command,
-			Dir:         workDir,
-			Env:         vars,
-			Stdout:      outfile,
-			Stderr:      outfile,
-			SysProcAttr: &syscall.SysProcAttr{Setpgid: true},
-		}).Cmd()
-
-		if err := s.cmd.Start(); err != nil {
-			pm.QueueLog(args, err.Error())
-			return
+		if handoff != nil {
+			s.handOff(handoff)
 		}
 
-		s.started = true
-		slog.Debug(slog.LogOpts{
-			Msg:   "service started",
-			Level: slog.DL2,
-			Payload: []zap.Field{
-				zap.String("arn", s.arn),
-				zap.Int("port", s.port),
-			},
-		})
+		for {
+			exitCode, err := s.run(ctx, workDir, vars, venv)
 
-		// Ignore error here: it could be related to spawning background processes and
-		// there is no easy way to understand if the cmd is a background process or not
-		if err := s.cmd.Wait(); err != nil {
-			slog.Errorf("error while waiting for service to finish, arn: %s, err: %s", s.arn, err.Error())
-		} else {
-			slog.Debug(slog.LogOpts{
-				Msg:   "service finished successfully",
-				Level: slog.DL2,
-				Payload: []zap.Field{
-					zap.String("arn", s.arn),
-					zap.Int("pid", service.Pid()),
-				},
-			})
-		}
+			if err != nil {
+				pm.QueueLog(args, err.Error())
+				return
+			}
+
+			// Check if the port is still in use after the service has finished. Background
+			// processes may still be serving requests, in which case the service is kept.
+			if s.killed.Load() || (s.port != 0 && utils.IsPortInUse(s.port)) {
+				return
+			}
 
-		// Check if the port is still in use after the service has finished and kill service
-		// if the port is not in use anymore.
-		if !utils.IsPortInUse(s.port) {
 			slog.Debug(slog.LogOpts{
 				Msg:   "service finished and port is not in use anymore",
 				Level: slog.DL2,
@@ -462,10 +530,41 @@ func (pm *ProcessManager) Start(ctx context.Context, args *InvokeArgs, workDir s
 					zap.Int("port", s.port),
 				},
 			})
-			s.Kill()
+
+			delay, state := s.nextRestart(exitCode)
+
+			if state == ServiceStateStopped {
+				if args.Worker != "" {
+					s.markWorkerExited()
+				}
+
+				s.Kill()
+				return
+			}
+
+			s.started.Store(false)
+			s.setState(state)
+
+			// Crash looping services are kept so that requests receive an error page
+			// until the service is removed due to inactivity or a new deployment.
+			if state == ServiceStateCrashLoop {
+				pm.QueueLog(args, fmt.Sprintf("service restarted %d times in a row, giving up", s.Status().Restarts))
+				return
+			}
+
+			pm.QueueLog(args, fmt.Sprintf("restarting service in %s", delay))
+			time.Sleep(delay)
+
+			if s.killed.Load() {
+				return
+			}
 		}
 	}(service)
 
+	if args.HealthCheck != nil {
+		go service.probe()
+	}
+
 	if args.CaptureLogs {
 		go service.logger()
 	}
@@ -473,13 +572,138 @@ func (pm *ProcessManager) Start(ctx context.Context, args *InvokeArgs, workDir s
 	return service, nil
 }
 
+// run starts the command of the service and waits for it to finish. It returns the
+// exit code of the process, or an error when the process could not be started.
+func (s *Service) run(ctx context.Context, workDir string, vars []string, venv string) (int, error) {
+	cmd := sys.Command(ctx, sys.CommandOpts{
+		String:      s.args.Command,
+		Dir:         workDir,
+		Env:         vars,
+		Stdout:      s.file,
+		Stderr:      s.file,
+		SysProcAttr: &syscall.SysProcAttr{Setpgid: true},
+	}).Cmd()
+
+	resolveVirtualEnvCommand(cmd, venv)
+
+	cgroup := s.applyResources(cmd)
+
+	// The service may be killed while it waits to be restarted. The check is done under
+	// the lock that Kill acquires, so that the process is either not started or killed.
+	s.statusMux.Lock()
+
+	if s.killed.Load() {
+		s.statusMux.Unlock()
+
+		if cgroup != nil {
+			cgroup.Close()
+		}
+
+		return 0, nil
+	}
+
+	err := cmd.Start()
+
+	if err == nil {
+		s.cmd = cmd
+		s.startedAt = time.Now()
+	}
+
+	s.statusMux.Unlock()
+
+	if cgroup != nil {
+		cgroup.Close()
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	s.started.Store(true)
+	s.setState(ServiceStateRunning)
+
+	slog.Debug(slog.LogOpts{
+		Msg:   "service started",
+		Level: slog.DL2,
+		Payload: []zap.Field{
+			zap.String("arn", s.arn),
+			zap.Int("port", s.port),
+		},
+	})
+
+	// Ignore error here: it could be related to spawning background processes and
+	// there is no easy way to understand if the cmd is a background process or not
+	if err := cmd.Wait(); err != nil {
+		slog.Errorf("error while waiting for service to finish, arn: %s, err: %s", s.arn, err.Error())
+	} else {
+		slog.Debug(slog.LogOpts{
+			Msg:   "service finished successfully",
+			Level: slog.DL2,
+			Payload: []zap.Field{
+				zap.String("arn", s.arn),
+				zap.Int("pid", s.Pid()),
+			},
+		})
+	}
+
+	s.checkOOM()
+
+	// The cgroup can only be removed once its processes exited.
+	if s.killed.Load() {
+		s.removeCgroup()
+	}
+
+	return cmd.ProcessState.ExitCode(), nil
+}
+
 // Invoke starts a new service if it doesn't exist yet, or waits for the existing one to be ready.
 // It then sends the request to the service and returns the result.
 // path is the path to the directory where the service is running.
 func (pm *ProcessManager) Invoke(args InvokeArgs, workDir string) (*InvokeResult, error) {
+	// The swap event and the cache invalidation are delivered independently, so the first
+	// request of a newly published deployment may arrive before the swap event. In that case
+	// the swap is started here so that the previous deployment keeps serving the requests.
+	// Deployments that share the traffic with other deployments do not replace them.
+	if args.FullTraffic && pm.GetService(args.ARN) == nil {
+		if old := pm.previousService(args); old != nil && old.isAvailable() {
+			if err := pm.Swap(args, workDir); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Serve the request from the previous deployment until the new service is ready.
+	// The previous service may be retired in the meantime, in which case the request
+	// is sent to the new service. The request is sent again only when it could not
+	// reach the previous service, so the body is buffered to be read a second time.
+	if old := pm.swappedService(args.ARN); old != nil {
+		var body []byte
+
+		if args.Body != nil {
+			var err error
+
+			if body, err = io.ReadAll(args.Body); err != nil {
+				return nil, err
+			}
+
+			args.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		result, err := pm.request(args, old)
+
+		if err == nil || !isDialError(err) {
+			return result, err
+		}
+
+		if args.Body != nil {
+			args.Body = io.NopCloser(bytes.NewReader(body))
+		}
+	}
+
+	args = pm.balance(args, workDir)
 	service := pm.GetService(args.ARN)
 
-	if service != nil && service.killed {
+	if service != nil && service.killed.Load() {
 		slog.Debug(slog.LogOpts{
 			Msg:     "service was previously killed, removing from the list",
 			Level:   slog.DL2,
@@ -531,7 +755,11 @@ func (pm *ProcessManager) Invoke(args InvokeArgs, workDir string) (*InvokeResult
 		pm.addService(service, args.ARN)
 	}
 
-	if service != nil && service.isSettingUp {
+	if service != nil && service.IsCrashLooping() {
+		return crashLoopResult(), nil
+	}
+
+	if service != nil && service.isSettingUp.Load() {
 		return &InvokeResult{
 			StatusCode: http.StatusOK,
 			Headers: http.Header{
@@ -547,7 +775,7 @@ func (pm *ProcessManager) Invoke(args InvokeArgs, workDir string) (*InvokeResult
 	}
 
 	// Wait for service to start with a timeout
-	if service != nil && !service.started {
+	if service != nil && !service.started.Load() {
 		timeout := time.After(10 * time.Second)
 		ticker := time.NewTicker(100 * time.Millisecond)
 		defer ticker.Stop()
@@ -557,7 +785,7 @@ func (pm *ProcessManager) Invoke(args InvokeArgs, workDir string) (*InvokeResult
 			case <-timeout:
 				goto serviceNotReadyYet
 			case <-ticker.C:
-				if service.started {
+				if service.started.Load() || service.IsCrashLooping() {
 					goto serviceNotReadyYet
 				}
 			}
@@ -565,7 +793,11 @@ func (pm *ProcessManager) Invoke(args InvokeArgs, workDir string) (*InvokeResult
 	}
 
 serviceNotReadyYet:
-	if service != nil && !service.started {
+	if service != nil && service.IsCrashLooping() {
+		return crashLoopResult(), nil
+	}
+
+	if service != nil && !service.started.Load() {
 		slog.Debug(slog.LogOpts{
 			Msg:     "service is not ready yet",
 			Level:   slog.DL2,
@@ -586,7 +818,12 @@ serviceNotReadyYet:
 		}, nil
 	}
 
-	return pm.requestWithRetry(args, pm.GetService(args.ARN))
+	// The service may have been killed in the meantime.
+	if service = pm.GetService(args.ARN); service == nil {
+		return nil, errors.New("service is no longer running")
+	}
+
+	return pm.requestWithRetry(args, service)
 }
 
 func (pm *ProcessManager) KillAll() error {
@@ -613,6 +850,36 @@ func (pm *ProcessManager) KillAll() error {
 	return nil
 }
 
+// KillEnv kills the services that are running deployments of the given environment.
+// It returns the number of services that were killed.
+func (pm *ProcessManager) KillEnv(envID types.ID) int {
+	pm.mux.Lock()
+	services := []*Service{}
+
+	for _, service := range pm.services {
+		if service.args != nil && service.args.EnvID == envID {
+			services = append(services, service)
+		}
+	}
+
+	pm.mux.Unlock()
+
+	slog.Debug(slog.LogOpts{
+		Msg:   "killing environment services",
+		Level: slog.DL2,
+		Payload: []zap.Field{
+			zap.String("env_id", envID.String()),
+			zap.Int("count", len(services)),
+		},
+	})
+
+	for _, service := range services {
+		service.Kill()
+	}
+
+	return len(services)
+}
+
 // GetService returns a service for the given ARN.
 func (pm *ProcessManager) GetService(ARN string) *Service {
 	pm.mux.Lock()
@@ -668,6 +935,11 @@ func (pm *ProcessManager) requestWithRetry(args InvokeArgs, service *Service) (*
 		case <-timeout:
 			return nil, errors.New("server is not up and running within allowed timeout")
 		case <-ticker.C:
+			// There is no point in waiting for a service that is not going to be restarted.
+			if service != nil && service.IsCrashLooping() {
+				return crashLoopResult(), nil
+			}
+
 			res, err := pm.request(args, service)
 
 			if err != nil {
@@ -681,6 +953,9 @@ func (pm *ProcessManager) requestWithRetry(args InvokeArgs, service *Service) (*
 
 // Request the given resource from the spawned server.
 func (pm *ProcessManager) request(args InvokeArgs, service *Service) (*InvokeResult, error) {
+	service.active.Add(1)
+	defer service.active.Add(-1)
+
 	target := *args.URL
 	target.Scheme = "http"
 	target.Host = fmt.Sprintf("localhost:%d", service.port)
@@ -766,8 +1041,173 @@ func prepareEnvironmentVariables(args *InvokeArgs, port int) []string {
 		vars,
 		fmt.Sprintf("PATH=%s", os.Getenv("PATH")),
 		fmt.Sprintf("HOME=%s", os.Getenv("HOME")),
-		fmt.Sprintf("PORT=%d", port),
 	)
 
+	// Workers do not listen to a port.
+	if port != 0 {
+		vars = append(vars, fmt.Sprintf("PORT=%d", port))
+	}
+
 	return vars
 }
+
+// preparePythonEnvironmentVariables activates the virtual environment that is bundled
+// with python deployments. The virtual environment's bin folder is prepended to the PATH
+// so that WSGI/ASGI servers (gunicorn, uvicorn...) installed in it can be used directly
+// in the server command.
+func preparePythonEnvironmentVariables(vars []string, venv string) []string {
+	bin := path.Join(venv, "bin")
+
+	if !file.Exists(bin) {
+		return vars
+	}
+
+	prepared := []string{}
+	paths := ""
+
+	// The PATH may be set multiple times, e.g. through the environment variables of the
+	// app. The last value takes precedence, as it does when the process is started.
+	for _, v := range vars {
+		if strings.HasPrefix(v, "PATH=") {
+			paths = strings.TrimPrefix(v, "PATH=")
+			continue
+		}
+
+		prepared = append(prepared, v)
+	}
+
+	if paths != bin && !strings.HasPrefix(paths, bin+":") {
+		paths = strings.TrimSuffix(fmt.Sprintf("%s:%s", bin, paths), ":")
+	}
+
+	return append(
+		prepared,
+		fmt.Sprintf("PATH=%s", paths),
+		fmt.Sprintf("VIRTUAL_ENV=%s", venv),
+		"PYTHONUNBUFFERED=1", // Otherwise logs are buffered and not captured in time
+	)
+}
+
+// pythonVersion returns the major and minor version of the interpreter that created
+// the virtual environment, e.g. 3.11. It is read from the version key of pyvenv.cfg
+// and falls back to the lib/pythonX.Y folder of the virtual environment.
+func pythonVersion(venv string, lines []string) string {
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+
+		if !ok || (key != "version" && key != "version_info") {
+			continue
+		}
+
+		if parts := strings.Split(strings.TrimSpace(value), "."); len(parts) >= 2 {
+			return parts[0] + "." + parts[1]
+		}
+	}
+
+	if libs, _ := filepath.Glob(path.Join(venv, "lib", "python3.*")); len(libs) == 1 {
+		return strings.TrimPrefix(filepath.Base(libs[0]), "python")
+	}
+
+	return ""
+}
+
+// venvMux serializes the relocation of the python virtual environments.
+var venvMux sync.Mutex
+
+// relocatePythonVirtualEnv points the virtual environment to the python interpreter of this
+// instance. Virtual environments are created on the runner: the home key of pyvenv.cfg and
+// the interpreter links of the bin folder refer to the interpreter of the runner, which may
+// be installed elsewhere on this instance.
+func relocatePythonVirtualEnv(venv string) error {
+	// The replicas of a service share the virtual environment.
+	venvMux.Lock()
+	defer venvMux.Unlock()
+
+	cfg := path.Join(venv, "pyvenv.cfg")
+	data, err := os.ReadFile(cfg)
+
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	python := ""
+
+	for i, line := range lines {
+		key, value, ok := strings.Cut(line, "=")
+
+		if !ok || strings.TrimSpace(key) != "home" {
+			continue
+		}
+
+		if file.Exists(path.Join(strings.TrimSpace(value), "python3")) {
+			return nil
+		}
+
+		version := pythonVersion(venv, lines)
+
+		if version == "" {
+			return fmt.Errorf("cannot relocate python virtual environment: python version is missing in %s", cfg)
+		}
+
+		// The packages of the virtual environment are installed under lib/pythonX.Y,
+		// an interpreter of another version would not find them.
+		if python, err = exec.LookPath("python" + version); err != nil {
+			return fmt.Errorf("cannot relocate python virtual environment: python%s is not installed", version)
+		}
+
+		lines[i] = fmt.Sprintf("home = %s", path.Dir(python))
+	}
+
+	if python == "" {
+		return nil
+	}
+
+	if err := os.WriteFile(cfg, []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return err
+	}
+
+	links, err := filepath.Glob(path.Join(venv, "bin", "python*"))
+
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		info, err := os.Lstat(link)
+
+		// Only the links that do not resolve on this instance are replaced.
+		if err != nil || info.Mode()&os.ModeSymlink == 0 || file.Exists(link) {
+			continue
+		}
+
+		if err := os.Remove(link); err != nil {
+			return err
+		}
+
+		if err := os.Symlink(python, link); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveVirtualEnvCommand makes sure that the command is looked up in the virtual environment
+// first. exec.Command resolves the executable using the PATH of the current process, therefore
+// prepending the virtual environment to the PATH of the service is not enough.
+func resolveVirtualEnvCommand(cmd *exec.Cmd, venv string) {
+	if cmd == nil || len(cmd.Args) == 0 || strings.Contains(cmd.Args[0], "/") {
+		return
+	}
+
+	if executable := path.Join(venv, "bin", cmd.Args[0]); file.Exists(executable) {
+		cmd.Path = executable
+		cmd.Err = nil
+	}
+}
@@ -0,0 +1,391 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/html"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"go.uber.org/zap"
+)
+
+const (
+	HealthCheckHTTP = "http"
+	HealthCheckTCP  = "tcp"
+)
+
+const (
+	RestartAlways    = "always"
+	RestartOnFailure = "on-failure"
+	RestartNever     = "never"
+)
+
+const (
+	ServiceStateRunning    = "running"
+	ServiceStateRestarting = "restarting"
+	ServiceStateCrashLoop  = "crash_loop"
+	ServiceStateStopped    = "stopped"
+)
+
+// crashLoopWindow is the duration a service has to stay up for its restart count to be reset.
+var crashLoopWindow = 5 * time.Minute
+
+// killGracePeriod is the duration an unhealthy service has to terminate before it is killed.
+var killGracePeriod = 5 * time.Second
+
+// HealthCheck configures the liveness probe of a service.
+type HealthCheck struct {
+	Type             string        // http | tcp
+	Path             string        // The path that is requested by http probes
+	Interval         time.Duration // The duration between two probes
+	Timeout          time.Duration // The duration after which a probe fails
+	FailureThreshold int           // The number of consecutive failures before the service is restarted
+}
+
+// RestartPolicy configures how a service is restarted when it crashes or fails its health checks.
+type RestartPolicy struct {
+	Policy      string        // always | on-failure | never
+	MaxRestarts int           // The number of consecutive restarts before the service is considered crash looping
+	Backoff     time.Duration // The initial delay before restarting, doubled on each restart
+	MaxBackoff  time.Duration // The maximum delay before restarting
+}
+
+// ServiceStatus represents the state of a service that is managed by the process manager.
+type ServiceStatus struct {
+	ARN           string   `json:"arn"`
+	AppID         types.ID `json:"appId,string"`
+	EnvID         types.ID `json:"envId,string"`
+	DeploymentID  types.ID `json:"deploymentId,string"`
+	Replica       int      `json:"replica"`
+	Worker        string   `json:"worker,omitempty"`
+	State         string   `json:"state"` // running | restarting | crash_loop | stopped
+	Healthy       bool     `json:"healthy"`
+	Restarts      int      `json:"restarts"`
+	LastExitCode  *int     `json:"lastExitCode"`
+	LastError     string   `json:"lastError,omitempty"`
+	LastRestartAt int64    `json:"lastRestartAt,omitempty"`
+	UpdatedAt     int64    `json:"updatedAt"`
+
+	// Usage is the resource usage of the service, only populated by ProcessManager.Statuses.
+	Usage *ResourceUsage `json:"usage,omitempty"`
+}
+
+// OnStatusChange registers a function that is called whenever the state of a service changes.
+func (pm *ProcessManager) OnStatusChange(fn func(ServiceStatus)) {
+	pm.mux.Lock()
+	defer pm.mux.Unlock()
+	pm.statusHook = fn
+}
+
+// Status returns the current status of the service.
+func (s *Service) Status() ServiceStatus {
+	s.statusMux.Lock()
+	defer s.statusMux.Unlock()
+
+	status := ServiceStatus{
+		ARN:       s.arn,
+		State:     s.state,
+		Healthy:   s.healthy,
+		Restarts:  s.restarts,
+		LastError: s.lastError,
+		UpdatedAt: time.Now().Unix(),
+	}
+
+	if s.args != nil {
+		status.AppID = s.args.AppID
+		status.EnvID = s.args.EnvID
+		status.DeploymentID = s.args.DeploymentID
+		status.Replica = s.args.Replica
+		status.Worker = s.args.Worker
+	}
+
+	if s.exited {
+		exitCode := s.lastExitCode
+		status.LastExitCode = &exitCode
+	}
+
+	if !s.lastRestartAt.IsZero() {
+		status.LastRestartAt = s.lastRestartAt.Unix()
+	}
+
+	return status
+}
+
+// IsCrashLooping returns true when the service has been restarted too many times
+// and the process manager gave up restarting it.
+func (s *Service) IsCrashLooping() bool {
+	s.statusMux.Lock()
+	defer s.statusMux.Unlock()
+	return s.state == ServiceStateCrashLoop
+}
+
+// setState updates the state of the service and notifies the status hook.
+func (s *Service) setState(state string) {
+	s.statusMux.Lock()
+	s.state = state
+	s.statusMux.Unlock()
+
+	s.pm.mux.Lock()
+	hook := s.pm.statusHook
+	s.pm.mux.Unlock()
+
+	if hook != nil {
+		hook(s.Status())
+	}
+}
+
+// nextRestart records the exit of the service and returns the delay before the service is
+// restarted together with the next state of the service. The service is only restarted when
+// the next state is ServiceStateRestarting.
+func (s *Service) nextRestart(exitCode int) (time.Duration, string) {
+	s.statusMux.Lock()
+	defer s.statusMux.Unlock()
+
+	failed := exitCode != 0 || !s.healthy
+	s.exited = true
+	s.lastExitCode = exitCode
+
+	if exitCode != 0 && s.healthy {
+		s.lastError = fmt.Sprintf("service exited with code %d", exitCode)
+	}
+
+	policy := s.args.RestartPolicy
+
+	if policy == nil || policy.Policy == RestartNever || policy.Policy == "" {
+		return 0, ServiceStateStopped
+	}
+
+	if policy.Policy == RestartOnFailure && !failed {
+		return 0, ServiceStateStopped
+	}
+
+	// The service has been running long enough, it is not part of a crash loop.
+	if time.Since(s.startedAt) > crashLoopWindow {
+		s.consecutiveRestarts = 0
+	}
+
+	if s.consecutiveRestarts >= policy.MaxRestarts {
+		return 0, ServiceStateCrashLoop
+	}
+
+	delay := policy.Backoff << s.consecutiveRestarts
+
+	if delay > policy.MaxBackoff || delay <= 0 {
+		delay = policy.MaxBackoff
+	}
+
+	s.consecutiveRestarts = s.consecutiveRestarts + 1
+	s.restarts = s.restarts + 1
+	s.lastRestartAt = time.Now()
+	s.healthy = true
+
+	return delay, ServiceStateRestarting
+}
+
+// crashLoopResult returns the error page that is served while the service is crash looping.
+func crashLoopResult() *InvokeResult {
+	return &InvokeResult{
+		StatusCode: http.StatusServiceUnavailable,
+		Headers: http.Header{
+			"Content-Type": []string{"text/html"},
+		},
+		Body: html.MustRender(html.RenderArgs{
+			PageTitle: "Stormkit - Service unavailable",
+			PageContent: `
+				<div class="container text-center">
+					<h2>Service keeps crashing</h2>
+					<h3>The service was restarted too many times in a row and will not be restarted automatically.<br />Check your runtime logs for details.</h3>
+				</div>
+			`,
+		}),
+	}
+}
+
+// terminate stops the process group of the service without removing the service.
+// The process is killed if it does not terminate within the grace period.
+func (s *Service) terminate() {
+	s.statusMux.Lock()
+	cmd := s.cmd
+	s.statusMux.Unlock()
+
+	if cmd == nil || cmd.Process == nil {
+		return
+	}
+
+	pgid, err := syscall.Getpgid(cmd.Process.Pid)
+
+	if err != nil {
+		return
+	}
+
+	if err := syscall.Kill(-pgid, syscall.SIGTERM); err != nil {
+		slog.Errorf("error while terminating process group: %s", err.Error())
+	}
+
+	// The group is probed rather than its leader: the children of the service
+	// may still be running after the leader has exited.
+	time.AfterFunc(killGracePeriod, func() {
+		if err := syscall.Kill(-pgid, 0); err == nil {
+			_ = syscall.Kill(-pgid, syscall.SIGKILL)
+		}
+	})
+}
+
+// probe runs the health checks of the service until the service is killed.
+func (s *Service) probe() {
+	hc := s.args.HealthCheck
+	ticker := time.NewTicker(hc.Interval)
+	defer ticker.Stop()
+
+	failures := 0
+
+	for range ticker.C {
+		if s.killed.Load() {
+			return
+		}
+
+		if !s.started.Load() || s.isSettingUp.Load() || s.IsCrashLooping() {
+			failures = 0
+			continue
+		}
+
+		err := s.check(hc)
+
+		if err == nil {
+			failures = 0
+			s.statusMux.Lock()
+			s.healthy = true
+			s.statusMux.Unlock()
+			continue
+		}
+
+		failures = failures + 1
+
+		if failures < hc.FailureThreshold {
+			continue
+		}
+
+		slog.Debug(slog.LogOpts{
+			Msg:   "service failed its health checks, terminating it",
+			Level: slog.DL2,
+			Payload: []zap.Field{
+				zap.String("arn", s.arn),
+				zap.Error(err),
+			},
+		})
+
+		msg := fmt.Sprintf("health check failed: %s", err.Error())
+
+		s.statusMux.Lock()
+		s.healthy = false
+		s.lastError = msg
+		s.statusMux.Unlock()
+
+		s.pm.QueueLog(s.args, msg)
+
+		failures = 0
+		s.terminate()
+	}
+}
+
+// check probes the service once.
+func (s *Service) check(hc *HealthCheck) error {
+	address := fmt.Sprintf("localhost:%d", s.port)
+
+	if hc.Type == HealthCheckTCP {
+		conn, err := net.DialTimeout("tcp", address, hc.Timeout)
+
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}
+
+	client := &http.Client{Timeout: hc.Timeout}
+	res, err := client.Get(fmt.Sprintf("http://%s%s", address, hc.Path))
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// serviceStatusTTL is the duration the status of a service is kept after its last update.
+var serviceStatusTTL = 24 * time.Hour
+
+// serviceStatusKey returns the redis key that holds the service statuses of an environment.
+func serviceStatusKey(envID types.ID) string {
+	return fmt.Sprintf("service_status:%s", envID.String())
+}
+
+// SaveServiceStatus stores the status of the service so that it can be retrieved by
+// other instances. Stopped services are removed from the store.
+func SaveServiceStatus(ctx context.Context, status ServiceStatus) error {
+	key := serviceStatusKey(status.EnvID)
+	client := rediscache.Client()
+
+	if status.State == ServiceStateStopped {
+		return client.HDel(ctx, key, status.ARN).Err()
+	}
+
+	data, err := json.Marshal(status)
+
+	if err != nil {
+		return err
+	}
+
+	pipe := client.TxPipeline()
+	pipe.HSet(ctx, key, status.ARN, data)
+	pipe.Expire(ctx, key, serviceStatusTTL)
+
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// ServiceStatuses returns the statuses of the services that belong to the environment,
+// most recent deployments first.
+func ServiceStatuses(ctx context.Context, envID types.ID) ([]ServiceStatus, error) {
+	values, err := rediscache.Client().HGetAll(ctx, serviceStatusKey(envID)).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := []ServiceStatus{}
+
+	for _, value := range values {
+		status := ServiceStatus{}
+
+		if err := json.Unmarshal([]byte(value), &status); err != nil {
+			return nil, err
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].DeploymentID == statuses[j].DeploymentID {
+			return statuses[i].Replica < statuses[j].Replica
+		}
+
+		return statuses[i].DeploymentID > statuses[j].DeploymentID
+	})
+
+	return statuses, nil
+}
@@ -0,0 +1,137 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"go.uber.org/zap"
+)
+
+const (
+	BalanceRoundRobin       = "round-robin"
+	BalanceLeastConnections = "least-connections"
+)
+
+// Replicas configures the number of processes that are spawned for a service
+// and how the requests are distributed between them.
+type Replicas struct {
+	Count    int    // The number of processes
+	Strategy string // round-robin | least-connections
+}
+
+// replicaARN returns the ARN of the replica with the given index. The first
+// replica uses the ARN of the function so that single process services are not affected.
+func replicaARN(arn string, index int) string {
+	if index == 0 {
+		return arn
+	}
+
+	return fmt.Sprintf("%s#replica-%d", arn, index)
+}
+
+// replicaCount returns the number of replicas that should be running for the invocation.
+func (args *InvokeArgs) replicaCount() int {
+	// Custom ports cannot be shared between processes.
+	if args.Replicas == nil || args.EnvVariables["PORT"] != "" {
+		return 1
+	}
+
+	return max(args.Replicas.Count, 1)
+}
+
+// isAvailable returns true when the service can receive requests.
+func (s *Service) isAvailable() bool {
+	if s == nil || !s.started.Load() || s.killed.Load() || s.isSettingUp.Load() {
+		return false
+	}
+
+	s.statusMux.Lock()
+	defer s.statusMux.Unlock()
+
+	return s.healthy && s.state != ServiceStateCrashLoop
+}
+
+// balance picks the replica that receives the request and returns the arguments
+// for that replica. Missing replicas are spawned once the first replica is up and
+// running, so that setup scripts are executed only once. When no replica is
+// available, the request is sent to the first replica.
+func (pm *ProcessManager) balance(args InvokeArgs, workDir string) InvokeArgs {
+	count := args.replicaCount()
+
+	if count == 1 {
+		return args
+	}
+
+	if primary := pm.GetService(args.ARN); primary.isAvailable() {
+		for i := 1; i < count; i++ {
+			pm.startReplica(args, workDir, i)
+		}
+	}
+
+	available := []*Service{}
+
+	for i := 0; i < count; i++ {
+		if service := pm.GetService(replicaARN(args.ARN, i)); service.isAvailable() {
+			available = append(available, service)
+		}
+	}
+
+	if len(available) == 0 {
+		return args
+	}
+
+	var picked *Service
+
+	if args.Replicas.Strategy == BalanceLeastConnections {
+		least := int64(math.MaxInt64)
+
+		for _, service := range available {
+			if active := service.active.Load(); active < least {
+				least = active
+				picked = service
+			}
+		}
+	} else {
+		pm.mux.Lock()
+		next := pm.nextReplica[args.ARN]
+		pm.nextReplica[args.ARN] = next + 1
+		pm.mux.Unlock()
+
+		picked = available[next%len(available)]
+	}
+
+	args.ARN = picked.arn
+	args.Replica = picked.args.Replica
+
+	return args
+}
+
+// startReplica spawns the replica with the given index unless it is already running.
+func (pm *ProcessManager) startReplica(args InvokeArgs, workDir string, index int) {
+	pm.replicaMux.Lock()
+	defer pm.replicaMux.Unlock()
+
+	args.ARN = replicaARN(args.ARN, index)
+	args.Replica = index
+
+	if pm.GetService(args.ARN) != nil {
+		return
+	}
+
+	slog.Debug(slog.LogOpts{
+		Msg:     "starting service replica",
+		Level:   slog.DL2,
+		Payload: []zap.Field{zap.String("arn", args.ARN)},
+	})
+
+	service, err := pm.Start(context.TODO(), &args, workDir)
+
+	if err != nil {
+		slog.Errorf("error while starting service replica %s: %s", args.ARN, err.Error())
+		return
+	}
+
+	pm.addService(service, args.ARN)
+}
@@ -0,0 +1,500 @@
+package integrations
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"go.uber.org/zap"
+)
+
+// cgroupFS is the mount point of the cgroup v2 hierarchy.
+var cgroupFS = "/sys/fs/cgroup"
+
+// CgroupRoot is the cgroup in which the services are placed. It can be
+// overwritten using the STORMKIT_CGROUP_ROOT environment variable.
+var CgroupRoot = path.Join(cgroupFS, "stormkit")
+
+// cpuPeriod is the period in microseconds used to compute the cpu quota.
+const cpuPeriod = 100000
+
+// clockTicks is the number of clock ticks per second used by /proc/<pid>/stat.
+const clockTicks = 100
+
+var cgroupOnce sync.Once
+var cgroupReady bool
+
+// cgroupLeaf is the cgroup in which the processes of a cgroup are moved when controllers
+// have to be enabled for its children.
+const cgroupLeaf = "stormkit-leaf"
+
+var invalidCgroupChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// Resources configures the limits of the processes spawned for a service.
+type Resources struct {
+	MemoryBytes int64   // The maximum amount of memory, zero means unlimited
+	CPU         float64 // The number of cpu cores, zero means unlimited
+	UID         uint32  // The user the processes run as, zero means the current user
+}
+
+// ResourceUsage represents the resources consumed by a service.
+type ResourceUsage struct {
+	MemoryBytes int64   `json:"memoryBytes"`
+	CPUSeconds  float64 `json:"cpuSeconds"`
+	MemoryLimit int64   `json:"memoryLimit,omitempty"`
+	CPULimit    float64 `json:"cpuLimit,omitempty"`
+}
+
+// cgroupsAvailable returns true when the services can be placed in cgroups v2.
+// The root cgroup is created and the cpu and memory controllers are enabled
+// on every cgroup from the hierarchy root down to it on the first call.
+func cgroupsAvailable() bool {
+	cgroupOnce.Do(func() {
+		if root := os.Getenv("STORMKIT_CGROUP_ROOT"); root != "" {
+			CgroupRoot = root
+		}
+
+		if _, err := os.Stat(path.Join(cgroupFS, "cgroup.controllers")); err != nil {
+			slog.Infof("cgroups v2 are not available, falling back to rlimits for resource limits")
+			return
+		}
+
+		if err := os.MkdirAll(CgroupRoot, 0755); err != nil {
+			slog.Errorf("cannot create cgroup %s, falling back to rlimits: %s", CgroupRoot, err.Error())
+			return
+		}
+
+		rel, err := filepath.Rel(cgroupFS, CgroupRoot)
+
+		if err != nil || strings.HasPrefix(rel, "..") {
+			slog.Errorf("cgroup %s is not within %s, falling back to rlimits", CgroupRoot, cgroupFS)
+			return
+		}
+
+		dir := cgroupFS
+
+		for _, name := range append([]string{""}, strings.Split(rel, "/")...) {
+			dir = path.Join(dir, name)
+
+			if err := enableControllers(dir); err != nil {
+				slog.Errorf("cannot enable cgroup controllers for %s, falling back to rlimits: %s", dir, err.Error())
+				return
+			}
+		}
+
+		cgroupReady = true
+	})
+
+	return cgroupReady
+}
+
+// enableControllers enables the cpu and memory controllers for the children of the cgroup.
+// Controllers cannot be enabled on a cgroup that contains processes (EBUSY), which is
+// the case when Stormkit runs in one of the cgroups of the path, for instance as the
+// only process of a container. The processes are moved into a leaf cgroup in that case.
+func enableControllers(dir string) error {
+	file := path.Join(dir, "cgroup.subtree_control")
+	err := os.WriteFile(file, []byte("+cpu +memory"), 0644)
+
+	if !errors.Is(err, syscall.EBUSY) {
+		return err
+	}
+
+	leaf := path.Join(dir, cgroupLeaf)
+
+	if err := os.MkdirAll(leaf, 0755); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path.Join(dir, "cgroup.procs"))
+
+	if err != nil {
+		return err
+	}
+
+	for _, pid := range strings.Fields(string(data)) {
+		// Processes may exit in the meantime.
+		_ = os.WriteFile(path.Join(leaf, "cgroup.procs"), []byte(pid), 0644)
+	}
+
+	return os.WriteFile(file, []byte("+cpu +memory"), 0644)
+}
+
+// cgroupPath returns the cgroup of the service.
+func (s *Service) cgroupPath() string {
+	return path.Join(CgroupRoot, invalidCgroupChars.ReplaceAllString(s.arn, "-"))
+}
+
+// hasLimits returns true when the service has memory or cpu limits.
+func (s *Service) hasLimits() bool {
+	r := s.args.Resources
+	return r != nil && (r.MemoryBytes > 0 || r.CPU > 0)
+}
+
+// credential returns the credential of the user the processes of the service run as,
+// or nil when they run as the current user. The processes cannot switch users when
+// Stormkit is not running as root.
+//
+// Isolated users are not expected to exist on the host: the group id matches the user id
+// and the supplementary groups of Stormkit are dropped.
+func (s *Service) credential() *syscall.Credential {
+	r := s.args.Resources
+
+	if r == nil || r.UID == 0 || os.Geteuid() != 0 {
+		return nil
+	}
+
+	return &syscall.Credential{Uid: r.UID, Gid: r.UID, Groups: []uint32{}}
+}
+
+// isolate prepares the work directory for the user the processes of the service run as.
+// The files of the deployment are owned by root, so they are handed over to the user and
+// the home directory is pointed to the work directory. It returns the updated variables.
+func (s *Service) isolate(workDir string, vars []string) []string {
+	cred := s.credential()
+
+	if cred == nil {
+		if r := s.args.Resources; r != nil && r.UID != 0 {
+			slog.Infof("cannot run service %s as uid %d: stormkit is not running as root", s.arn, r.UID)
+		}
+
+		return vars
+	}
+
+	if err := chownOnce(workDir, cred); err != nil {
+		slog.Errorf("cannot change the owner of %s for service %s: %s", workDir, s.arn, err.Error())
+	}
+
+	for i, v := range vars {
+		if strings.HasPrefix(v, "HOME=") {
+			vars[i] = fmt.Sprintf("HOME=%s", workDir)
+		}
+	}
+
+	return vars
+}
+
+// chownOnce hands the files of the directory over to the user. The directory itself
+// is handed over last, so that its owner tells whether the tree was already handed
+// over and the deployment is not walked again on every start.
+func chownOnce(dir string, cred *syscall.Credential) error {
+	info, err := os.Lstat(dir)
+
+	if err != nil {
+		return err
+	}
+
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok && stat.Uid == cred.Uid && stat.Gid == cred.Gid {
+		return nil
+	}
+
+	err = filepath.WalkDir(dir, func(name string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if name == dir {
+			return nil
+		}
+
+		return os.Lchown(name, int(cred.Uid), int(cred.Gid))
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return os.Lchown(dir, int(cred.Uid), int(cred.Gid))
+}
+
+// applyResources configures the command so that it runs with the resources of the service.
+// It has to be called before the command is started. When cgroups are available, the process
+// is spawned directly into the cgroup of the service and the returned file, which refers to
+// the cgroup, has to be closed once the command is started. When cgroups are not available,
+// the memory limit is enforced through rlimits and the cpu limit is ignored.
+func (s *Service) applyResources(cmd *exec.Cmd) *os.File {
+	r := s.args.Resources
+
+	if r == nil {
+		return nil
+	}
+
+	cmd.SysProcAttr.Credential = s.credential()
+
+	if !s.hasLimits() {
+		return nil
+	}
+
+	if cgroupsAvailable() {
+		cgroup, err := s.createCgroup()
+		s.cgroup.Store(err == nil)
+
+		if err == nil {
+			cmd.SysProcAttr.UseCgroupFD = true
+			cmd.SysProcAttr.CgroupFD = int(cgroup.Fd())
+			return cgroup
+		}
+
+		slog.Errorf("cannot create cgroup for service %s, falling back to rlimits: %s", s.arn, err.Error())
+	}
+
+	if r.CPU > 0 {
+		s.pm.QueueLog(s.args, "cpu limits are not enforced because cgroups v2 are not available on this host")
+	}
+
+	// The data segment is limited rather than the address space, because runtimes like
+	// V8 reserve large virtual memory ranges that they never use. Commands that cannot
+	// be found are not wrapped, so that cmd.Start returns a meaningful error.
+	if r.MemoryBytes > 0 && cmd.Err == nil {
+		script := fmt.Sprintf(`ulimit -d %d && exec "$0" "$@"`, r.MemoryBytes/1024)
+		cmd.Args = append([]string{"sh", "-c", script, cmd.Path}, cmd.Args[1:]...)
+		cmd.Path = "/bin/sh"
+	}
+
+	return nil
+}
+
+// createCgroup creates the cgroup of the service, writes its limits and returns
+// the opened cgroup directory.
+func (s *Service) createCgroup() (*os.File, error) {
+	r := s.args.Resources
+	dir := s.cgroupPath()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	memory := "max"
+	cpu := fmt.Sprintf("max %d", cpuPeriod)
+
+	if r.MemoryBytes > 0 {
+		memory = strconv.FormatInt(r.MemoryBytes, 10)
+	}
+
+	if r.CPU > 0 {
+		cpu = fmt.Sprintf("%d %d", int64(r.CPU*cpuPeriod), cpuPeriod)
+	}
+
+	if err := os.WriteFile(path.Join(dir, "memory.max"), []byte(memory), 0644); err != nil {
+		return nil, err
+	}
+
+	// Disable swap so that the memory limit cannot be bypassed.
+	_ = os.WriteFile(path.Join(dir, "memory.swap.max"), []byte("0"), 0644)
+
+	if err := os.WriteFile(path.Join(dir, "cpu.max"), []byte(cpu), 0644); err != nil {
+		return nil, err
+	}
+
+	s.oomKills = cgroupStat(path.Join(dir, "memory.events"), "oom_kill")
+	return os.Open(dir)
+}
+
+// removeCgroup removes the cgroup of the service. It fails silently when processes
+// are still running in the cgroup.
+func (s *Service) removeCgroup() {
+	if s.cgroup.Load() {
+		_ = os.Remove(s.cgroupPath())
+	}
+}
+
+// checkOOM reports the service in the runtime logs when it was killed because it
+// exceeded its memory limit. It returns true in that case.
+func (s *Service) checkOOM() bool {
+	if !s.cgroup.Load() {
+		return false
+	}
+
+	kills := cgroupStat(path.Join(s.cgroupPath(), "memory.events"), "oom_kill")
+
+	if kills <= s.oomKills {
+		return false
+	}
+
+	s.oomKills = kills
+
+	msg := fmt.Sprintf("service was killed because it exceeded its memory limit of %d MB", s.args.Resources.MemoryBytes/1024/1024)
+
+	slog.Debug(slog.LogOpts{
+		Msg:     "service was oom killed",
+		Level:   slog.DL2,
+		Payload: []zap.Field{zap.String("arn", s.arn)},
+	})
+
+	s.pm.QueueLog(s.args, msg)
+
+	s.statusMux.Lock()
+	s.lastError = msg
+	s.statusMux.Unlock()
+
+	return true
+}
+
+// Usage returns the resources consumed by the service. When the service runs in a cgroup
+// the usage of all of its processes is returned, otherwise only the main process is measured.
+func (s *Service) Usage() ResourceUsage {
+	usage := ResourceUsage{}
+
+	if r := s.args.Resources; r != nil {
+		usage.MemoryLimit = r.MemoryBytes
+		usage.CPULimit = r.CPU
+	}
+
+	if s.cgroup.Load() {
+		dir := s.cgroupPath()
+
+		if data, err := os.ReadFile(path.Join(dir, "memory.current")); err == nil {
+			usage.MemoryBytes, _ = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		}
+
+		usage.CPUSeconds = float64(cgroupStat(path.Join(dir, "cpu.stat"), "usage_usec")) / 1e6
+		return usage
+	}
+
+	s.statusMux.Lock()
+	cmd := s.cmd
+	s.statusMux.Unlock()
+
+	if cmd == nil || cmd.Process == nil {
+		return usage
+	}
+
+	pid := cmd.Process.Pid
+
+	if rss := cgroupStat(fmt.Sprintf("/proc/%d/status", pid), "VmRSS:"); rss > 0 {
+		usage.MemoryBytes = rss * 1024
+	}
+
+	if data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid)); err == nil {
+		// The command name may contain spaces, fields are counted after the closing parenthesis.
+		if i := bytes.LastIndexByte(data, ')'); i > 0 {
+			fields := strings.Fields(string(data[i+1:]))
+
+			// utime and stime are the 14th and 15th fields of the file.
+			if len(fields) > 12 {
+				utime, _ := strconv.ParseInt(fields[11], 10, 64)
+				stime, _ := strconv.ParseInt(fields[12], 10, 64)
+				usage.CPUSeconds = float64(utime+stime) / clockTicks
+			}
+		}
+	}
+
+	return usage
+}
+
+// Statuses returns the status and the resource usage of the services that are
+// running on this instance.
+func (pm *ProcessManager) Statuses() []ServiceStatus {
+	pm.mux.Lock()
+	services := make([]*Service, 0, len(pm.services))
+
+	for _, s := range pm.services {
+		services = append(services, s)
+	}
+
+	pm.mux.Unlock()
+
+	statuses := []ServiceStatus{}
+
+	for _, s := range services {
+		status := s.Status()
+		usage := s.Usage()
+		status.Usage = &usage
+		statuses = append(statuses, status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ARN < statuses[j].ARN
+	})
+
+	return statuses
+}
+
+// serviceUsageTTL is the duration the resource usage of an instance is kept after its last report.
+var serviceUsageTTL = 2 * time.Minute
+
+// serviceUsageKey returns the redis key that holds the service usage of an instance.
+func serviceUsageKey(instance string) string {
+	return fmt.Sprintf("service_usage:%s", instance)
+}
+
+// SaveServiceUsage stores the statuses and the resource usage of the services that are
+// running on the given instance, so that they can be retrieved through the admin api.
+func SaveServiceUsage(ctx context.Context, instance string, statuses []ServiceStatus) error {
+	data, err := json.Marshal(statuses)
+
+	if err != nil {
+		return err
+	}
+
+	return rediscache.Client().Set(ctx, serviceUsageKey(instance), data, serviceUsageTTL).Err()
+}
+
+// ServiceUsage returns the services that are running on each instance, keyed by the instance name.
+func ServiceUsage(ctx context.Context) (map[string][]ServiceStatus, error) {
+	client := rediscache.Client()
+	prefix := serviceUsageKey("")
+	usage := map[string][]ServiceStatus{}
+	iter := client.Scan(ctx, 0, prefix+"*", 100).Iterator()
+
+	for iter.Next(ctx) {
+		value, err := client.Get(ctx, iter.Val()).Result()
+
+		// The key may have expired in the meantime.
+		if err != nil {
+			continue
+		}
+
+		statuses := []ServiceStatus{}
+
+		if err := json.Unmarshal([]byte(value), &statuses); err != nil {
+			return nil, err
+		}
+
+		usage[strings.TrimPrefix(iter.Val(), prefix)] = statuses
+	}
+
+	return usage, iter.Err()
+}
+
+// cgroupStat returns the value of the given key in a flat keyed file, such as
+// memory.events, cpu.stat or /proc/<pid>/status.
+func cgroupStat(file, key string) int64 {
+	f, err := os.Open(file)
+
+	if err != nil {
+		return 0
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+
+		if len(fields) >= 2 && fields[0] == key {
+			value, _ := strconv.ParseInt(fields[1], 10, 64)
+			return value
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,243 @@
+package integrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"path"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/utils"
+	"go.uber.org/zap"
+)
+
+// swapTimeout is the duration a pre-warmed service has to become ready before the swap is aborted.
+var swapTimeout = 2 * time.Minute
+
+// drainTimeout is the duration in-flight requests have to complete before a replaced service is killed.
+var drainTimeout = 30 * time.Second
+
+// Swap pre-warms the service of a newly published deployment and switches the traffic
+// to it once it is ready. Until then, requests for the new deployment are served by the
+// service of the previously published deployment. Replaced services are killed after
+// their in-flight requests complete.
+//
+// Services that use a custom port cannot run side by side, so the previous service
+// is drained and killed once the setup script of the new deployment has finished.
+//
+// Instances that are not running the previous deployment do not pre-warm the service,
+// it is started by the first request instead.
+func (pm *ProcessManager) Swap(args InvokeArgs, workDir string) error {
+	// Swaps are only triggered for deployments that receive all the traffic.
+	args.IsPublished = true
+	args.FullTraffic = true
+
+	if service := pm.GetService(args.ARN); service != nil {
+		go pm.retire(args)
+		return nil
+	}
+
+	old := pm.previousService(args)
+
+	if old == nil {
+		return nil
+	}
+
+	pm.mux.Lock()
+
+	// A swap is already in progress for this deployment.
+	if _, ok := pm.swaps[args.ARN]; ok {
+		pm.mux.Unlock()
+		return nil
+	}
+
+	pm.swaps[args.ARN] = old
+	pm.mux.Unlock()
+
+	var handoff *Service
+
+	if old.isCustomPort {
+		handoff = old
+	}
+
+	service, err := pm.start(context.TODO(), &args, workDir, handoff)
+
+	if err != nil {
+		pm.endSwap(args.ARN)
+		return err
+	}
+
+	pm.addService(service, args.ARN)
+
+	slog.Debug(slog.LogOpts{
+		Msg:   "pre-warming service",
+		Level: slog.DL2,
+		Payload: []zap.Field{
+			zap.String("arn", args.ARN),
+			zap.Bool("handoff", handoff != nil),
+		},
+	})
+
+	go func() {
+		// The swap is aborted when the new service does not become ready: the new service is
+		// killed and the previous service keeps serving the requests. A later request or swap
+		// event starts the new service again. Services that took over a custom port cannot be
+		// aborted as the previous service is already killed.
+		if err := service.waitUntilReady(swapTimeout); err != nil && handoff == nil {
+			pm.QueueLog(&args, fmt.Sprintf("pre-warmed service did not become ready, keeping the previous deployment: %s", err.Error()))
+			service.Kill()
+			pm.endSwap(args.ARN)
+			return
+		}
+
+		pm.endSwap(args.ARN)
+		pm.retire(args)
+	}()
+
+	return nil
+}
+
+// endSwap switches the traffic of the deployment to its own service.
+func (pm *ProcessManager) endSwap(arn string) {
+	pm.mux.Lock()
+	delete(pm.swaps, arn)
+	pm.mux.Unlock()
+}
+
+// swappedService returns the service that serves the requests of the given deployment
+// while its own service is being pre-warmed, or nil when there is no such service.
+func (pm *ProcessManager) swappedService(arn string) *Service {
+	pm.mux.Lock()
+	old, ok := pm.swaps[arn]
+	pm.mux.Unlock()
+
+	if !ok || !old.isAvailable() {
+		return nil
+	}
+
+	return old
+}
+
+// isDialError returns true when the request could not connect to the service,
+// which means that the service has not received the request.
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// previousService returns the primary service of the previously published deployment
+// of the environment that runs the same function as the given deployment.
+func (pm *ProcessManager) previousService(args InvokeArgs) *Service {
+	pm.mux.Lock()
+	defer pm.mux.Unlock()
+
+	for _, s := range pm.services {
+		if s.args.Replica != 0 || !s.isPrevious(args) {
+			continue
+		}
+
+		if path.Base(s.arn) == path.Base(args.ARN) {
+			return s
+		}
+	}
+
+	return nil
+}
+
+// isPrevious returns true when the service belongs to a deployment that was published
+// to the same environment before the given deployment. Only deployments that receive
+// all the traffic replace the other deployments of the environment.
+func (s *Service) isPrevious(args InvokeArgs) bool {
+	return args.FullTraffic &&
+		s.args.EnvID == args.EnvID &&
+		s.args.IsPublished &&
+		s.args.DeploymentID != args.DeploymentID &&
+		!s.killed.Load()
+}
+
+// retire drains and kills the services of the previously published deployments of the environment.
+func (pm *ProcessManager) retire(args InvokeArgs) {
+	pm.mux.Lock()
+	services := []*Service{}
+
+	for _, s := range pm.services {
+		if s.isPrevious(args) {
+			services = append(services, s)
+		}
+	}
+
+	pm.mux.Unlock()
+
+	for _, s := range services {
+		go func(s *Service) {
+			s.drain(drainTimeout)
+			s.Kill()
+		}(s)
+	}
+}
+
+// drain waits until the in-flight requests of the service complete or the timeout is reached.
+func (s *Service) drain(timeout time.Duration) {
+	deadline := time.Now().Add(timeout)
+
+	for s.active.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	slog.Debug(slog.LogOpts{
+		Msg:   "service drained",
+		Level: slog.DL2,
+		Payload: []zap.Field{
+			zap.String("arn", s.arn),
+			zap.Int64("active", s.active.Load()),
+		},
+	})
+}
+
+// handOff drains and kills the service that holds the custom port of this service,
+// and waits until the port is released.
+func (s *Service) handOff(prev *Service) {
+	prev.drain(drainTimeout)
+	prev.Kill()
+
+	deadline := time.Now().Add(killGracePeriod + time.Second)
+
+	for utils.IsPortInUse(s.port) && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// waitUntilReady waits until the service accepts requests and passes its health check.
+func (s *Service) waitUntilReady(timeout time.Duration) error {
+	hc := s.args.HealthCheck
+
+	if hc == nil {
+		hc = &HealthCheck{Type: HealthCheckTCP, Timeout: time.Second}
+	}
+
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if s.killed.Load() {
+			return errors.New("service was stopped")
+		}
+
+		if s.IsCrashLooping() {
+			return errors.New("service is crash looping")
+		}
+
+		if s.started.Load() && !s.isSettingUp.Load() && s.check(hc) == nil {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return errors.New("timed out waiting for the service to start")
+		}
+	}
+
+	return nil
+}
@@ -3,9 +3,11 @@ package integrations_test
 import (
 	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"os/exec"
 	"path"
 	"strings"
 	"sync"
@@ -17,6 +19,7 @@ import (
 	"github.com/stormkit-io/stormkit-io/src/lib/html"
 	"github.com/stormkit-io/stormkit-io/src/lib/integrations"
 	"github.com/stormkit-io/stormkit-io/src/lib/shttp"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils"
 	"github.com/stormkit-io/stormkit-io/src/lib/utils/file"
 	"github.com/stretchr/testify/suite"
@@ -173,6 +176,126 @@ func (s *ProcessManagerSuite) Test_Invoke_WithServerCmd() {
 	s.Equal("Hello, https://example.org!\n", string(result.Body))
 }
 
+func (s *ProcessManagerSuite) Test_Invoke_PythonVirtualEnv() {
+	appDir := path.Join(s.tmpdir, "python-app")
+	venvBin := path.Join(appDir, ".venv", "bin")
+	python, err := exec.LookPath("python3")
+
+	if err != nil {
+		s.T().Skip("python3 is not installed")
+	}
+
+	// Sample WSGI application that echoes the active virtual environment
+	s.NoError(os.MkdirAll(venvBin, 0755))
+	s.NoError(os.Symlink(python, path.Join(venvBin, "python")))
+	s.NoError(os.WriteFile(path.Join(appDir, "app.py"), []byte(`
+import os
+from wsgiref.simple_server import make_server
+
+def app(environ, start_response):
+    start_response("200 OK", [("Content-Type", "text/plain")])
+    return [os.environ["VIRTUAL_ENV"].encode()]
+
+make_server("127.0.0.1", int(os.environ["PORT"]), app).serve_forever()
+`), 0664))
+
+	result, err := s.pm.Invoke(integrations.InvokeArgs{
+		URL:          &url.URL{},
+		ARN:          fmt.Sprintf("local:%s:python_virtual_env", path.Join(appDir, "app.py")),
+		Method:       shttp.MethodGet,
+		Command:      "python app.py",
+		HostName:     "example.org",
+		DeploymentID: 1,
+	}, appDir)
+
+	s.NoError(err)
+	s.NotEmpty(result)
+	s.Equal(path.Join(appDir, ".venv"), string(result.Body))
+}
+
+func (s *ProcessManagerSuite) Test_Invoke_PythonVirtualEnv_Relocated() {
+	appDir := path.Join(s.tmpdir, "python-relocated-app")
+	venvBin := path.Join(appDir, ".venv", "bin")
+	out, err := exec.Command("python3", "-c", "import sys; print('%d.%d' % sys.version_info[:2])").Output()
+
+	if err != nil {
+		s.T().Skip("python3 is not installed")
+	}
+
+	version := strings.TrimSpace(string(out))
+	python, err := exec.LookPath("python" + version)
+
+	if err != nil {
+		s.T().Skipf("python%s is not installed", version)
+	}
+
+	// The virtual environment refers to the interpreter of the runner.
+	s.NoError(os.MkdirAll(venvBin, 0755))
+	s.NoError(os.Symlink("/opt/runner/python/bin/python3", path.Join(venvBin, "python")))
+	s.NoError(os.WriteFile(path.Join(appDir, ".venv", "pyvenv.cfg"), []byte(fmt.Sprintf("home = /opt/runner/python/bin\ninclude-system-site-packages = false\nversion = %s.1\n", version)), 0644))
+	s.NoError(os.WriteFile(path.Join(appDir, "app.py"), []byte(`
+import os
+from wsgiref.simple_server import make_server
+
+def app(environ, start_response):
+    start_response("200 OK", [("Content-Type", "text/plain")])
+    return [os.environ["PATH"].encode()]
+
+make_server("127.0.0.1", int(os.environ["PORT"]), app).serve_forever()
+`), 0664))
+
+	result, err := s.pm.Invoke(integrations.InvokeArgs{
+		URL:          &url.URL{},
+		ARN:          fmt.Sprintf("local:%s:python_virtual_env_relocated", path.Join(appDir, "app.py")),
+		Method:       shttp.MethodGet,
+		Command:      "python app.py",
+		HostName:     "example.org",
+		DeploymentID: 1,
+		EnvVariables: map[string]string{"PATH": "/usr/local/bin"},
+	}, appDir)
+
+	s.NoError(err)
+	s.NotEmpty(result)
+	s.Equal(1, strings.Count(string(result.Body), venvBin))
+
+	cfg, err := os.ReadFile(path.Join(appDir, ".venv", "pyvenv.cfg"))
+	s.NoError(err)
+	s.Contains(string(cfg), fmt.Sprintf("home = %s\n", path.Dir(python)))
+
+	target, err := os.Readlink(path.Join(venvBin, "python"))
+	s.NoError(err)
+	s.Equal(python, target)
+}
+
+func (s *ProcessManagerSuite) Test_Invoke_PythonVirtualEnv_VersionNotInstalled() {
+	appDir := path.Join(s.tmpdir, "python-version-mismatch-app")
+	venvBin := path.Join(appDir, ".venv", "bin")
+	cfg := "home = /opt/runner/python/bin\ninclude-system-site-packages = false\nversion = 3.0.99\n"
+
+	s.NoError(os.MkdirAll(venvBin, 0755))
+	s.NoError(os.Symlink("/opt/runner/python/bin/python3", path.Join(venvBin, "python")))
+	s.NoError(os.WriteFile(path.Join(appDir, ".venv", "pyvenv.cfg"), []byte(cfg), 0644))
+	s.NoError(os.WriteFile(path.Join(appDir, "app.py"), []byte("print('hello')"), 0664))
+
+	result, err := s.pm.Invoke(integrations.InvokeArgs{
+		URL:          &url.URL{},
+		ARN:          fmt.Sprintf("local:%s:python_version_not_installed", path.Join(appDir, "app.py")),
+		Method:       shttp.MethodGet,
+		Command:      "python app.py",
+		HostName:     "example.org",
+		DeploymentID: 1,
+	}, appDir)
+
+	s.Nil(result)
+	s.Error(err)
+	s.Contains(err.Error(), "python3.0 is not installed")
+
+	// The virtual environment is left untouched.
+	data, err := os.ReadFile(path.Join(appDir, ".venv", "pyvenv.cfg"))
+	s.NoError(err)
+	s.Equal(cfg, string(data))
+}
+
 func (s *ProcessManagerSuite) Test_CustomPortHandling_Published() {
 	args := &integrations.InvokeArgs{
 		URL:          &url.URL{},
@@ -290,6 +413,704 @@ func (s *ProcessManagerSuite) Test_Kill_TerminatesChildProcesses() {
 	}
 }
 
+func (s *ProcessManagerSuite) Test_KillEnv() {
+	fileName := path.Join(s.tmpdir, "index.js")
+
+	killed := integrations.InvokeArgs{
+		URL:          &url.URL{},
+		ARN:          fmt.Sprintf("local:%s:kill_env_1", fileName),
+		Method:       shttp.MethodGet,
+		Command:      "node index.js",
+		HostName:     "example.org",
+		DeploymentID: 1,
+		EnvID:        51,
+	}
+
+	kept := killed
+	kept.ARN = fmt.Sprintf("local:%s:kill_env_2", fileName)
+	kept.DeploymentID = 2
+	kept.EnvID = 52
+
+	for _, args := range []integrations.InvokeArgs{killed, kept} {
+		result, err := s.pm.Invoke(args, s.tmpdir)
+		s.NoError(err)
+		s.Equal("Hello, https://example.org!\n", string(result.Body))
+	}
+
+	s.Equal(1, s.pm.KillEnv(51))
+	s.Nil(s.pm.GetService(killed.ARN))
+	s.NotNil(s.pm.GetService(kept.ARN))
+	s.Equal(0, s.pm.KillEnv(51))
+}
+
+func (s *ProcessManagerSuite) Test_RestartPolicy_CrashLoop() {
+	args := integrations.InvokeArgs{
+		URL:          &url.URL{},
+		ARN:          fmt.Sprintf("local:%s:crash_loop", path.Join(s.tmpdir, "index.js")),
+		Method:       shttp.MethodGet,
+		Command:      "node -e 'process.exit(3)'",
+		HostName:     "example.org",
+		DeploymentID: 3,
+		EnvID:        53,
+		RestartPolicy: &integrations.RestartPolicy{
+			Policy:      integrations.RestartOnFailure,
+			MaxRestarts: 2,
+			Backoff:     10 * time.Millisecond,
+			MaxBackoff:  20 * time.Millisecond,
+		},
+	}
+
+	statuses := make(chan integrations.ServiceStatus, 10)
+
+	s.pm.OnStatusChange(func(status integrations.ServiceStatus) {
+		if status.ARN == args.ARN {
+			statuses <- status
+		}
+	})
+
+	defer s.pm.OnStatusChange(nil)
+
+	_, err := s.pm.Invoke(args, s.tmpdir)
+	s.NoError(err)
+
+	s.Eventually(func() bool {
+		service := s.pm.GetService(args.ARN)
+		return service != nil && service.IsCrashLooping()
+	}, 5*time.Second, 50*time.Millisecond)
+
+	status := s.pm.GetService(args.ARN).Status()
+	s.Equal(integrations.ServiceStateCrashLoop, status.State)
+	s.Equal(2, status.Restarts)
+	s.Equal(3, *status.LastExitCode)
+	s.Equal(types.ID(53), status.EnvID)
+	s.Equal("service exited with code 3", status.LastError)
+
+	states := []string{}
+
+	for len(statuses) > 0 {
+		states = append(states, (<-statuses).State)
+	}
+
+	s.Equal([]string{
+		integrations.ServiceStateRunning,
+		integrations.ServiceStateRestarting,
+		integrations.ServiceStateRunning,
+		integrations.ServiceStateRestarting,
+		integrations.ServiceStateRunning,
+		integrations.ServiceStateCrashLoop,
+	}, states)
+
+	result, err := s.pm.Invoke(args, s.tmpdir)
+	s.NoError(err)
+	s.Equal(http.StatusServiceUnavailable, result.StatusCode)
+	s.Contains(string(result.Body), "Service keeps crashing")
+
+	s.Equal(1, s.pm.KillEnv(53))
+}
+
+func (s *ProcessManagerSuite) Test_HealthCheck_RestartsUnhealthyService() {
+	s.NoError(os.WriteFile(path.Join(s.tmpdir, "index-unhealthy.js"), []byte(`
+		const http = require('http');
+
+		http.createServer((req, res) => {
+			res.statusCode = req.url === '/health' ? 500 : 200;
+			res.end('ok');
+		}).listen(process.env.PORT, '127.0.0.1');
+	`), 0664))
+
+	args := integrations.InvokeArgs{
+		URL:          &url.URL{},
+		ARN:          fmt.Sprintf("local:%s:unhealthy", path.Join(s.tmpdir, "index-unhealthy.js")),
+		Method:       shttp.MethodGet,
+		Command:      "node index-unhealthy.js",
+		HostName:     "example.org",
+		DeploymentID: 4,
+		EnvID:        54,
+		HealthCheck: &integrations.HealthCheck{
+			Type:             integrations.HealthCheckHTTP,
+			Path:             "/health",
+			Interval:         100 * time.Millisecond,
+			Timeout:          50 * time.Millisecond,
+			FailureThreshold: 2,
+		},
+		RestartPolicy: &integrations.RestartPolicy{
+			Policy:      integrations.RestartOnFailure,
+			MaxRestarts: 5,
+			Backoff:     10 * time.Millisecond,
+			MaxBackoff:  10 * time.Millisecond,
+		},
+	}
+
+	result, err := s.pm.Invoke(args, s.tmpdir)
+	s.NoError(err)
+	s.Equal(http.StatusOK, result.StatusCode)
+
+	s.Eventually(func() bool {
+		service := s.pm.GetService(args.ARN)
+		return service != nil && service.Status().Restarts >= 1
+	}, 5*time.Second, 50*time.Millisecond)
+
+	status := s.pm.GetService(args.ARN).Status()
+	s.Contains(status.LastError, "health check failed: unexpected status code 500")
+
+	s.Equal(1, s.pm.KillEnv(54))
+}
+
+func (s *ProcessManagerSuite) Test_Replicas() {
+	s.NoError(os.WriteFile(path.Join(s.tmpdir, "index-pid.js"), []byte(`
+		const http = require('http');
+
+		http.createServer((req, res) => {
+			res.end(String(process.pid));
+		}).listen(process.env.PORT, '127.0.0.1');
+	`), 0664))
+
+	args := integrations.InvokeArgs{
+		URL:          &url.URL{},
+		ARN:          fmt.Sprintf("local:%s:replicas", path.Join(s.tmpdir, "index-pid.js")),
+		Method:       shttp.MethodGet,
+		Command:      "node index-pid.js",
+		HostName:     "example.org",
+		DeploymentID: 5,
+		EnvID:        55,
+		Replicas: &integrations.Replicas{
+			Count:    3,
+			Strategy: integrations.BalanceRoundRobin,
+		},
+	}
+
+	pids := map[string]bool{}
+
+	s.Eventually(func() bool {
+		result, err := s.pm.Invoke(args, s.tmpdir)
+		s.NoError(err)
+
+		if result.StatusCode == http.StatusOK {
+			pids[string(result.Body)] = true
+		}
+
+		return len(pids) == 3
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// Crash one of the replicas, the remaining ones keep serving requests.
+	replica := s.pm.GetService(args.ARN + "#replica-1")
+	s.NotNil(replica)
+	s.NoError(syscall.Kill(replica.Pid(), syscall.SIGKILL))
+
+	s.Eventually(func() bool {
+		return s.pm.GetService(args.ARN+"#replica-1") != replica
+	}, 5*time.Second, 50*time.Millisecond)
+
+	for range 6 {
+		result, err := s.pm.Invoke(args, s.tmpdir)
+		s.NoError(err)
+		s.Equal(http.StatusOK, result.StatusCode)
+	}
+
+	s.Equal(3, s.pm.KillEnv(55))
+}
+
+func (s *ProcessManagerSuite) Test_Resources() {
+	s.NoError(os.WriteFile(path.Join(s.tmpdir, "index-alloc.js"), []byte(`
+		const http = require('http');
+		const buffers = [];
+
+		http.createServer((req, res) => {
+			res.end('ok');
+
+			if (req.url === '/alloc') {
+				setImmediate(() => {
+					for (let i = 0; i < 32; i++) buffers.push(Buffer.alloc(8 * 1024 * 1024, 1));
+				});
+			}
+		}).listen(process.env.PORT, '127.0.0.1');
+	`), 0664))
+
+	args := integrations.InvokeArgs{
+		URL:          &url.URL{},
+		ARN:          fmt.Sprintf("local:%s:resources", path.Join(s.tmpdir, "index-alloc.js")),
+		Method:       shttp.MethodGet,
+		Command:      "node index-alloc.js",
+		HostName:     "example.org",
+		DeploymentID: 6,
+		EnvID:        66,
+		Resources: &integrations.Resources{
+			MemoryBytes: 128 * 1024 * 1024,
+		},
+		RestartPolicy: &integrations.RestartPolicy{
+			Policy: integrations.RestartNever,
+		},
+	}
+
+	result, err := s.pm.Invoke(args, s.tmpdir)
+	s.NoError(err)
+	s.Equal(http.StatusOK, result.StatusCode)
+
+	var status *integrations.ServiceStatus
+
+	for _, st := range s.pm.Statuses() {
+		if st.ARN == args.ARN {
+			status = &st
+		}
+	}
+
+	s.NotNil(status)
+	s.Equal(int64(128*1024*1024), status.Usage.MemoryLimit)
+	s.Greater(status.Usage.MemoryBytes, int64(0))
+
+	// Allocating more memory than the limit terminates the process.
+	args.URL = &url.URL{Path: "/alloc"}
+	result, err = s.pm.Invoke(args, s.tmpdir)
+	s.NoError(err)
+	s.Equal(http.StatusOK, result.StatusCode)
+
+	s.Eventually(func() bool {
+		return s.pm.GetService(args.ARN) == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func (s *ProcessManagerSuite) Test_Resources_Isolated() {
+	if os.Geteuid() != 0 {
+		s.T().Skip("processes can only be isolated when running as root")
+	}
+
+	workDir := path.Join(s.tmpdir, "isolated")
+	s.NoError(os.MkdirAll(workDir, 0755))
+	s.NoError(os.Chmod(s.tmpdir, 0755))
+	s.NoError(os.WriteFile(path.Join(workDir, "index.js"), []byte(`
+		require('fs').writeFileSync('written.txt', 'ok');
+
+		require('http').createServer((req, res) => {
+			res.end(process.getuid() + ':' + process.env.HOME);
+		}).listen(process.env.PORT, '127.0.0.1');
+	`), 0664))
+
+	args := integrations.InvokeArgs{
+		URL:          &url.URL{},
+		ARN:          fmt.Sprintf("local:%s:isolated", path.Join(workDir, "index.js")),
+		Method:       shttp.MethodGet,
+		Command:      "node index.js",
+		HostName:     "example.org",
+		DeploymentID: 7,
+		EnvID:        77,
+		Resources:    &integrations.Resources{UID: 100007},
+	}
+
+	result, err := s.pm.Invoke(args, workDir)
+	s.NoError(err)
+	s.Equal(http.StatusOK, result.StatusCode)
+	s.Equal(fmt.Sprintf("100007:%s", workDir), string(result.Body))
+	s.FileExists(path.Join(workDir, "written.txt"))
+
+	s.pm.KillEnv(args.EnvID)
+
+	// The work directory is handed over once, files added later keep their owner.
+	s.NoError(os.WriteFile(path.Join(workDir, "root.txt"), []byte("root"), 0664))
+
+	result, err = s.pm.Invoke(args, workDir)
+	s.NoError(err)
+	s.Equal(http.StatusOK, result.StatusCode)
+
+	info, err := os.Stat(path.Join(workDir, "root.txt"))
+	s.NoError(err)
+	s.Equal(uint32(0), info.Sys().(*syscall.Stat_t).Uid)
+
+	s.pm.KillEnv(args.EnvID)
+}
+
+func (s *ProcessManagerSuite) Test_Resources_Isolated_StopScript() {
+	if os.Geteuid() != 0 {
+		s.T().Skip("processes can only be isolated when running as root")
+	}
+
+	workDir := path.Join(s.tmpdir, "isolated-stop")
+	s.NoError(os.MkdirAll(workDir, 0755))
+	s.NoError(os.Chmod(s.tmpdir, 0755))
+	s.NoError(os.WriteFile(path.Join(workDir, "index.js"), []byte(`
+		require('http').createServer((req, res) => res.end('ok')).listen(process.env.PORT, '127.0.0.1');
+	`), 0664))
+
+	content, err := yaml.Marshal(map[string]any{
+		"stop": []string{"touch stopped.txt"},
+	})
+
+	s.NoError(err)
+	s.NoError(os.WriteFile(path.Join(workDir, "stormkit.server.yml"), content, 0755))
+
+	args := integrations.InvokeArgs{
+		URL:          &url.URL{},
+		ARN:          fmt.Sprintf("local:%s:isolated_stop", path.Join(workDir, "index.js")),
+		Method:       shttp.MethodGet,
+		Command:      "node index.js",
+		HostName:     "example.org",
+		DeploymentID: 7,
+		EnvID:        78,
+		Resources:    &integrations.Resources{UID: 100008},
+	}
+
+	s.Eventually(func() bool {
+		result, err := s.pm.Invoke(args, workDir)
+		return err == nil && string(result.Body) == "ok"
+	}, 5*time.Second, 100*time.Millisecond)
+
+	s.pm.KillEnv(args.EnvID)
+
+	// The file is owned by the user that ran the stop script.
+	info, err := os.Stat(path.Join(workDir, "stopped.txt"))
+	s.NoError(err)
+	s.Equal(uint32(100008), info.Sys().(*syscall.Stat_t).Uid)
+}
+
+func (s *ProcessManagerSuite) Test_Workers() {
+	workDir := path.Join(s.tmpdir, "workers")
+	s.NoError(os.MkdirAll(workDir, 0755))
+	s.NoError(os.WriteFile(path.Join(workDir, "worker.js"), []byte(`
+		console.log("consuming, port: " + (process.env.PORT || "none"));
+		setTimeout(() => process.exit(1), 200);
+	`), 0664))
+
+	logs := make(chan *integrations.Log, 100)
+
+	args := integrations.InvokeArgs{
+		Command:      "node worker.js",
+		DeploymentID: 7,
+		EnvID:        88,
+		Worker:       "consumer",
+		QueueLog:     func(l *integrations.Log) { logs <- l },
+	}
+
+	s.NoError(s.pm.StartWorker(args, workDir))
+
+	arn := integrations.WorkerARN(7, "consumer")
+
+	// Workers are started without incoming requests and restarted when they exit.
+	s.Eventually(func() bool {
+		service := s.pm.GetService(arn)
+		return service != nil && service.Status().Restarts > 0
+	}, 10*time.Second, 50*time.Millisecond)
+
+	// Starting a running worker is a no-op.
+	s.NoError(s.pm.StartWorker(args, workDir))
+	s.Len(s.pm.Workers(88), 1)
+	s.Equal("consumer", s.pm.Workers(88)[0].Status().Worker)
+
+	s.Eventually(func() bool {
+		select {
+		case l := <-logs:
+			return l.Level == "worker:consumer" && strings.Contains(l.Message, "consuming, port: none")
+		default:
+			return false
+		}
+	}, 5*time.Second, 50*time.Millisecond)
+
+	s.Equal(0, s.pm.StopWorkers(88, "other"))
+	s.Equal(1, s.pm.StopWorkers(88, "consumer"))
+	s.Nil(s.pm.GetService(arn))
+	s.Empty(s.pm.Workers(88))
+}
+
+func (s *ProcessManagerSuite) Test_Workers_RestartPolicy() {
+	workDir := path.Join(s.tmpdir, "workers-restart-policy")
+	s.NoError(os.MkdirAll(workDir, 0755))
+	s.NoError(os.WriteFile(path.Join(workDir, "worker.js"), []byte(`
+		setTimeout(() => process.exit(0), 100);
+	`), 0664))
+
+	ctx := context.Background()
+	envID := types.ID(89)
+	s.NoError(integrations.ClearStoppedWorkers(ctx, envID))
+
+	s.NoError(s.pm.StartWorker(integrations.InvokeArgs{
+		Command:      "node worker.js",
+		DeploymentID: 7,
+		EnvID:        envID,
+		Worker:       "oneshot",
+		RestartPolicy: &integrations.RestartPolicy{
+			Policy: integrations.RestartOnFailure,
+		},
+	}, workDir))
+
+	// Workers that exit cleanly are not restarted and are not started again by the synchronization.
+	s.Eventually(func() bool {
+		stopped, err := integrations.StoppedWorkers(ctx, envID)
+		return err == nil && stopped["oneshot"]
+	}, 5*time.Second, 50*time.Millisecond)
+
+	s.Nil(s.pm.GetService(integrations.WorkerARN(7, "oneshot")))
+	s.NoError(integrations.ClearStoppedWorkers(ctx, envID))
+}
+
+func (s *ProcessManagerSuite) Test_Kill_WhileRestarting() {
+	args := integrations.InvokeArgs{
+		Command:      "node -e 'process.exit(1)'",
+		DeploymentID: 8,
+		EnvID:        89,
+		Worker:       "restarting",
+		RestartPolicy: &integrations.RestartPolicy{
+			Policy:      integrations.RestartOnFailure,
+			MaxRestarts: 5,
+			Backoff:     500 * time.Millisecond,
+			MaxBackoff:  500 * time.Millisecond,
+		},
+	}
+
+	s.NoError(s.pm.StartWorker(args, s.tmpdir))
+
+	service := s.pm.GetService(integrations.WorkerARN(8, "restarting"))
+	s.NotNil(service)
+
+	s.Eventually(func() bool {
+		return service.Status().State == integrations.ServiceStateRestarting
+	}, 5*time.Second, 10*time.Millisecond)
+
+	pid := service.Pid()
+	service.Kill()
+
+	// The service is not started again once the backoff elapses.
+	time.Sleep(time.Second)
+	s.Equal(pid, service.Pid())
+	s.Equal(integrations.ServiceStateStopped, service.Status().State)
+	s.Empty(s.pm.Workers(89))
+}
+
+func (s *ProcessManagerSuite) Test_Kill_BeforeStart() {
+	workDir := path.Join(s.tmpdir, "kill-before-start")
+	s.NoError(os.MkdirAll(workDir, 0755))
+	s.NoError(os.WriteFile(path.Join(workDir, "index.js"), []byte(`
+		require('http').createServer((req, res) => res.end('ok')).listen(process.env.PORT, '127.0.0.1');
+	`), 0664))
+
+	content, err := yaml.Marshal(map[string]any{
+		"setup": []string{"sleep 1"},
+	})
+
+	s.NoError(err)
+	s.NoError(os.WriteFile(path.Join(workDir, "stormkit.server.yml"), content, 0755))
+
+	args := integrations.InvokeArgs{
+		URL:          &url.URL{},
+		ARN:          fmt.Sprintf("local:%s:kill_before_start", path.Join(workDir, "index.js")),
+		Method:       shttp.MethodGet,
+		Command:      "node index.js",
+		HostName:     "example.org",
+		DeploymentID: 9,
+		EnvID:        90,
+	}
+
+	result, err := s.pm.Invoke(args, workDir)
+	s.NoError(err)
+	s.Contains(string(result.Body), "Service is currently being set up")
+
+	service := s.pm.GetService(args.ARN)
+	s.NotNil(service)
+
+	// The setup script is still running, so the process is not started yet.
+	s.Equal(1, s.pm.KillEnv(args.EnvID))
+	s.Nil(s.pm.GetService(args.ARN))
+
+	// The process is not started once the setup script finishes.
+	time.Sleep(1500 * time.Millisecond)
+	s.Equal(0, service.Pid())
+	s.Equal(integrations.ServiceStateStopped, service.Status().State)
+}
+
+func (s *ProcessManagerSuite) swapArgs(version string, deploymentID types.ID, vars map[string]string) integrations.InvokeArgs {
+	workDir := path.Join(s.tmpdir, "swap")
+	s.NoError(os.MkdirAll(workDir, 0755))
+	s.NoError(os.WriteFile(path.Join(workDir, "index-version.js"), []byte(`
+		const http = require('http');
+
+		// Simulate a slow start so that the swap has to wait for the service.
+		setTimeout(() => {
+			http.createServer((req, res) => {
+				if (req.url === '/reset') {
+					return req.socket.destroy();
+				}
+
+				const delay = req.url === '/slow' ? 1000 : 0;
+				setTimeout(() => res.end(process.env.VERSION), delay);
+			}).listen(process.env.PORT, '127.0.0.1');
+		}, 500);
+	`), 0664))
+
+	if vars == nil {
+		vars = map[string]string{}
+	}
+
+	vars["VERSION"] = version
+
+	return integrations.InvokeArgs{
+		URL:          &url.URL{},
+		ARN:          fmt.Sprintf("local:%s/%s/index-version.js", workDir, version),
+		Method:       shttp.MethodGet,
+		Command:      "node index-version.js",
+		HostName:     "example.org",
+		DeploymentID: deploymentID,
+		EnvID:        77,
+		IsPublished:  true,
+		FullTraffic:  true,
+		EnvVariables: vars,
+	}
+}
+
+// invokeUntil sends the requests one after another, so that no request is
+// in-flight once it returns. Requests that outlive a test would start the
+// services of the swap tests again, as they share the same ARNs.
+func (s *ProcessManagerSuite) invokeUntil(args integrations.InvokeArgs, body string) {
+	deadline := time.Now().Add(10 * time.Second)
+
+	for time.Now().Before(deadline) {
+		result, err := s.pm.Invoke(args, path.Join(s.tmpdir, "swap"))
+
+		if err == nil && string(result.Body) == body {
+			return
+		}
+
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	s.Failf("unexpected response", "expected %s from %s", body, args.ARN)
+}
+
+func (s *ProcessManagerSuite) Test_Swap() {
+	workDir := path.Join(s.tmpdir, "swap")
+	v1 := s.swapArgs("v1", 10, nil)
+	v2 := s.swapArgs("v2", 11, nil)
+
+	s.invokeUntil(v1, "v1")
+
+	// Keep a request in-flight while the traffic is switched.
+	slow := make(chan string)
+
+	go func() {
+		args := v1
+		args.URL = &url.URL{Path: "/slow"}
+		result, err := s.pm.Invoke(args, workDir)
+		s.NoError(err)
+		slow <- string(result.Body)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	s.NoError(s.pm.Swap(v2, workDir))
+
+	// The previous deployment serves the requests while the new service is starting.
+	result, err := s.pm.Invoke(v2, workDir)
+	s.NoError(err)
+	s.Equal("v1", string(result.Body))
+
+	s.invokeUntil(v2, "v2")
+	s.Equal("v1", <-slow)
+
+	s.Eventually(func() bool {
+		return s.pm.GetService(v1.ARN) == nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	s.Equal(1, s.pm.KillEnv(77))
+}
+
+func (s *ProcessManagerSuite) Test_Swap_PreviousServiceFails() {
+	workDir := path.Join(s.tmpdir, "swap")
+	v1 := s.swapArgs("v1", 21, nil)
+	v2 := s.swapArgs("v2", 22, nil)
+
+	s.invokeUntil(v1, "v1")
+	s.NoError(s.pm.Swap(v2, workDir))
+
+	// The request reached the previous service, so it is not sent to the new service.
+	args := v2
+	args.Method = shttp.MethodPost
+	args.URL = &url.URL{Path: "/reset"}
+	args.Body = io.NopCloser(strings.NewReader(`{"hello":"world"}`))
+
+	result, err := s.pm.Invoke(args, workDir)
+	s.Error(err)
+	s.Nil(result)
+
+	s.invokeUntil(v2, "v2")
+	s.Equal(1, s.pm.KillEnv(77))
+}
+
+func (s *ProcessManagerSuite) Test_Swap_OnFirstRequest() {
+	workDir := path.Join(s.tmpdir, "swap")
+	v1 := s.swapArgs("v1", 14, nil)
+	v2 := s.swapArgs("v2", 15, nil)
+
+	s.invokeUntil(v1, "v1")
+
+	// The request for the new deployment arrives before the swap event.
+	result, err := s.pm.Invoke(v2, workDir)
+	s.NoError(err)
+	s.Equal("v1", string(result.Body))
+
+	s.invokeUntil(v2, "v2")
+
+	s.Eventually(func() bool {
+		return s.pm.GetService(v1.ARN) == nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	s.Equal(1, s.pm.KillEnv(77))
+}
+
+func (s *ProcessManagerSuite) Test_Swap_SplitTraffic() {
+	v1 := s.swapArgs("v1", 17, nil)
+	v2 := s.swapArgs("v2", 18, nil)
+	v1.FullTraffic = false
+	v2.FullTraffic = false
+
+	// Deployments that share the traffic do not replace each other.
+	s.invokeUntil(v1, "v1")
+	s.invokeUntil(v2, "v2")
+	s.invokeUntil(v1, "v1")
+
+	s.NotNil(s.pm.GetService(v1.ARN))
+	s.NotNil(s.pm.GetService(v2.ARN))
+	s.Equal(2, s.pm.KillEnv(77))
+}
+
+func (s *ProcessManagerSuite) Test_Swap_NotReady() {
+	workDir := path.Join(s.tmpdir, "swap")
+	v1 := s.swapArgs("v1", 19, nil)
+	v2 := s.swapArgs("v2", 20, nil)
+	v2.Command = "node -e 'process.exit(1)'"
+	v2.RestartPolicy = &integrations.RestartPolicy{Policy: integrations.RestartOnFailure}
+
+	s.invokeUntil(v1, "v1")
+	s.NoError(s.pm.Swap(v2, workDir))
+
+	// The swap is aborted and the previous deployment keeps serving the requests.
+	s.Eventually(func() bool {
+		return s.pm.GetService(v2.ARN) == nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	s.NotNil(s.pm.GetService(v1.ARN))
+	s.Equal(1, s.pm.KillEnv(77))
+}
+
+func (s *ProcessManagerSuite) Test_Swap_WithoutPreviousService() {
+	args := s.swapArgs("v1", 16, nil)
+	args.EnvID = 78
+
+	// Instances that are not running the previous deployment do not pre-warm the service.
+	s.NoError(s.pm.Swap(args, path.Join(s.tmpdir, "swap")))
+	s.Nil(s.pm.GetService(args.ARN))
+}
+
+func (s *ProcessManagerSuite) Test_Swap_CustomPort() {
+	workDir := path.Join(s.tmpdir, "swap")
+	v1 := s.swapArgs("v1", 12, map[string]string{"PORT": "9010"})
+	v2 := s.swapArgs("v2", 13, map[string]string{"PORT": "9010"})
+
+	s.invokeUntil(v1, "v1")
+	s.NoError(s.pm.Swap(v2, workDir))
+	s.invokeUntil(v2, "v2")
+
+	s.Eventually(func() bool {
+		return s.pm.GetService(v1.ARN) == nil
+	}, 5*time.Second, 50*time.Millisecond)
+
+	s.Equal(1, s.pm.KillEnv(77))
+}
+
 func (s *ProcessManagerSuite) Test_ProcessManager_Invoke_CustomPort_Unpublished() {
 	result, err := s.pm.Invoke(integrations.InvokeArgs{
 		URL:         &url.URL{},
@@ -0,0 +1,249 @@
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+	"github.com/stormkit-io/stormkit-io/src/lib/types"
+	"go.uber.org/zap"
+)
+
+// defaultWorkerRestartPolicy is used for workers when the environment has no restart policy.
+var defaultWorkerRestartPolicy = &RestartPolicy{
+	Policy:      RestartAlways,
+	MaxRestarts: 5,
+	Backoff:     time.Second,
+	MaxBackoff:  time.Minute,
+}
+
+// The actions that can be performed on the workers of an environment.
+const (
+	WorkerActionStart   = "start"
+	WorkerActionStop    = "stop"
+	WorkerActionRestart = "restart"
+)
+
+// WorkerClaimTTL is the duration an instance owns the workers of an environment
+// after its last claim.
+var WorkerClaimTTL = 3 * time.Minute
+
+// WorkerARN returns the ARN of the worker process type of the deployment.
+func WorkerARN(deploymentID types.ID, name string) string {
+	return fmt.Sprintf("worker:%s:%s", deploymentID.String(), name)
+}
+
+// WorkerLabel returns the label of the runtime logs of the worker process type.
+func WorkerLabel(name string) string {
+	return fmt.Sprintf("worker:%s", name)
+}
+
+// StartWorker starts the worker process type given in args.Worker. Workers do not
+// listen to a port and are not invoked: they are kept running until they are stopped,
+// regardless of traffic. Running workers are left untouched.
+func (pm *ProcessManager) StartWorker(args InvokeArgs, workDir string) error {
+	if args.Worker == "" {
+		return fmt.Errorf("worker name is required")
+	}
+
+	args.ARN = WorkerARN(args.DeploymentID, args.Worker)
+	args.IsPublished = true
+	args.CaptureLogs = true
+	args.HealthCheck = nil
+	args.Replicas = nil
+
+	if args.RestartPolicy == nil {
+		args.RestartPolicy = defaultWorkerRestartPolicy
+	}
+
+	if service := pm.GetService(args.ARN); service != nil && !service.killed.Load() {
+		return nil
+	}
+
+	service, err := pm.Start(context.TODO(), &args, workDir)
+
+	if err != nil {
+		return err
+	}
+
+	pm.addService(service, args.ARN)
+
+	slog.Debug(slog.LogOpts{
+		Msg:   "worker started",
+		Level: slog.DL2,
+		Payload: []zap.Field{
+			zap.String("arn", args.ARN),
+			zap.String("env_id", args.EnvID.String()),
+		},
+	})
+
+	return nil
+}
+
+// Workers returns the workers that are running for the environment.
+func (pm *ProcessManager) Workers(envID types.ID) []*Service {
+	pm.mux.Lock()
+	defer pm.mux.Unlock()
+
+	workers := []*Service{}
+
+	for _, s := range pm.services {
+		if s.args.Worker != "" && s.args.EnvID == envID {
+			workers = append(workers, s)
+		}
+	}
+
+	sort.Slice(workers, func(i, j int) bool {
+		return workers[i].arn < workers[j].arn
+	})
+
+	return workers
+}
+
+// WorkerEnvs returns the environments that have running workers on this instance.
+func (pm *ProcessManager) WorkerEnvs() []types.ID {
+	pm.mux.Lock()
+	defer pm.mux.Unlock()
+
+	visited := map[types.ID]bool{}
+	envIDs := []types.ID{}
+
+	for _, s := range pm.services {
+		if s.args.Worker != "" && !visited[s.args.EnvID] {
+			visited[s.args.EnvID] = true
+			envIDs = append(envIDs, s.args.EnvID)
+		}
+	}
+
+	return envIDs
+}
+
+// StopWorkers stops the workers of the environment with the given name. When the name
+// is empty, all workers of the environment are stopped. It returns the number of stopped workers.
+func (pm *ProcessManager) StopWorkers(envID types.ID, name string) int {
+	stopped := 0
+
+	for _, s := range pm.Workers(envID) {
+		if name == "" || s.args.Worker == name {
+			s.Kill()
+			stopped = stopped + 1
+		}
+	}
+
+	return stopped
+}
+
+// Name returns the name of the worker process type, or an empty string when the service is not a worker.
+func (s *Service) Name() string {
+	return s.args.Worker
+}
+
+// DeploymentID returns the id of the deployment that the service belongs to.
+func (s *Service) DeploymentID() types.ID {
+	return s.args.DeploymentID
+}
+
+// stoppedWorkersKey returns the redis key that holds the workers that were stopped manually.
+func stoppedWorkersKey(envID types.ID) string {
+	return fmt.Sprintf("workers_stopped:%s", envID.String())
+}
+
+// workerOwnerKey returns the redis key that holds the instance which runs the workers of the environment.
+func workerOwnerKey(envID types.ID) string {
+	return fmt.Sprintf("workers_owner:%s", envID.String())
+}
+
+// SetWorkerStopped marks the worker as stopped so that it is not started again
+// until it is started manually or a new deployment is published. Workers are
+// marked as stopped when they are stopped manually, or when they exit and their
+// restart policy does not restart them.
+func SetWorkerStopped(ctx context.Context, envID types.ID, name string, stopped bool) error {
+	client := rediscache.Client()
+
+	if stopped {
+		return client.SAdd(ctx, stoppedWorkersKey(envID), name).Err()
+	}
+
+	return client.SRem(ctx, stoppedWorkersKey(envID), name).Err()
+}
+
+// markWorkerExited marks the worker as stopped after it exited without being restarted,
+// otherwise the synchronization would start it again regardless of its restart policy.
+func (s *Service) markWorkerExited() {
+	if err := SetWorkerStopped(context.Background(), s.args.EnvID, s.args.Worker, true); err != nil {
+		slog.Errorf("cannot mark worker %s as stopped: %s", s.arn, err.Error())
+	}
+}
+
+// ClearStoppedWorkers removes the stopped marks of the workers of the environment.
+func ClearStoppedWorkers(ctx context.Context, envID types.ID) error {
+	return rediscache.Client().Del(ctx, stoppedWorkersKey(envID)).Err()
+}
+
+// StoppedWorkers returns the names of the workers of the environment that were stopped.
+func StoppedWorkers(ctx context.Context, envID types.ID) (map[string]bool, error) {
+	names, err := rediscache.Client().SMembers(ctx, stoppedWorkersKey(envID)).Result()
+
+	if err != nil {
+		return nil, err
+	}
+
+	stopped := map[string]bool{}
+
+	for _, name := range names {
+		stopped[name] = true
+	}
+
+	return stopped, nil
+}
+
+// claimWorkersScript sets the owner of the workers unless another instance owns them.
+// The expiry is renewed only when the key still holds the instance, so that a claim that
+// expired and was taken over by another instance in the meantime is not extended.
+var claimWorkersScript = redis.NewScript(`
+	if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+		return 1
+	end
+
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		redis.call("PEXPIRE", KEYS[1], ARGV[2])
+		return 1
+	end
+
+	return 0
+`)
+
+// releaseWorkersScript removes the owner of the workers only when it is the instance.
+var releaseWorkersScript = redis.NewScript(`
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		return redis.call("DEL", KEYS[1])
+	end
+
+	return 0
+`)
+
+// ClaimWorkers makes the instance responsible for running the workers of the environment,
+// so that workers are not started on every hosting instance. It returns false when the
+// workers are owned by another instance. The claim has to be renewed within WorkerClaimTTL.
+func ClaimWorkers(ctx context.Context, envID types.ID, instance string) (bool, error) {
+	keys := []string{workerOwnerKey(envID)}
+	claimed, err := claimWorkersScript.Run(ctx, rediscache.Client(), keys, instance, WorkerClaimTTL.Milliseconds()).Int()
+
+	if err != nil {
+		return false, err
+	}
+
+	return claimed == 1, nil
+}
+
+// ReleaseWorkers gives up the claim of the instance on the workers of the environment,
+// so that another instance can run them without waiting for the claim to expire. Claims
+// of other instances are left untouched.
+func ReleaseWorkers(ctx context.Context, envID types.ID, instance string) error {
+	keys := []string{workerOwnerKey(envID)}
+	return releaseWorkersScript.Run(ctx, rediscache.Client(), keys, instance).Err()
+}
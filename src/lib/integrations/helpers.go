@@ -20,6 +20,8 @@ import (
 type UploadOverview struct {
 	BytesUploaded int64
 	FilesUploaded int64
+	FilesSkipped  int64    // FilesSkipped is the number of blobs that were already stored
+	Blobs         []string // Blobs are the hashes of the blobs that were uploaded, even when the upload failed
 	Location      string
 }
 
@@ -36,6 +38,10 @@ type UploadArgs struct {
 	// The path to the client zip.
 	ClientZip string
 
+	// When provided, the client files are stored by their content hash
+	// instead of uploading the client zip.
+	ClientBlobs []Blob
+
 	// When provided, this file will be uploaded to the bucket.
 	FilePath string
 
@@ -34,6 +34,11 @@ const (
 	EventInvalidateHostingCache = "cache_invalidate"
 	EventMiseUpdate             = "mise_update"
 	EventRuntimesInstall        = "runtimes_install"
+	EventOSVUpdate              = "osv_update"
+	EventStopDeployment         = "stop_deployment"
+	EventStopEnvServices        = "stop_env_services"
+	EventSwapServices           = "swap_services"
+	EventWorkers                = "workers"
 )
 
 const (
@@ -306,6 +311,29 @@ func Broadcast(event string, payload ...string) error {
 	return Service().Broadcast(event, payload...)
 }
 
+// EncodePayload encodes multiple values into a single payload. Broadcast publishes only
+// one value, so events that carry multiple values have to be encoded with this function.
+func EncodePayload(values ...string) string {
+	data, _ := json.Marshal(values)
+	return string(data)
+}
+
+// DecodePayload decodes the values of a payload that was encoded with EncodePayload.
+// It returns nil when the payload cannot be decoded.
+func DecodePayload(payload ...string) []string {
+	if len(payload) == 0 {
+		return nil
+	}
+
+	values := []string{}
+
+	if err := json.Unmarshal([]byte(payload[0]), &values); err != nil {
+		return nil
+	}
+
+	return values
+}
+
 // SetAll is a convenience function to set a key-value pair across all services with optional filtering.
 func SetAll(key, value string, filter []string) error {
 	return Service().SetAll(key, value, filter)
@@ -55,6 +55,33 @@ func (s *ServiceSuite) Test_SubscribeAndBroadcast() {
 	}, 5*time.Second, 500*time.Millisecond)
 }
 
+func (s *ServiceSuite) Test_SubscribeAndBroadcast_EncodedPayload() {
+	service := rediscache.Service()
+	channel := "test-event-channel-encoded"
+	received := make(chan []string, 10)
+
+	s.NoError(service.SubscribeAsync(channel, func(ctx context.Context, payload ...string) {
+		received <- rediscache.DecodePayload(payload...)
+	}))
+
+	s.Eventually(func() bool {
+		s.NoError(service.Broadcast(channel, rediscache.EncodePayload("my-app", "production")))
+
+		select {
+		case values := <-received:
+			return s.Equal([]string{"my-app", "production"}, values)
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 500*time.Millisecond)
+}
+
+func (s *ServiceSuite) Test_DecodePayload() {
+	s.Equal([]string{"a", "b:c"}, rediscache.DecodePayload(rediscache.EncodePayload("a", "b:c")))
+	s.Nil(rediscache.DecodePayload("not-encoded"))
+	s.Nil(rediscache.DecodePayload())
+}
+
 func TestServiceSuite(t *testing.T) {
 	suite.Run(t, &ServiceSuite{})
 }
@@ -144,6 +144,16 @@ func Infof(msg string, args ...any) {
 	Info(fmt.Sprintf(msg, args...))
 }
 
+// Warn logs warn level stuff.
+func Warn(v ...any) {
+	getLogger().Warn(fmt.Sprint(v...))
+}
+
+// Warnf accepts a formatted string and calls Warn function.
+func Warnf(msg string, args ...any) {
+	Warn(fmt.Sprintf(msg, args...))
+}
+
 // Error logs error level stuff.
 func Error(v ...any) {
 	_, file, no, ok := runtime.Caller(1)
@@ -24,6 +24,7 @@ func init() {
 // A list of task types.
 const (
 	DeploymentStart     = "deployment:start"
+	DeploymentPublish   = "deployment:publish"
 	TriggerFunctionHttp = "triggerfunction:http"
 )
 
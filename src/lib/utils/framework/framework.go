@@ -0,0 +1,203 @@
+package framework
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+)
+
+// Preset represents the build settings proposed for a framework.
+type Preset struct {
+	// ID is the unique identifier of the framework (nextjs, nuxt, astro...).
+	ID string `json:"id"`
+
+	// Name is the human readable name of the framework.
+	Name string `json:"name"`
+
+	// BuildCmd is the command that builds the application.
+	BuildCmd string `json:"buildCmd,omitempty"`
+
+	// DistFolder is the output folder, relative to the working directory.
+	DistFolder string `json:"distFolder,omitempty"`
+
+	// ServerCmd is the command that starts the server, if the framework needs one.
+	ServerCmd string `json:"serverCmd,omitempty"`
+
+	// APIFolder is the folder that contains the serverless api functions.
+	APIFolder string `json:"apiFolder,omitempty"`
+}
+
+// DetectArgs are the inputs used to detect the framework.
+type DetectArgs struct {
+	// Files is the list of file and directory names located at the root
+	// of the working directory.
+	Files []string
+
+	// PackageJson is the raw content of the package.json file, if any.
+	PackageJson []byte
+}
+
+type packageJson struct {
+	Scripts         map[string]string `json:"scripts"`
+	Dependencies    map[string]string `json:"dependencies"`
+	DevDependencies map[string]string `json:"devDependencies"`
+}
+
+// definition describes how to detect a framework and which settings to propose.
+type definition struct {
+	id           string
+	name         string
+	dependencies []string // Any of these dependencies marks the framework
+	files        []string // Any of these files marks the framework
+	configFiles  []string // Any of these files marks the framework when one of the dirs exists too
+	dirs         []string // Directories that are specific to the framework
+	buildCmd     string   // Used when there is no package.json
+	distFolder   string
+	serverScript string // The package.json script that starts the server
+	serverCmd    string // Used when the server is not started through a package.json script
+	serverDeps   []string
+}
+
+// definitions is ordered from the most specific framework to the least specific one.
+// For instance, most frameworks depend on vite, so vite is checked last.
+var definitions = []definition{
+	{id: "nextjs", name: "Next.js", dependencies: []string{"next"}, distFolder: ".next", serverScript: "start"},
+	{id: "nuxt", name: "Nuxt", dependencies: []string{"nuxt", "nuxt3"}, distFolder: ".output", serverCmd: "node .output/server/index.mjs"},
+	{id: "remix", name: "Remix", dependencies: []string{"@remix-run/dev"}, distFolder: "build", serverScript: "start"},
+	{id: "react-router", name: "React Router", dependencies: []string{"@react-router/dev"}, distFolder: "build", serverScript: "start"},
+	{id: "sveltekit", name: "SvelteKit", dependencies: []string{"@sveltejs/kit"}, distFolder: "build", serverCmd: "node build", serverDeps: []string{"@sveltejs/adapter-node"}},
+	{id: "astro", name: "Astro", dependencies: []string{"astro"}, distFolder: "dist", serverCmd: "node dist/server/entry.mjs", serverDeps: []string{"@astrojs/node"}},
+	{id: "docusaurus", name: "Docusaurus", dependencies: []string{"@docusaurus/core"}, distFolder: "build"},
+	{id: "gatsby", name: "Gatsby", dependencies: []string{"gatsby"}, distFolder: "public"},
+	{id: "angular", name: "Angular", dependencies: []string{"@angular/core"}, distFolder: "dist"},
+	{id: "eleventy", name: "Eleventy", dependencies: []string{"@11ty/eleventy"}, distFolder: "_site"},
+	{id: "create-react-app", name: "Create React App", dependencies: []string{"react-scripts"}, distFolder: "build"},
+	{id: "vite", name: "Vite", dependencies: []string{"vite"}, distFolder: "dist"},
+	{id: "hugo", name: "Hugo", files: []string{"hugo.toml", "hugo.yaml", "hugo.json"}, configFiles: []string{"config.toml", "config.yaml", "config.json"}, dirs: []string{"archetypes", "layouts"}, buildCmd: "hugo", distFolder: "public"},
+	{id: "jekyll", name: "Jekyll", files: []string{"_config.yml"}, buildCmd: "bundle exec jekyll build", distFolder: "_site"},
+}
+
+// Detect inspects the package.json dependencies and the root files
+// to find the framework. It returns nil when no framework is detected.
+func Detect(args DetectArgs) *Preset {
+	var pkg *packageJson
+
+	if len(args.PackageJson) > 0 {
+		pkg = &packageJson{}
+
+		if err := json.Unmarshal(args.PackageJson, pkg); err != nil {
+			pkg = nil
+		}
+	}
+
+	for _, def := range definitions {
+		if !def.matches(pkg, args.Files) {
+			continue
+		}
+
+		preset := &Preset{
+			ID:         def.id,
+			Name:       def.name,
+			DistFolder: def.distFolder,
+			BuildCmd:   def.buildCmd,
+		}
+
+		if pkg != nil {
+			if pkg.Scripts["build"] != "" {
+				preset.BuildCmd = runScript(args.Files, "build")
+			}
+
+			if len(def.serverDeps) == 0 || pkg.hasAny(def.serverDeps) {
+				if def.serverScript != "" && pkg.Scripts[def.serverScript] != "" {
+					preset.ServerCmd = runScript(args.Files, def.serverScript)
+				} else {
+					preset.ServerCmd = def.serverCmd
+				}
+			}
+		}
+
+		if slices.Contains(args.Files, "api") {
+			preset.APIFolder = "/api"
+		}
+
+		return preset
+	}
+
+	return nil
+}
+
+// DetectDir detects the framework of the given directory.
+func DetectDir(dir string) *Preset {
+	entries, err := os.ReadDir(dir)
+
+	if err != nil {
+		return nil
+	}
+
+	args := DetectArgs{}
+
+	for _, entry := range entries {
+		args.Files = append(args.Files, entry.Name())
+	}
+
+	if slices.Contains(args.Files, "package.json") {
+		args.PackageJson, _ = os.ReadFile(path.Join(dir, "package.json"))
+	}
+
+	return Detect(args)
+}
+
+func (def definition) matches(pkg *packageJson, files []string) bool {
+	if pkg != nil && pkg.hasAny(def.dependencies) {
+		return true
+	}
+
+	for _, file := range def.files {
+		if slices.Contains(files, file) {
+			return true
+		}
+	}
+
+	// Configuration files with generic names, such as config.toml, are used by other
+	// tools as well. They only mark the framework next to its directories.
+	for _, file := range def.configFiles {
+		if !slices.Contains(files, file) {
+			continue
+		}
+
+		for _, dir := range def.dirs {
+			if slices.Contains(files, dir) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (pkg *packageJson) hasAny(deps []string) bool {
+	for _, dep := range deps {
+		if pkg.Dependencies[dep] != "" || pkg.DevDependencies[dep] != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runScript returns the command to run the given package.json script
+// with the package manager that is used by the repository.
+func runScript(files []string, script string) string {
+	switch {
+	case slices.Contains(files, "bun.lockb") || slices.Contains(files, "bun.lock"):
+		return fmt.Sprintf("bun run %s", script)
+	case slices.Contains(files, "yarn.lock"):
+		return fmt.Sprintf("yarn %s", script)
+	case slices.Contains(files, "pnpm-lock.yaml"):
+		return fmt.Sprintf("pnpm %s", script)
+	default:
+		return fmt.Sprintf("npm run %s", script)
+	}
+}
@@ -0,0 +1,143 @@
+package framework_test
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/framework"
+	"github.com/stretchr/testify/suite"
+)
+
+type FrameworkSuite struct {
+	suite.Suite
+}
+
+func (s *FrameworkSuite) Test_Detect_NextJS() {
+	preset := framework.Detect(framework.DetectArgs{
+		Files:       []string{"package.json", "pnpm-lock.yaml", "next.config.js"},
+		PackageJson: []byte(`{ "scripts": { "build": "next build", "start": "next start" }, "dependencies": { "next": "15.0.0", "react": "19.0.0" } }`),
+	})
+
+	s.Equal(&framework.Preset{
+		ID:         "nextjs",
+		Name:       "Next.js",
+		BuildCmd:   "pnpm build",
+		DistFolder: ".next",
+		ServerCmd:  "pnpm start",
+	}, preset)
+}
+
+func (s *FrameworkSuite) Test_Detect_Nuxt() {
+	preset := framework.Detect(framework.DetectArgs{
+		Files:       []string{"package.json", "package-lock.json", "nuxt.config.ts", "api"},
+		PackageJson: []byte(`{ "scripts": { "build": "nuxt build" }, "devDependencies": { "nuxt": "3.12.0", "vite": "5.0.0" } }`),
+	})
+
+	s.Equal(&framework.Preset{
+		ID:         "nuxt",
+		Name:       "Nuxt",
+		BuildCmd:   "npm run build",
+		DistFolder: ".output",
+		ServerCmd:  "node .output/server/index.mjs",
+		APIFolder:  "/api",
+	}, preset)
+}
+
+func (s *FrameworkSuite) Test_Detect_SvelteKit() {
+	// Static adapter: no server command
+	preset := framework.Detect(framework.DetectArgs{
+		Files:       []string{"package.json", "yarn.lock"},
+		PackageJson: []byte(`{ "scripts": { "build": "vite build" }, "devDependencies": { "@sveltejs/kit": "2.0.0", "@sveltejs/adapter-static": "3.0.0", "vite": "5.0.0" } }`),
+	})
+
+	s.Equal("sveltekit", preset.ID)
+	s.Equal("yarn build", preset.BuildCmd)
+	s.Equal("build", preset.DistFolder)
+	s.Empty(preset.ServerCmd)
+
+	// Node adapter
+	preset = framework.Detect(framework.DetectArgs{
+		Files:       []string{"package.json", "bun.lock"},
+		PackageJson: []byte(`{ "scripts": { "build": "vite build" }, "devDependencies": { "@sveltejs/kit": "2.0.0", "@sveltejs/adapter-node": "5.0.0" } }`),
+	})
+
+	s.Equal("sveltekit", preset.ID)
+	s.Equal("bun run build", preset.BuildCmd)
+	s.Equal("node build", preset.ServerCmd)
+}
+
+func (s *FrameworkSuite) Test_Detect_Vite() {
+	preset := framework.Detect(framework.DetectArgs{
+		Files:       []string{"package.json"},
+		PackageJson: []byte(`{ "scripts": { "build": "vite build" }, "devDependencies": { "vite": "5.0.0" } }`),
+	})
+
+	s.Equal(&framework.Preset{
+		ID:         "vite",
+		Name:       "Vite",
+		BuildCmd:   "npm run build",
+		DistFolder: "dist",
+	}, preset)
+}
+
+func (s *FrameworkSuite) Test_Detect_Hugo() {
+	preset := framework.Detect(framework.DetectArgs{
+		Files: []string{"hugo.toml", "content", "themes"},
+	})
+
+	s.Equal(&framework.Preset{
+		ID:         "hugo",
+		Name:       "Hugo",
+		BuildCmd:   "hugo",
+		DistFolder: "public",
+	}, preset)
+}
+
+func (s *FrameworkSuite) Test_Detect_HugoConfigFile() {
+	preset := framework.Detect(framework.DetectArgs{
+		Files: []string{"config.toml", "archetypes", "content"},
+	})
+
+	s.Equal("hugo", preset.ID)
+
+	// config.toml is not specific to hugo.
+	s.Nil(framework.Detect(framework.DetectArgs{
+		Files: []string{"config.toml", "content", "templates"},
+	}))
+}
+
+func (s *FrameworkSuite) Test_Detect_Unknown() {
+	s.Nil(framework.Detect(framework.DetectArgs{
+		Files:       []string{"package.json", "index.js"},
+		PackageJson: []byte(`{ "dependencies": { "express": "4.0.0" } }`),
+	}))
+
+	s.Nil(framework.Detect(framework.DetectArgs{
+		Files:       []string{"package.json"},
+		PackageJson: []byte(`invalid json`),
+	}))
+}
+
+func (s *FrameworkSuite) Test_DetectDir() {
+	dir, err := os.MkdirTemp("", "tmp-framework-")
+	s.NoError(err)
+
+	defer os.RemoveAll(dir)
+
+	s.NoError(os.WriteFile(path.Join(dir, "package.json"), []byte(`{ "scripts": { "build": "docusaurus build" }, "dependencies": { "@docusaurus/core": "3.0.0" } }`), 0664))
+	s.NoError(os.WriteFile(path.Join(dir, "yarn.lock"), []byte(""), 0664))
+
+	preset := framework.DetectDir(dir)
+
+	s.Equal(&framework.Preset{
+		ID:         "docusaurus",
+		Name:       "Docusaurus",
+		BuildCmd:   "yarn build",
+		DistFolder: "build",
+	}, preset)
+}
+
+func TestFrameworkSuite(t *testing.T) {
+	suite.Run(t, &FrameworkSuite{})
+}
@@ -0,0 +1,665 @@
+package osv
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/rediscache"
+	"github.com/stormkit-io/stormkit-io/src/lib/slog"
+)
+
+const (
+	SeverityLow      = "low"
+	SeverityModerate = "moderate"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+	SeverityUnknown  = "unknown"
+)
+
+// KeyUpdatedAt is the redis key that holds the unix timestamp
+// of the last successful advisory database update.
+const KeyUpdatedAt = "osv_updated_at"
+
+// EcosystemNpm is the name of the npm ecosystem in the OSV schema.
+const EcosystemNpm = "npm"
+
+// EcosystemPyPI is the name of the python package index ecosystem in the OSV schema.
+const EcosystemPyPI = "PyPI"
+
+// Ecosystems is the list of ecosystems that are downloaded when the
+// advisory database is refreshed.
+var Ecosystems = []string{EcosystemNpm, EcosystemPyPI}
+
+// ExportURL is the address of the OSV export for a given ecosystem.
+// See https://google.github.io/osv.dev/data/#data-dumps for more details.
+var ExportURL = "https://osv-vulnerabilities.storage.googleapis.com/%s/all.zip"
+
+// ErrDatabaseNotFound is returned when no advisory database is stored locally.
+var ErrDatabaseNotFound = errors.New("advisory database not found")
+
+// Dir returns the directory that contains the advisory database.
+func Dir() string {
+	if dir := os.Getenv("STORMKIT_OSV_DIR"); dir != "" {
+		return dir
+	}
+
+	return path.Join(os.Getenv("HOME"), ".stormkit", "osv")
+}
+
+// SeverityRank returns a number that can be used to compare severities.
+// Unknown severities have the lowest rank.
+func SeverityRank(severity string) int {
+	switch strings.ToLower(severity) {
+	case SeverityLow:
+		return 1
+	case SeverityModerate, "medium":
+		return 2
+	case SeverityHigh:
+		return 3
+	case SeverityCritical:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// IsValidSeverity returns true when the given value is a known severity.
+func IsValidSeverity(severity string) bool {
+	return SeverityRank(severity) > 0
+}
+
+// Package is a resolved dependency that is checked against the database.
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+}
+
+// Finding is an advisory that affects one of the scanned packages.
+type Finding struct {
+	Package
+
+	ID       string   `json:"id"`
+	Summary  string   `json:"summary,omitempty"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Severity string   `json:"severity"`
+	FixedIn  string   `json:"fixedIn,omitempty"`
+}
+
+type event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+func (e event) version() string {
+	return e.Introduced + e.Fixed + e.LastAffected
+}
+
+type affected struct {
+	Package struct {
+		Ecosystem string `json:"ecosystem"`
+		Name      string `json:"name"`
+	} `json:"package"`
+	Ranges []struct {
+		Type   string  `json:"type"`
+		Events []event `json:"events"`
+	} `json:"ranges"`
+	Versions []string `json:"versions"`
+}
+
+// advisory is a subset of the OSV schema.
+// See https://ossf.github.io/osv-schema/ for the full specification.
+type advisory struct {
+	ID               string     `json:"id"`
+	Summary          string     `json:"summary"`
+	Aliases          []string   `json:"aliases"`
+	Withdrawn        string     `json:"withdrawn"`
+	Affected         []affected `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// IndexFile is the name of the file that indexes the advisories of an ecosystem
+// export by package. It is stored next to the export.
+const IndexFile = "index.json"
+
+// index maps the packages to the names of the advisories in the export that affect them.
+// It is built once per download, so that scans only decode the advisories of the scanned
+// packages instead of the whole export.
+type index map[string][]string
+
+// packageKey returns the key of the package in the index. Python package names
+// are normalized, as they are case insensitive and treat -, _ and . the same way.
+func packageKey(ecosystem, name string) string {
+	if ecosystem == EcosystemPyPI {
+		name = pythonNameSeparators.ReplaceAllString(strings.ToLower(name), "-")
+	}
+
+	return ecosystem + "/" + name
+}
+
+// buildIndex indexes the advisories of the given export and stores the index next to it.
+// Withdrawn advisories are left out. The index is returned even when it cannot be stored.
+func buildIndex(file string) (index, error) {
+	reader, err := zip.OpenReader(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	idx := index{}
+
+	for _, f := range reader.File {
+		adv, err := readAdvisory(f)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if adv == nil || adv.Withdrawn != "" {
+			continue
+		}
+
+		visited := map[string]bool{}
+
+		for _, aff := range adv.Affected {
+			key := packageKey(aff.Package.Ecosystem, aff.Package.Name)
+
+			if !visited[key] {
+				visited[key] = true
+				idx[key] = append(idx[key], f.Name)
+			}
+		}
+	}
+
+	data, err := json.Marshal(idx)
+
+	if err != nil {
+		return idx, err
+	}
+
+	tmp, err := os.CreateTemp(path.Dir(file), "index-*.json")
+
+	if err != nil {
+		return idx, err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return idx, err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return idx, err
+	}
+
+	return idx, os.Rename(tmp.Name(), path.Join(path.Dir(file), IndexFile))
+}
+
+// loadIndex returns the index of the given export. The index is built when the export
+// is downloaded, it is only built here when it is missing or older than the export.
+func loadIndex(file string) (index, error) {
+	export, err := os.Stat(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	indexFile := path.Join(path.Dir(file), IndexFile)
+
+	if info, err := os.Stat(indexFile); err != nil || info.ModTime().Before(export.ModTime()) {
+		idx, err := buildIndex(file)
+
+		// The directory may not be writable by the runner, the index is used in memory in that case.
+		if err != nil && idx != nil {
+			slog.Errorf("cannot store advisory index %s: %v", indexFile, err)
+			return idx, nil
+		}
+
+		return idx, err
+	}
+
+	data, err := os.ReadFile(indexFile)
+
+	if err != nil {
+		return nil, err
+	}
+
+	idx := index{}
+
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return nil, fmt.Errorf("cannot parse advisory index %s: %w", indexFile, err)
+	}
+
+	return idx, nil
+}
+
+// scanExport returns the findings of the packages that belong to the ecosystem of the export.
+// Only the advisories that are indexed for the packages are decoded.
+func scanExport(file string, idx index, packages []Package) ([]Finding, error) {
+	reader, err := zip.OpenReader(file)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	files := map[string]*zip.File{}
+
+	for _, f := range reader.File {
+		files[f.Name] = f
+	}
+
+	findings := []Finding{}
+	advisories := map[string]*advisory{}
+
+	for _, pkg := range packages {
+		for _, name := range idx[packageKey(pkg.Ecosystem, pkg.Name)] {
+			adv, ok := advisories[name]
+
+			if !ok {
+				if f := files[name]; f != nil {
+					if adv, err = readAdvisory(f); err != nil {
+						return nil, err
+					}
+				}
+
+				advisories[name] = adv
+			}
+
+			if adv == nil {
+				continue
+			}
+
+			if finding := adv.match(pkg); finding != nil {
+				findings = append(findings, *finding)
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// Scan checks the given packages against the advisory database stored in dir.
+// It returns ErrDatabaseNotFound when none of the ecosystem exports exist.
+func Scan(dir string, packages []Package) ([]Finding, error) {
+	byEcosystem := map[string][]Package{}
+
+	for _, pkg := range packages {
+		byEcosystem[pkg.Ecosystem] = append(byEcosystem[pkg.Ecosystem], pkg)
+	}
+
+	findings := []Finding{}
+	found := false
+
+	for _, ecosystem := range Ecosystems {
+		file := path.Join(dir, ecosystem, "all.zip")
+
+		if _, err := os.Stat(file); os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		found = true
+
+		if len(byEcosystem[ecosystem]) == 0 {
+			continue
+		}
+
+		idx, err := loadIndex(file)
+
+		if err != nil {
+			return nil, err
+		}
+
+		ecosystemFindings, err := scanExport(file, idx, byEcosystem[ecosystem])
+
+		if err != nil {
+			return nil, err
+		}
+
+		findings = append(findings, ecosystemFindings...)
+	}
+
+	if !found {
+		return nil, ErrDatabaseNotFound
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if ri, rj := SeverityRank(findings[i].Severity), SeverityRank(findings[j].Severity); ri != rj {
+			return ri > rj
+		}
+
+		return findings[i].Name < findings[j].Name
+	})
+
+	return findings, nil
+}
+
+func readAdvisory(f *zip.File) (*advisory, error) {
+	if !strings.HasSuffix(f.Name, ".json") {
+		return nil, nil
+	}
+
+	rc, err := f.Open()
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rc.Close()
+
+	adv := &advisory{}
+
+	if err := json.NewDecoder(rc).Decode(adv); err != nil {
+		return nil, fmt.Errorf("cannot parse advisory %s: %w", f.Name, err)
+	}
+
+	return adv, nil
+}
+
+// match returns the finding for the package when it is affected by the advisory.
+func (a *advisory) match(pkg Package) *Finding {
+	severity := strings.ToLower(a.DatabaseSpecific.Severity)
+
+	if severity == "medium" {
+		severity = SeverityModerate
+	}
+
+	if !IsValidSeverity(severity) {
+		severity = SeverityUnknown
+	}
+
+	key := packageKey(pkg.Ecosystem, pkg.Name)
+
+	for _, aff := range a.Affected {
+		if packageKey(aff.Package.Ecosystem, aff.Package.Name) != key {
+			continue
+		}
+
+		isAffected, fixedIn := aff.affects(pkg.Version)
+
+		if !isAffected {
+			continue
+		}
+
+		return &Finding{
+			Package:  pkg,
+			ID:       a.ID,
+			Summary:  a.Summary,
+			Aliases:  a.Aliases,
+			Severity: severity,
+			FixedIn:  fixedIn,
+		}
+	}
+
+	return nil
+}
+
+// affects returns true when the given version is affected. When a fix exists,
+// the version that fixes the vulnerability is returned as well.
+func (a affected) affects(version string) (bool, string) {
+	compare := CompareVersions
+
+	if a.Package.Ecosystem == EcosystemPyPI {
+		compare = ComparePythonVersions
+	}
+
+	for _, v := range a.Versions {
+		if v == version {
+			return true, ""
+		}
+	}
+
+	for _, r := range a.Ranges {
+		if r.Type != "SEMVER" && r.Type != "ECOSYSTEM" {
+			continue
+		}
+
+		events := make([]event, len(r.Events))
+		copy(events, r.Events)
+
+		sort.SliceStable(events, func(i, j int) bool {
+			return compare(events[i].version(), events[j].version()) < 0
+		})
+
+		isAffected := false
+
+		for _, e := range events {
+			switch {
+			case e.Introduced != "":
+				if e.Introduced == "0" || compare(version, e.Introduced) >= 0 {
+					isAffected = true
+				}
+			case e.Fixed != "":
+				if compare(version, e.Fixed) >= 0 {
+					isAffected = false
+				}
+			case e.LastAffected != "":
+				if compare(version, e.LastAffected) > 0 {
+					isAffected = false
+				}
+			}
+		}
+
+		if !isAffected {
+			continue
+		}
+
+		for _, e := range events {
+			if e.Fixed != "" && compare(version, e.Fixed) < 0 {
+				return true, e.Fixed
+			}
+		}
+
+		return true, ""
+	}
+
+	return false, ""
+}
+
+// CompareVersions compares two semantic versions. It returns -1 when a < b,
+// 0 when a == b and 1 when a > b. The special version "0" is lower than any other.
+func CompareVersions(a, b string) int {
+	a, aPre := splitVersion(a)
+	b, bPre := splitVersion(b)
+
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < 3; i++ {
+		if c := compareNumeric(part(aParts, i), part(bParts, i)); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case aPre == bPre:
+		return 0
+	case aPre == "":
+		return 1
+	case bPre == "":
+		return -1
+	}
+
+	aIDs := strings.Split(aPre, ".")
+	bIDs := strings.Split(bPre, ".")
+
+	for i := 0; i < len(aIDs) && i < len(bIDs); i++ {
+		_, aErr := strconv.Atoi(aIDs[i])
+		_, bErr := strconv.Atoi(bIDs[i])
+
+		var c int
+
+		switch {
+		case aErr == nil && bErr == nil:
+			c = compareNumeric(aIDs[i], bIDs[i])
+		case aErr == nil:
+			c = -1
+		case bErr == nil:
+			c = 1
+		default:
+			c = strings.Compare(aIDs[i], bIDs[i])
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	return compareNumeric(strconv.Itoa(len(aIDs)), strconv.Itoa(len(bIDs)))
+}
+
+// splitVersion removes the build metadata and returns the version and the pre-release.
+func splitVersion(version string) (string, string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+
+	if i := strings.Index(version, "+"); i >= 0 {
+		version = version[:i]
+	}
+
+	if i := strings.Index(version, "-"); i >= 0 {
+		return version[:i], version[i+1:]
+	}
+
+	return version, ""
+}
+
+func part(parts []string, i int) string {
+	if i < len(parts) {
+		return parts[i]
+	}
+
+	return "0"
+}
+
+func compareNumeric(a, b string) int {
+	ai, _ := strconv.Atoi(a)
+	bi, _ := strconv.Atoi(b)
+
+	switch {
+	case ai < bi:
+		return -1
+	case ai > bi:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Download fetches the latest OSV exports and stores them in dir together with their index.
+// Existing exports are replaced only when the download succeeds.
+func Download(ctx context.Context, dir string) error {
+	for _, ecosystem := range Ecosystems {
+		if err := download(ctx, fmt.Sprintf(ExportURL, ecosystem), path.Join(dir, ecosystem)); err != nil {
+			return fmt.Errorf("cannot download %s advisories: %w", ecosystem, err)
+		}
+	}
+
+	return nil
+}
+
+func download(ctx context.Context, url, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+
+	if err != nil {
+		return err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+
+	tmp, err := os.CreateTemp(dir, "all-*.zip")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, res.Body); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	// Make sure that the export is a valid zip before replacing the existing one
+	reader, err := zip.OpenReader(tmp.Name())
+
+	if err != nil {
+		return err
+	}
+
+	reader.Close()
+
+	file := path.Join(dir, "all.zip")
+
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return err
+	}
+
+	// The export is indexed once here rather than on every scan.
+	_, err = buildIndex(file)
+	return err
+}
+
+// AutoUpdate is a job that refreshes the advisory database.
+func AutoUpdate(ctx context.Context, payload ...string) {
+	var err error
+
+	keyName := rediscache.Service().Key("osv_update")
+
+	slog.Debug(slog.LogOpts{
+		Msg:   "running advisory database update job",
+		Level: slog.DL1,
+	})
+
+	defer func() {
+		if err != nil {
+			slog.Errorf("error while updating advisory database: %v", err)
+			rediscache.Client().Set(ctx, keyName, rediscache.StatusErr, time.Minute)
+		} else {
+			rediscache.Client().Set(ctx, keyName, rediscache.StatusOK, time.Minute)
+			rediscache.Client().Set(ctx, KeyUpdatedAt, time.Now().Unix(), 0)
+		}
+	}()
+
+	rediscache.Client().Set(ctx, keyName, rediscache.StatusProcessing, time.Hour)
+
+	err = Download(ctx, Dir())
+}
@@ -0,0 +1,225 @@
+package osv_test
+
+import (
+	"archive/zip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/osv"
+	"github.com/stretchr/testify/suite"
+)
+
+type OSVSuite struct {
+	suite.Suite
+	dir string
+}
+
+func (s *OSVSuite) BeforeTest(_, _ string) {
+	s.dir = s.T().TempDir()
+}
+
+func (s *OSVSuite) writeExport(advisories map[string]string) string {
+	file := path.Join(s.T().TempDir(), "all.zip")
+	f, err := os.Create(file)
+	s.NoError(err)
+
+	w := zip.NewWriter(f)
+
+	for name, content := range advisories {
+		fw, err := w.Create(name)
+		s.NoError(err)
+		_, err = fw.Write([]byte(content))
+		s.NoError(err)
+	}
+
+	s.NoError(w.Close())
+	s.NoError(f.Close())
+
+	return file
+}
+
+func (s *OSVSuite) install(ecosystem, file string) {
+	data, err := os.ReadFile(file)
+	s.NoError(err)
+	s.NoError(os.MkdirAll(path.Join(s.dir, ecosystem), 0755))
+	s.NoError(os.WriteFile(path.Join(s.dir, ecosystem, "all.zip"), data, 0644))
+}
+
+var advisories = map[string]string{
+	"GHSA-1.json": `{
+		"id": "GHSA-1",
+		"summary": "Prototype pollution",
+		"aliases": ["CVE-2021-23337"],
+		"affected": [{
+			"package": { "ecosystem": "npm", "name": "lodash" },
+			"ranges": [{ "type": "SEMVER", "events": [{ "introduced": "0" }, { "fixed": "4.17.21" }] }]
+		}],
+		"database_specific": { "severity": "HIGH" }
+	}`,
+	"GHSA-2.json": `{
+		"id": "GHSA-2",
+		"summary": "ReDoS",
+		"affected": [{
+			"package": { "ecosystem": "npm", "name": "minimist" },
+			"ranges": [{ "type": "SEMVER", "events": [{ "introduced": "1.0.0" }, { "last_affected": "1.2.5" }] }]
+		}],
+		"database_specific": { "severity": "MODERATE" }
+	}`,
+	"GHSA-3.json": `{
+		"id": "GHSA-3",
+		"withdrawn": "2023-01-01T00:00:00Z",
+		"affected": [{
+			"package": { "ecosystem": "npm", "name": "lodash" },
+			"versions": ["4.17.20"]
+		}],
+		"database_specific": { "severity": "CRITICAL" }
+	}`,
+}
+
+func (s *OSVSuite) Test_Scan() {
+	s.install(osv.EcosystemNpm, s.writeExport(advisories))
+
+	findings, err := osv.Scan(s.dir, []osv.Package{
+		{Ecosystem: osv.EcosystemNpm, Name: "lodash", Version: "4.17.20"},
+		{Ecosystem: osv.EcosystemNpm, Name: "minimist", Version: "1.2.5"},
+		{Ecosystem: osv.EcosystemNpm, Name: "minimist", Version: "1.2.6"},
+		{Ecosystem: osv.EcosystemNpm, Name: "react", Version: "18.2.0"},
+	})
+
+	s.NoError(err)
+	s.Equal([]osv.Finding{
+		{
+			Package:  osv.Package{Ecosystem: osv.EcosystemNpm, Name: "lodash", Version: "4.17.20"},
+			ID:       "GHSA-1",
+			Summary:  "Prototype pollution",
+			Aliases:  []string{"CVE-2021-23337"},
+			Severity: osv.SeverityHigh,
+			FixedIn:  "4.17.21",
+		},
+		{
+			Package:  osv.Package{Ecosystem: osv.EcosystemNpm, Name: "minimist", Version: "1.2.5"},
+			ID:       "GHSA-2",
+			Summary:  "ReDoS",
+			Severity: osv.SeverityModerate,
+		},
+	}, findings)
+}
+
+func (s *OSVSuite) Test_Scan_PyPI() {
+	s.install(osv.EcosystemPyPI, s.writeExport(map[string]string{
+		"PYSEC-1.json": `{
+			"id": "PYSEC-1",
+			"summary": "SQL injection",
+			"affected": [{
+				"package": { "ecosystem": "PyPI", "name": "Django" },
+				"ranges": [{ "type": "ECOSYSTEM", "events": [{ "introduced": "5.0a1" }, { "fixed": "5.0.2" }] }]
+			}],
+			"database_specific": { "severity": "CRITICAL" }
+		}`,
+	}))
+
+	findings, err := osv.Scan(s.dir, []osv.Package{
+		{Ecosystem: osv.EcosystemPyPI, Name: "django", Version: "5.0.1"},
+		{Ecosystem: osv.EcosystemPyPI, Name: "django", Version: "5.0.2"},
+		{Ecosystem: osv.EcosystemPyPI, Name: "django", Version: "4.2.9"},
+	})
+
+	s.NoError(err)
+	s.Equal([]osv.Finding{
+		{
+			Package:  osv.Package{Ecosystem: osv.EcosystemPyPI, Name: "django", Version: "5.0.1"},
+			ID:       "PYSEC-1",
+			Summary:  "SQL injection",
+			Severity: osv.SeverityCritical,
+			FixedIn:  "5.0.2",
+		},
+	}, findings)
+}
+
+func (s *OSVSuite) Test_Scan_ReloadsReplacedExport() {
+	s.install(osv.EcosystemNpm, s.writeExport(map[string]string{}))
+
+	packages := []osv.Package{{Ecosystem: osv.EcosystemNpm, Name: "lodash", Version: "4.17.20"}}
+	findings, err := osv.Scan(s.dir, packages)
+	s.NoError(err)
+	s.Empty(findings)
+
+	// Exports are replaced by a new download.
+	file := path.Join(s.dir, osv.EcosystemNpm, "all.zip")
+	s.install(osv.EcosystemNpm, s.writeExport(advisories))
+	s.NoError(os.Chtimes(file, time.Now().Add(time.Minute), time.Now().Add(time.Minute)))
+
+	findings, err = osv.Scan(s.dir, packages)
+	s.NoError(err)
+	s.Len(findings, 1)
+}
+
+func (s *OSVSuite) Test_Scan_DatabaseNotFound() {
+	_, err := osv.Scan(s.dir, []osv.Package{})
+	s.ErrorIs(err, osv.ErrDatabaseNotFound)
+}
+
+func (s *OSVSuite) Test_CompareVersions() {
+	s.Equal(-1, osv.CompareVersions("1.2.3", "1.10.0"))
+	s.Equal(1, osv.CompareVersions("2.0.0", "2.0.0-rc.1"))
+	s.Equal(-1, osv.CompareVersions("2.0.0-alpha", "2.0.0-beta"))
+	s.Equal(-1, osv.CompareVersions("2.0.0-rc.2", "2.0.0-rc.10"))
+	s.Equal(0, osv.CompareVersions("v1.0.0+build.1", "1.0.0"))
+}
+
+func (s *OSVSuite) Test_ComparePythonVersions() {
+	s.Equal(-1, osv.ComparePythonVersions("1.0.dev1", "1.0a1"))
+	s.Equal(-1, osv.ComparePythonVersions("1.0a1", "1.0b2"))
+	s.Equal(-1, osv.ComparePythonVersions("1.0rc1", "1.0"))
+	s.Equal(-1, osv.ComparePythonVersions("1.0", "1.0.post1"))
+	s.Equal(-1, osv.ComparePythonVersions("1.0.post1.dev0", "1.0.post1"))
+	s.Equal(-1, osv.ComparePythonVersions("2.9.10", "2.10"))
+	s.Equal(-1, osv.ComparePythonVersions("2024.1", "1!0.1"))
+	s.Equal(0, osv.ComparePythonVersions("1.0", "1.0.0"))
+	s.Equal(0, osv.ComparePythonVersions("1.0-1", "1.0.post1"))
+	s.Equal(0, osv.ComparePythonVersions("1.0RC1", "1.0rc1"))
+}
+
+func (s *OSVSuite) Test_SeverityRank() {
+	s.True(osv.SeverityRank(osv.SeverityCritical) > osv.SeverityRank(osv.SeverityHigh))
+	s.True(osv.SeverityRank(osv.SeverityModerate) > osv.SeverityRank(osv.SeverityLow))
+	s.False(osv.IsValidSeverity("severe"))
+}
+
+func (s *OSVSuite) Test_Download() {
+	export, err := os.ReadFile(s.writeExport(advisories))
+	s.NoError(err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.Contains([]string{"/npm/all.zip", "/PyPI/all.zip"}, r.URL.Path)
+		w.Write(export)
+	}))
+
+	defer server.Close()
+
+	exportURL := osv.ExportURL
+	osv.ExportURL = server.URL + "/%s/all.zip"
+	defer func() { osv.ExportURL = exportURL }()
+
+	s.NoError(osv.Download(context.Background(), s.dir))
+
+	// The exports are indexed when they are downloaded.
+	s.FileExists(path.Join(s.dir, osv.EcosystemNpm, osv.IndexFile))
+	s.FileExists(path.Join(s.dir, osv.EcosystemPyPI, osv.IndexFile))
+
+	findings, err := osv.Scan(s.dir, []osv.Package{
+		{Ecosystem: osv.EcosystemNpm, Name: "lodash", Version: "4.17.21"},
+	})
+
+	s.NoError(err)
+	s.Empty(findings)
+}
+
+func TestOSV(t *testing.T) {
+	suite.Run(t, &OSVSuite{})
+}
@@ -0,0 +1,128 @@
+package osv
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// pythonNameSeparators matches the separators of python package names.
+// See https://peps.python.org/pep-0503/#normalized-names.
+var pythonNameSeparators = regexp.MustCompile(`[-_.]+`)
+
+// pythonVersion matches the versions that are described in PEP 440.
+// See https://peps.python.org/pep-0440/#appendix-b-parsing-version-strings-with-regular-expressions.
+var pythonVersion = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|alpha|b|beta|c|rc|pre|preview)[-_.]?(\d+)?)?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d+)?)?` +
+	`(?:[-_.]?(dev)[-_.]?(\d+)?)?` +
+	`(?:\+[a-z0-9]+(?:[-_.][a-z0-9]+)*)?$`)
+
+// pythonRelease is a parsed PEP 440 version. The fields are compared in order.
+type pythonRelease struct {
+	epoch   int
+	release []int
+	pre     int // -1 for development releases, 0-2 for alpha, beta and rc, 3 for final releases
+	preNum  int
+	post    int // -1 when the version is not a post-release
+	dev     int // math.MaxInt when the version is not a development release
+}
+
+// parsePythonVersion parses the given PEP 440 version. It returns false
+// when the version does not follow PEP 440.
+func parsePythonVersion(version string) (pythonRelease, bool) {
+	m := pythonVersion.FindStringSubmatch(strings.ToLower(strings.TrimSpace(version)))
+
+	if m == nil {
+		return pythonRelease{}, false
+	}
+
+	v := pythonRelease{pre: 3, post: -1, dev: math.MaxInt}
+	v.epoch, _ = strconv.Atoi(m[1])
+
+	for _, p := range strings.Split(m[2], ".") {
+		n, _ := strconv.Atoi(p)
+		v.release = append(v.release, n)
+	}
+
+	switch m[3] {
+	case "a", "alpha":
+		v.pre = 0
+	case "b", "beta":
+		v.pre = 1
+	case "c", "rc", "pre", "preview":
+		v.pre = 2
+	}
+
+	v.preNum, _ = strconv.Atoi(m[4])
+
+	if m[5] != "" || m[6] != "" {
+		v.post, _ = strconv.Atoi(m[5] + m[7])
+	}
+
+	if m[8] != "" {
+		v.dev, _ = strconv.Atoi(m[9])
+
+		// Development releases come before the pre-releases of the same version.
+		if m[3] == "" && v.post == -1 {
+			v.pre = -1
+		}
+	}
+
+	return v, true
+}
+
+// ComparePythonVersions compares two PEP 440 versions. It returns -1 when a < b,
+// 0 when a == b and 1 when a > b. Versions that do not follow PEP 440 are compared
+// as semantic versions.
+func ComparePythonVersions(a, b string) int {
+	va, aOk := parsePythonVersion(a)
+	vb, bOk := parsePythonVersion(b)
+
+	if !aOk || !bOk {
+		return CompareVersions(a, b)
+	}
+
+	if c := compareInts(va.epoch, vb.epoch); c != 0 {
+		return c
+	}
+
+	for i := 0; i < len(va.release) || i < len(vb.release); i++ {
+		if c := compareInts(releasePart(va.release, i), releasePart(vb.release, i)); c != 0 {
+			return c
+		}
+	}
+
+	for _, c := range []int{
+		compareInts(va.pre, vb.pre),
+		compareInts(va.preNum, vb.preNum),
+		compareInts(va.post, vb.post),
+		compareInts(va.dev, vb.dev),
+	} {
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func releasePart(release []int, i int) int {
+	if i < len(release) {
+		return release[i]
+	}
+
+	return 0
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,87 @@
+package redact
+
+import (
+	"encoding/base64"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Mask is the text that replaces the secret values.
+const Mask = "***"
+
+// MinLength is the minimum length of a secret value to be redacted.
+// Shorter values would mask unrelated parts of the output.
+const MinLength = 4
+
+// Redactor masks the secret values, including their base64 and
+// url encoded forms, in a given text.
+type Redactor struct {
+	replacer *strings.Replacer
+}
+
+// New returns a new redactor for the given secret values. It returns
+// nil when there is nothing to redact. A nil redactor is safe to use.
+func New(secrets []string) *Redactor {
+	values := []string{}
+
+	for _, secret := range secrets {
+		if len(secret) < MinLength {
+			continue
+		}
+
+		values = append(values, Variants(secret)...)
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	// Longer values first so that the longest match wins
+	slices.SortFunc(values, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	values = slices.Compact(values)
+	pairs := make([]string, 0, len(values)*2)
+
+	for _, value := range values {
+		pairs = append(pairs, value, Mask)
+	}
+
+	return &Redactor{replacer: strings.NewReplacer(pairs...)}
+}
+
+// Variants returns the forms of the secret value that are redacted.
+func Variants(secret string) []string {
+	variants := []string{
+		secret,
+		base64.StdEncoding.EncodeToString([]byte(secret)),
+		base64.RawStdEncoding.EncodeToString([]byte(secret)),
+		base64.URLEncoding.EncodeToString([]byte(secret)),
+		base64.RawURLEncoding.EncodeToString([]byte(secret)),
+		url.QueryEscape(secret),
+		url.PathEscape(secret),
+	}
+
+	slices.Sort(variants)
+	return slices.Compact(variants)
+}
+
+// String redacts the given text.
+func (r *Redactor) String(text string) string {
+	if r == nil {
+		return text
+	}
+
+	return r.replacer.Replace(text)
+}
+
+// Bytes redacts the given data.
+func (r *Redactor) Bytes(data []byte) []byte {
+	if r == nil {
+		return data
+	}
+
+	return []byte(r.replacer.Replace(string(data)))
+}
@@ -0,0 +1,43 @@
+package redact_test
+
+import (
+	"encoding/base64"
+	"net/url"
+	"testing"
+
+	"github.com/stormkit-io/stormkit-io/src/lib/utils/redact"
+	"github.com/stretchr/testify/suite"
+)
+
+type RedactSuite struct {
+	suite.Suite
+}
+
+func (s *RedactSuite) Test_String() {
+	secret := "s3cr3t/t0ken+value"
+	r := redact.New([]string{secret, "abc", ""})
+
+	s.Equal("token is ***", r.String("token is "+secret))
+	s.Equal("base64: ***", r.String("base64: "+base64.StdEncoding.EncodeToString([]byte(secret))))
+	s.Equal("base64url: ***", r.String("base64url: "+base64.RawURLEncoding.EncodeToString([]byte(secret))))
+	s.Equal("https://example.org/?token=***", r.String("https://example.org/?token="+url.QueryEscape(secret)))
+	s.Equal("short values are not masked: abc", r.String("short values are not masked: abc"))
+}
+
+func (s *RedactSuite) Test_Bytes() {
+	r := redact.New([]string{"my-api-token"})
+
+	s.Equal([]byte("Authorization: Bearer ***\n"), r.Bytes([]byte("Authorization: Bearer my-api-token\n")))
+}
+
+func (s *RedactSuite) Test_Nil() {
+	r := redact.New([]string{"abc"})
+
+	s.Nil(r)
+	s.Equal("abc", r.String("abc"))
+	s.Equal([]byte("abc"), r.Bytes([]byte("abc")))
+}
+
+func TestRedactSuite(t *testing.T) {
+	suite.Run(t, &RedactSuite{})
+}
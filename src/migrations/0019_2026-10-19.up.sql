@@ -0,0 +1,2 @@
+-- Store the results of the http checks executed against the deployment preview
+ALTER TABLE skitapi.deployments ADD COLUMN IF NOT EXISTS http_checks JSONB NULL;
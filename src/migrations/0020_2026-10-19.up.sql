@@ -0,0 +1,4 @@
+-- Store the software bill of materials generated for each deployment
+ALTER TABLE skitapi.deployments ADD COLUMN IF NOT EXISTS sbom JSONB NULL;
+
+CREATE INDEX IF NOT EXISTS idx_deployments_sbom_components ON skitapi.deployments USING GIN ((sbom->'components') jsonb_path_ops);
@@ -0,0 +1,2 @@
+-- Store the findings of the dependency vulnerability scan
+ALTER TABLE skitapi.deployments ADD COLUMN IF NOT EXISTS vulnerabilities JSONB NULL;
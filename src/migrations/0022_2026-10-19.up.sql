@@ -0,0 +1,29 @@
+-- Publishes that are executed by the workerserver at a given time
+CREATE TABLE IF NOT EXISTS skitapi.scheduled_publishes (
+    schedule_id bigserial primary key NOT NULL,
+    app_id bigint NOT NULL,
+    env_id bigint NOT NULL,
+    publish_settings jsonb NOT NULL,
+    publish_at timestamp without time zone NOT NULL,
+    override_freeze boolean DEFAULT false NOT NULL,
+    created_by bigint NOT NULL,
+    executed_at timestamp without time zone NULL,
+    publish_error text NULL,
+    created_at timestamp without time zone DEFAULT (now() AT TIME ZONE 'UTC'::text) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_scheduled_publishes_publish_at ON skitapi.scheduled_publishes USING btree (publish_at) WHERE executed_at IS NULL;
+
+DO $$
+BEGIN
+  BEGIN
+
+    ALTER TABLE ONLY skitapi.scheduled_publishes
+        ADD CONSTRAINT scheduled_publishes_env_id_fkey FOREIGN KEY (env_id) REFERENCES skitapi.apps_build_conf(env_id) ON DELETE CASCADE;
+
+  EXCEPTION
+    WHEN duplicate_table THEN  -- postgres raises duplicate_table at surprising times. Ex.: for UNIQUE constraints.
+    WHEN duplicate_object THEN
+      RAISE NOTICE 'Table constraint already exists';
+  END;
+END $$;
@@ -0,0 +1,30 @@
+-- Approvals and rejections of deployments that are published to protected environments
+CREATE TABLE IF NOT EXISTS skitapi.deployment_approvals (
+    approval_id bigserial primary key NOT NULL,
+    deployment_id bigint NOT NULL,
+    app_id bigint NOT NULL,
+    env_id bigint NOT NULL,
+    user_id bigint NOT NULL,
+    approval_status text NOT NULL,
+    approval_comment text NULL,
+    created_at timestamp without time zone DEFAULT (now() AT TIME ZONE 'UTC'::text) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_deployment_approvals_user ON skitapi.deployment_approvals USING btree (deployment_id, env_id, user_id);
+
+DO $$
+BEGIN
+  BEGIN
+
+    ALTER TABLE ONLY skitapi.deployment_approvals
+        ADD CONSTRAINT deployment_approvals_deployment_id_fkey FOREIGN KEY (deployment_id) REFERENCES skitapi.deployments(deployment_id) ON DELETE CASCADE;
+
+    ALTER TABLE ONLY skitapi.deployment_approvals
+        ADD CONSTRAINT deployment_approvals_env_id_fkey FOREIGN KEY (env_id) REFERENCES skitapi.apps_build_conf(env_id) ON DELETE CASCADE;
+
+  EXCEPTION
+    WHEN duplicate_table THEN  -- postgres raises duplicate_table at surprising times. Ex.: for UNIQUE constraints.
+    WHEN duplicate_object THEN
+      RAISE NOTICE 'Table constraint already exists';
+  END;
+END $$;
@@ -0,0 +1,30 @@
+-- Deployments that are monitored after publish to be rolled back on health degradation
+CREATE TABLE IF NOT EXISTS skitapi.rollback_watches (
+    watch_id bigserial primary key NOT NULL,
+    app_id bigint NOT NULL,
+    env_id bigint NOT NULL,
+    deployment_id bigint NOT NULL,
+    previous_deployment_id bigint NOT NULL,
+    watch_until timestamp without time zone NOT NULL,
+    resolved_at timestamp without time zone NULL,
+    rolled_back boolean DEFAULT false NOT NULL,
+    created_at timestamp without time zone DEFAULT (now() AT TIME ZONE 'UTC'::text) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_rollback_watches_active ON skitapi.rollback_watches USING btree (env_id) WHERE resolved_at IS NULL;
+
+ALTER TABLE skitapi.deployments ADD COLUMN IF NOT EXISTS rollback_info JSONB NULL;
+
+DO $$
+BEGIN
+  BEGIN
+
+    ALTER TABLE ONLY skitapi.rollback_watches
+        ADD CONSTRAINT rollback_watches_env_id_fkey FOREIGN KEY (env_id) REFERENCES skitapi.apps_build_conf(env_id) ON DELETE CASCADE;
+
+  EXCEPTION
+    WHEN duplicate_table THEN  -- postgres raises duplicate_table at surprising times. Ex.: for UNIQUE constraints.
+    WHEN duplicate_object THEN
+      RAISE NOTICE 'Table constraint already exists';
+  END;
+END $$;
@@ -0,0 +1,12 @@
+-- Content-addressed artifacts that are shared across deployments of the same app
+CREATE TABLE IF NOT EXISTS skitapi.artifact_blobs (
+    blob_location text NOT NULL,
+    blob_hash text NOT NULL,
+    app_id bigint NOT NULL,
+    ref_count integer DEFAULT 0 NOT NULL,
+    created_at timestamp without time zone DEFAULT (now() AT TIME ZONE 'UTC'::text) NOT NULL,
+    updated_at timestamp without time zone DEFAULT (now() AT TIME ZONE 'UTC'::text) NOT NULL,
+    PRIMARY KEY (blob_location, blob_hash)
+);
+
+CREATE INDEX IF NOT EXISTS idx_artifact_blobs_unreferenced ON skitapi.artifact_blobs USING btree (updated_at) WHERE ref_count <= 0;
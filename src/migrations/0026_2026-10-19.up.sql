@@ -0,0 +1,27 @@
+-- Deployments that are waiting to be built or that are being built
+CREATE TABLE IF NOT EXISTS skitapi.deployment_queue (
+    deployment_id bigint primary key NOT NULL,
+    app_id bigint NOT NULL,
+    team_id bigint NULL,
+    env_id bigint NOT NULL,
+    branch text NOT NULL,
+    payload text NOT NULL,
+    dispatched_at timestamp without time zone NULL,
+    created_at timestamp without time zone DEFAULT (now() AT TIME ZONE 'UTC'::text) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_deployment_queue_env_branch ON skitapi.deployment_queue USING btree (env_id, branch);
+
+DO $$
+BEGIN
+  BEGIN
+
+    ALTER TABLE ONLY skitapi.deployment_queue
+        ADD CONSTRAINT deployment_queue_deployment_id_fkey FOREIGN KEY (deployment_id) REFERENCES skitapi.deployments(deployment_id) ON DELETE CASCADE;
+
+  EXCEPTION
+    WHEN duplicate_table THEN  -- postgres raises duplicate_table at surprising times. Ex.: for UNIQUE constraints.
+    WHEN duplicate_object THEN
+      RAISE NOTICE 'Table constraint already exists';
+  END;
+END $$;
@@ -0,0 +1,5 @@
+-- Pinned deployments are never removed by the retention policy
+ALTER TABLE skitapi.deployments ADD COLUMN IF NOT EXISTS is_pinned BOOLEAN DEFAULT FALSE NOT NULL;
+
+-- The last time the deployment was published, used to keep the last published deployment
+ALTER TABLE skitapi.deployments ADD COLUMN IF NOT EXISTS published_at TIMESTAMP WITHOUT TIME ZONE NULL;
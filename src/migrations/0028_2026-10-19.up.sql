@@ -0,0 +1,2 @@
+-- Gitea and Forgejo oauth tokens
+ALTER TYPE skitapi.access_token_type ADD VALUE IF NOT EXISTS 'gitea';
@@ -0,0 +1,2 @@
+-- The git tag that was deployed, set when deploying a tag instead of a branch head
+ALTER TABLE skitapi.deployments ADD COLUMN IF NOT EXISTS git_tag TEXT NULL;
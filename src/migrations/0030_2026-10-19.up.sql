@@ -0,0 +1,28 @@
+-- Credentials and change detection settings of the apps that are deployed from a generic git remote
+CREATE TABLE IF NOT EXISTS skitapi.app_git_remotes (
+    app_id bigint primary key NOT NULL,
+    auth_type text DEFAULT 'ssh' NOT NULL,
+    https_username text NULL,
+    https_password text NULL,
+    known_hosts text NULL,
+    webhook_secret text NOT NULL,
+    poll_interval integer DEFAULT 0 NOT NULL,
+    last_refs jsonb NULL,
+    last_polled_at timestamp without time zone NULL,
+    created_at timestamp without time zone DEFAULT (now() AT TIME ZONE 'UTC'::text) NOT NULL,
+    updated_at timestamp without time zone NULL
+);
+
+DO $$
+BEGIN
+  BEGIN
+
+    ALTER TABLE ONLY skitapi.app_git_remotes
+        ADD CONSTRAINT app_git_remotes_app_id_fkey FOREIGN KEY (app_id) REFERENCES skitapi.apps(app_id) ON DELETE CASCADE;
+
+  EXCEPTION
+    WHEN duplicate_table THEN  -- postgres raises duplicate_table at surprising times. Ex.: for UNIQUE constraints.
+    WHEN duplicate_object THEN
+      RAISE NOTICE 'Table constraint already exists';
+  END;
+END $$;